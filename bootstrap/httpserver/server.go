@@ -42,6 +42,7 @@ type HttpServer struct {
 
 	logger         log.Logger
 	publicApi      services.PublicApi
+	gossipTraffic  GossipTrafficReporter
 	metricRegistry metric.Registry
 	config         config.HttpServerConfig
 
@@ -124,6 +125,10 @@ func (s *HttpServer) RegisterPublicApi(publicApi services.PublicApi) {
 	s.publicApi = publicApi
 }
 
+func (s *HttpServer) RegisterGossipTrafficReporter(reporter GossipTrafficReporter) {
+	s.gossipTraffic = reporter
+}
+
 // Allows handler to be called via XHR requests from any host
 func wrapHandlerWithCORS(f func(w http.ResponseWriter, r *http.Request)) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -166,16 +171,23 @@ func (s *HttpServer) createRouter() *http.ServeMux {
 
 	s.registerHttpHandler(router, "/api/v1/send-transaction", true, s.sendTransactionHandler)
 	s.registerHttpHandler(router, "/api/v1/send-transaction-async", true, s.sendTransactionAsyncHandler)
+	s.registerHttpHandler(router, "/api/v1/send-transactions", true, s.sendTransactionsHandler)
+	s.registerHttpHandler(router, "/api/v1/send-transactions-async", true, s.sendTransactionsAsyncHandler)
+	s.registerHttpHandler(router, "/api/v1/cancel-transaction", true, s.cancelTransactionHandler)
 	s.registerHttpHandler(router, "/api/v1/run-query", true, s.runQueryHandler)
+	s.registerHttpHandler(router, "/api/v1/simulate-transaction", true, s.simulateTransactionHandler)
 	s.registerHttpHandler(router, "/api/v1/get-transaction-status", true, s.getTransactionStatusHandler)
 	s.registerHttpHandler(router, "/api/v1/get-transaction-receipt-proof", true, s.getTransactionReceiptProofHandler)
 	s.registerHttpHandler(router, "/api/v1/get-block", true, s.getBlockHandler)
+	s.registerHttpHandler(router, "/api/v1/pending-transactions", true, s.pendingTransactionsHandler)
 	s.registerHttpHandler(router, "/metrics", true, s.dumpMetricsAsJSON)
 	s.registerHttpHandler(router, "/metrics.json", true, s.dumpMetricsAsJSON)
 	s.registerHttpHandler(router, "/metrics.prometheus", true, s.dumpMetricsAsPrometheus)
 	s.registerHttpHandler(router, "/robots.txt", false, s.robots)
 	s.registerHttpHandler(router, "/debug/logs/filter-on", false, s.filterOn)
 	s.registerHttpHandler(router, "/debug/logs/filter-off", false, s.filterOff)
+	s.registerHttpHandler(router, "/debug/gossip", true, s.dumpGossipTrafficAsJSON)
+	s.registerHttpHandler(router, "/debug/trace-transaction", true, s.traceTransactionHandler)
 
 	router.Handle("/", http.HandlerFunc(wrapHandlerWithCORS(s.Index)))
 
@@ -0,0 +1,206 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package httpserver
+
+import (
+	"encoding/binary"
+	"github.com/orbs-network/orbs-network-go/services/publicapi"
+	"github.com/orbs-network/orbs-spec/types/go/protocol/client"
+	"github.com/orbs-network/scribe/log"
+	"github.com/pkg/errors"
+	"net/http"
+)
+
+// The batch endpoints take a sequence of SendTransactionRequest membuffers and answer with a SendTransactionResponse per
+// request in the same order, each message prefixed with its size as a little endian uint32
+
+func (s *HttpServer) sendTransactionsHandler(w http.ResponseWriter, r *http.Request) {
+	s.handleSendTransactions(w, r, true)
+}
+
+func (s *HttpServer) sendTransactionsAsyncHandler(w http.ResponseWriter, r *http.Request) {
+	s.handleSendTransactions(w, r, false)
+}
+
+func (s *HttpServer) handleSendTransactions(w http.ResponseWriter, r *http.Request, waitForCommit bool) {
+	sender, ok := s.publicApi.(publicapi.BatchTransactionSender)
+	if !ok {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusNotImplemented, nil, "public api does not support batch transactions"})
+		return
+	}
+
+	bytes, e := readInput(r)
+	if e != nil {
+		s.writeErrorResponseAndLog(w, e)
+		return
+	}
+
+	messages, err := splitSizePrefixed(bytes)
+	if err != nil {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusBadRequest, log.Error(err), err.Error()})
+		return
+	}
+
+	requests := make([]*client.SendTransactionRequest, len(messages))
+	for i, message := range messages {
+		requests[i] = client.SendTransactionRequestReader(message)
+		if e := validate(requests[i]); e != nil {
+			s.writeErrorResponseAndLog(w, e)
+			return
+		}
+	}
+
+	s.logger.Info("http HttpServer received send-transactions", log.Int("num-transactions", len(requests)))
+	result, err := sender.SendTransactions(r.Context(), &publicapi.SendTransactionsInput{ClientRequests: requests, WaitForCommit: waitForCommit})
+	if result == nil {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusBadRequest, log.Error(err), err.Error()})
+		return
+	}
+
+	var body []byte
+	for _, response := range result.ClientResponses {
+		body = appendSizePrefixed(body, response.Raw())
+	}
+
+	w.Header().Set("Content-Type", "application/membuffers")
+	if err != nil {
+		w.Header().Set("X-ORBS-ERROR-DETAILS", err.Error())
+	}
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		s.logger.Info("error writing response", log.Error(err))
+	}
+}
+
+// cancel-transaction takes the SendTransactionRequest of a signed cancellation, optionally followed by the one of its
+// replacement, and answers with the response to the cancellation followed by the one to the replacement if it was added
+func (s *HttpServer) cancelTransactionHandler(w http.ResponseWriter, r *http.Request) {
+	canceller, ok := s.publicApi.(publicapi.TransactionCanceller)
+	if !ok {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusNotImplemented, nil, "public api does not support cancelling transactions"})
+		return
+	}
+
+	bytes, e := readInput(r)
+	if e != nil {
+		s.writeErrorResponseAndLog(w, e)
+		return
+	}
+
+	messages, err := splitSizePrefixed(bytes)
+	if err != nil {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusBadRequest, log.Error(err), err.Error()})
+		return
+	}
+	if len(messages) > 2 {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusBadRequest, log.Int("num-transactions", len(messages)), "expected a cancellation and an optional replacement"})
+		return
+	}
+
+	input := &publicapi.CancelTransactionInput{ClientRequest: client.SendTransactionRequestReader(messages[0])}
+	if len(messages) == 2 {
+		input.Replacement = client.SendTransactionRequestReader(messages[1])
+	}
+	for _, request := range []*client.SendTransactionRequest{input.ClientRequest, input.Replacement} {
+		if request == nil {
+			continue
+		}
+		if e := validate(request); e != nil {
+			s.writeErrorResponseAndLog(w, e)
+			return
+		}
+	}
+
+	s.logger.Info("http HttpServer received cancel-transaction", log.Int("num-transactions", len(messages)))
+	result, err := canceller.CancelTransaction(r.Context(), input)
+	if result == nil {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusBadRequest, log.Error(err), err.Error()})
+		return
+	}
+
+	body := appendSizePrefixed(nil, result.CancellationResponse.Raw())
+	if result.ReplacementResponse != nil {
+		body = appendSizePrefixed(body, result.ReplacementResponse.Raw())
+	}
+
+	w.Header().Set("Content-Type", "application/membuffers")
+	if err != nil {
+		w.Header().Set("X-ORBS-ERROR-DETAILS", err.Error())
+	}
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		s.logger.Info("error writing response", log.Error(err))
+	}
+}
+
+// simulate-transaction takes a single SendTransactionRequest, which does not have to be signed but is rejected if its
+// signature is invalid, and answers with the RunQueryResponse of its execution followed by the ContractStateDiff of
+// every contract it would have changed, it fails unless the node enables simulations in its config
+func (s *HttpServer) simulateTransactionHandler(w http.ResponseWriter, r *http.Request) {
+	simulator, ok := s.publicApi.(publicapi.TransactionSimulator)
+	if !ok {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusNotImplemented, nil, "public api does not support simulating transactions"})
+		return
+	}
+
+	bytes, e := readInput(r)
+	if e != nil {
+		s.writeErrorResponseAndLog(w, e)
+		return
+	}
+
+	clientRequest := client.SendTransactionRequestReader(bytes)
+	if e := validate(clientRequest); e != nil {
+		s.writeErrorResponseAndLog(w, e)
+		return
+	}
+
+	s.logger.Info("http HttpServer received simulate-transaction", log.Stringable("request", clientRequest))
+	result, err := simulator.SimulateTransaction(r.Context(), &publicapi.SimulateTransactionInput{ClientRequest: clientRequest})
+	if result == nil {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusBadRequest, log.Error(err), err.Error()})
+		return
+	}
+
+	body := appendSizePrefixed(nil, result.ClientResponse.Raw())
+	for _, stateDiff := range result.ContractStateDiffs {
+		body = appendSizePrefixed(body, stateDiff.Raw())
+	}
+
+	w.Header().Set("Content-Type", "application/membuffers")
+	if err != nil {
+		w.Header().Set("X-ORBS-ERROR-DETAILS", err.Error())
+	}
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		s.logger.Info("error writing response", log.Error(err))
+	}
+}
+
+func appendSizePrefixed(buf []byte, message []byte) []byte {
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(message)))
+	return append(append(buf, size[:]...), message...)
+}
+
+func splitSizePrefixed(buf []byte) (messages [][]byte, err error) {
+	for len(buf) > 0 {
+		if len(buf) < 4 {
+			return nil, errors.Errorf("truncated message size")
+		}
+		size := binary.LittleEndian.Uint32(buf)
+		if uint64(len(buf)-4) < uint64(size) {
+			return nil, errors.Errorf("message of size %d is truncated", size)
+		}
+		messages = append(messages, buf[4:4+size])
+		buf = buf[4+size:]
+	}
+	if len(messages) == 0 {
+		return nil, errors.Errorf("no transactions in batch")
+	}
+	return
+}
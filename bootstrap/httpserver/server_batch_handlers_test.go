@@ -0,0 +1,181 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package httpserver
+
+import (
+	"bytes"
+	"context"
+	"github.com/orbs-network/orbs-network-go/services/publicapi"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-network-go/test/with"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/protocol/client"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type batchPublicApiStub struct {
+	*services.MockPublicApi
+	input *publicapi.SendTransactionsInput
+}
+
+func (p *batchPublicApiStub) SendTransactions(ctx context.Context, input *publicapi.SendTransactionsInput) (*publicapi.SendTransactionsOutput, error) {
+	p.input = input
+	output := &publicapi.SendTransactionsOutput{}
+	for range input.ClientRequests {
+		output.ClientResponses = append(output.ClientResponses, (&client.SendTransactionResponseBuilder{
+			RequestResult:     aCompletedResult(),
+			TransactionStatus: protocol.TRANSACTION_STATUS_COMMITTED,
+		}).Build())
+	}
+	return output, nil
+}
+
+func sendTransactionsBody(count int) []byte {
+	var body []byte
+	for i := 0; i < count; i++ {
+		body = appendSizePrefixed(body, (&client.SendTransactionRequestBuilder{SignedTransaction: builders.TransferTransaction().Builder()}).Build().Raw())
+	}
+	return body
+}
+
+func TestHttpServer_SendTransactions(t *testing.T) {
+	with.Logging(t, func(parent *with.LoggingHarness) {
+		withServerHarness(parent, func(h *harness) {
+			req, _ := http.NewRequest("POST", "", bytes.NewReader(sendTransactionsBody(2)))
+			rec := httptest.NewRecorder()
+			h.server.sendTransactionsHandler(rec, req)
+
+			require.Equal(t, http.StatusNotImplemented, rec.Code, "should fail if the public api does not support batches")
+
+			stub := &batchPublicApiStub{MockPublicApi: h.publicApi}
+			h.server.RegisterPublicApi(stub)
+
+			req, _ = http.NewRequest("POST", "", bytes.NewReader(sendTransactionsBody(2)))
+			rec = httptest.NewRecorder()
+			h.server.sendTransactionsHandler(rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code, "should succeed")
+			require.True(t, stub.input.WaitForCommit)
+			require.Len(t, stub.input.ClientRequests, 2)
+
+			responses, err := splitSizePrefixed(rec.Body.Bytes())
+			require.NoError(t, err)
+			require.Len(t, responses, 2, "should respond per transaction")
+			require.Equal(t, protocol.TRANSACTION_STATUS_COMMITTED, client.SendTransactionResponseReader(responses[1]).TransactionStatus())
+
+			req, _ = http.NewRequest("POST", "", bytes.NewReader(sendTransactionsBody(1)[:10]))
+			rec = httptest.NewRecorder()
+			h.server.sendTransactionsAsyncHandler(rec, req)
+
+			require.Equal(t, http.StatusBadRequest, rec.Code, "should reject a truncated batch")
+		})
+	})
+}
+
+type cancellingPublicApiStub struct {
+	*services.MockPublicApi
+	input *publicapi.CancelTransactionInput
+}
+
+func (p *cancellingPublicApiStub) CancelTransaction(ctx context.Context, input *publicapi.CancelTransactionInput) (*publicapi.CancelTransactionOutput, error) {
+	p.input = input
+	output := &publicapi.CancelTransactionOutput{
+		CancellationResponse: (&client.SendTransactionResponseBuilder{
+			RequestResult:     aCompletedResult(),
+			TransactionStatus: protocol.TRANSACTION_STATUS_PRE_ORDER_VALID,
+		}).Build(),
+	}
+	if input.Replacement != nil {
+		output.ReplacementResponse = (&client.SendTransactionResponseBuilder{
+			RequestResult:     aCompletedResult(),
+			TransactionStatus: protocol.TRANSACTION_STATUS_PENDING,
+		}).Build()
+	}
+	return output, nil
+}
+
+func TestHttpServer_CancelTransaction(t *testing.T) {
+	with.Logging(t, func(parent *with.LoggingHarness) {
+		withServerHarness(parent, func(h *harness) {
+			req, _ := http.NewRequest("POST", "", bytes.NewReader(sendTransactionsBody(1)))
+			rec := httptest.NewRecorder()
+			h.server.cancelTransactionHandler(rec, req)
+
+			require.Equal(t, http.StatusNotImplemented, rec.Code, "should fail if the public api does not support cancellations")
+
+			stub := &cancellingPublicApiStub{MockPublicApi: h.publicApi}
+			h.server.RegisterPublicApi(stub)
+
+			req, _ = http.NewRequest("POST", "", bytes.NewReader(sendTransactionsBody(2)))
+			rec = httptest.NewRecorder()
+			h.server.cancelTransactionHandler(rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code, "should succeed")
+			require.NotNil(t, stub.input.Replacement, "the second request should be the replacement")
+
+			responses, err := splitSizePrefixed(rec.Body.Bytes())
+			require.NoError(t, err)
+			require.Len(t, responses, 2, "should respond to the cancellation and the replacement")
+			require.Equal(t, protocol.TRANSACTION_STATUS_PRE_ORDER_VALID, client.SendTransactionResponseReader(responses[0]).TransactionStatus())
+
+			req, _ = http.NewRequest("POST", "", bytes.NewReader(sendTransactionsBody(3)))
+			rec = httptest.NewRecorder()
+			h.server.cancelTransactionHandler(rec, req)
+
+			require.Equal(t, http.StatusBadRequest, rec.Code, "should reject more than one replacement")
+		})
+	})
+}
+
+type simulatingPublicApiStub struct {
+	*services.MockPublicApi
+}
+
+func (p *simulatingPublicApiStub) SimulateTransaction(ctx context.Context, input *publicapi.SimulateTransactionInput) (*publicapi.SimulateTransactionOutput, error) {
+	return &publicapi.SimulateTransactionOutput{
+		ClientResponse: (&client.RunQueryResponseBuilder{
+			RequestResult: aCompletedResult(),
+			QueryResult:   &protocol.QueryResultBuilder{ExecutionResult: protocol.EXECUTION_RESULT_SUCCESS},
+		}).Build(),
+		ContractStateDiffs: []*protocol.ContractStateDiff{
+			(&protocol.ContractStateDiffBuilder{ContractName: "Contract1"}).Build(),
+			(&protocol.ContractStateDiffBuilder{ContractName: "Contract2"}).Build(),
+		},
+	}, nil
+}
+
+func TestHttpServer_SimulateTransaction(t *testing.T) {
+	with.Logging(t, func(parent *with.LoggingHarness) {
+		withServerHarness(parent, func(h *harness) {
+			request := (&client.SendTransactionRequestBuilder{SignedTransaction: builders.TransferTransaction().Builder()}).Build()
+
+			req, _ := http.NewRequest("POST", "", bytes.NewReader(request.Raw()))
+			rec := httptest.NewRecorder()
+			h.server.simulateTransactionHandler(rec, req)
+
+			require.Equal(t, http.StatusNotImplemented, rec.Code, "should fail if the public api does not support simulations")
+
+			h.server.RegisterPublicApi(&simulatingPublicApiStub{MockPublicApi: h.publicApi})
+
+			req, _ = http.NewRequest("POST", "", bytes.NewReader(request.Raw()))
+			rec = httptest.NewRecorder()
+			h.server.simulateTransactionHandler(rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code, "should succeed")
+
+			messages, err := splitSizePrefixed(rec.Body.Bytes())
+			require.NoError(t, err)
+			require.Len(t, messages, 3, "should respond with the query response followed by the state diffs")
+			require.Equal(t, protocol.EXECUTION_RESULT_SUCCESS, client.RunQueryResponseReader(messages[0]).QueryResult().ExecutionResult())
+			require.EqualValues(t, "Contract2", protocol.ContractStateDiffReader(messages[2]).ContractName())
+		})
+	})
+}
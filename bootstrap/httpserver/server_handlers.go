@@ -9,12 +9,23 @@ package httpserver
 import (
 	"encoding/json"
 	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/services/gossip"
+	"github.com/orbs-network/orbs-network-go/services/publicapi"
+	"github.com/orbs-network/orbs-network-go/services/transactionpool"
 	"github.com/orbs-network/orbs-spec/types/go/protocol/client"
 	"github.com/orbs-network/orbs-spec/types/go/services"
 	"github.com/orbs-network/scribe/log"
+	"github.com/pkg/errors"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 )
 
+type GossipTrafficReporter interface {
+	TrafficReport() *gossip.TrafficReport
+}
+
 type IndexResponse struct {
 	Status      string
 	Description string
@@ -101,6 +112,95 @@ func (s *HttpServer) dumpMetricsAsPrometheus(w http.ResponseWriter, r *http.Requ
 	}
 }
 
+func (s *HttpServer) dumpGossipTrafficAsJSON(w http.ResponseWriter, r *http.Request) {
+	if s.gossipTraffic == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	bytes, _ := json.Marshal(s.gossipTraffic.TrafficReport())
+	_, err := w.Write(bytes)
+	if err != nil {
+		s.logger.Info("error writing response", log.Error(err))
+	}
+}
+
+// accepts the query parameters signer, contract, method, min-age, max-age (durations such as 90s), offset and limit
+func (s *HttpServer) pendingTransactionsHandler(w http.ResponseWriter, r *http.Request) {
+	inspector, ok := s.publicApi.(publicapi.PendingTransactionsInspector)
+	if !ok {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusNotImplemented, nil, "public api does not support listing pending transactions"})
+		return
+	}
+
+	query, err := parsePendingTransactionsQuery(r.URL.Query())
+	if err != nil {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusBadRequest, log.Error(err), err.Error()})
+		return
+	}
+
+	output, err := inspector.GetPendingTransactions(r.Context(), &publicapi.GetPendingTransactionsInput{Query: query})
+	if err != nil {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusInternalServerError, log.Error(err), err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	bytes, _ := json.Marshal(output.Report)
+	_, err = w.Write(bytes)
+	if err != nil {
+		s.logger.Info("error writing response", log.Error(err))
+	}
+}
+
+func parsePendingTransactionsQuery(values url.Values) (*transactionpool.PendingTransactionsQuery, error) {
+	query := &transactionpool.PendingTransactionsQuery{
+		Signer:       values.Get("signer"),
+		ContractName: values.Get("contract"),
+		MethodName:   values.Get("method"),
+	}
+
+	var err error
+	if query.MinAge, err = parseDurationParam(values, "min-age"); err != nil {
+		return nil, err
+	}
+	if query.MaxAge, err = parseDurationParam(values, "max-age"); err != nil {
+		return nil, err
+	}
+	if query.Offset, err = parseIntParam(values, "offset"); err != nil {
+		return nil, err
+	}
+	if query.Limit, err = parseIntParam(values, "limit"); err != nil {
+		return nil, err
+	}
+	return query, nil
+}
+
+func parseDurationParam(values url.Values, name string) (time.Duration, error) {
+	value := values.Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, errors.Errorf("invalid %s %q", name, value)
+	}
+	return duration, nil
+}
+
+func parseIntParam(values url.Values, name string) (int, error) {
+	value := values.Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, errors.Errorf("invalid %s %q", name, value)
+	}
+	return n, nil
+}
+
 func (s *HttpServer) sendTransactionHandler(w http.ResponseWriter, r *http.Request) {
 	bytes, e := readInput(r)
 	if e != nil {
@@ -8,9 +8,13 @@ package httpserver
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"github.com/orbs-network/go-mock"
 	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-network-go/services/gossip"
+	"github.com/orbs-network/orbs-network-go/services/publicapi"
+	"github.com/orbs-network/orbs-network-go/services/transactionpool"
 	"github.com/orbs-network/orbs-network-go/test/builders"
 	"github.com/orbs-network/orbs-network-go/test/with"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
@@ -231,6 +235,81 @@ func TestHttpServer_Robots(t *testing.T) {
 	})
 }
 
+type gossipTrafficStub struct {
+	report *gossip.TrafficReport
+}
+
+func (g *gossipTrafficStub) TrafficReport() *gossip.TrafficReport {
+	return g.report
+}
+
+func TestHttpServer_GossipTraffic(t *testing.T) {
+	with.Logging(t, func(parent *with.LoggingHarness) {
+		withServerHarness(parent, func(h *harness) {
+			req, _ := http.NewRequest("GET", "/debug/gossip", nil)
+			rec := httptest.NewRecorder()
+			h.server.dumpGossipTrafficAsJSON(rec, req)
+
+			require.Equal(t, http.StatusServiceUnavailable, rec.Code, "should return 503 until gossip is registered")
+
+			h.server.RegisterGossipTrafficReporter(&gossipTrafficStub{report: &gossip.TrafficReport{
+				Peers: []*gossip.PeerTraffic{{Peer: "10.0.0.1", Inbound: []*gossip.MessageTraffic{{Topic: "BlockSync", MessageType: "BLOCK_SYNC_REQUEST", Messages: 2, Bytes: 100}}}},
+			}})
+
+			rec = httptest.NewRecorder()
+			h.server.dumpGossipTrafficAsJSON(rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code, "should succeed")
+			require.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"), "should have our content type")
+			require.Contains(t, rec.Body.String(), `"peer":"10.0.0.1"`)
+			require.Contains(t, rec.Body.String(), `"messageType":"BLOCK_SYNC_REQUEST","messages":2,"bytes":100`)
+		})
+	})
+}
+
+type inspectingPublicApiStub struct {
+	*services.MockPublicApi
+	query *transactionpool.PendingTransactionsQuery
+}
+
+func (p *inspectingPublicApiStub) GetPendingTransactions(ctx context.Context, input *publicapi.GetPendingTransactionsInput) (*publicapi.GetPendingTransactionsOutput, error) {
+	p.query = input.Query
+	return &publicapi.GetPendingTransactionsOutput{Report: &transactionpool.PendingTransactionsReport{
+		Stats:        &transactionpool.PendingPoolStats{Count: 7, SizeInBytes: 1400, Signers: 2},
+		Matching:     1,
+		Transactions: []*transactionpool.PendingTransactionInfo{{TxHash: "abcd", ContractName: "BenchmarkToken", MethodName: "transfer"}},
+	}}, nil
+}
+
+func TestHttpServer_PendingTransactions(t *testing.T) {
+	with.Logging(t, func(parent *with.LoggingHarness) {
+		withServerHarness(parent, func(h *harness) {
+			req, _ := http.NewRequest("GET", "/api/v1/pending-transactions?contract=BenchmarkToken&method=transfer&min-age=30s&offset=10&limit=5", nil)
+			rec := httptest.NewRecorder()
+			h.server.pendingTransactionsHandler(rec, req)
+
+			require.Equal(t, http.StatusNotImplemented, rec.Code, "should fail when the public api cannot list pending transactions")
+
+			stub := &inspectingPublicApiStub{MockPublicApi: h.publicApi}
+			h.server.RegisterPublicApi(stub)
+
+			rec = httptest.NewRecorder()
+			h.server.pendingTransactionsHandler(rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code, "should succeed")
+			require.Equal(t, &transactionpool.PendingTransactionsQuery{ContractName: "BenchmarkToken", MethodName: "transfer", MinAge: 30 * time.Second, Offset: 10, Limit: 5}, stub.query)
+			require.Contains(t, rec.Body.String(), `"stats":{"count":7,"sizeInBytes":1400,"signers":2`)
+			require.Contains(t, rec.Body.String(), `"txHash":"abcd"`)
+
+			badReq, _ := http.NewRequest("GET", "/api/v1/pending-transactions?max-age=soon", nil)
+			rec = httptest.NewRecorder()
+			h.server.pendingTransactionsHandler(rec, badReq)
+
+			require.Equal(t, http.StatusBadRequest, rec.Code, "should reject an invalid age")
+		})
+	})
+}
+
 func TestHttpServer_PublicApiResponds503UntilRegistered(t *testing.T) {
 	with.Logging(t, func(parent *with.LoggingHarness) {
 		withUnregisteredPublicApiServerHarness(parent, func(h *harness) {
@@ -0,0 +1,110 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package httpserver
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"github.com/orbs-network/orbs-network-go/services/publicapi"
+	"github.com/orbs-network/orbs-network-go/services/virtualmachine"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol/client"
+	"github.com/orbs-network/scribe/log"
+	"github.com/pkg/errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// trace-transaction answers with the sdk calls of a transaction as JSON, a GET re-executes the committed transaction
+// given by the txhash and timestamp (unix nanoseconds) query parameters, a POST simulates the SendTransactionRequest in its body
+func (s *HttpServer) traceTransactionHandler(w http.ResponseWriter, r *http.Request) {
+	var trace *virtualmachine.ExecutionTrace
+	if r.Method == http.MethodPost {
+		trace = s.traceSimulatedTransaction(w, r)
+	} else {
+		trace = s.traceCommittedTransaction(w, r)
+	}
+	if trace == nil {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	bytes, _ := json.Marshal(trace)
+	if _, err := w.Write(bytes); err != nil {
+		s.logger.Info("error writing response", log.Error(err))
+	}
+}
+
+func (s *HttpServer) traceCommittedTransaction(w http.ResponseWriter, r *http.Request) *virtualmachine.ExecutionTrace {
+	tracer, ok := s.publicApi.(publicapi.TransactionTracer)
+	if !ok {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusNotImplemented, nil, "public api does not support tracing transactions"})
+		return nil
+	}
+
+	input, err := parseTraceTransactionQuery(r.URL.Query())
+	if err != nil {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusBadRequest, log.Error(err), err.Error()})
+		return nil
+	}
+
+	s.logger.Info("http HttpServer received trace-transaction", log.Stringable("txhash", input.Txhash))
+	output, err := tracer.TraceTransaction(r.Context(), input)
+	if err != nil {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusBadRequest, log.Error(err), err.Error()})
+		return nil
+	}
+	return output.Trace
+}
+
+func (s *HttpServer) traceSimulatedTransaction(w http.ResponseWriter, r *http.Request) *virtualmachine.ExecutionTrace {
+	simulator, ok := s.publicApi.(publicapi.TransactionSimulator)
+	if !ok {
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusNotImplemented, nil, "public api does not support simulating transactions"})
+		return nil
+	}
+
+	bytes, e := readInput(r)
+	if e != nil {
+		s.writeErrorResponseAndLog(w, e)
+		return nil
+	}
+
+	clientRequest := client.SendTransactionRequestReader(bytes)
+	if e := validate(clientRequest); e != nil {
+		s.writeErrorResponseAndLog(w, e)
+		return nil
+	}
+
+	s.logger.Info("http HttpServer received trace-transaction of a simulation", log.Stringable("request", clientRequest))
+	output, err := simulator.SimulateTransaction(r.Context(), &publicapi.SimulateTransactionInput{ClientRequest: clientRequest, Trace: true})
+	if output == nil || output.Trace == nil {
+		if err == nil {
+			err = errors.New("transaction was not executed")
+		}
+		s.writeErrorResponseAndLog(w, &httpErr{http.StatusBadRequest, log.Error(err), err.Error()})
+		return nil
+	}
+	return output.Trace
+}
+
+func parseTraceTransactionQuery(values url.Values) (*publicapi.TraceTransactionInput, error) {
+	txHash, err := hex.DecodeString(strings.TrimPrefix(values.Get("txhash"), "0x"))
+	if err != nil || len(txHash) == 0 {
+		return nil, errors.Errorf("invalid txhash %q", values.Get("txhash"))
+	}
+	timestamp, err := strconv.ParseUint(values.Get("timestamp"), 10, 64)
+	if err != nil {
+		return nil, errors.Errorf("invalid timestamp %q", values.Get("timestamp"))
+	}
+	return &publicapi.TraceTransactionInput{
+		Txhash:               primitives.Sha256(txHash),
+		TransactionTimestamp: primitives.TimestampNano(timestamp),
+	}, nil
+}
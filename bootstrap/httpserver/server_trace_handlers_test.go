@@ -0,0 +1,69 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/orbs-network/orbs-network-go/services/publicapi"
+	"github.com/orbs-network/orbs-network-go/services/virtualmachine"
+	"github.com/orbs-network/orbs-network-go/test/with"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type tracingPublicApiStub struct {
+	*services.MockPublicApi
+	input *publicapi.TraceTransactionInput
+}
+
+func (p *tracingPublicApiStub) TraceTransaction(ctx context.Context, input *publicapi.TraceTransactionInput) (*publicapi.TraceTransactionOutput, error) {
+	p.input = input
+	return &publicapi.TraceTransactionOutput{
+		BlockHeight: 3,
+		Trace: &virtualmachine.ExecutionTrace{
+			Contract: "Contract1",
+			Method:   "method1",
+			Calls:    []*virtualmachine.SdkCallTrace{{Operation: "Sdk.State", Method: "read"}},
+		},
+	}, nil
+}
+
+func TestHttpServer_TraceCommittedTransaction(t *testing.T) {
+	with.Logging(t, func(parent *with.LoggingHarness) {
+		withServerHarness(parent, func(h *harness) {
+			req, _ := http.NewRequest("GET", "/debug/trace-transaction?txhash=0x0102&timestamp=17", nil)
+			rec := httptest.NewRecorder()
+			h.server.traceTransactionHandler(rec, req)
+
+			require.Equal(t, http.StatusNotImplemented, rec.Code, "should fail if the public api does not support tracing")
+
+			stub := &tracingPublicApiStub{MockPublicApi: h.publicApi}
+			h.server.RegisterPublicApi(stub)
+
+			rec = httptest.NewRecorder()
+			h.server.traceTransactionHandler(rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code, "should succeed")
+			require.EqualValues(t, []byte{0x01, 0x02}, stub.input.Txhash)
+			require.EqualValues(t, 17, stub.input.TransactionTimestamp)
+
+			trace := &virtualmachine.ExecutionTrace{}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), trace))
+			require.Equal(t, "read", trace.Calls[0].Method)
+
+			req, _ = http.NewRequest("GET", "/debug/trace-transaction?txhash=0x0102", nil)
+			rec = httptest.NewRecorder()
+			h.server.traceTransactionHandler(rec, req)
+
+			require.Equal(t, http.StatusBadRequest, rec.Code, "should require the transaction timestamp")
+		})
+	})
+}
@@ -146,6 +146,8 @@ func (n *Network) CreateAndStartNodes(ctx context.Context, numOfNodesToStart int
 			node.stateBlockHeightReporter,
 			node.transactionPoolBlockTracker,
 			n.MaybeClock,
+			nil,
+			nil,
 			node.nativeCompiler,
 			node.managementProvider,
 			nodeLogger,
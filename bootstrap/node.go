@@ -22,6 +22,7 @@ import (
 	nativeProcessorAdapter "github.com/orbs-network/orbs-network-go/services/processor/native/adapter"
 	stateStorageAdapter "github.com/orbs-network/orbs-network-go/services/statestorage/adapter/memory"
 	txPoolAdapter "github.com/orbs-network/orbs-network-go/services/transactionpool/adapter"
+	txPoolFilesystem "github.com/orbs-network/orbs-network-go/services/transactionpool/adapter/filesystem"
 	"github.com/orbs-network/orbs-network-go/synchronization/supervised"
 	"github.com/orbs-network/scribe/log"
 )
@@ -34,6 +35,8 @@ type Node struct {
 	transport        *tcp.DirectTransport
 	logger           log.Logger
 	blockPersistence *filesystem.BlockPersistence
+	pendingJournal   *txPoolFilesystem.PendingTransactionJournal
+	committedIndex   *txPoolFilesystem.CommittedTransactionIndex
 }
 
 func getMetricRegistry(nodeConfig config.NodeConfig) metric.Registry {
@@ -72,14 +75,35 @@ func NewNode(nodeConfig config.NodeConfig, logger log.Logger) *Node {
 		panic(fmt.Sprintf("failed initializing blocks database, err=%s", err.Error()))
 	}
 
+	var pendingJournal txPoolAdapter.PendingTransactionJournal
+	var pendingJournalFile *txPoolFilesystem.PendingTransactionJournal
+	if nodeConfig.TransactionPoolPendingJournalEnabled() {
+		pendingJournalFile, err = txPoolFilesystem.NewPendingTransactionJournal(nodeConfig, nodeLogger)
+		if err != nil {
+			panic(fmt.Sprintf("failed initializing pending transactions journal, err=%s", err.Error()))
+		}
+		pendingJournal = pendingJournalFile
+	}
+
+	var committedIndex txPoolAdapter.CommittedTransactionIndex
+	var committedIndexFiles *txPoolFilesystem.CommittedTransactionIndex
+	if nodeConfig.TransactionPoolCommittedIndexEnabled() {
+		committedIndexFiles, err = txPoolFilesystem.NewCommittedTransactionIndex(nodeConfig, nodeLogger, metricRegistry)
+		if err != nil {
+			panic(fmt.Sprintf("failed initializing committed transactions index, err=%s", err.Error()))
+		}
+		committedIndex = committedIndexFiles
+	}
+
 	statePersistence := stateStorageAdapter.NewStatePersistence(metricRegistry)
 	ethereumConnection := ethereumAdapter.NewEthereumRpcConnection(nodeConfig, logger, metricRegistry)
 	nativeCompiler := nativeProcessorAdapter.NewNativeCompiler(nodeConfig, nodeLogger, metricRegistry)
 	nodeLogic := NewNodeLogic(ctx,
-		transport, blockPersistence, statePersistence, nil, nil, txPoolAdapter.NewSystemClock(), nativeCompiler, managementProvider,
+		transport, blockPersistence, statePersistence, nil, nil, txPoolAdapter.NewSystemClock(), pendingJournal, committedIndex, nativeCompiler, managementProvider,
 		nodeLogger, metricRegistry, nodeConfig, ethereumConnection)
 
 	httpServer.RegisterPublicApi(nodeLogic.PublicApi())
+	httpServer.RegisterGossipTrafficReporter(nodeLogic.GossipTraffic())
 
 	n := &Node{
 		logger:           nodeLogger,
@@ -88,6 +112,8 @@ func NewNode(nodeConfig config.NodeConfig, logger log.Logger) *Node {
 		transport:        transport,
 		httpServer:       httpServer,
 		blockPersistence: blockPersistence,
+		pendingJournal:   pendingJournalFile,
+		committedIndex:   committedIndexFiles,
 	}
 
 	ethereumConnection.ReportConnectionStatus(ctx)
@@ -103,4 +129,10 @@ func (n *Node) GracefulShutdown(shutdownContext context.Context) {
 	n.logger.Info("Shutting down")
 	n.cancelFunc()
 	supervised.ShutdownAllGracefully(shutdownContext, n.httpServer, n.transport, n.blockPersistence)
+	if n.pendingJournal != nil {
+		n.pendingJournal.GracefulShutdown(shutdownContext)
+	}
+	if n.committedIndex != nil {
+		n.committedIndex.GracefulShutdown(shutdownContext)
+	}
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/orbs-network/govnr"
 	"github.com/orbs-network/orbs-network-go/config"
 	"github.com/orbs-network/orbs-network-go/crypto/signer"
+	"github.com/orbs-network/orbs-network-go/crypto/validators"
 	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
 	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
 	"github.com/orbs-network/orbs-network-go/services/blockstorage"
@@ -43,11 +44,13 @@ import (
 type NodeLogic interface {
 	govnr.ShutdownWaiter
 	PublicApi() services.PublicApi
+	GossipTraffic() *gossip.Service
 }
 
 type nodeLogic struct {
 	govnr.TreeSupervisor
 	publicApi      services.PublicApi
+	gossip         *gossip.Service
 	consensusAlgos []services.ConsensusAlgo
 }
 
@@ -57,7 +60,10 @@ func NewNodeLogic(parentCtx context.Context,
 	statePersistence stateStorageAdapter.StatePersistence,
 	stateBlockHeightReporter stateStorageAdapter.BlockHeightReporter,
 	transactionPoolBlockHeightReporter transactionpool.BlockHeightReporter,
-	maybeClock txPoolAdapter.Clock, nativeCompiler nativeProcessorAdapter.Compiler,
+	maybeClock txPoolAdapter.Clock,
+	pendingJournal txPoolAdapter.PendingTransactionJournal,
+	committedIndex txPoolAdapter.CommittedTransactionIndex,
+	nativeCompiler nativeProcessorAdapter.Compiler,
 	managementProvider management.Provider,
 	logger log.Logger, 	metricRegistry metric.Registry, nodeConfig config.NodeConfig,
 	ethereumConnection ethereumAdapter.EthereumConnection, ) NodeLogic {
@@ -82,18 +88,29 @@ func NewNodeLogic(parentCtx context.Context,
 		logger.Error("Node logic signer error cannot start" , log.Error(err))
 		panic(fmt.Sprintf("Node logic signer error cannot start: %s", err))
 	}
+	if signingTransport, ok := gossipTransport.(signingGossipTransport); ok {
+		signingTransport.SetSigner(signer)
+	}
 
 	gossipService := gossip.NewGossip(ctx, gossipTransport, nodeConfig, logger, metricRegistry)
 	management := management.NewManagement(ctx, nodeConfig, managementProvider, gossipService, logger)
 	stateStorageService := statestorage.NewStateStorage(nodeConfig, statePersistence, stateBlockHeightReporter, logger, metricRegistry)
-	virtualMachineService := virtualmachine.NewVirtualMachine(stateStorageService, processors, crosschainConnectors, management, logger)
-	transactionPoolService := transactionpool.NewTransactionPool(ctx, maybeClock, gossipService, virtualMachineService, signer, transactionPoolBlockHeightReporter, nodeConfig, logger, metricRegistry)
+	virtualMachineService := virtualmachine.NewVirtualMachine(stateStorageService, processors, crosschainConnectors, management, nodeConfig, logger)
+	signatureVerifier := validators.NewTransactionSignatureVerifier(nodeConfig, logger, metricRegistry)
+	if verifierUser, ok := virtualMachineService.(virtualmachine.TransactionSignatureVerifierUser); ok {
+		verifierUser.SetTransactionSignatureVerifier(signatureVerifier)
+	}
+	transactionPoolService := transactionpool.NewTransactionPool(ctx, maybeClock, gossipService, virtualMachineService, signer, transactionPoolBlockHeightReporter, pendingJournal, committedIndex, nodeConfig, logger, metricRegistry)
+	transactionPoolService.SetTransactionSignatureVerifier(signatureVerifier)
 	serviceSyncCommitters := []servicesync.BlockPairCommitter{servicesync.NewStateStorageCommitter(stateStorageService), servicesync.NewTxPoolCommitter(transactionPoolService)}
 	blockStorageService := blockstorage.NewBlockStorage(ctx, nodeConfig, blockPersistence, gossipService, logger, metricRegistry, serviceSyncCommitters)
 	publicApiService := publicapi.NewPublicApi(nodeConfig, transactionPoolService, virtualMachineService, blockStorageService, logger, metricRegistry)
 	consensusContextService := consensuscontext.NewConsensusContext(transactionPoolService, virtualMachineService, stateStorageService, nodeConfig, logger, metricRegistry)
 
 	consensusAlgo := createConsensusAlgo(nodeConfig)(ctx, gossipService, blockStorageService, consensusContextService, signer, logger, metricRegistry)
+	if leaderProvider, ok := consensusAlgo.(transactionpool.LeaderProvider); ok {
+		transactionPoolService.SetLeaderProvider(leaderProvider)
+	}
 
 	metric.RegisterConfigIndicators(metricRegistry, nodeConfig)
 
@@ -101,6 +118,7 @@ func NewNodeLogic(parentCtx context.Context,
 
 	node := &nodeLogic{
 		publicApi:      publicApiService,
+		gossip:         gossipService,
 		consensusAlgos: []services.ConsensusAlgo{consensusAlgo},
 	}
 
@@ -118,6 +136,12 @@ func NewNodeLogic(parentCtx context.Context,
 	return node
 }
 
+// implemented by transports which authenticate their connections with the node key, which must be set before the
+// transport gets the topology
+type signingGossipTransport interface {
+	SetSigner(nodeSigner signer.Signer)
+}
+
 type consensusAlgo interface {
 	services.ConsensusAlgo
 	govnr.ShutdownWaiter
@@ -146,3 +170,7 @@ func createConsensusAlgo(nodeConfig config.NodeConfig) func(ctx context.Context,
 func (n *nodeLogic) PublicApi() services.PublicApi {
 	return n.publicApi
 }
+
+func (n *nodeLogic) GossipTraffic() *gossip.Service {
+	return n.gossip
+}
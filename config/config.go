@@ -74,6 +74,29 @@ type NodeConfig interface {
 	TransactionPoolPropagationBatchingTimeout() time.Duration
 	TransactionPoolTimeBetweenEmptyBlocks() time.Duration
 	TransactionPoolNodeSyncRejectTime() time.Duration
+	TransactionPoolPendingJournalEnabled() bool
+	TransactionPoolPriorityOrderingEnabled() bool
+	TransactionPoolPrioritySystemContracts() string // comma separated contract names
+	TransactionPoolPriorityHighContracts() string   // comma separated contract names
+	TransactionPoolPriorityHighSigners() string     // comma separated hex client addresses
+	TransactionPoolPriorityMinSharePercent() uint32
+	TransactionPoolSignerQuotasEnabled() bool
+	TransactionPoolMaxPendingTransactionsPerSigner() uint32
+	TransactionPoolMaxPendingBytesPerSigner() uint32
+	TransactionSignerSequencingEnabled() bool
+	TransactionPoolAdmissionAllowedContracts() string // comma separated contract names, empty allows all
+	TransactionPoolAdmissionDeniedContracts() string  // comma separated contract names
+	TransactionPoolAdmissionAllowedSigners() string   // comma separated hex client addresses, empty allows all
+	TransactionPoolAdmissionDeniedSigners() string    // comma separated hex client addresses
+	TransactionPoolAdmissionMaxArgumentsSize() uint32
+	TransactionPoolAdmissionPrecheckContracts() string // comma separated contract names
+	TransactionPoolAdmissionPrecheckMethod() string
+	TransactionPoolLeaderAwareForwardingEnabled() bool
+	TransactionPoolForwardingUpcomingLeaders() uint32
+	TransactionPoolForwardingFallbackFanout() uint32
+	TransactionPoolCommittedIndexEnabled() bool
+	TransactionPoolCommittedIndexBloomSizeInBytes() uint32
+	TransactionPoolProvisionalSignerSchemesEnabled() bool
 
 	// gossip
 	GossipListenPort() uint16
@@ -81,15 +104,40 @@ type NodeConfig interface {
 	GossipConnectionKeepAliveInterval() time.Duration
 	GossipNetworkTimeout() time.Duration
 	GossipReconnectInterval() time.Duration
+	GossipInboundRateLimitEnabled() bool
+	GossipInboundRateLimitMessagesPerSecond() uint32
+	GossipInboundRateLimitBurst() uint32
+	GossipInboundConsensusRateLimitMessagesPerSecond() uint32
+	GossipInboundConsensusRateLimitBurst() uint32
+	GossipInboundPeerBanDuration() time.Duration
+	GossipRelayBroadcastEnabled() bool
+	GossipRelayBroadcastFanout() uint32
+	GossipDuplicateSuppressionWindow() time.Duration
+	GossipDuplicateSuppressionMaxEntries() uint32
+	GossipDuplicateSuppressionExemptions() string
+	GossipBidirectionalConnectionsEnabled() bool
 
 	// public api
 	PublicApiSendTransactionTimeout() time.Duration
 	PublicApiNodeSyncWarningTime() time.Duration
+	PublicApiMaxTransactionsPerBatch() uint32
+	PublicApiSimulateTransactionEnabled() bool
 
 	// processor
 	ProcessorArtifactPath() string
 	ProcessorSanitizeDeployedContracts() bool
 	ProcessorPerformWarmUpCompilation() bool
+	ProcessorNativeExecutionStepLimit() uint32
+
+	// virtual machine
+	VirtualMachineGasMeteringEnabled() bool
+	VirtualMachineGasLimitPerTransaction() uint32
+	VirtualMachineGasLimitPerBlock() uint32
+	VirtualMachineParallelExecutionWorkers() uint32
+
+	// transaction signatures
+	TransactionSignatureVerificationWorkers() uint32
+	TransactionSignatureCacheSize() uint32
 
 	// ethereum connector (crosschain)
 	EthereumEndpoint() string
@@ -158,6 +206,29 @@ type FilesystemBlockPersistenceConfig interface {
 	NetworkType() protocol.SignerNetworkType
 }
 
+type VirtualMachineConfig interface {
+	TransactionSignerSequencingEnabled() bool
+	VirtualMachineGasMeteringEnabled() bool
+	VirtualMachineGasLimitPerTransaction() uint32
+	VirtualMachineGasLimitPerBlock() uint32
+	VirtualMachineParallelExecutionWorkers() uint32
+	StateStorageHistorySnapshotNum() uint32
+}
+
+type TransactionSignatureVerifierConfig interface {
+	TransactionSignatureVerificationWorkers() uint32
+	TransactionSignatureCacheSize() uint32
+}
+
+type FilesystemPendingJournalConfig interface {
+	BlockStorageFileSystemDataDir() string
+}
+
+type FilesystemCommittedIndexConfig interface {
+	BlockStorageFileSystemDataDir() string
+	TransactionPoolCommittedIndexBloomSizeInBytes() uint32
+}
+
 type GossipTransportConfig interface {
 	NodeAddress() primitives.NodeAddress
 	GossipPeers() topologyProviderAdapter.GossipPeers
@@ -165,6 +236,13 @@ type GossipTransportConfig interface {
 	GossipConnectionKeepAliveInterval() time.Duration
 	GossipNetworkTimeout() time.Duration
 	GossipReconnectInterval() time.Duration
+	GossipInboundRateLimitEnabled() bool
+	GossipInboundRateLimitMessagesPerSecond() uint32
+	GossipInboundRateLimitBurst() uint32
+	GossipInboundConsensusRateLimitMessagesPerSecond() uint32
+	GossipInboundConsensusRateLimitBurst() uint32
+	GossipInboundPeerBanDuration() time.Duration
+	GossipBidirectionalConnectionsEnabled() bool
 }
 
 // Config based on https://github.com/orbs-network/orbs-spec/blob/master/behaviors/config/services.md#consensus-context
@@ -184,6 +262,8 @@ type CommitteeProviderConfig interface {
 type PublicApiConfig interface {
 	PublicApiSendTransactionTimeout() time.Duration
 	PublicApiNodeSyncWarningTime() time.Duration
+	PublicApiMaxTransactionsPerBatch() uint32
+	PublicApiSimulateTransactionEnabled() bool
 	VirtualChainId() primitives.VirtualChainId
 }
 
@@ -207,6 +287,26 @@ type TransactionPoolConfig interface {
 	TransactionPoolPropagationBatchingTimeout() time.Duration
 	TransactionPoolTimeBetweenEmptyBlocks() time.Duration
 	TransactionPoolNodeSyncRejectTime() time.Duration
+	TransactionPoolPriorityOrderingEnabled() bool
+	TransactionPoolPrioritySystemContracts() string // comma separated contract names
+	TransactionPoolPriorityHighContracts() string   // comma separated contract names
+	TransactionPoolPriorityHighSigners() string     // comma separated hex client addresses
+	TransactionPoolPriorityMinSharePercent() uint32
+	TransactionPoolSignerQuotasEnabled() bool
+	TransactionPoolMaxPendingTransactionsPerSigner() uint32
+	TransactionPoolMaxPendingBytesPerSigner() uint32
+	TransactionSignerSequencingEnabled() bool
+	TransactionPoolAdmissionAllowedContracts() string // comma separated contract names, empty allows all
+	TransactionPoolAdmissionDeniedContracts() string  // comma separated contract names
+	TransactionPoolAdmissionAllowedSigners() string   // comma separated hex client addresses, empty allows all
+	TransactionPoolAdmissionDeniedSigners() string    // comma separated hex client addresses
+	TransactionPoolAdmissionMaxArgumentsSize() uint32
+	TransactionPoolAdmissionPrecheckContracts() string // comma separated contract names
+	TransactionPoolAdmissionPrecheckMethod() string
+	TransactionPoolLeaderAwareForwardingEnabled() bool
+	TransactionPoolForwardingUpcomingLeaders() uint32
+	TransactionPoolForwardingFallbackFanout() uint32
+	TransactionPoolProvisionalSignerSchemesEnabled() bool
 }
 
 type TransactionPoolConfigForTests interface {
@@ -221,6 +321,7 @@ type EthereumCrosschainConnectorConfig interface {
 
 type NativeProcessorConfig interface {
 	ProcessorSanitizeDeployedContracts() bool
+	ProcessorNativeExecutionStepLimit() uint32
 	VirtualChainId() primitives.VirtualChainId
 }
 
@@ -78,18 +78,68 @@ const (
 	TRANSACTION_POOL_PROPAGATION_BATCHING_TIMEOUT          = "TRANSACTION_POOL_PROPAGATION_BATCHING_TIMEOUT"
 	TRANSACTION_POOL_TIME_BETWEEN_EMPTY_BLOCKS             = "TRANSACTION_POOL_TIME_BETWEEN_EMPTY_BLOCKS"
 	TRANSACTION_POOL_NODE_SYNC_REJECT_TIME                 = "TRANSACTION_POOL_NODE_SYNC_REJECT_TIME"
+	TRANSACTION_POOL_PENDING_JOURNAL_ENABLED               = "TRANSACTION_POOL_PENDING_JOURNAL_ENABLED"
+	TRANSACTION_POOL_PRIORITY_ORDERING_ENABLED             = "TRANSACTION_POOL_PRIORITY_ORDERING_ENABLED"
+	TRANSACTION_POOL_PRIORITY_SYSTEM_CONTRACTS             = "TRANSACTION_POOL_PRIORITY_SYSTEM_CONTRACTS"
+	TRANSACTION_POOL_PRIORITY_HIGH_CONTRACTS               = "TRANSACTION_POOL_PRIORITY_HIGH_CONTRACTS"
+	TRANSACTION_POOL_PRIORITY_HIGH_SIGNERS                 = "TRANSACTION_POOL_PRIORITY_HIGH_SIGNERS"
+	TRANSACTION_POOL_PRIORITY_MIN_SHARE_PERCENT            = "TRANSACTION_POOL_PRIORITY_MIN_SHARE_PERCENT"
+	TRANSACTION_POOL_SIGNER_QUOTAS_ENABLED                 = "TRANSACTION_POOL_SIGNER_QUOTAS_ENABLED"
+	TRANSACTION_POOL_MAX_PENDING_TRANSACTIONS_PER_SIGNER   = "TRANSACTION_POOL_MAX_PENDING_TRANSACTIONS_PER_SIGNER"
+	TRANSACTION_POOL_MAX_PENDING_BYTES_PER_SIGNER          = "TRANSACTION_POOL_MAX_PENDING_BYTES_PER_SIGNER"
+	TRANSACTION_SIGNER_SEQUENCING_ENABLED                  = "TRANSACTION_SIGNER_SEQUENCING_ENABLED"
+	TRANSACTION_POOL_ADMISSION_ALLOWED_CONTRACTS           = "TRANSACTION_POOL_ADMISSION_ALLOWED_CONTRACTS"
+	TRANSACTION_POOL_ADMISSION_DENIED_CONTRACTS            = "TRANSACTION_POOL_ADMISSION_DENIED_CONTRACTS"
+	TRANSACTION_POOL_ADMISSION_ALLOWED_SIGNERS             = "TRANSACTION_POOL_ADMISSION_ALLOWED_SIGNERS"
+	TRANSACTION_POOL_ADMISSION_DENIED_SIGNERS              = "TRANSACTION_POOL_ADMISSION_DENIED_SIGNERS"
+	TRANSACTION_POOL_ADMISSION_MAX_ARGUMENTS_SIZE          = "TRANSACTION_POOL_ADMISSION_MAX_ARGUMENTS_SIZE"
+	TRANSACTION_POOL_ADMISSION_PRECHECK_CONTRACTS          = "TRANSACTION_POOL_ADMISSION_PRECHECK_CONTRACTS"
+	TRANSACTION_POOL_ADMISSION_PRECHECK_METHOD             = "TRANSACTION_POOL_ADMISSION_PRECHECK_METHOD"
+	TRANSACTION_POOL_LEADER_AWARE_FORWARDING_ENABLED       = "TRANSACTION_POOL_LEADER_AWARE_FORWARDING_ENABLED"
+	TRANSACTION_POOL_FORWARDING_UPCOMING_LEADERS           = "TRANSACTION_POOL_FORWARDING_UPCOMING_LEADERS"
+	TRANSACTION_POOL_FORWARDING_FALLBACK_FANOUT            = "TRANSACTION_POOL_FORWARDING_FALLBACK_FANOUT"
+	TRANSACTION_POOL_COMMITTED_INDEX_ENABLED               = "TRANSACTION_POOL_COMMITTED_INDEX_ENABLED"
+	TRANSACTION_POOL_COMMITTED_INDEX_BLOOM_SIZE_IN_BYTES   = "TRANSACTION_POOL_COMMITTED_INDEX_BLOOM_SIZE_IN_BYTES"
+	TRANSACTION_POOL_PROVISIONAL_SIGNER_SCHEMES_ENABLED    = "TRANSACTION_POOL_PROVISIONAL_SIGNER_SCHEMES_ENABLED"
 
 	GOSSIP_LISTEN_PORT                    = "GOSSIP_LISTEN_PORT"
 	GOSSIP_CONNECTION_KEEP_ALIVE_INTERVAL = "GOSSIP_CONNECTION_KEEP_ALIVE_INTERVAL"
 	GOSSIP_NETWORK_TIMEOUT                = "GOSSIP_NETWORK_TIMEOUT"
 	GOSSIP_RECONNECT_INTERVAL             = "GOSSIP_RECONNECT_INTERVAL"
 
-	PUBLIC_API_SEND_TRANSACTION_TIMEOUT = "PUBLIC_API_SEND_TRANSACTION_TIMEOUT"
-	PUBLIC_API_NODE_SYNC_WARNING_TIME   = "PUBLIC_API_NODE_SYNC_WARNING_TIME"
+	GOSSIP_INBOUND_RATE_LIMIT_ENABLED                       = "GOSSIP_INBOUND_RATE_LIMIT_ENABLED"
+	GOSSIP_INBOUND_RATE_LIMIT_MESSAGES_PER_SECOND           = "GOSSIP_INBOUND_RATE_LIMIT_MESSAGES_PER_SECOND"
+	GOSSIP_INBOUND_RATE_LIMIT_BURST                         = "GOSSIP_INBOUND_RATE_LIMIT_BURST"
+	GOSSIP_INBOUND_CONSENSUS_RATE_LIMIT_MESSAGES_PER_SECOND = "GOSSIP_INBOUND_CONSENSUS_RATE_LIMIT_MESSAGES_PER_SECOND"
+	GOSSIP_INBOUND_CONSENSUS_RATE_LIMIT_BURST               = "GOSSIP_INBOUND_CONSENSUS_RATE_LIMIT_BURST"
+	GOSSIP_INBOUND_PEER_BAN_DURATION                        = "GOSSIP_INBOUND_PEER_BAN_DURATION"
+
+	GOSSIP_RELAY_BROADCAST_ENABLED = "GOSSIP_RELAY_BROADCAST_ENABLED"
+	GOSSIP_RELAY_BROADCAST_FANOUT  = "GOSSIP_RELAY_BROADCAST_FANOUT"
+
+	GOSSIP_DUPLICATE_SUPPRESSION_WINDOW      = "GOSSIP_DUPLICATE_SUPPRESSION_WINDOW"
+	GOSSIP_DUPLICATE_SUPPRESSION_MAX_ENTRIES = "GOSSIP_DUPLICATE_SUPPRESSION_MAX_ENTRIES"
+	GOSSIP_DUPLICATE_SUPPRESSION_EXEMPTIONS  = "GOSSIP_DUPLICATE_SUPPRESSION_EXEMPTIONS"
+
+	GOSSIP_BIDIRECTIONAL_CONNECTIONS_ENABLED = "GOSSIP_BIDIRECTIONAL_CONNECTIONS_ENABLED"
+
+	PUBLIC_API_SEND_TRANSACTION_TIMEOUT     = "PUBLIC_API_SEND_TRANSACTION_TIMEOUT"
+	PUBLIC_API_NODE_SYNC_WARNING_TIME       = "PUBLIC_API_NODE_SYNC_WARNING_TIME"
+	PUBLIC_API_MAX_TRANSACTIONS_PER_BATCH   = "PUBLIC_API_MAX_TRANSACTIONS_PER_BATCH"
+	PUBLIC_API_SIMULATE_TRANSACTION_ENABLED = "PUBLIC_API_SIMULATE_TRANSACTION_ENABLED"
 
 	PROCESSOR_ARTIFACT_PATH               = "PROCESSOR_ARTIFACT_PATH"
 	PROCESSOR_SANITIZE_DEPLOYED_CONTRACTS = "PROCESSOR_SANITIZE_DEPLOYED_CONTRACTS"
 	PROCESSOR_PERFORM_WARM_UP_COMPILATION = "PROCESSOR_PERFORM_WARM_UP_COMPILATION"
+	PROCESSOR_NATIVE_EXECUTION_STEP_LIMIT = "PROCESSOR_NATIVE_EXECUTION_STEP_LIMIT"
+
+	VIRTUAL_MACHINE_GAS_METERING_ENABLED       = "VIRTUAL_MACHINE_GAS_METERING_ENABLED"
+	VIRTUAL_MACHINE_GAS_LIMIT_PER_TRANSACTION  = "VIRTUAL_MACHINE_GAS_LIMIT_PER_TRANSACTION"
+	VIRTUAL_MACHINE_GAS_LIMIT_PER_BLOCK        = "VIRTUAL_MACHINE_GAS_LIMIT_PER_BLOCK"
+	VIRTUAL_MACHINE_PARALLEL_EXECUTION_WORKERS = "VIRTUAL_MACHINE_PARALLEL_EXECUTION_WORKERS"
+
+	TRANSACTION_SIGNATURE_VERIFICATION_WORKERS = "TRANSACTION_SIGNATURE_VERIFICATION_WORKERS"
+	TRANSACTION_SIGNATURE_CACHE_SIZE           = "TRANSACTION_SIGNATURE_CACHE_SIZE"
 
 	ETHEREUM_ENDPOINT                  = "ETHEREUM_ENDPOINT"
 	ETHEREUM_FINALITY_TIME_COMPONENT   = "ETHEREUM_FINALITY_TIME_COMPONENT"
@@ -311,6 +361,98 @@ func (c *config) TransactionPoolNodeSyncRejectTime() time.Duration {
 	return c.kv[TRANSACTION_POOL_NODE_SYNC_REJECT_TIME].DurationValue
 }
 
+func (c *config) TransactionPoolPendingJournalEnabled() bool {
+	return c.kv[TRANSACTION_POOL_PENDING_JOURNAL_ENABLED].BoolValue
+}
+
+func (c *config) TransactionPoolPriorityOrderingEnabled() bool {
+	return c.kv[TRANSACTION_POOL_PRIORITY_ORDERING_ENABLED].BoolValue
+}
+
+func (c *config) TransactionPoolPrioritySystemContracts() string {
+	return c.kv[TRANSACTION_POOL_PRIORITY_SYSTEM_CONTRACTS].StringValue
+}
+
+func (c *config) TransactionPoolPriorityHighContracts() string {
+	return c.kv[TRANSACTION_POOL_PRIORITY_HIGH_CONTRACTS].StringValue
+}
+
+func (c *config) TransactionPoolPriorityHighSigners() string {
+	return c.kv[TRANSACTION_POOL_PRIORITY_HIGH_SIGNERS].StringValue
+}
+
+func (c *config) TransactionPoolPriorityMinSharePercent() uint32 {
+	return c.kv[TRANSACTION_POOL_PRIORITY_MIN_SHARE_PERCENT].Uint32Value
+}
+
+func (c *config) TransactionPoolSignerQuotasEnabled() bool {
+	return c.kv[TRANSACTION_POOL_SIGNER_QUOTAS_ENABLED].BoolValue
+}
+
+func (c *config) TransactionPoolMaxPendingTransactionsPerSigner() uint32 {
+	return c.kv[TRANSACTION_POOL_MAX_PENDING_TRANSACTIONS_PER_SIGNER].Uint32Value
+}
+
+func (c *config) TransactionPoolMaxPendingBytesPerSigner() uint32 {
+	return c.kv[TRANSACTION_POOL_MAX_PENDING_BYTES_PER_SIGNER].Uint32Value
+}
+
+func (c *config) TransactionSignerSequencingEnabled() bool {
+	return c.kv[TRANSACTION_SIGNER_SEQUENCING_ENABLED].BoolValue
+}
+
+func (c *config) TransactionPoolAdmissionAllowedContracts() string {
+	return c.kv[TRANSACTION_POOL_ADMISSION_ALLOWED_CONTRACTS].StringValue
+}
+
+func (c *config) TransactionPoolAdmissionDeniedContracts() string {
+	return c.kv[TRANSACTION_POOL_ADMISSION_DENIED_CONTRACTS].StringValue
+}
+
+func (c *config) TransactionPoolAdmissionAllowedSigners() string {
+	return c.kv[TRANSACTION_POOL_ADMISSION_ALLOWED_SIGNERS].StringValue
+}
+
+func (c *config) TransactionPoolAdmissionDeniedSigners() string {
+	return c.kv[TRANSACTION_POOL_ADMISSION_DENIED_SIGNERS].StringValue
+}
+
+func (c *config) TransactionPoolAdmissionMaxArgumentsSize() uint32 {
+	return c.kv[TRANSACTION_POOL_ADMISSION_MAX_ARGUMENTS_SIZE].Uint32Value
+}
+
+func (c *config) TransactionPoolAdmissionPrecheckContracts() string {
+	return c.kv[TRANSACTION_POOL_ADMISSION_PRECHECK_CONTRACTS].StringValue
+}
+
+func (c *config) TransactionPoolAdmissionPrecheckMethod() string {
+	return c.kv[TRANSACTION_POOL_ADMISSION_PRECHECK_METHOD].StringValue
+}
+
+func (c *config) TransactionPoolLeaderAwareForwardingEnabled() bool {
+	return c.kv[TRANSACTION_POOL_LEADER_AWARE_FORWARDING_ENABLED].BoolValue
+}
+
+func (c *config) TransactionPoolForwardingUpcomingLeaders() uint32 {
+	return c.kv[TRANSACTION_POOL_FORWARDING_UPCOMING_LEADERS].Uint32Value
+}
+
+func (c *config) TransactionPoolForwardingFallbackFanout() uint32 {
+	return c.kv[TRANSACTION_POOL_FORWARDING_FALLBACK_FANOUT].Uint32Value
+}
+
+func (c *config) TransactionPoolCommittedIndexEnabled() bool {
+	return c.kv[TRANSACTION_POOL_COMMITTED_INDEX_ENABLED].BoolValue
+}
+
+func (c *config) TransactionPoolCommittedIndexBloomSizeInBytes() uint32 {
+	return c.kv[TRANSACTION_POOL_COMMITTED_INDEX_BLOOM_SIZE_IN_BYTES].Uint32Value
+}
+
+func (c *config) TransactionPoolProvisionalSignerSchemesEnabled() bool {
+	return c.kv[TRANSACTION_POOL_PROVISIONAL_SIGNER_SCHEMES_ENABLED].BoolValue
+}
+
 func (c *config) PublicApiSendTransactionTimeout() time.Duration {
 	return c.kv[PUBLIC_API_SEND_TRANSACTION_TIMEOUT].DurationValue
 }
@@ -319,6 +461,14 @@ func (c *config) PublicApiNodeSyncWarningTime() time.Duration {
 	return c.kv[PUBLIC_API_NODE_SYNC_WARNING_TIME].DurationValue
 }
 
+func (c *config) PublicApiMaxTransactionsPerBatch() uint32 {
+	return c.kv[PUBLIC_API_MAX_TRANSACTIONS_PER_BATCH].Uint32Value
+}
+
+func (c *config) PublicApiSimulateTransactionEnabled() bool {
+	return c.kv[PUBLIC_API_SIMULATE_TRANSACTION_ENABLED].BoolValue
+}
+
 func (c *config) BlockSyncCollectChunksTimeout() time.Duration {
 	return c.kv[BLOCK_SYNC_COLLECT_CHUNKS_TIMEOUT].DurationValue
 }
@@ -335,6 +485,34 @@ func (c *config) ProcessorPerformWarmUpCompilation() bool {
 	return c.kv[PROCESSOR_PERFORM_WARM_UP_COMPILATION].BoolValue
 }
 
+func (c *config) ProcessorNativeExecutionStepLimit() uint32 {
+	return c.kv[PROCESSOR_NATIVE_EXECUTION_STEP_LIMIT].Uint32Value
+}
+
+func (c *config) VirtualMachineGasMeteringEnabled() bool {
+	return c.kv[VIRTUAL_MACHINE_GAS_METERING_ENABLED].BoolValue
+}
+
+func (c *config) VirtualMachineGasLimitPerTransaction() uint32 {
+	return c.kv[VIRTUAL_MACHINE_GAS_LIMIT_PER_TRANSACTION].Uint32Value
+}
+
+func (c *config) VirtualMachineGasLimitPerBlock() uint32 {
+	return c.kv[VIRTUAL_MACHINE_GAS_LIMIT_PER_BLOCK].Uint32Value
+}
+
+func (c *config) VirtualMachineParallelExecutionWorkers() uint32 {
+	return c.kv[VIRTUAL_MACHINE_PARALLEL_EXECUTION_WORKERS].Uint32Value
+}
+
+func (c *config) TransactionSignatureVerificationWorkers() uint32 {
+	return c.kv[TRANSACTION_SIGNATURE_VERIFICATION_WORKERS].Uint32Value
+}
+
+func (c *config) TransactionSignatureCacheSize() uint32 {
+	return c.kv[TRANSACTION_SIGNATURE_CACHE_SIZE].Uint32Value
+}
+
 func (c *config) GossipListenPort() uint16 {
 	return uint16(c.kv[GOSSIP_LISTEN_PORT].Uint32Value)
 }
@@ -355,6 +533,54 @@ func (c *config) GossipReconnectInterval() time.Duration {
 	return c.kv[GOSSIP_RECONNECT_INTERVAL].DurationValue
 }
 
+func (c *config) GossipInboundRateLimitEnabled() bool {
+	return c.kv[GOSSIP_INBOUND_RATE_LIMIT_ENABLED].BoolValue
+}
+
+func (c *config) GossipInboundRateLimitMessagesPerSecond() uint32 {
+	return c.kv[GOSSIP_INBOUND_RATE_LIMIT_MESSAGES_PER_SECOND].Uint32Value
+}
+
+func (c *config) GossipInboundRateLimitBurst() uint32 {
+	return c.kv[GOSSIP_INBOUND_RATE_LIMIT_BURST].Uint32Value
+}
+
+func (c *config) GossipInboundConsensusRateLimitMessagesPerSecond() uint32 {
+	return c.kv[GOSSIP_INBOUND_CONSENSUS_RATE_LIMIT_MESSAGES_PER_SECOND].Uint32Value
+}
+
+func (c *config) GossipInboundConsensusRateLimitBurst() uint32 {
+	return c.kv[GOSSIP_INBOUND_CONSENSUS_RATE_LIMIT_BURST].Uint32Value
+}
+
+func (c *config) GossipInboundPeerBanDuration() time.Duration {
+	return c.kv[GOSSIP_INBOUND_PEER_BAN_DURATION].DurationValue
+}
+
+func (c *config) GossipRelayBroadcastEnabled() bool {
+	return c.kv[GOSSIP_RELAY_BROADCAST_ENABLED].BoolValue
+}
+
+func (c *config) GossipRelayBroadcastFanout() uint32 {
+	return c.kv[GOSSIP_RELAY_BROADCAST_FANOUT].Uint32Value
+}
+
+func (c *config) GossipDuplicateSuppressionWindow() time.Duration {
+	return c.kv[GOSSIP_DUPLICATE_SUPPRESSION_WINDOW].DurationValue
+}
+
+func (c *config) GossipDuplicateSuppressionMaxEntries() uint32 {
+	return c.kv[GOSSIP_DUPLICATE_SUPPRESSION_MAX_ENTRIES].Uint32Value
+}
+
+func (c *config) GossipDuplicateSuppressionExemptions() string {
+	return c.kv[GOSSIP_DUPLICATE_SUPPRESSION_EXEMPTIONS].StringValue
+}
+
+func (c *config) GossipBidirectionalConnectionsEnabled() bool {
+	return c.kv[GOSSIP_BIDIRECTIONAL_CONNECTIONS_ENABLED].BoolValue
+}
+
 func (c *config) BenchmarkConsensusRequiredQuorumPercentage() uint32 {
 	return c.kv[BENCHMARK_CONSENSUS_REQUIRED_QUORUM_PERCENTAGE].Uint32Value
 }
@@ -65,6 +65,14 @@ func ForPublicApiTests(virtualChain uint32, txTimeout time.Duration, outOfSyncWa
 	return cfg
 }
 
+func ForPublicApiSimulationTests(virtualChain uint32) PublicApiConfig {
+	cfg := emptyConfig()
+
+	cfg.SetUint32(VIRTUAL_CHAIN_ID, virtualChain)
+	cfg.SetBool(PUBLIC_API_SIMULATE_TRANSACTION_ENABLED, true)
+	return cfg
+}
+
 func ForStateStorageTest(numOfStateRevisionsToRetain uint32, graceBlockDiff uint32, graceTimeoutMillis uint64) StateStorageConfig {
 	cfg := emptyConfig()
 
@@ -126,6 +134,34 @@ func ForBenchmarkConsensusTests(keyPair *testKeys.TestEcdsaSecp256K1KeyPair, lea
 	return cfg
 }
 
+func ForVirtualMachineTests(signerSequencingEnabled bool) VirtualMachineConfig {
+	cfg := emptyConfig()
+	cfg.SetBool(TRANSACTION_SIGNER_SEQUENCING_ENABLED, signerSequencingEnabled)
+	cfg.SetUint32(STATE_STORAGE_HISTORY_SNAPSHOT_NUM, 5)
+	return cfg
+}
+
+func ForVirtualMachineGasMeteringTests(gasLimitPerTransaction uint32, gasLimitPerBlock uint32) VirtualMachineConfig {
+	cfg := emptyConfig()
+	cfg.SetBool(VIRTUAL_MACHINE_GAS_METERING_ENABLED, true)
+	cfg.SetUint32(VIRTUAL_MACHINE_GAS_LIMIT_PER_TRANSACTION, gasLimitPerTransaction)
+	cfg.SetUint32(VIRTUAL_MACHINE_GAS_LIMIT_PER_BLOCK, gasLimitPerBlock)
+	return cfg
+}
+
+func ForVirtualMachineParallelExecutionTests(workers uint32) VirtualMachineConfig {
+	cfg := emptyConfig()
+	cfg.SetUint32(VIRTUAL_MACHINE_PARALLEL_EXECUTION_WORKERS, workers)
+	return cfg
+}
+
+func ForTransactionSignatureVerifierTests(workers uint32, cacheSize uint32) TransactionSignatureVerifierConfig {
+	cfg := emptyConfig()
+	cfg.SetUint32(TRANSACTION_SIGNATURE_VERIFICATION_WORKERS, workers)
+	cfg.SetUint32(TRANSACTION_SIGNATURE_CACHE_SIZE, cacheSize)
+	return cfg
+}
+
 func ForNativeProcessorTests(id primitives.VirtualChainId) NativeProcessorConfig {
 	cfg := emptyConfig()
 	cfg.SetUint32(VIRTUAL_CHAIN_ID, uint32(id))
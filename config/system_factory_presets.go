@@ -66,6 +66,12 @@ func defaultProductionConfig() mutableNodeConfig {
 	// 5 empty blocks
 	cfg.SetDuration(PUBLIC_API_NODE_SYNC_WARNING_TIME, 50*time.Second)
 
+	// larger batches are rejected as a whole by the send transactions api
+	cfg.SetUint32(PUBLIC_API_MAX_TRANSACTIONS_PER_BATCH, 1000)
+
+	// simulating transactions executes contracts for any client without consensus, nodes have to opt in
+	cfg.SetBool(PUBLIC_API_SIMULATE_TRANSACTION_ENABLED, false)
+
 	cfg.SetDuration(BLOCK_STORAGE_TRANSACTION_RECEIPT_QUERY_TIMESTAMP_GRACE, 5*time.Second)
 
 	cfg.SetUint32(STATE_STORAGE_HISTORY_SNAPSHOT_NUM, 5)
@@ -79,6 +85,48 @@ func defaultProductionConfig() mutableNodeConfig {
 	cfg.SetDuration(TRANSACTION_POOL_PENDING_POOL_CLEAR_EXPIRED_INTERVAL, 10*time.Second)
 	cfg.SetDuration(TRANSACTION_POOL_COMMITTED_POOL_CLEAR_EXPIRED_INTERVAL, 30*time.Second)
 
+	// journaled under BLOCK_STORAGE_FILE_SYSTEM_DATA_DIR so that pending transactions survive a restart
+	cfg.SetBool(TRANSACTION_POOL_PENDING_JOURNAL_ENABLED, true)
+
+	// only shapes the blocks this node proposes, blocks proposed by other nodes are not checked against it
+	cfg.SetBool(TRANSACTION_POOL_PRIORITY_ORDERING_ENABLED, false)
+	cfg.SetString(TRANSACTION_POOL_PRIORITY_SYSTEM_CONTRACTS, "_Deployments,_Elections,_Info")
+	cfg.SetString(TRANSACTION_POOL_PRIORITY_HIGH_CONTRACTS, "")
+	cfg.SetString(TRANSACTION_POOL_PRIORITY_HIGH_SIGNERS, "")
+	cfg.SetUint32(TRANSACTION_POOL_PRIORITY_MIN_SHARE_PERCENT, 10)
+
+	// a single signer may fill at most a fifth of the pending pool, and batches for ordering are shared fairly between signers
+	cfg.SetBool(TRANSACTION_POOL_SIGNER_QUOTAS_ENABLED, true)
+	cfg.SetUint32(TRANSACTION_POOL_MAX_PENDING_TRANSACTIONS_PER_SIGNER, 10000)
+	cfg.SetUint32(TRANSACTION_POOL_MAX_PENDING_BYTES_PER_SIGNER, 4*1024*1024)
+
+	// signed transactions may opt in to a per-signer nonce enforced against state, must be enabled on all nodes together
+	cfg.SetBool(TRANSACTION_SIGNER_SEQUENCING_ENABLED, false)
+
+	// admission filters run on transactions submitted to this node before they enter the pending pool, none by default
+	cfg.SetString(TRANSACTION_POOL_ADMISSION_ALLOWED_CONTRACTS, "")
+	cfg.SetString(TRANSACTION_POOL_ADMISSION_DENIED_CONTRACTS, "")
+	cfg.SetString(TRANSACTION_POOL_ADMISSION_ALLOWED_SIGNERS, "")
+	cfg.SetString(TRANSACTION_POOL_ADMISSION_DENIED_SIGNERS, "")
+	cfg.SetUint32(TRANSACTION_POOL_ADMISSION_MAX_ARGUMENTS_SIZE, 0)
+	cfg.SetString(TRANSACTION_POOL_ADMISSION_PRECHECK_CONTRACTS, "")
+	cfg.SetString(TRANSACTION_POOL_ADMISSION_PRECHECK_METHOD, "precheck")
+
+	// when enabled, new transactions are forwarded to the leaders of the first views of the next Lean Helix term and a
+	// few other committee members instead of to all peers, and pending ones to the new leaders of every term
+	cfg.SetBool(TRANSACTION_POOL_LEADER_AWARE_FORWARDING_ENABLED, false)
+	cfg.SetUint32(TRANSACTION_POOL_FORWARDING_UPCOMING_LEADERS, 3)
+	cfg.SetUint32(TRANSACTION_POOL_FORWARDING_FALLBACK_FANOUT, 2)
+
+	// hashes of all committed transactions are indexed under BLOCK_STORAGE_FILE_SYSTEM_DATA_DIR to reject resubmissions
+	// at any age, the bloom filter in front of the index is the only part kept in memory
+	cfg.SetBool(TRANSACTION_POOL_COMMITTED_INDEX_ENABLED, true)
+	cfg.SetUint32(TRANSACTION_POOL_COMMITTED_INDEX_BLOOM_SIZE_IN_BYTES, 16*1024*1024)
+
+	// the EcdsaSecp256K1 and MultiSig signer schemes are encoded ahead of orbs-spec, which does not define them yet,
+	// all the nodes of a virtual chain must enable them together since the pool validates the transactions of blocks
+	cfg.SetBool(TRANSACTION_POOL_PROVISIONAL_SIGNER_SCHEMES_ENABLED, false)
+
 	cfg.SetUint32(TRANSACTION_POOL_PROPAGATION_BATCH_SIZE, 100)
 	cfg.SetDuration(TRANSACTION_POOL_PROPAGATION_BATCHING_TIMEOUT, 100*time.Millisecond)
 
@@ -86,6 +134,28 @@ func defaultProductionConfig() mutableNodeConfig {
 	cfg.SetDuration(GOSSIP_RECONNECT_INTERVAL, 1*time.Second)
 	cfg.SetDuration(GOSSIP_NETWORK_TIMEOUT, 30*time.Second)
 
+	// per peer and topic, generous enough for block sync bursts, a peer exceeding it is disconnected for the ban duration
+	cfg.SetBool(GOSSIP_INBOUND_RATE_LIMIT_ENABLED, true)
+	cfg.SetUint32(GOSSIP_INBOUND_RATE_LIMIT_MESSAGES_PER_SECOND, 500)
+	cfg.SetUint32(GOSSIP_INBOUND_RATE_LIMIT_BURST, 2000)
+	// consensus topics are not limited (zero), since banning a committee member for a legitimate burst hurts liveness
+	cfg.SetUint32(GOSSIP_INBOUND_CONSENSUS_RATE_LIMIT_MESSAGES_PER_SECOND, 0)
+	cfg.SetUint32(GOSSIP_INBOUND_CONSENSUS_RATE_LIMIT_BURST, 0)
+	cfg.SetDuration(GOSSIP_INBOUND_PEER_BAN_DURATION, 1*time.Minute)
+
+	// relay broadcast is opt-in, when enabled each node uploads a broadcast to at most fanout peers
+	cfg.SetBool(GOSSIP_RELAY_BROADCAST_ENABLED, false)
+	cfg.SetUint32(GOSSIP_RELAY_BROADCAST_FANOUT, 4)
+
+	// short window since block sync may legitimately repeat a response after a retry, requests are exempt for the same reason.
+	// relayed messages are deduplicated by their relay message id in the same window, which is long enough for a relay to complete
+	cfg.SetDuration(GOSSIP_DUPLICATE_SUPPRESSION_WINDOW, 5*time.Second)
+	cfg.SetUint32(GOSSIP_DUPLICATE_SUPPRESSION_MAX_ENTRIES, 10000)
+	cfg.SetString(GOSSIP_DUPLICATE_SUPPRESSION_EXEMPTIONS, "BLOCK_SYNC_AVAILABILITY_REQUEST,BLOCK_SYNC_REQUEST")
+
+	// must be enabled on all nodes together, since in this mode only one node of each pair dials the other
+	cfg.SetBool(GOSSIP_BIDIRECTIONAL_CONNECTIONS_ENABLED, false)
+
 	// 10 minutes + 60 blocks is about 25 minutes
 	cfg.SetDuration(ETHEREUM_FINALITY_TIME_COMPONENT, 10*time.Minute)
 	cfg.SetUint32(ETHEREUM_FINALITY_BLOCKS_COMPONENT, 60)
@@ -93,6 +163,22 @@ func defaultProductionConfig() mutableNodeConfig {
 	cfg.SetBool(PROCESSOR_SANITIZE_DEPLOYED_CONTRACTS, true)
 	cfg.SetBool(PROCESSOR_PERFORM_WARM_UP_COMPILATION, true)
 
+	// a contract call, including the calls it makes to other contracts, fails once it makes more function calls, loop
+	// iterations and sdk calls than this, it changes execution results so must be the same on all nodes
+	cfg.SetUint32(PROCESSOR_NATIVE_EXECUTION_STEP_LIMIT, 10000000)
+
+	// gas is charged for sdk calls and recorded in receipts, it changes execution results so must be enabled on all nodes together
+	cfg.SetBool(VIRTUAL_MACHINE_GAS_METERING_ENABLED, false)
+	cfg.SetUint32(VIRTUAL_MACHINE_GAS_LIMIT_PER_TRANSACTION, 10000000)
+	cfg.SetUint32(VIRTUAL_MACHINE_GAS_LIMIT_PER_BLOCK, 500000000)
+
+	// results are identical to executing one transaction at a time, so nodes may differ in this setting, 0 disables it
+	cfg.SetUint32(VIRTUAL_MACHINE_PARALLEL_EXECUTION_WORKERS, 0)
+
+	// verified signatures are shared by the transaction pool and the virtual machine, 0 workers verifies one at a time
+	cfg.SetUint32(TRANSACTION_SIGNATURE_VERIFICATION_WORKERS, 4)
+	cfg.SetUint32(TRANSACTION_SIGNATURE_CACHE_SIZE, 100000)
+
 	cfg.SetActiveConsensusAlgo(consensus.CONSENSUS_ALGO_TYPE_BENCHMARK_CONSENSUS)
 	cfg.SetString(ETHEREUM_ENDPOINT, "http://localhost:8545")
 	cfg.SetString(PROCESSOR_ARTIFACT_PATH, filepath.Join(GetProjectSourceTmpPath(), "processor-artifacts"))
@@ -0,0 +1,98 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package bloom
+
+import (
+	"encoding/binary"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/pkg/errors"
+)
+
+// HashBloomFilter holds a set of sha256 hashes in a fixed amount of memory, Test never returns false for a hash that was
+// added and returns true for a hash that was not added with a probability that grows with the number of added hashes.
+// Since the hashes are uniformly distributed the bit positions are taken from their bytes rather than rehashing them.
+// It is not safe for concurrent use.
+type HashBloomFilter struct {
+	bits      []byte
+	mask      uint64 // size in bits - 1
+	hashCount uint8
+}
+
+const minHashBloomFilterSizeInBytes = 8
+
+// the size is rounded up to a power of two
+func NewHashBloomFilter(sizeInBytes uint32, hashCount uint8) *HashBloomFilter {
+	if sizeInBytes < minHashBloomFilterSizeInBytes {
+		sizeInBytes = minHashBloomFilterSizeInBytes
+	}
+	if hashCount == 0 {
+		hashCount = 1
+	}
+	size := nextHighPowerOfTwo(sizeInBytes)
+	return &HashBloomFilter{
+		bits:      make([]byte, size),
+		mask:      uint64(size)*8 - 1,
+		hashCount: hashCount,
+	}
+}
+
+func NewHashBloomFilterFromRaw(raw []byte, hashCount uint8) (*HashBloomFilter, error) {
+	size := uint32(len(raw))
+	if size < minHashBloomFilterSizeInBytes || nextHighPowerOfTwo(size) != size {
+		return nil, errors.Errorf("invalid bloom filter size %d, expected a power of two of at least %d bytes", size, minHashBloomFilterSizeInBytes)
+	}
+	if hashCount == 0 {
+		return nil, errors.New("bloom filter must use at least one hash")
+	}
+
+	bits := make([]byte, size)
+	copy(bits, raw)
+	return &HashBloomFilter{
+		bits:      bits,
+		mask:      uint64(size)*8 - 1,
+		hashCount: hashCount,
+	}, nil
+}
+
+func (bf *HashBloomFilter) SizeInBytes() uint32 {
+	return uint32(len(bf.bits))
+}
+
+func (bf *HashBloomFilter) HashCount() uint8 {
+	return bf.hashCount
+}
+
+func (bf *HashBloomFilter) Add(h primitives.Sha256) {
+	h1, h2 := bf.seeds(h)
+	for i := uint64(0); i < uint64(bf.hashCount); i++ {
+		loc := (h1 + i*h2) & bf.mask
+		bf.bits[loc>>3] |= 1 << (loc & 7)
+	}
+}
+
+func (bf *HashBloomFilter) Test(h primitives.Sha256) bool {
+	h1, h2 := bf.seeds(h)
+	for i := uint64(0); i < uint64(bf.hashCount); i++ {
+		loc := (h1 + i*h2) & bf.mask
+		if bf.bits[loc>>3]&(1<<(loc&7)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// the filter's bits, the returned slice must not be modified
+func (bf *HashBloomFilter) Raw() []byte {
+	return bf.bits
+}
+
+// double hashing (Kirsch-Mitzenmacher) from the last 16 bytes of the hash; the odd step visits distinct bits
+func (bf *HashBloomFilter) seeds(h primitives.Sha256) (uint64, uint64) {
+	var padded [32]byte
+	copy(padded[:], h)
+	return binary.LittleEndian.Uint64(padded[16:24]), binary.LittleEndian.Uint64(padded[24:32]) | 1
+}
@@ -0,0 +1,55 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package bloom_test
+
+import (
+	"encoding/binary"
+	"github.com/orbs-network/orbs-network-go/crypto/bloom"
+	"github.com/orbs-network/orbs-network-go/crypto/hash"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func hashOf(i int) primitives.Sha256 {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, uint64(i))
+	return hash.CalcSha256(b)
+}
+
+func TestHashBloomFilter_HasNoFalseNegativesAndFewFalsePositives(t *testing.T) {
+	bf := bloom.NewHashBloomFilter(1000, 7)
+	require.EqualValues(t, 1024, bf.SizeInBytes(), "size should be rounded up to a power of two")
+
+	for i := 0; i < 500; i++ {
+		bf.Add(hashOf(i))
+	}
+	for i := 0; i < 500; i++ {
+		require.True(t, bf.Test(hashOf(i)), "added hash %d should be found", i)
+	}
+
+	falsePositives := 0
+	for i := 500; i < 10500; i++ {
+		if bf.Test(hashOf(i)) {
+			falsePositives++
+		}
+	}
+	require.True(t, falsePositives < 100, "expected a false positive rate well under 1%%, got %d in 10000", falsePositives)
+}
+
+func TestHashBloomFilter_FromRaw(t *testing.T) {
+	bf := bloom.NewHashBloomFilter(64, 3)
+	bf.Add(hashOf(1))
+
+	restored, err := bloom.NewHashBloomFilterFromRaw(bf.Raw(), bf.HashCount())
+	require.NoError(t, err)
+	require.True(t, restored.Test(hashOf(1)))
+	require.Equal(t, bf.Raw(), restored.Raw())
+
+	_, err = bloom.NewHashBloomFilterFromRaw(make([]byte, 100), 3)
+	require.Error(t, err, "size must be a power of two")
+}
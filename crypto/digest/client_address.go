@@ -7,11 +7,14 @@
 package digest
 
 import (
+	"bytes"
+	"github.com/orbs-network/membuffers/go"
 	"github.com/orbs-network/orbs-network-go/crypto/hash"
 	"github.com/orbs-network/orbs-network-go/crypto/keys"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
 	"github.com/pkg/errors"
+	"sort"
 )
 
 const (
@@ -32,6 +35,65 @@ func CalcClientAddressOfEd25519Signer(signer *protocol.Signer) (primitives.Clien
 	return CalcClientAddressOfEd25519PublicKey(signerPublicKey)
 }
 
+// the address of a secp256k1 signer is its ethereum address
+func CalcClientAddressOfEcdsaSecp256K1PublicKey(publicKey primitives.EcdsaSecp256K1PublicKey) (primitives.ClientAddress, error) {
+	if len(publicKey) != keys.ECDSA_SECP256K1_PUBLIC_KEY_SIZE_BYTES {
+		return nil, errors.New("transaction is not signed by a valid Signer")
+	}
+	res := hash.CalcKeccak256(publicKey)[hash.KECCAK256_HASH_SIZE_BYTES-CLIENT_ADDRESS_SIZE_BYTES:]
+	return primitives.ClientAddress(res), nil
+}
+
+func CalcClientAddressOfEcdsaSecp256K1Signer(signer *protocol.Signer) (primitives.ClientAddress, error) {
+	signerPublicKey, err := EcdsaSecp256K1SignerPublicKey(signer)
+	if err != nil {
+		return nil, err
+	}
+	return CalcClientAddressOfEcdsaSecp256K1PublicKey(signerPublicKey)
+}
+
+// the address of a multi signature signer depends only on its threshold and set of public keys, so it stays the same
+// whichever of its keys sign and in whatever order they are listed. every key is prefixed by its length since keys of
+// both sizes may be mixed, otherwise different key sets could hash the same
+func CalcClientAddressOfMultiSigSigner(signer *protocol.Signer) (primitives.ClientAddress, error) {
+	threshold, publicKeys, err := MultiSigSignerThresholdAndPublicKeys(signer)
+	if err != nil {
+		return nil, err
+	}
+
+	sortedPublicKeys := make([][]byte, len(publicKeys))
+	copy(sortedPublicKeys, publicKeys)
+	sort.Slice(sortedPublicKeys, func(i, j int) bool {
+		return bytes.Compare(sortedPublicKeys[i], sortedPublicKeys[j]) < 0
+	})
+
+	data := [][]byte{[]byte("MultiSig"), uint32Bytes(threshold)}
+	for _, publicKey := range sortedPublicKeys {
+		data = append(data, uint32Bytes(uint32(len(publicKey))), publicKey)
+	}
+	res := hash.CalcSha256(data...)[CLIENT_ADDRESS_SHA256_OFFSET:]
+	return primitives.ClientAddress(res), nil
+}
+
+func uint32Bytes(value uint32) []byte {
+	res := make([]byte, 4)
+	membuffers.WriteUint32(res, value)
+	return res
+}
+
+func CalcClientAddressOfSigner(signer *protocol.Signer) (primitives.ClientAddress, error) {
+	switch SignerScheme(signer) {
+	case protocol.SIGNER_SCHEME_EDDSA:
+		return CalcClientAddressOfEd25519Signer(signer)
+	case SIGNER_SCHEME_ECDSA_SECP256K1:
+		return CalcClientAddressOfEcdsaSecp256K1Signer(signer)
+	case SIGNER_SCHEME_MULTISIG:
+		return CalcClientAddressOfMultiSigSigner(signer)
+	default:
+		return nil, errors.New("transaction is not signed by any Signer")
+	}
+}
+
 // TODO(v1): add argument (spec feature)
 func CalcClientAddressOfContract(contractName primitives.ContractName) (primitives.ClientAddress, error) {
 	if len(contractName) == 0 {
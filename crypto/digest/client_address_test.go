@@ -0,0 +1,116 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package digest_test
+
+import (
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/test/crypto/keys"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestEcdsaSecp256K1Signer(t *testing.T) {
+	keyPair := keys.EcdsaSecp256K1KeyPairForTests(1)
+	signer := digest.EcdsaSecp256K1SignerBuilder(protocol.NETWORK_TYPE_TEST_NET, keyPair.PublicKey()).Build()
+
+	require.Equal(t, digest.SIGNER_SCHEME_ECDSA_SECP256K1, digest.SignerScheme(signer))
+	publicKey, err := digest.EcdsaSecp256K1SignerPublicKey(signer)
+	require.NoError(t, err)
+	require.EqualValues(t, keyPair.PublicKey(), publicKey)
+
+	address, err := digest.CalcClientAddressOfSigner(signer)
+	require.NoError(t, err)
+	require.EqualValues(t, keyPair.NodeAddress(), address, "the client address of a secp256k1 signer should be its ethereum address")
+}
+
+func TestCalcClientAddressOfSignerOfEachScheme(t *testing.T) {
+	ed25519Signer := (&protocol.SignerBuilder{
+		Scheme: protocol.SIGNER_SCHEME_EDDSA,
+		Eddsa: &protocol.EdDSA01SignerBuilder{
+			NetworkType:     protocol.NETWORK_TYPE_TEST_NET,
+			SignerPublicKey: keys.Ed25519KeyPairForTests(1).PublicKey(),
+		},
+	}).Build()
+	require.Equal(t, protocol.SIGNER_SCHEME_EDDSA, digest.SignerScheme(ed25519Signer))
+	expectedAddress, err := digest.CalcClientAddressOfEd25519Signer(ed25519Signer)
+	require.NoError(t, err)
+	address, err := digest.CalcClientAddressOfSigner(ed25519Signer)
+	require.NoError(t, err)
+	require.Equal(t, expectedAddress, address)
+
+	_, err = digest.EcdsaSecp256K1SignerPublicKey(ed25519Signer)
+	require.Error(t, err, "an ed25519 signer has no secp256k1 public key")
+
+	unknownSigner := (&protocol.SignerBuilder{Scheme: protocol.SIGNER_SCHEME_EDDSA + 10000}).Build()
+	_, err = digest.CalcClientAddressOfSigner(unknownSigner)
+	require.Error(t, err, "an unknown signer scheme has no address")
+}
+
+func TestMultiSigSigner(t *testing.T) {
+	publicKeys := [][]byte{keys.Ed25519KeyPairForTests(1).PublicKey(), keys.EcdsaSecp256K1KeyPairForTests(1).PublicKey()}
+	signer := digest.MultiSigSignerBuilder(protocol.NETWORK_TYPE_TEST_NET, 2, publicKeys).Build()
+
+	require.Equal(t, digest.SIGNER_SCHEME_MULTISIG, digest.SignerScheme(signer))
+	threshold, signerPublicKeys, err := digest.MultiSigSignerThresholdAndPublicKeys(signer)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, threshold)
+	require.Equal(t, publicKeys, signerPublicKeys)
+
+	address, err := digest.CalcClientAddressOfSigner(signer)
+	require.NoError(t, err)
+	require.Len(t, address, digest.CLIENT_ADDRESS_SIZE_BYTES)
+
+	sameSigner := digest.MultiSigSignerBuilder(protocol.NETWORK_TYPE_MAIN_NET, 2, publicKeys).Build()
+	sameAddress, err := digest.CalcClientAddressOfSigner(sameSigner)
+	require.NoError(t, err)
+	require.Equal(t, address, sameAddress, "the address should depend only on the threshold and the public keys")
+
+	otherSigner := digest.MultiSigSignerBuilder(protocol.NETWORK_TYPE_TEST_NET, 1, publicKeys).Build()
+	otherAddress, err := digest.CalcClientAddressOfSigner(otherSigner)
+	require.NoError(t, err)
+	require.NotEqual(t, address, otherAddress, "the address should depend on the threshold")
+
+	reorderedSigner := digest.MultiSigSignerBuilder(protocol.NETWORK_TYPE_TEST_NET, 2, [][]byte{publicKeys[1], publicKeys[0]}).Build()
+	reorderedAddress, err := digest.CalcClientAddressOfSigner(reorderedSigner)
+	require.NoError(t, err)
+	require.Equal(t, address, reorderedAddress, "the address should not depend on the order of the public keys")
+}
+
+func TestMultiSigSignerMustBeValid(t *testing.T) {
+	publicKey := keys.Ed25519KeyPairForTests(1).PublicKey()
+	otherPublicKey := keys.Ed25519KeyPairForTests(2).PublicKey()
+	tests := []struct {
+		name       string
+		threshold  uint32
+		publicKeys [][]byte
+	}{
+		{"zero threshold", 0, [][]byte{publicKey}},
+		{"threshold above the number of keys", 3, [][]byte{publicKey, otherPublicKey}},
+		{"duplicate public key", 2, [][]byte{publicKey, publicKey}},
+		{"public key of invalid size", 1, [][]byte{publicKey[1:]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer := digest.MultiSigSignerBuilder(protocol.NETWORK_TYPE_TEST_NET, tt.threshold, tt.publicKeys).Build()
+			_, _, err := digest.MultiSigSignerThresholdAndPublicKeys(signer)
+			require.Error(t, err)
+			_, err = digest.CalcClientAddressOfSigner(signer)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestMultiSigSignatures(t *testing.T) {
+	signatures := [][]byte{{0x01, 0x02}, {}, {0x03}}
+	decoded, err := digest.MultiSigSignaturesOf(digest.MultiSigSignatures(signatures))
+	require.NoError(t, err)
+	require.Equal(t, signatures, decoded)
+
+	_, err = digest.MultiSigSignaturesOf([]byte{0x01})
+	require.Error(t, err)
+}
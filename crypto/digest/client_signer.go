@@ -0,0 +1,111 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package digest
+
+import (
+	"github.com/orbs-network/membuffers/go"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
+)
+
+// not part of the spec's Signer union yet, a secp256k1 signer is encoded as the next union member and its
+// message has the same layout as EdDSA01Signer (NetworkType followed by SignerPublicKey). until orbs-spec defines
+// the member and generates its builder the encoding is provisional, see IsProvisionalSignerScheme
+const SIGNER_SCHEME_ECDSA_SECP256K1 = protocol.SignerScheme(2)
+
+var _clientSigner_Scheme = []membuffers.FieldType{membuffers.TypeUnion}
+var _clientSigner_Unions = [][]membuffers.FieldType{{membuffers.TypeMessage, membuffers.TypeMessage, membuffers.TypeMessage, membuffers.TypeMessage}}
+var _ecdsaSecp256K1Signer_Scheme = []membuffers.FieldType{membuffers.TypeUint16, membuffers.TypeBytes}
+
+// a provisional scheme is hand encoded here rather than generated from orbs-spec, a later spec may assign its union
+// index to a different message so transactions signed with it are only accepted where explicitly enabled
+func IsProvisionalSignerScheme(scheme protocol.SignerScheme) bool {
+	return scheme == SIGNER_SCHEME_ECDSA_SECP256K1 || scheme == SIGNER_SCHEME_MULTISIG
+}
+
+// unlike Signer.Scheme() this also identifies schemes the spec's Signer union does not know
+func SignerScheme(signer *protocol.Signer) protocol.SignerScheme {
+	raw := signer.Raw()
+	if membuffers.Offset(len(raw)) < membuffers.FieldSizes[membuffers.TypeUnion] {
+		return signer.Scheme()
+	}
+	return protocol.SignerScheme(membuffers.GetUnionType(raw))
+}
+
+func EcdsaSecp256K1SignerPublicKey(signer *protocol.Signer) (primitives.EcdsaSecp256K1PublicKey, error) {
+	signerMessage, err := signerMessageOfScheme(signer, SIGNER_SCHEME_ECDSA_SECP256K1, _ecdsaSecp256K1Signer_Scheme)
+	if err != nil {
+		return nil, errors.Wrap(err, "transaction is not signed by a valid ecdsa secp256k1 Signer")
+	}
+	return primitives.EcdsaSecp256K1PublicKey(signerMessage.GetBytes(1)), nil
+}
+
+func EcdsaSecp256K1SignerBuilder(networkType protocol.SignerNetworkType, publicKey primitives.EcdsaSecp256K1PublicKey) *protocol.SignerBuilder {
+	return signerBuilderOfScheme(SIGNER_SCHEME_ECDSA_SECP256K1, &signerMessageBuilder{write: func(w *membuffers.InternalBuilder, buf []byte) {
+		w.WriteUint16(buf, uint16(networkType))
+		w.WriteBytes(buf, publicKey)
+	}})
+}
+
+func signerMessageOfScheme(signer *protocol.Signer, scheme protocol.SignerScheme, messageScheme []membuffers.FieldType) (*membuffers.InternalMessage, error) {
+	raw := signer.Raw()
+	var m membuffers.InternalMessage
+	m.Init(raw, membuffers.Offset(len(raw)), _clientSigner_Scheme, _clientSigner_Unions)
+	is, off := m.IsUnionIndex(0, 0, uint16(scheme))
+	if !is {
+		return nil, errors.Errorf("signer scheme is not %d", scheme)
+	}
+	b, s := m.GetMessageInOffset(off)
+	signerMessage := &membuffers.InternalMessage{}
+	signerMessage.Init(b[:s], s, messageScheme, nil)
+	if !signerMessage.IsValid() {
+		return nil, errors.New("signer message is corrupt")
+	}
+	return signerMessage, nil
+}
+
+func signerBuilderOfScheme(scheme protocol.SignerScheme, signerMessage membuffers.MessageWriter) *protocol.SignerBuilder {
+	var w membuffers.InternalBuilder
+	w.WriteUnionIndex(nil, uint16(scheme))
+	w.WriteMessage(nil, signerMessage)
+	buf := make([]byte, w.GetSize())
+	w.Reset()
+	w.WriteUnionIndex(buf, uint16(scheme))
+	w.WriteMessage(buf, signerMessage)
+	return protocol.SignerBuilderFromRaw(buf)
+}
+
+// writes the fields of a signer message the spec has no builder for
+type signerMessageBuilder struct {
+	write    func(w *membuffers.InternalBuilder, buf []byte)
+	_builder membuffers.InternalBuilder
+}
+
+func (w *signerMessageBuilder) Write(buf []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &membuffers.ErrBufferOverrun{}
+		}
+	}()
+	w._builder.Reset()
+	w.write(&w._builder, buf)
+	return nil
+}
+
+func (w *signerMessageBuilder) HexDump(prefix string, offsetFromStart membuffers.Offset) error {
+	return nil
+}
+
+func (w *signerMessageBuilder) GetSize() membuffers.Offset {
+	return w._builder.GetSize()
+}
+
+func (w *signerMessageBuilder) CalcRequiredSize() membuffers.Offset {
+	w.Write(nil)
+	return w._builder.GetSize()
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package digest
+
+import (
+	"github.com/orbs-network/membuffers/go"
+	"github.com/orbs-network/orbs-network-go/crypto/keys"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
+)
+
+// a multi signature signer approves a transaction when Threshold of its PublicKeys signed it, each public key is
+// ed25519 or secp256k1 by its size, the transaction signature holds one (possibly empty) signature per public key
+const SIGNER_SCHEME_MULTISIG = protocol.SignerScheme(3)
+
+const MULTISIG_MAX_PUBLIC_KEYS = 32
+
+var _multiSigSigner_Scheme = []membuffers.FieldType{membuffers.TypeUint16, membuffers.TypeUint32, membuffers.TypeBytesArray}
+var _multiSigSignatures_Scheme = []membuffers.FieldType{membuffers.TypeBytesArray}
+
+func MultiSigSignerBuilder(networkType protocol.SignerNetworkType, threshold uint32, publicKeys [][]byte) *protocol.SignerBuilder {
+	return signerBuilderOfScheme(SIGNER_SCHEME_MULTISIG, &signerMessageBuilder{write: func(w *membuffers.InternalBuilder, buf []byte) {
+		w.WriteUint16(buf, uint16(networkType))
+		w.WriteUint32(buf, threshold)
+		w.WriteBytesArray(buf, publicKeys)
+	}})
+}
+
+// fails unless the threshold is reachable by distinct public keys of a known size
+func MultiSigSignerThresholdAndPublicKeys(signer *protocol.Signer) (uint32, [][]byte, error) {
+	signerMessage, err := signerMessageOfScheme(signer, SIGNER_SCHEME_MULTISIG, _multiSigSigner_Scheme)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "transaction is not signed by a valid multi signature Signer")
+	}
+
+	threshold := signerMessage.GetUint32(1)
+	var publicKeys [][]byte
+	seen := make(map[string]bool)
+	for i := signerMessage.GetBytesArrayIterator(2); i.HasNext(); {
+		publicKey := i.NextBytes()
+		if len(publicKey) != keys.ED25519_PUBLIC_KEY_SIZE_BYTES && len(publicKey) != keys.ECDSA_SECP256K1_PUBLIC_KEY_SIZE_BYTES {
+			return 0, nil, errors.Errorf("multi signature Signer has a public key of invalid size %d", len(publicKey))
+		}
+		if seen[string(publicKey)] {
+			return 0, nil, errors.New("multi signature Signer has a duplicate public key")
+		}
+		if len(publicKeys) == MULTISIG_MAX_PUBLIC_KEYS {
+			return 0, nil, errors.Errorf("multi signature Signer has more than %d public keys", MULTISIG_MAX_PUBLIC_KEYS)
+		}
+		seen[string(publicKey)] = true
+		publicKeys = append(publicKeys, publicKey)
+	}
+
+	if threshold == 0 || threshold > uint32(len(publicKeys)) {
+		return 0, nil, errors.Errorf("multi signature Signer threshold %d is not within 1 and %d", threshold, len(publicKeys))
+	}
+	return threshold, publicKeys, nil
+}
+
+func MultiSigSignatures(signatures [][]byte) []byte {
+	var w membuffers.InternalBuilder
+	w.WriteBytesArray(nil, signatures)
+	buf := make([]byte, w.GetSize())
+	w.Reset()
+	w.WriteBytesArray(buf, signatures)
+	return buf
+}
+
+func MultiSigSignaturesOf(signature []byte) ([][]byte, error) {
+	var m membuffers.InternalMessage
+	m.Init(signature, membuffers.Offset(len(signature)), _multiSigSignatures_Scheme, nil)
+	if !m.IsValid() {
+		return nil, errors.New("multi signature is corrupt")
+	}
+	var signatures [][]byte
+	for i := m.GetBytesArrayIterator(0); i.HasNext(); {
+		signatures = append(signatures, i.NextBytes())
+	}
+	return signatures, nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package digest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
+)
+
+// not part of the spec's Transaction yet, a signed transaction opts in to signer sequencing by carrying its per-signer
+// nonce as its first input argument: a bytes argument made of SIGNER_NONCE_ARGUMENT_TAG followed by the nonce as a
+// big endian uint64, which is not passed on to the called contract method. No other argument may start with the tag,
+// so that an argument of the contract method is never taken for a nonce or the other way around
+var SIGNER_NONCE_ARGUMENT_TAG = []byte("orbs:signer-nonce:")
+
+const SIGNER_NONCE_ARGUMENT_SIZE = 26 // the tag and a uint64
+
+func SignerNonceArgument(nonce uint64) []byte {
+	res := make([]byte, SIGNER_NONCE_ARGUMENT_SIZE)
+	copy(res, SIGNER_NONCE_ARGUMENT_TAG)
+	binary.BigEndian.PutUint64(res[len(SIGNER_NONCE_ARGUMENT_TAG):], nonce)
+	return res
+}
+
+func isTaggedAsSignerNonce(argument *protocol.Argument) bool {
+	return argument.IsTypeBytesValue() && bytes.HasPrefix(argument.BytesValue(), SIGNER_NONCE_ARGUMENT_TAG)
+}
+
+// returns false if the transaction carries no signer nonce, or an error if it carries a malformed or misplaced one
+func SignerNonce(transaction *protocol.Transaction) (uint64, bool, error) {
+	var nonce uint64
+	found := false
+	index := 0
+	for i := protocol.ArgumentArrayReader(transaction.RawInputArgumentArrayWithHeader()).ArgumentsIterator(); i.HasNext(); index++ {
+		argument := i.NextArguments()
+		if !isTaggedAsSignerNonce(argument) {
+			continue
+		}
+		if index != 0 {
+			return 0, false, errors.Errorf("input argument %d is tagged as a signer nonce, which may only be the first input argument", index)
+		}
+		if len(argument.BytesValue()) != SIGNER_NONCE_ARGUMENT_SIZE {
+			return 0, false, errors.Errorf("signer nonce argument is %d bytes instead of %d", len(argument.BytesValue()), SIGNER_NONCE_ARGUMENT_SIZE)
+		}
+		if len(transaction.Signer().Raw()) == 0 {
+			return 0, false, errors.New("transaction without a signer carries a signer nonce")
+		}
+		nonce = binary.BigEndian.Uint64(argument.BytesValue()[len(SIGNER_NONCE_ARGUMENT_TAG):])
+		found = true
+	}
+	return nonce, found, nil
+}
+
+// returns the input arguments of a transaction which carries a signer nonce without it
+func InputArgumentsWithoutSignerNonce(transaction *protocol.Transaction) (*protocol.ArgumentArray, error) {
+	_, found, err := SignerNonce(transaction)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errors.New("transaction carries no signer nonce")
+	}
+	args, err := protocol.ArgumentArrayReader(transaction.RawInputArgumentArrayWithHeader()).ToNatives()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed decoding transaction input arguments")
+	}
+	return protocol.ArgumentArrayFromNatives(args[1:])
+}
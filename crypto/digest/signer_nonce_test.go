@@ -0,0 +1,50 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package digest_test
+
+import (
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSignerNonce_IsTheTaggedFirstInputArgumentAndIsStrippedFromTheContractArguments(t *testing.T) {
+	tx := builders.Transaction().WithArgs(uint64(10), []byte{0x01}).WithSignerNonce(7).Build().Transaction()
+
+	nonce, found, err := digest.SignerNonce(tx)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, 7, nonce)
+
+	args, err := digest.InputArgumentsWithoutSignerNonce(tx)
+	require.NoError(t, err)
+	natives, err := args.ToNatives()
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{uint64(10), []byte{0x01}}, natives)
+}
+
+func TestSignerNonce_IsOptIn(t *testing.T) {
+	_, found, err := digest.SignerNonce(builders.Transaction().WithArgs(uint64(10), uint64(7)).Build().Transaction())
+	require.NoError(t, err)
+	require.False(t, found, "a trailing uint64 argument should not be taken for a signer nonce")
+
+	_, found, err = digest.SignerNonce(builders.Transaction().WithArgs().Build().Transaction())
+	require.NoError(t, err)
+	require.False(t, found)
+}
+
+func TestSignerNonce_RejectsAmbiguousEncodings(t *testing.T) {
+	_, _, err := digest.SignerNonce(builders.Transaction().WithArgs(uint64(10), digest.SignerNonceArgument(7)).Build().Transaction())
+	require.Error(t, err, "a signer nonce which is not the first argument should be rejected")
+
+	_, _, err = digest.SignerNonce(builders.Transaction().WithArgs(append(digest.SignerNonceArgument(7), 0x01)).Build().Transaction())
+	require.Error(t, err, "a tagged argument which is not exactly a nonce should be rejected")
+
+	_, _, err = digest.SignerNonce(builders.Transaction().WithArgs(digest.SignerNonceArgument(7), digest.SignerNonceArgument(8)).Build().Transaction())
+	require.Error(t, err, "a second tagged argument should be rejected")
+}
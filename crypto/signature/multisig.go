@@ -0,0 +1,39 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package signature
+
+import (
+	"github.com/orbs-network/orbs-network-go/crypto/keys"
+)
+
+// signatures[i] is the signature of publicKeys[i] or empty when that key did not sign, a signature which is
+// present must be valid even when the threshold is already reached without it
+func VerifyMultiSig(threshold uint32, publicKeys [][]byte, data []byte, signatures [][]byte) bool {
+	if len(signatures) != len(publicKeys) {
+		return false
+	}
+	signed := uint32(0)
+	for i, publicKey := range publicKeys {
+		if len(signatures[i]) == 0 {
+			continue
+		}
+		switch len(publicKey) {
+		case keys.ED25519_PUBLIC_KEY_SIZE_BYTES:
+			if !VerifyEd25519(publicKey, data, signatures[i]) {
+				return false
+			}
+		case keys.ECDSA_SECP256K1_PUBLIC_KEY_SIZE_BYTES:
+			if !VerifyEcdsaSecp256K1(publicKey, data, signatures[i]) {
+				return false
+			}
+		default:
+			return false
+		}
+		signed++
+	}
+	return signed >= threshold
+}
@@ -0,0 +1,32 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package signature
+
+import (
+	"github.com/orbs-network/orbs-network-go/crypto/hash"
+	"github.com/orbs-network/orbs-network-go/test/crypto/keys"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestVerifyMultiSig(t *testing.T) {
+	data := hash.CalcSha256([]byte("this is what we want to sign"))
+	ed25519KeyPair := keys.Ed25519KeyPairForTests(1)
+	ecdsaKeyPair := keys.EcdsaSecp256K1KeyPairForTests(1)
+	otherKeyPair := keys.Ed25519KeyPairForTests(2)
+	publicKeys := [][]byte{ed25519KeyPair.PublicKey(), ecdsaKeyPair.PublicKey(), otherKeyPair.PublicKey()}
+
+	ed25519Sig, err := SignEd25519(ed25519KeyPair.PrivateKey(), data)
+	require.NoError(t, err)
+	ecdsaSig, err := SignEcdsaSecp256K1(ecdsaKeyPair.PrivateKey(), data)
+	require.NoError(t, err)
+
+	require.True(t, VerifyMultiSig(2, publicKeys, data, [][]byte{ed25519Sig, ecdsaSig, nil}), "2 of 3 keys signed")
+	require.False(t, VerifyMultiSig(3, publicKeys, data, [][]byte{ed25519Sig, ecdsaSig, nil}), "only 2 of 3 keys signed")
+	require.False(t, VerifyMultiSig(1, publicKeys, data, [][]byte{ed25519Sig, ecdsaSig, ed25519Sig}), "a signature of another key should fail even past the threshold")
+	require.False(t, VerifyMultiSig(1, publicKeys, data, [][]byte{ed25519Sig}), "a signature should be given for every key")
+}
@@ -0,0 +1,186 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package validators
+
+import (
+	"container/list"
+	"github.com/orbs-network/govnr"
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/crypto/hash"
+	"github.com/orbs-network/orbs-network-go/crypto/signature"
+	"github.com/orbs-network/orbs-network-go/instrumentation/logfields"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/scribe/log"
+	"sync"
+	"sync/atomic"
+)
+
+// VerifyTransactionSignature returns TRANSACTION_STATUS_PRE_ORDER_VALID if the transaction is signed by its signer
+func VerifyTransactionSignature(signedTransaction *protocol.SignedTransaction) protocol.TransactionStatus {
+	return verifyTransactionSignature(signedTransaction, digest.CalcTxHash(signedTransaction.Transaction()))
+}
+
+func verifyTransactionSignature(signedTransaction *protocol.SignedTransaction, txHash primitives.Sha256) protocol.TransactionStatus {
+	signer := signedTransaction.Transaction().Signer()
+
+	var ok bool
+	switch digest.SignerScheme(signer) {
+	case protocol.SIGNER_SCHEME_EDDSA:
+		ok = signature.VerifyEd25519(signer.Eddsa().SignerPublicKey(), txHash, signedTransaction.Signature())
+	case digest.SIGNER_SCHEME_ECDSA_SECP256K1:
+		signerPublicKey, err := digest.EcdsaSecp256K1SignerPublicKey(signer)
+		ok = err == nil && signature.VerifyEcdsaSecp256K1(signerPublicKey, txHash, signedTransaction.Signature())
+	case digest.SIGNER_SCHEME_MULTISIG:
+		threshold, signerPublicKeys, err := digest.MultiSigSignerThresholdAndPublicKeys(signer)
+		if err == nil {
+			signatures, err := digest.MultiSigSignaturesOf(signedTransaction.Signature())
+			ok = err == nil && signature.VerifyMultiSig(threshold, signerPublicKeys, txHash, signatures)
+		}
+	default:
+		return protocol.TRANSACTION_STATUS_REJECTED_UNKNOWN_SIGNER_SCHEME
+	}
+
+	if !ok {
+		return protocol.TRANSACTION_STATUS_REJECTED_SIGNATURE_MISMATCH
+	}
+	return protocol.TRANSACTION_STATUS_PRE_ORDER_VALID
+}
+
+// VerifyTransactionSignatures verifies one transaction at a time, skipping transactions which already failed
+func VerifyTransactionSignatures(signedTransactions []*protocol.SignedTransaction, resultStatuses []protocol.TransactionStatus) {
+	for i, signedTransaction := range signedTransactions {
+		if resultStatuses[i] == protocol.TRANSACTION_STATUS_RESERVED {
+			resultStatuses[i] = VerifyTransactionSignature(signedTransaction)
+		}
+	}
+}
+
+// The same transaction has its signature verified on admission, when the leader pre orders it and when the other
+// nodes validate its block. TransactionSignatureVerifier remembers a hash of each valid (tx hash, signature) pair, so an
+// entry takes the same memory whatever the size of the signature, evicting the least recently used beyond the configured
+// cache size, and verifies the rest of a transaction set with parallel workers.
+type TransactionSignatureVerifier struct {
+	workers   int
+	cacheSize int
+	logger    log.Logger
+
+	cache struct {
+		sync.Mutex
+		elements map[string]*list.Element
+		order    *list.List // the most recently used key is at the front
+	}
+
+	metrics struct {
+		cacheHits     *metric.Gauge
+		verifications *metric.Gauge
+	}
+}
+
+func NewTransactionSignatureVerifier(config config.TransactionSignatureVerifierConfig, logger log.Logger, metricFactory metric.Factory) *TransactionSignatureVerifier {
+	v := &TransactionSignatureVerifier{
+		workers:   int(config.TransactionSignatureVerificationWorkers()),
+		cacheSize: int(config.TransactionSignatureCacheSize()),
+		logger:    logger,
+	}
+	v.cache.elements = make(map[string]*list.Element)
+	v.cache.order = list.New()
+	v.metrics.cacheHits = metricFactory.NewGauge("TransactionSignatures.CacheHits.Count")
+	v.metrics.verifications = metricFactory.NewGauge("TransactionSignatures.Verifications.Count")
+	return v
+}
+
+func (v *TransactionSignatureVerifier) VerifyTransactionSignature(signedTransaction *protocol.SignedTransaction) protocol.TransactionStatus {
+	txHash := digest.CalcTxHash(signedTransaction.Transaction())
+	// the tx hash covers the signer, so a signature valid once for a tx hash is always valid for it
+	key := signatureCacheKey(txHash, signedTransaction.Signature())
+	if v.isCached(key) {
+		v.metrics.cacheHits.Inc()
+		return protocol.TRANSACTION_STATUS_PRE_ORDER_VALID
+	}
+
+	v.metrics.verifications.Inc()
+	status := verifyTransactionSignature(signedTransaction, txHash)
+	if status == protocol.TRANSACTION_STATUS_PRE_ORDER_VALID {
+		v.addToCache(key)
+	}
+	return status
+}
+
+// VerifyTransactionSignatures sets the status of each transaction whose status is still TRANSACTION_STATUS_RESERVED
+func (v *TransactionSignatureVerifier) VerifyTransactionSignatures(signedTransactions []*protocol.SignedTransaction, resultStatuses []protocol.TransactionStatus) {
+	workers := v.workers
+	if workers > len(signedTransactions) {
+		workers = len(signedTransactions)
+	}
+	if workers < 2 {
+		for i, signedTransaction := range signedTransactions {
+			if resultStatuses[i] == protocol.TRANSACTION_STATUS_RESERVED {
+				resultStatuses[i] = v.VerifyTransactionSignature(signedTransaction)
+			}
+		}
+		return
+	}
+
+	next := int32(-1)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		govnr.Once(logfields.GovnrErrorer(v.logger), func() {
+			defer wg.Done()
+			for {
+				index := int(atomic.AddInt32(&next, 1))
+				if index >= len(signedTransactions) {
+					return
+				}
+				if resultStatuses[index] == protocol.TRANSACTION_STATUS_RESERVED {
+					resultStatuses[index] = v.VerifyTransactionSignature(signedTransactions[index])
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func signatureCacheKey(txHash primitives.Sha256, signature []byte) string {
+	return string(hash.CalcSha256(txHash, signature))
+}
+
+func (v *TransactionSignatureVerifier) isCached(key string) bool {
+	if v.cacheSize == 0 {
+		return false
+	}
+	v.cache.Lock()
+	defer v.cache.Unlock()
+
+	element, found := v.cache.elements[key]
+	if found {
+		v.cache.order.MoveToFront(element)
+	}
+	return found
+}
+
+func (v *TransactionSignatureVerifier) addToCache(key string) {
+	if v.cacheSize == 0 {
+		return
+	}
+	v.cache.Lock()
+	defer v.cache.Unlock()
+
+	if element, found := v.cache.elements[key]; found {
+		v.cache.order.MoveToFront(element)
+		return
+	}
+	v.cache.elements[key] = v.cache.order.PushFront(key)
+	for v.cache.order.Len() > v.cacheSize {
+		oldest := v.cache.order.Back()
+		v.cache.order.Remove(oldest)
+		delete(v.cache.elements, oldest.Value.(string))
+	}
+}
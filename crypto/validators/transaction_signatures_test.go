@@ -0,0 +1,113 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package validators
+
+import (
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/crypto/hash"
+	cryptoKeys "github.com/orbs-network/orbs-network-go/crypto/keys"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	testKeys "github.com/orbs-network/orbs-network-go/test/crypto/keys"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/scribe/log"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func newTransactionSignatureVerifierForTests(t *testing.T, workers uint32, cacheSize uint32) (*TransactionSignatureVerifier, metric.Registry) {
+	logger := log.GetLogger().WithOutput(log.NewTestOutput(t, log.NewHumanReadableFormatter()))
+	registry := metric.NewRegistry()
+	return NewTransactionSignatureVerifier(config.ForTransactionSignatureVerifierTests(workers, cacheSize), logger, registry), registry
+}
+
+func signedTransactionsForTests(count int) []*protocol.SignedTransaction {
+	var transactions []*protocol.SignedTransaction
+	for i := 0; i < count; i++ {
+		if i%3 == 2 {
+			transactions = append(transactions, builders.TransferTransaction().WithAmountAndTargetAddress(uint64(i), builders.ClientAddressForEd25519SignerForTests(2)).WithInvalidEd25519Signer(testKeys.Ed25519KeyPairForTests(1)).Build())
+		} else {
+			transactions = append(transactions, builders.TransferTransaction().WithAmountAndTargetAddress(uint64(i), builders.ClientAddressForEd25519SignerForTests(2)).Build())
+		}
+	}
+	return transactions
+}
+
+func gaugeValue(registry metric.Registry, name string) int64 {
+	return registry.Get(name).(*metric.Gauge).Value()
+}
+
+func TestTransactionSignatureVerifier_VerifiesInParallelLikeOneAtATime(t *testing.T) {
+	transactions := signedTransactionsForTests(20)
+	expected := make([]protocol.TransactionStatus, len(transactions))
+	expected[0] = protocol.TRANSACTION_STATUS_REJECTED_TIMESTAMP_WINDOW_EXCEEDED
+	actual := make([]protocol.TransactionStatus, len(transactions))
+	actual[0] = protocol.TRANSACTION_STATUS_REJECTED_TIMESTAMP_WINDOW_EXCEEDED
+
+	VerifyTransactionSignatures(transactions, expected)
+	verifier, registry := newTransactionSignatureVerifierForTests(t, 4, 100)
+	verifier.VerifyTransactionSignatures(transactions, actual)
+
+	require.Equal(t, expected, actual, "parallel verification should match verifying one transaction at a time")
+	require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_TIMESTAMP_WINDOW_EXCEEDED, actual[0], "a transaction which already failed should not be verified")
+	require.Equal(t, protocol.TRANSACTION_STATUS_PRE_ORDER_VALID, actual[1])
+	require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_SIGNATURE_MISMATCH, actual[2])
+	require.EqualValues(t, len(transactions)-1, gaugeValue(registry, "TransactionSignatures.Verifications.Count"))
+}
+
+func TestTransactionSignatureVerifier_DoesNotVerifyValidSignatureTwice(t *testing.T) {
+	transactions := signedTransactionsForTests(3)
+	verifier, registry := newTransactionSignatureVerifierForTests(t, 2, 100)
+
+	verifier.VerifyTransactionSignatures(transactions, make([]protocol.TransactionStatus, len(transactions)))
+	statuses := make([]protocol.TransactionStatus, len(transactions))
+	verifier.VerifyTransactionSignatures(transactions, statuses)
+
+	require.Equal(t, []protocol.TransactionStatus{protocol.TRANSACTION_STATUS_PRE_ORDER_VALID, protocol.TRANSACTION_STATUS_PRE_ORDER_VALID, protocol.TRANSACTION_STATUS_REJECTED_SIGNATURE_MISMATCH}, statuses)
+	require.EqualValues(t, 2, gaugeValue(registry, "TransactionSignatures.CacheHits.Count"), "valid signatures should be cached")
+	require.EqualValues(t, 4, gaugeValue(registry, "TransactionSignatures.Verifications.Count"), "invalid signatures should be verified every time")
+}
+
+func TestTransactionSignatureVerifier_EvictsLeastRecentlyUsedBeyondCacheSize(t *testing.T) {
+	transactions := signedTransactionsForTests(5) // the third is invalid
+	verifier, registry := newTransactionSignatureVerifierForTests(t, 1, 2)
+
+	verifier.VerifyTransactionSignature(transactions[0])
+	verifier.VerifyTransactionSignature(transactions[1])
+	verifier.VerifyTransactionSignature(transactions[0])
+	verifier.VerifyTransactionSignature(transactions[3]) // evicts transactions[1]
+	require.EqualValues(t, 1, gaugeValue(registry, "TransactionSignatures.CacheHits.Count"))
+
+	verifier.VerifyTransactionSignature(transactions[0])
+	verifier.VerifyTransactionSignature(transactions[3])
+	require.EqualValues(t, 3, gaugeValue(registry, "TransactionSignatures.CacheHits.Count"), "recently used signatures should stay cached")
+
+	verifier.VerifyTransactionSignature(transactions[1])
+	require.EqualValues(t, 3, gaugeValue(registry, "TransactionSignatures.CacheHits.Count"), "least recently used signature should be evicted")
+	require.EqualValues(t, 4, gaugeValue(registry, "TransactionSignatures.Verifications.Count"))
+}
+
+func TestTransactionSignatureVerifier_CacheCanBeDisabled(t *testing.T) {
+	transactions := signedTransactionsForTests(2)
+	verifier, registry := newTransactionSignatureVerifierForTests(t, 2, 0)
+
+	verifier.VerifyTransactionSignatures(transactions, make([]protocol.TransactionStatus, len(transactions)))
+	verifier.VerifyTransactionSignatures(transactions, make([]protocol.TransactionStatus, len(transactions)))
+
+	require.EqualValues(t, 0, gaugeValue(registry, "TransactionSignatures.CacheHits.Count"))
+	require.EqualValues(t, 4, gaugeValue(registry, "TransactionSignatures.Verifications.Count"))
+}
+
+func TestTransactionSignatureVerifier_CachesAFixedSizeKeyWhateverTheSignatureSize(t *testing.T) {
+	verifier, _ := newTransactionSignatureVerifierForTests(t, 1, 100)
+	transaction := builders.Transaction().WithMultiSigSigner(3, []*cryptoKeys.Ed25519KeyPair{testKeys.Ed25519KeyPairForTests(1), testKeys.Ed25519KeyPairForTests(2), testKeys.Ed25519KeyPairForTests(3)}, 0, 1, 2).Build()
+
+	require.Equal(t, protocol.TRANSACTION_STATUS_PRE_ORDER_VALID, verifier.VerifyTransactionSignature(transaction))
+	for key := range verifier.cache.elements {
+		require.Len(t, key, hash.SHA256_HASH_SIZE_BYTES, "a cached entry should not grow with the signature")
+	}
+}
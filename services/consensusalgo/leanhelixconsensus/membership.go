@@ -15,6 +15,7 @@ import (
 	"github.com/orbs-network/scribe/log"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 type membership struct {
@@ -22,6 +23,21 @@ type membership struct {
 	consensusContext services.ConsensusContext
 	logger           log.Logger
 	maxCommitteeSize uint32
+
+	currentTerm struct {
+		sync.RWMutex
+		height primitives.BlockHeight
+	}
+	nextTerm struct {
+		sync.Mutex
+		height           primitives.BlockHeight
+		orderedCommittee []primitives.NodeAddress
+	}
+}
+
+// implemented by the consensus context service, the committee of the next term is only needed to forward transactions
+type nextOrderingCommitteeProvider interface {
+	RequestNextOrderingCommittee(ctx context.Context, input *services.RequestCommitteeInput) (*services.RequestCommitteeOutput, error)
 }
 
 func NewMembership(logger log.Logger, memberId primitives.NodeAddress, consensusContext services.ConsensusContext, maxCommitteeSize uint32) *membership {
@@ -59,6 +75,7 @@ func (m *membership) RequestOrderedCommittee(ctx context.Context, blockHeight lh
 		return nil, err
 	}
 
+	m.recordTerm(primitives.BlockHeight(blockHeight))
 	nodeAddresses := toMemberIds(res.NodeAddresses)
 	committeeMembersStr := nodeAddressesToCommaSeparatedString(res.NodeAddresses)
 	// random-seed printed as string for logz.io, do not change it back to log.Uint64()
@@ -67,6 +84,50 @@ func (m *membership) RequestOrderedCommittee(ctx context.Context, blockHeight lh
 	return nodeAddresses, nil
 }
 
+// committees of earlier heights are requested when validating synced blocks, only the latest term is kept
+func (m *membership) recordTerm(height primitives.BlockHeight) {
+	m.currentTerm.Lock()
+	defer m.currentTerm.Unlock()
+
+	if height > m.currentTerm.height {
+		m.currentTerm.height = height
+	}
+}
+
+// returns the committee of the height after the current term ordered by the views its members lead, transactions
+// forwarded during a term usually miss its proposal. Nothing is returned before the first term or on failure
+func (m *membership) upcomingLeaders(ctx context.Context) (primitives.BlockHeight, []primitives.NodeAddress) {
+	m.currentTerm.RLock()
+	currentHeight := m.currentTerm.height
+	m.currentTerm.RUnlock()
+	if currentHeight == 0 {
+		return 0, nil
+	}
+
+	m.nextTerm.Lock()
+	defer m.nextTerm.Unlock()
+
+	if m.nextTerm.height == currentHeight+1 {
+		return m.nextTerm.height, m.nextTerm.orderedCommittee
+	}
+
+	provider, ok := m.consensusContext.(nextOrderingCommitteeProvider)
+	if !ok {
+		return 0, nil
+	}
+	res, err := provider.RequestNextOrderingCommittee(ctx, &services.RequestCommitteeInput{
+		CurrentBlockHeight: currentHeight,
+		MaxCommitteeSize:   m.maxCommitteeSize,
+	})
+	if err != nil {
+		m.logger.Info("failed requesting the committee of the next term", logfields.BlockHeight(currentHeight), log.Error(err))
+		return 0, nil
+	}
+
+	m.nextTerm.height, m.nextTerm.orderedCommittee = currentHeight+1, res.NodeAddresses
+	return m.nextTerm.height, m.nextTerm.orderedCommittee
+}
+
 func toMemberIds(nodeAddresses []primitives.NodeAddress) []lhprimitives.MemberId {
 	memberIds := make([]lhprimitives.MemberId, 0, len(nodeAddresses))
 	for _, nodeAddress := range nodeAddresses {
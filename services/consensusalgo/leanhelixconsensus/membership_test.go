@@ -7,7 +7,11 @@
 package leanhelixconsensus
 
 import (
+	"context"
 	"github.com/orbs-network/orbs-network-go/test/crypto/keys"
+	"github.com/orbs-network/orbs-network-go/test/with"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/services"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
@@ -31,3 +35,38 @@ func TestNodeAddressesToCommaSeparatedString_NilArray(t *testing.T) {
 func TestNodeAddressesToCommaSeparatedString(t *testing.T) {
 	require.True(t, len(nodeAddressesToCommaSeparatedString(keys.NodeAddressesForTests())) > 0, "returned zero length string although input is not empty")
 }
+
+type nextCommitteeConsensusContextMock struct {
+	services.MockConsensusContext
+	requestedHeights []primitives.BlockHeight
+	committee        []primitives.NodeAddress
+}
+
+func (c *nextCommitteeConsensusContextMock) RequestNextOrderingCommittee(ctx context.Context, input *services.RequestCommitteeInput) (*services.RequestCommitteeOutput, error) {
+	c.requestedHeights = append(c.requestedHeights, input.CurrentBlockHeight)
+	return &services.RequestCommitteeOutput{NodeAddresses: c.committee}, nil
+}
+
+func TestMembership_UpcomingLeadersAreTheCommitteeOfTheHeightAfterTheLatestTerm(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(harness *with.LoggingHarness) {
+			nodeAddresses := keys.NodeAddressesForTests()
+			consensusContext := &nextCommitteeConsensusContextMock{committee: nodeAddresses[:4]}
+			m := NewMembership(harness.Logger, nodeAddresses[0], consensusContext, 4)
+
+			height, committee := m.upcomingLeaders(ctx)
+			require.EqualValues(t, 0, height, "there should be no upcoming leaders before the first term")
+			require.Empty(t, committee)
+
+			m.recordTerm(10)
+			m.recordTerm(7)
+
+			height, committee = m.upcomingLeaders(ctx)
+			require.EqualValues(t, 11, height, "committees of earlier heights requested during block sync should be ignored")
+			require.Equal(t, nodeAddresses[:4], committee)
+
+			m.upcomingLeaders(ctx)
+			require.Equal(t, []primitives.BlockHeight{10}, consensusContext.requestedHeights, "the committee of the next term should be requested once")
+		})
+	})
+}
@@ -90,6 +90,7 @@ func NewLeanHelixConsensusAlgo(
 	s := &Service{
 		com:           com,
 		blockStorage:  blockStorage,
+		membership:    membership,
 		logger:        logger,
 		config:        config,
 		blockProvider: provider,
@@ -211,6 +212,12 @@ func (s *Service) HandleLeanHelixMessage(ctx context.Context, input *gossiptopic
 	return nil, nil
 }
 
+// UpcomingLeaders returns the committee of the height after the latest term this node took part in, ordered by the views
+// its members lead
+func (s *Service) UpcomingLeaders(ctx context.Context) (primitives.BlockHeight, []primitives.NodeAddress) {
+	return s.membership.upcomingLeaders(ctx)
+}
+
 func (s *Service) onCommit(ctx context.Context, block lh.Block, blockProof []byte) error {
 	logger := s.logger.WithTags(trace.LogFieldFrom(ctx))
 	logger.Info("YEYYYY CONSENSUS!!!! will save to block storage", logfields.BlockHeight(primitives.BlockHeight(block.Height())))
@@ -24,7 +24,7 @@ func (s *service) getOrderedCommittee(ctx context.Context, currentBlockHeight pr
 
 	// current block is used as seed and needs to be for the block being calculated Now.
 	logger.Info("system-call GetOrderedCommittee", logfields.BlockHeight(currentBlockHeight))
-	orderedCommittee, err := s.callGetOrderedCommitteeSystemContract(ctx, currentBlockHeight)
+	orderedCommittee, err := s.callGetOrderedCommitteeSystemContract(ctx, currentBlockHeight, committee_systemcontract.METHOD_GET_ORDERED_COMMITTEE)
 	if err != nil {
 		return nil, err
 	}
@@ -33,9 +33,9 @@ func (s *service) getOrderedCommittee(ctx context.Context, currentBlockHeight pr
 	return orderedCommittee, nil
 }
 
-func (s *service) callGetOrderedCommitteeSystemContract(ctx context.Context, blockHeight primitives.BlockHeight) ([]primitives.NodeAddress, error) {
+func (s *service) callGetOrderedCommitteeSystemContract(ctx context.Context, blockHeight primitives.BlockHeight, method string) ([]primitives.NodeAddress, error) {
 	systemContractName := primitives.ContractName(committee_systemcontract.CONTRACT_NAME)
-	systemMethodName := primitives.MethodName(committee_systemcontract.METHOD_GET_ORDERED_COMMITTEE)
+	systemMethodName := primitives.MethodName(method)
 
 	output, err := s.virtualMachine.CallSystemContract(ctx, &services.CallSystemContractInput{
 		BlockHeight:        blockHeight,
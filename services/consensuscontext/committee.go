@@ -11,6 +11,7 @@ import (
 	lhprimitives "github.com/orbs-network/lean-helix-go/spec/types/go/primitives"
 	"github.com/orbs-network/orbs-network-go/instrumentation/logfields"
 	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
+	"github.com/orbs-network/orbs-network-go/services/processor/native/repository/Committee"
 	"github.com/orbs-network/orbs-spec/types/go/services"
 	"github.com/orbs-network/scribe/log"
 	"strings"
@@ -40,3 +41,18 @@ func (s *service) RequestValidationCommittee(ctx context.Context, input *service
 	}
 	return res, nil
 }
+
+// RequestNextOrderingCommittee is offered beside services.ConsensusContext, it returns the committee of the block after
+// input.CurrentBlockHeight ordered by the views its members lead. The committee is read from the state the current block
+// is built on so it is known before the current block is committed, an election in the current block is not reflected
+func (s *service) RequestNextOrderingCommittee(ctx context.Context, input *services.RequestCommitteeInput) (*services.RequestCommitteeOutput, error) {
+	committee, err := s.callGetOrderedCommitteeSystemContract(ctx, input.CurrentBlockHeight, committee_systemcontract.METHOD_GET_NEXT_ORDERED_COMMITTEE)
+	if err != nil {
+		return nil, err
+	}
+
+	return &services.RequestCommitteeOutput{
+		NodeAddresses:            committee,
+		NodeRandomSeedPublicKeys: nil,
+	}, nil
+}
@@ -30,6 +30,7 @@ const LISTENER_HANDLE_TIMEOUT = 7 * time.Second
 var LogTag = log.String("adapter", "gossip")
 
 type message struct {
+	sender       primitives.NodeAddress
 	payloads     [][]byte
 	traceContext *trace.Context
 }
@@ -172,7 +173,7 @@ func (p *peer) send(ctx context.Context, data *adapter.TransportData) {
 	before := time.Now()
 	tracingContext, _ := trace.FromContext(ctx)
 	select {
-	case p.socket <- message{payloads: data.Payloads, traceContext: tracingContext}:
+	case p.socket <- message{sender: data.SenderNodeAddress, payloads: data.Payloads, traceContext: tracingContext}:
 		p.logger.Info("deposited message into peer queue", trace.LogFieldFrom(ctx), log.Stringable("duration", time.Since(before)))
 		return
 	case <-ctx.Done():
@@ -201,7 +202,7 @@ func receive(bgCtx context.Context, listener adapter.TransportListener, message
 	ctx, cancel := context.WithTimeout(bgCtx, LISTENER_HANDLE_TIMEOUT)
 	defer cancel()
 	traceContext := contextFrom(ctx, message)
-	listener.OnTransportMessageReceived(traceContext, message.payloads)
+	listener.OnTransportMessageReceived(adapter.ContextWithSenderNodeAddress(traceContext, message.sender), message.payloads)
 }
 
 func contextFrom(ctx context.Context, message message) context.Context {
@@ -0,0 +1,233 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package tcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"github.com/orbs-network/govnr"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/crypto/signer"
+	"github.com/orbs-network/orbs-network-go/instrumentation/logfields"
+	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/pkg/errors"
+	"net"
+	"time"
+)
+
+// In bidirectional mode every pair of peers shares a single full-duplex connection. The tie is broken by node address:
+// the peer with the lower address dials and identifies itself with a handshake message, the other peer accepts the
+// connection and from then on writes its outgoing queue for that peer to the accepted socket instead of dialing.
+// Since the accepting peer sends to whoever completes the handshake, the handshake is authenticated: the connection
+// must come from a host of the claimed peer in the topology, and the dialer must sign a random challenge of the
+// accepting peer, bound to both node addresses, with its node key.
+
+const BIDIRECTIONAL_CHALLENGE_SIZE = 32
+
+var bidirectionalHandshakeMagic = []byte("orbs-gossip-bidirectional-handshake")
+var bidirectionalChallengeMagic = []byte("orbs-gossip-bidirectional-challenge")
+var bidirectionalResponseMagic = []byte("orbs-gossip-bidirectional-response")
+
+type connectionReceiver interface {
+	receiveFromConnection(ctx context.Context, conn net.Conn, acceptHandshake bool)
+}
+
+type acceptedConnectionHandler interface {
+	thisNodeAddress() primitives.NodeAddress
+	acceptConnection(peerNodeAddress primitives.NodeAddress, conn net.Conn) (release func(), err error)
+}
+
+func dialsInBidirectionalMode(nodeAddress primitives.NodeAddress, peerNodeAddress primitives.NodeAddress) bool {
+	return bytes.Compare(nodeAddress, peerNodeAddress) < 0
+}
+
+func bidirectionalHandshake(nodeAddress primitives.NodeAddress) [][]byte {
+	return [][]byte{bidirectionalHandshakeMagic, nodeAddress}
+}
+
+func parseBidirectionalHandshake(payloads [][]byte) (primitives.NodeAddress, bool) {
+	return parseBidirectionalMessage(bidirectionalHandshakeMagic, payloads)
+}
+
+func parseBidirectionalMessage(magic []byte, payloads [][]byte) ([]byte, bool) {
+	if len(payloads) != 2 || !bytes.Equal(payloads[0], magic) || len(payloads[1]) == 0 {
+		return nil, false
+	}
+	return payloads[1], true
+}
+
+// the data the dialer signs, so that a signature is only good for the connection between the two peers it was made for
+func bidirectionalChallengeSignedData(challenge []byte, dialerNodeAddress primitives.NodeAddress, acceptorNodeAddress primitives.NodeAddress) []byte {
+	return bytes.Join([][]byte{bidirectionalResponseMagic, challenge, dialerNodeAddress, acceptorNodeAddress}, nil)
+}
+
+func isHostOfPeer(topology topologyHosts, host string, peerNodeAddress primitives.NodeAddress) bool {
+	for _, nodeAddress := range topology.nodeAddressesAtHost(host) {
+		if nodeAddress.Equal(peerNodeAddress) {
+			return true
+		}
+	}
+	return false
+}
+
+// the accepting side of the handshake, fails unless the dialer proves it holds the key of the node address it claims
+func (t *transportServer) authenticateBidirectionalPeer(ctx context.Context, conn net.Conn, host string, claimedNodeAddress primitives.NodeAddress) error {
+	if t.topology == nil || !isHostOfPeer(t.topology, host, claimedNodeAddress) {
+		return errors.Errorf("bidirectional handshake of peer %s from host %s which is not its host in topology", claimedNodeAddress, host)
+	}
+
+	challenge := make([]byte, BIDIRECTIONAL_CHALLENGE_SIZE)
+	if _, err := rand.Read(challenge); err != nil {
+		return errors.Wrap(err, "failed generating bidirectional handshake challenge")
+	}
+	if err := writeTransportData(ctx, conn, [][]byte{bidirectionalChallengeMagic, challenge}, t.config.GossipNetworkTimeout()); err != nil {
+		return errors.Wrap(err, "failed sending bidirectional handshake challenge")
+	}
+
+	payloads, err := t.receiveTransportData(ctx, conn)
+	if err != nil {
+		return errors.Wrap(err, "failed receiving bidirectional handshake response")
+	}
+	signature, isResponse := parseBidirectionalMessage(bidirectionalResponseMagic, payloads)
+	if !isResponse {
+		return errors.Errorf("peer %s did not respond to bidirectional handshake challenge", claimedNodeAddress)
+	}
+	signedData := bidirectionalChallengeSignedData(challenge, claimedNodeAddress, t.acceptedConnections.thisNodeAddress())
+	if err := digest.VerifyNodeSignature(claimedNodeAddress, signedData, signature); err != nil {
+		return errors.Wrapf(err, "bidirectional handshake response is not signed by peer %s", claimedNodeAddress)
+	}
+	return nil
+}
+
+// the dialing side of the handshake
+func (c *outgoingConnection) authenticateToPeer(ctx context.Context, conn net.Conn) error {
+	if c.signer == nil {
+		return errors.New("no signer to authenticate bidirectional handshake with")
+	}
+
+	timeout := c.config.GossipNetworkTimeout()
+	if err := writeTransportData(ctx, conn, bidirectionalHandshake(c.nodeAddress), timeout); err != nil {
+		return errors.Wrap(err, "failed sending bidirectional handshake")
+	}
+
+	payloads, err := readTransportData(ctx, conn, timeout)
+	if err != nil {
+		return errors.Wrap(err, "failed receiving bidirectional handshake challenge")
+	}
+	challenge, isChallenge := parseBidirectionalMessage(bidirectionalChallengeMagic, payloads)
+	if !isChallenge || len(challenge) != BIDIRECTIONAL_CHALLENGE_SIZE {
+		return errors.New("peer did not send a bidirectional handshake challenge")
+	}
+
+	signature, err := c.signer.Sign(ctx, bidirectionalChallengeSignedData(challenge, c.nodeAddress, c.peerNodeAddress))
+	if err != nil {
+		return errors.Wrap(err, "failed signing bidirectional handshake challenge")
+	}
+	return writeTransportData(ctx, conn, [][]byte{bidirectionalResponseMagic, signature}, timeout)
+}
+
+func (c *outgoingConnections) thisNodeAddress() primitives.NodeAddress {
+	return c.nodeAddress
+}
+
+func (c *outgoingConnections) acceptConnection(peerNodeAddress primitives.NodeAddress, conn net.Conn) (func(), error) {
+	c.RLock()
+	client, found := c.activeConnections[peerNodeAddress.KeyForMap()]
+	c.RUnlock()
+
+	if !found {
+		return nil, errors.Errorf("bidirectional handshake from peer %s which is not in topology", peerNodeAddress)
+	}
+	if client.dials {
+		return nil, errors.Errorf("bidirectional handshake from peer %s which this node is supposed to dial", peerNodeAddress)
+	}
+
+	return client.acceptConnection(conn), nil
+}
+
+func (c *outgoingConnection) enableBidirectionalMode(nodeAddress primitives.NodeAddress, peerNodeAddress primitives.NodeAddress, receiver connectionReceiver, nodeSigner signer.Signer) {
+	c.nodeAddress = nodeAddress
+	c.peerNodeAddress = peerNodeAddress
+	c.receiver = receiver
+	c.signer = nodeSigner
+	c.dials = dialsInBidirectionalMode(nodeAddress, peerNodeAddress)
+}
+
+// instead of dialing, waits for the peer to connect to us and only cleans up once disconnected
+func (c *outgoingConnection) waitForAcceptedConnections(ctx context.Context) {
+	handle := govnr.Forever(ctx, fmt.Sprintf("TCP accepted connection for %s", c.peerHexAddress), logfields.GovnrErrorer(c.logger), func() {
+		<-ctx.Done()
+		c.accepted.Lock()
+		defer c.accepted.Unlock()
+		if c.accepted.done != nil {
+			<-c.accepted.done
+		}
+		c.onDisconnect(c.logger)
+	})
+	c.closed = handle.Done()
+	handle.MarkSupervised()
+}
+
+// writes the outgoing queue to a connection the peer dialed, replacing the previous one since the peer only reconnects
+// once it has given up on it; returns a func that stops writing once the peer stops reading
+func (c *outgoingConnection) acceptConnection(conn net.Conn) func() {
+	c.accepted.Lock()
+	defer c.accepted.Unlock()
+
+	if c.accepted.cancel != nil {
+		c.accepted.cancel()
+		<-c.accepted.done // so that the previous writer disables the queue before the new one enables it
+	}
+
+	ctx, cancel := context.WithCancel(c.ctx)
+	done := make(chan struct{})
+	c.accepted.cancel, c.accepted.done = cancel, done
+
+	govnr.Once(logfields.GovnrErrorer(c.logger), func() {
+		defer close(done)
+		c.handleOutgoingConnection(trace.NewContext(ctx, fmt.Sprintf("Gossip.Transport.TCP.Accepted.%s", c.peerHexAddress)), conn)
+	})
+
+	return cancel
+}
+
+// the dialing side of a bidirectional connection also reads from it; returns true if should attempt reconnect
+func (c *outgoingConnection) handleDialedConnection(ctx context.Context, conn net.Conn) bool {
+	if c.receiver == nil {
+		return c.handleOutgoingConnection(ctx, conn)
+	}
+
+	if err := c.authenticateToPeer(ctx, conn); err != nil {
+		_ = conn.Close()
+		c.reconnectAfterSocketError(c.logger, err)
+		return c.waitBeforeReconnecting(ctx)
+	}
+
+	writerCtx, stopWriting := context.WithCancel(ctx)
+	govnr.Once(logfields.GovnrErrorer(c.logger), func() {
+		c.receiver.receiveFromConnection(ctx, conn, false)
+		stopWriting()
+	})
+
+	c.handleOutgoingConnection(writerCtx, conn)
+	stopWriting()
+	return c.waitBeforeReconnecting(ctx)
+}
+
+// the peer closes a connection whose handshake it rejects, possibly only after reading our response, and a peer which
+// banned us closes it on accept, so redialing at once would turn either into a hot loop
+func (c *outgoingConnection) waitBeforeReconnecting(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(c.config.GossipReconnectInterval()):
+		return true
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"context"
 	"github.com/orbs-network/govnr"
 	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/crypto/signer"
 	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
 	"github.com/orbs-network/orbs-network-go/services/gossip/adapter"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
@@ -40,6 +41,12 @@ func NewDirectTransport(parentCtx context.Context, config config.GossipTransport
 		server:              newServer(config, parentLogger.WithTags(log.String("component", "tcp-transport-server")), registry),
 	}
 
+	t.server.topology = t.outgoingConnections
+	if config.GossipBidirectionalConnectionsEnabled() {
+		t.outgoingConnections.receiver = t.server
+		t.server.acceptedConnections = t.outgoingConnections
+	}
+
 	t.Supervise(t.server)
 	t.Supervise(t.outgoingConnections)
 
@@ -49,6 +56,14 @@ func NewDirectTransport(parentCtx context.Context, config config.GossipTransport
 	return t
 }
 
+// in bidirectional mode the node signs handshakes with its node key, so the signer must be set before the topology
+func (t *DirectTransport) SetSigner(nodeSigner signer.Signer) {
+	t.outgoingConnections.Lock()
+	defer t.outgoingConnections.Unlock()
+
+	t.outgoingConnections.signer = nodeSigner
+}
+
 func (t *DirectTransport) UpdateTopology(bgCtx context.Context, newPeers adapter.GossipPeers) {
 	t.outgoingConnections.updateTopology(bgCtx, newPeers)
 }
@@ -72,6 +87,15 @@ func (t *DirectTransport) IsServerListening() bool {
 	return t.server.IsListening()
 }
 
+// IsConnectedTo returns false while the queue to the peer is disabled, i.e. until a connection to it is established
+func (t *DirectTransport) IsConnectedTo(nodeAddress primitives.NodeAddress) bool {
+	t.outgoingConnections.RLock()
+	defer t.outgoingConnections.RUnlock()
+
+	client, found := t.outgoingConnections.activeConnections[nodeAddress.KeyForMap()]
+	return found && !client.queue.disabled()
+}
+
 func (t *DirectTransport) allOutgoingQueuesEnabled() bool {
 	t.outgoingConnections.RLock()
 	defer t.outgoingConnections.RUnlock()
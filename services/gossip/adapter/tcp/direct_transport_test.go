@@ -11,6 +11,7 @@ import (
 	"encoding/hex"
 	"github.com/orbs-network/govnr"
 	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/crypto/signer"
 	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
 	"github.com/orbs-network/orbs-network-go/services/gossip/adapter"
 	"github.com/orbs-network/orbs-network-go/services/gossip/adapter/testkit"
@@ -21,6 +22,7 @@ import (
 	"github.com/orbs-network/orbs-spec/types/go/protocol/gossipmessages"
 	"github.com/orbs-network/scribe/log"
 	"github.com/stretchr/testify/require"
+	"net"
 	"testing"
 	"time"
 )
@@ -181,6 +183,109 @@ func TestDirectTransport_FailsGracefullyIfMulticastFailedToSendToASingleRecipien
 	})
 }
 
+func TestDirectTransport_BidirectionalMode_SharesOneConnectionPerPeerPair(t *testing.T) {
+	with.Concurrency(t, func(ctx context.Context, harness *with.ConcurrencyHarness) {
+		node1 := aBidirectionalNode(ctx, harness.Logger)
+		node2 := aBidirectionalNode(ctx, harness.Logger)
+		node3 := aBidirectionalNode(ctx, harness.Logger)
+		superviseAll(harness, node1, node2, node3)
+		defer shutdownAll(ctx, node1, node2, node3)
+
+		waitForAllNodesToSatisfy(t, "server did not start", func(node *nodeHarness) bool { return node.transport.IsServerListening() }, node1, node2, node3)
+
+		topology := aTopologyContaining(node1, node2, node3)
+		node1.updateTopology(ctx, topology)
+		node2.updateTopology(ctx, topology)
+		node3.updateTopology(ctx, topology)
+
+		waitForAllNodesToSatisfy(t,
+			"expected all outgoing queues to become enabled once peers connected",
+			func(node *nodeHarness) bool { return node.transport.allOutgoingQueuesEnabled() },
+			node1, node2, node3)
+
+		node1.requireSendsSuccessfullyTo(t, ctx, node2)
+		node2.requireSendsSuccessfullyTo(t, ctx, node1)
+		node2.requireSendsSuccessfullyTo(t, ctx, node3)
+		node3.requireSendsSuccessfullyTo(t, ctx, node2)
+		node3.requireSendsSuccessfullyTo(t, ctx, node1)
+		node1.requireSendsSuccessfullyTo(t, ctx, node3)
+
+		incomingConnections := int64(0)
+		for _, node := range []*nodeHarness{node1, node2, node3} {
+			incomingConnections += node.transport.server.metrics.activeConnections.Value()
+		}
+		require.EqualValues(t, 3, incomingConnections, "expected a single connection per pair of nodes")
+	})
+}
+
+func TestBidirectionalHandshake_DialingIsDecidedByNodeAddress(t *testing.T) {
+	lower, higher := primitives.NodeAddress{0x01, 0x02}, primitives.NodeAddress{0x01, 0x03}
+
+	require.True(t, dialsInBidirectionalMode(lower, higher))
+	require.False(t, dialsInBidirectionalMode(higher, lower))
+
+	peer, isHandshake := parseBidirectionalHandshake(bidirectionalHandshake(lower))
+	require.True(t, isHandshake)
+	require.EqualValues(t, lower, peer)
+
+	_, isHandshake = parseBidirectionalHandshake(aMessage())
+	require.False(t, isHandshake, "a regular message should not be mistaken for a handshake")
+}
+
+type acceptorOf primitives.NodeAddress
+
+func (a acceptorOf) thisNodeAddress() primitives.NodeAddress {
+	return primitives.NodeAddress(a)
+}
+
+func (a acceptorOf) acceptConnection(peerNodeAddress primitives.NodeAddress, conn net.Conn) (func(), error) {
+	return func() {}, nil
+}
+
+func authenticateBidirectionalHandshake(t *testing.T, ctx context.Context, logger log.Logger, dialerSigner signer.Signer, topology topologyHosts) error {
+	dialer, acceptor := keys.EcdsaSecp256K1KeyPairForTests(1).NodeAddress(), keys.EcdsaSecp256K1KeyPairForTests(2).NodeAddress()
+
+	server := newServer(&serverCfg{}, logger, metric.NewRegistry())
+	server.topology = topology
+	server.acceptedConnections = acceptorOf(acceptor)
+	client := &outgoingConnection{config: &timeouts{}, nodeAddress: dialer, peerNodeAddress: acceptor, signer: dialerSigner}
+
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+
+	dialerDone := make(chan error, 1)
+	go func() {
+		dialerDone <- client.authenticateToPeer(ctx, clientConn)
+	}()
+
+	payloads, err := server.receiveTransportData(ctx, serverConn)
+	require.NoError(t, err)
+	claimedNodeAddress, isHandshake := parseBidirectionalHandshake(payloads)
+	require.True(t, isHandshake)
+	require.EqualValues(t, dialer, claimedNodeAddress)
+
+	err = server.authenticateBidirectionalPeer(ctx, serverConn, "127.0.0.1", claimedNodeAddress)
+	serverConn.Close()
+	<-dialerDone
+	return err
+}
+
+func TestBidirectionalHandshake_AuthenticatesDialerByItsNodeKey(t *testing.T) {
+	with.Logging(t, func(harness *with.LoggingHarness) {
+		ctx := context.Background()
+		dialer := keys.EcdsaSecp256K1KeyPairForTests(1)
+		topology := topologyHostsOf{dialer.NodeAddress()}
+
+		require.NoError(t, authenticateBidirectionalHandshake(t, ctx, harness.Logger, signer.NewLocalSigner(dialer.PrivateKey()), topology))
+
+		impostor := signer.NewLocalSigner(keys.EcdsaSecp256K1KeyPairForTests(3).PrivateKey())
+		require.Error(t, authenticateBidirectionalHandshake(t, ctx, harness.Logger, impostor, topology), "a dialer which does not hold the key of the node address it claims should be rejected")
+
+		require.Error(t, authenticateBidirectionalHandshake(t, ctx, harness.Logger, signer.NewLocalSigner(dialer.PrivateKey()), topologyHostsOf{}), "a dialer connecting from a host which is not its host in topology should be rejected")
+	})
+}
+
 type nodeHarness struct {
 	transport        *DirectTransport
 	address          primitives.NodeAddress
@@ -240,6 +345,25 @@ func aNode(ctx context.Context, logger log.Logger) *nodeHarness {
 	return &nodeHarness{transport, address, listener}
 }
 
+type bidirectionalTransportConfig struct {
+	config.GossipTransportConfig
+}
+
+func (c *bidirectionalTransportConfig) GossipBidirectionalConnectionsEnabled() bool {
+	return true
+}
+
+func aBidirectionalNode(ctx context.Context, logger log.Logger) *nodeHarness {
+	keyPair := keys.EcdsaSecp256K1KeyPairForTests(currentNodeIndex)
+	address := aKey()
+	cfg := config.ForDirectTransportTests(address, make(adapter.GossipPeers), 20*time.Hour /*disable keep alive*/, 1*time.Second)
+	transport := NewDirectTransport(ctx, &bidirectionalTransportConfig{cfg}, logger, metric.NewRegistry())
+	transport.SetSigner(signer.NewLocalSigner(keyPair.PrivateKey()))
+	listener := &testkit.MockTransportListener{}
+	transport.RegisterListener(listener, address)
+	return &nodeHarness{transport, address, listener}
+}
+
 var currentNodeIndex = 1
 
 func aKey() primitives.NodeAddress {
@@ -0,0 +1,294 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package tcp
+
+import (
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol/gossipmessages"
+	"github.com/orbs-network/scribe/log"
+	"github.com/pkg/errors"
+	"net"
+	"sync"
+	"time"
+)
+
+type inboundRateLimitConfig interface {
+	GossipInboundRateLimitEnabled() bool
+	GossipInboundRateLimitMessagesPerSecond() uint32
+	GossipInboundRateLimitBurst() uint32
+	GossipInboundConsensusRateLimitMessagesPerSecond() uint32
+	GossipInboundConsensusRateLimitBurst() uint32
+	GossipInboundPeerBanDuration() time.Duration
+}
+
+type tokenBucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+func newTokenBucket(burst uint32, now time.Time) *tokenBucket {
+	return &tokenBucket{tokens: float64(burst), lastRefill: now}
+}
+
+func (b *tokenBucket) take(now time.Time, ratePerSecond uint32, burst uint32) bool {
+	if elapsed := now.Sub(b.lastRefill); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * float64(ratePerSecond)
+		if b.tokens > float64(burst) {
+			b.tokens = float64(burst)
+		}
+		b.lastRefill = now
+	}
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// limits the messages a peer may deliver over all of its incoming connections, one token bucket per topic;
+// consensus topics have their own limit, where zero means they are not limited
+type inboundPeerRateLimiter struct {
+	sync.Mutex
+	config       inboundRateLimitConfig
+	buckets      map[gossipmessages.HeaderTopic]*tokenBucket
+	lastAdmitted time.Time
+
+	key         string // empty if the limiter belongs to a single connection
+	connections int    // guarded by the inboundPeerRateLimiters lock
+}
+
+func newInboundPeerRateLimiter(config inboundRateLimitConfig) *inboundPeerRateLimiter {
+	return &inboundPeerRateLimiter{
+		config:  config,
+		buckets: make(map[gossipmessages.HeaderTopic]*tokenBucket),
+	}
+}
+
+func isConsensusTopic(topic gossipmessages.HeaderTopic) bool {
+	return topic == gossipmessages.HEADER_TOPIC_LEAN_HELIX || topic == gossipmessages.HEADER_TOPIC_BENCHMARK_CONSENSUS
+}
+
+func (l *inboundPeerRateLimiter) limitOf(topic gossipmessages.HeaderTopic) (ratePerSecond uint32, burst uint32) {
+	if isConsensusTopic(topic) {
+		return l.config.GossipInboundConsensusRateLimitMessagesPerSecond(), l.config.GossipInboundConsensusRateLimitBurst()
+	}
+	return l.config.GossipInboundRateLimitMessagesPerSecond(), l.config.GossipInboundRateLimitBurst()
+}
+
+// returns the topic the payloads were sent on, or an error if the message is malformed or exceeds the topic's rate
+func (l *inboundPeerRateLimiter) admit(payloads [][]byte, now time.Time) (gossipmessages.HeaderTopic, error) {
+	header := gossipmessages.HeaderReader(payloads[0])
+	if !header.IsValid() {
+		return 0, &invalidHeaderError{errors.New("message header is corrupt")}
+	}
+
+	topic := header.Topic()
+	if topic > gossipmessages.HEADER_TOPIC_BENCHMARK_CONSENSUS {
+		return topic, &invalidHeaderError{errors.Errorf("message header has unknown topic %d", topic)}
+	}
+
+	ratePerSecond, burst := l.limitOf(topic)
+	if ratePerSecond == 0 {
+		return topic, nil
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	l.lastAdmitted = now
+	bucket, found := l.buckets[topic]
+	if !found {
+		bucket = newTokenBucket(burst, now)
+		l.buckets[topic] = bucket
+	}
+
+	if !bucket.take(now, ratePerSecond, burst) {
+		return topic, errors.Errorf("peer exceeded the rate limit of %d messages per second (burst %d) on topic %s", ratePerSecond, burst, header.StringTopic())
+	}
+
+	return topic, nil
+}
+
+// a limiter whose buckets have all refilled is no different from a new one
+func (l *inboundPeerRateLimiter) isIdle(now time.Time) bool {
+	l.Lock()
+	defer l.Unlock()
+
+	for topic := range l.buckets {
+		ratePerSecond, burst := l.limitOf(topic)
+		if now.Sub(l.lastAdmitted).Seconds()*float64(ratePerSecond) < float64(burst) {
+			return false
+		}
+	}
+	return true
+}
+
+// the rate limiters of peers by their ban key, so a peer can neither refill its buckets by reconnecting nor multiply
+// its limit by opening parallel connections
+type inboundPeerRateLimiters struct {
+	sync.Mutex
+	config inboundRateLimitConfig
+	byPeer map[string]*inboundPeerRateLimiter
+}
+
+func newInboundPeerRateLimiters(config inboundRateLimitConfig) *inboundPeerRateLimiters {
+	return &inboundPeerRateLimiters{
+		config: config,
+		byPeer: make(map[string]*inboundPeerRateLimiter),
+	}
+}
+
+// every acquired limiter must be released when its connection closes
+func (r *inboundPeerRateLimiters) acquire(peer *inboundPeer, now time.Time) *inboundPeerRateLimiter {
+	key, canBan := peer.banKey()
+	if !canBan { // the connections of a host shared by topology peers cannot be attributed to one of them
+		return newInboundPeerRateLimiter(r.config)
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	limiter, found := r.byPeer[key]
+	if !found {
+		r.removeIdle(now)
+		limiter = newInboundPeerRateLimiter(r.config)
+		limiter.key = key
+		r.byPeer[key] = limiter
+	}
+	limiter.connections++
+	return limiter
+}
+
+func (r *inboundPeerRateLimiters) release(limiter *inboundPeerRateLimiter) {
+	if limiter.key == "" {
+		return
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	limiter.connections--
+}
+
+func (r *inboundPeerRateLimiters) removeIdle(now time.Time) {
+	for key, limiter := range r.byPeer {
+		if limiter.connections == 0 && limiter.isIdle(now) {
+			delete(r.byPeer, key)
+		}
+	}
+}
+
+func (r *inboundPeerRateLimiters) count() int {
+	r.Lock()
+	defer r.Unlock()
+
+	return len(r.byPeer)
+}
+
+type invalidHeaderError struct {
+	error
+}
+
+func isInvalidHeader(err error) bool {
+	_, ok := err.(*invalidHeaderError)
+	return ok
+}
+
+// An incoming connection is identified by the node address of the single topology peer at its remote host, since
+// incoming connections reconnect from a different port. A host which is not in the topology is identified by the host
+// itself, while a host shared by several topology peers (e.g. behind a NAT) cannot be attributed to one of them, so
+// a misbehaving connection from it is disconnected without banning the honest peers sharing it.
+type inboundPeer struct {
+	host        string
+	nodeAddress primitives.NodeAddress // nil unless the host belongs to a single topology peer
+	sharedHost  bool
+}
+
+func newInboundPeer(host string, topologyNodeAddresses []primitives.NodeAddress) *inboundPeer {
+	peer := &inboundPeer{host: host}
+	switch len(topologyNodeAddresses) {
+	case 0:
+	case 1:
+		peer.nodeAddress = topologyNodeAddresses[0]
+	default:
+		peer.sharedHost = true
+	}
+	return peer
+}
+
+// returns false if the peer cannot be banned without banning others
+func (p *inboundPeer) banKey() (string, bool) {
+	if p.nodeAddress != nil {
+		return "node-address:" + p.nodeAddress.KeyForMap(), true
+	}
+	if p.sharedHost {
+		return "", false
+	}
+	return "host:" + p.host, true
+}
+
+func (p *inboundPeer) logFields() []*log.Field {
+	fields := []*log.Field{log.String("peer-host", p.host)}
+	if p.nodeAddress != nil {
+		fields = append(fields, log.Stringable("peer-node-address", p.nodeAddress))
+	}
+	return fields
+}
+
+type bannedPeers struct {
+	sync.Mutex
+	until map[string]time.Time
+}
+
+func newBannedPeers() *bannedPeers {
+	return &bannedPeers{until: make(map[string]time.Time)}
+}
+
+func (b *bannedPeers) ban(key string, now time.Time, duration time.Duration) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.until[key] = now.Add(duration)
+}
+
+func (b *bannedPeers) isBanned(key string, now time.Time) bool {
+	b.Lock()
+	defer b.Unlock()
+
+	until, found := b.until[key]
+	if !found {
+		return false
+	}
+	if now.After(until) {
+		delete(b.until, key)
+		return false
+	}
+	return true
+}
+
+func (b *bannedPeers) count(now time.Time) (res int) {
+	b.Lock()
+	defer b.Unlock()
+
+	for key, until := range b.until {
+		if now.After(until) {
+			delete(b.until, key)
+		} else {
+			res++
+		}
+	}
+	return
+}
+
+func peerHostOf(addr net.Addr) string {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
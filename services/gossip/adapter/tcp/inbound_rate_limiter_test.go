@@ -0,0 +1,154 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package tcp
+
+import (
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol/consensus"
+	"github.com/orbs-network/orbs-spec/types/go/protocol/gossipmessages"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func headerPayloadsForTopic(topic gossipmessages.HeaderTopic) [][]byte {
+	builder := &gossipmessages.HeaderBuilder{
+		RecipientMode: gossipmessages.RECIPIENT_LIST_MODE_BROADCAST,
+	}
+	switch topic {
+	case gossipmessages.HEADER_TOPIC_TRANSACTION_RELAY:
+		builder.Topic = gossipmessages.HEADER_TOPIC_TRANSACTION_RELAY
+		builder.TransactionRelay = gossipmessages.TRANSACTION_RELAY_FORWARDED_TRANSACTIONS
+	case gossipmessages.HEADER_TOPIC_BLOCK_SYNC:
+		builder.Topic = gossipmessages.HEADER_TOPIC_BLOCK_SYNC
+		builder.BlockSync = gossipmessages.BLOCK_SYNC_AVAILABILITY_REQUEST
+	case gossipmessages.HEADER_TOPIC_LEAN_HELIX:
+		builder.Topic = gossipmessages.HEADER_TOPIC_LEAN_HELIX
+		builder.LeanHelix = consensus.LEAN_HELIX_MESSAGE_TYPE_LEAN_HELIX
+	}
+	return [][]byte{builder.Build().Raw()}
+}
+
+func TestTokenBucket_RefillsOverTimeUpToBurst(t *testing.T) {
+	now := time.Now()
+	bucket := newTokenBucket(2, now)
+
+	require.True(t, bucket.take(now, 10, 2))
+	require.True(t, bucket.take(now, 10, 2))
+	require.False(t, bucket.take(now, 10, 2), "bucket should be empty after burst was consumed")
+
+	now = now.Add(100 * time.Millisecond)
+	require.True(t, bucket.take(now, 10, 2), "bucket should refill one token after 100ms at 10 per second")
+	require.False(t, bucket.take(now, 10, 2))
+
+	now = now.Add(1 * time.Hour)
+	require.True(t, bucket.take(now, 10, 2))
+	require.True(t, bucket.take(now, 10, 2))
+	require.False(t, bucket.take(now, 10, 2), "bucket should never hold more than burst")
+}
+
+func TestInboundPeerRateLimiter_LimitsEachTopicSeparately(t *testing.T) {
+	now := time.Now()
+	limiter := newInboundPeerRateLimiter(&serverCfg{rateLimitPerSecond: 1, rateLimitBurst: 1})
+
+	_, err := limiter.admit(headerPayloadsForTopic(gossipmessages.HEADER_TOPIC_TRANSACTION_RELAY), now)
+	require.NoError(t, err)
+	_, err = limiter.admit(headerPayloadsForTopic(gossipmessages.HEADER_TOPIC_BLOCK_SYNC), now)
+	require.NoError(t, err, "a different topic should have its own bucket")
+
+	topic, err := limiter.admit(headerPayloadsForTopic(gossipmessages.HEADER_TOPIC_TRANSACTION_RELAY), now)
+	require.Error(t, err, "second message on same topic should exceed the limit")
+	require.False(t, isInvalidHeader(err))
+	require.Equal(t, gossipmessages.HEADER_TOPIC_TRANSACTION_RELAY, topic)
+}
+
+func TestInboundPeerRateLimiter_LimitsConsensusTopicsSeparately(t *testing.T) {
+	now := time.Now()
+	consensusMessage := headerPayloadsForTopic(gossipmessages.HEADER_TOPIC_LEAN_HELIX)
+
+	unlimited := newInboundPeerRateLimiter(&serverCfg{rateLimitPerSecond: 1, rateLimitBurst: 1})
+	for i := 0; i < 10; i++ {
+		_, err := unlimited.admit(consensusMessage, now)
+		require.NoError(t, err, "consensus topics should not be limited when their limit is zero")
+	}
+
+	limited := newInboundPeerRateLimiter(&serverCfg{rateLimitPerSecond: 1, rateLimitBurst: 1, consensusRateLimit: 3})
+	for i := 0; i < 3; i++ {
+		_, err := limited.admit(consensusMessage, now)
+		require.NoError(t, err, "consensus topics should have their own limit")
+	}
+	_, err := limited.admit(consensusMessage, now)
+	require.Error(t, err)
+}
+
+func TestInboundPeerRateLimiters_ShareBucketsBetweenConnectionsOfAPeer(t *testing.T) {
+	now := time.Now()
+	limiters := newInboundPeerRateLimiters(&serverCfg{rateLimitPerSecond: 1, rateLimitBurst: 1})
+	peer := newInboundPeer("10.0.0.1", []primitives.NodeAddress{{0x01}})
+	relay := headerPayloadsForTopic(gossipmessages.HEADER_TOPIC_TRANSACTION_RELAY)
+
+	first := limiters.acquire(peer, now)
+	_, err := first.admit(relay, now)
+	require.NoError(t, err)
+
+	parallel := limiters.acquire(peer, now)
+	_, err = parallel.admit(relay, now)
+	require.Error(t, err, "a parallel connection should not get its own buckets")
+
+	limiters.release(first)
+	limiters.release(parallel)
+	reconnected := limiters.acquire(peer, now)
+	_, err = reconnected.admit(relay, now)
+	require.Error(t, err, "reconnecting should not refill the buckets")
+	limiters.release(reconnected)
+
+	limiters.acquire(newInboundPeer("10.0.0.2", nil), now.Add(1*time.Second))
+	require.Equal(t, 1, limiters.count(), "the limiter of a peer should be removed once it has no connections and its buckets refilled")
+}
+
+func TestInboundPeerRateLimiter_RejectsCorruptHeader(t *testing.T) {
+	limiter := newInboundPeerRateLimiter(&serverCfg{rateLimitPerSecond: 1, rateLimitBurst: 1})
+
+	_, err := limiter.admit([][]byte{{0x11}}, time.Now())
+	require.Error(t, err)
+	require.True(t, isInvalidHeader(err))
+}
+
+func TestBannedPeers_ExpireAfterDuration(t *testing.T) {
+	now := time.Now()
+	bans := newBannedPeers()
+
+	bans.ban("10.0.0.1", now, 1*time.Minute)
+	require.True(t, bans.isBanned("10.0.0.1", now.Add(30*time.Second)))
+	require.False(t, bans.isBanned("10.0.0.2", now))
+	require.Equal(t, 1, bans.count(now))
+
+	require.False(t, bans.isBanned("10.0.0.1", now.Add(2*time.Minute)), "ban should expire")
+	require.Equal(t, 0, bans.count(now.Add(2*time.Minute)))
+}
+
+func TestInboundPeer_IsBannedByTopologyNodeAddress(t *testing.T) {
+	unknown := newInboundPeer("10.0.0.1", nil)
+	key, canBan := unknown.banKey()
+	require.True(t, canBan)
+	require.Equal(t, "host:10.0.0.1", key, "a host outside the topology should be banned by host")
+
+	topologyPeer := newInboundPeer("10.0.0.1", []primitives.NodeAddress{{0x01}})
+	key, canBan = topologyPeer.banKey()
+	require.True(t, canBan)
+	require.Equal(t, "node-address:"+primitives.NodeAddress{0x01}.KeyForMap(), key, "a topology peer should be banned by node address")
+
+	_, canBan = newInboundPeer("10.0.0.1", []primitives.NodeAddress{{0x01}, {0x02}}).banKey()
+	require.False(t, canBan, "a host shared by several topology peers should not be banned")
+}
+
+func TestPeerEndpoint_IsHost(t *testing.T) {
+	require.True(t, resolvePeerEndpoint("10.0.0.1").isHost("10.0.0.1"))
+	require.True(t, resolvePeerEndpoint("::ffff:10.0.0.1").isHost("10.0.0.1"))
+	require.True(t, resolvePeerEndpoint("localhost").isHost("127.0.0.1"))
+	require.False(t, resolvePeerEndpoint("10.0.0.2").isHost("10.0.0.1"))
+}
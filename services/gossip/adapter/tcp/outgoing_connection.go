@@ -11,13 +11,16 @@ import (
 	"fmt"
 	"github.com/orbs-network/govnr"
 	"github.com/orbs-network/membuffers/go"
+	"github.com/orbs-network/orbs-network-go/crypto/signer"
 	"github.com/orbs-network/orbs-network-go/instrumentation/logfields"
 	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
 	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
 	"github.com/orbs-network/orbs-network-go/services/gossip/adapter"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/scribe/log"
 	"github.com/pkg/errors"
 	"net"
+	"sync"
 	"time"
 )
 
@@ -34,8 +37,21 @@ type outgoingConnection struct {
 	sharedMetrics  *outgoingConnectionMetrics // TODO this is smelly, see how we can restructure metrics so that an outgoing connection doesn't have to share the parent metrics
 	queue          *transportQueue
 	peerHexAddress string
+	ctx            context.Context
 	cancel         context.CancelFunc
 
+	// bidirectional mode, see bidirectional.go
+	nodeAddress     primitives.NodeAddress
+	peerNodeAddress primitives.NodeAddress
+	receiver        connectionReceiver
+	signer          signer.Signer
+	dials           bool
+	accepted        struct {
+		sync.Mutex
+		cancel context.CancelFunc
+		done   chan struct{}
+	}
+
 	sendErrors      *metric.Gauge
 	sendQueueErrors *metric.Gauge
 
@@ -59,6 +75,7 @@ func newOutgoingConnection(peer adapter.GossipPeer, parentLogger log.Logger, met
 		config:          transportConfig,
 		queue:           queue,
 		peerHexAddress:  hexAddressSliceForLogging,
+		dials:           true,
 		sendErrors:      metricFactory.NewGauge(fmt.Sprintf("Gossip.OutgoingConnection.SendError.%s.Count", hexAddressSliceForLogging)),
 		sendQueueErrors: metricFactory.NewGauge(fmt.Sprintf("Gossip.OutgoingConnection.EnqueueErrors.%s.Count", hexAddressSliceForLogging)),
 	}
@@ -68,8 +85,14 @@ func newOutgoingConnection(peer adapter.GossipPeer, parentLogger log.Logger, met
 
 func (c *outgoingConnection) connect(parent context.Context) {
 	ctx, cancel := context.WithCancel(parent)
+	c.ctx = ctx
 	c.cancel = cancel
 
+	if !c.dials {
+		c.waitForAcceptedConnections(ctx)
+		return
+	}
+
 	handle := govnr.Forever(ctx, fmt.Sprintf("TCP client for %s", c.peerHexAddress), logfields.GovnrErrorer(c.logger), func() {
 		c.connectionMainLoop(ctx)
 	})
@@ -99,7 +122,8 @@ func (c *outgoingConnection) connectionMainLoop(parentCtx context.Context) {
 			continue
 		}
 
-		if !c.handleOutgoingConnection(ctx, conn) {
+		if !c.handleDialedConnection(ctx, conn) {
+			c.onDisconnect(logger)
 			return
 		}
 	}
@@ -137,7 +161,7 @@ func (c *outgoingConnection) handleOutgoingConnection(ctx context.Context, conn
 		} else {
 			// parent ctx is closed, we're disconnecting
 			logger.Info("connection closing due to requested disconnect")
-			return c.onDisconnect(logger)
+			return false
 		}
 	}
 }
@@ -154,12 +178,11 @@ func shouldKeepAlive(ctx context.Context) bool {
 	return ctx.Err() == nil
 }
 
-func (c *outgoingConnection) onDisconnect(logger log.Logger) bool {
+func (c *outgoingConnection) onDisconnect(logger log.Logger) {
 	logger.Info("client loop stopped since a disconnect was requested (topology change or system shutdown)")
 	c.metricRegistry.Remove(c.sendErrors)
 	c.metricRegistry.Remove(c.sendQueueErrors)
 	c.metricRegistry.Remove(c.queue.usagePercentageMetric)
-	return false
 }
 
 func (c *outgoingConnection) reconnectAfterKeepAliveFailure(logger log.Logger, err error) bool {
@@ -185,18 +208,21 @@ func (c *outgoingConnection) addDataToOutgoingPeerQueue(ctx context.Context, dat
 }
 
 func (c *outgoingConnection) sendToSocket(ctx context.Context, conn net.Conn, data *adapter.TransportData) error {
-	timeout := c.config.GossipNetworkTimeout()
+	return writeTransportData(ctx, conn, data.Payloads, c.config.GossipNetworkTimeout())
+}
+
+func writeTransportData(ctx context.Context, conn net.Conn, payloads [][]byte, timeout time.Duration) error {
 	zeroBuffer := make([]byte, 4)
 	sizeBuffer := make([]byte, 4)
 
 	// send num payloads
-	membuffers.WriteUint32(sizeBuffer, uint32(len(data.Payloads)))
+	membuffers.WriteUint32(sizeBuffer, uint32(len(payloads)))
 	err := write(ctx, conn, sizeBuffer, timeout)
 	if err != nil {
 		return err
 	}
 
-	for _, payload := range data.Payloads {
+	for _, payload := range payloads {
 		// send payload size
 		membuffers.WriteUint32(sizeBuffer, uint32(len(payload)))
 		err := write(ctx, conn, sizeBuffer, timeout)
@@ -62,6 +62,34 @@ func TestOutgoingConnection_ReconnectsWhenServerDisconnects_AndSendKeepAlive(t *
 	})
 }
 
+type connectionReceiverStub struct{}
+
+func (r *connectionReceiverStub) receiveFromConnection(ctx context.Context, conn net.Conn, acceptHandshake bool) {
+}
+
+func TestOutgoingConnection_WaitsBeforeRedialing_WhenBidirectionalHandshakeFails(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+			server := newServerStub(t)
+			defer server.Close()
+
+			registry := metric.NewRegistry()
+			config := &timeouts{keepAliveInterval: 20 * time.Hour}
+			peer := adapter.NewGossipPeer(server.port, "127.0.0.1", "020000")
+			client := newOutgoingConnection(peer, parent.Logger, registry, createOutgoingConnectionMetrics(registry), config)
+			client.enableBidirectionalMode([]byte{0x01, 0x00, 0x00}, []byte{0x02, 0x00, 0x00}, &connectionReceiverStub{}, nil) // no signer, so authenticating fails
+			client.connect(ctx)
+			defer client.disconnect()
+
+			server.acceptClientConnection(t)
+			firstDialAt := time.Now()
+			server.acceptClientConnection(t)
+
+			require.True(t, time.Since(firstDialAt) >= config.GossipReconnectInterval(), "client should wait the reconnect interval before redialing after a failed handshake")
+		})
+	})
+}
+
 type timeouts struct {
 	keepAliveInterval time.Duration
 }
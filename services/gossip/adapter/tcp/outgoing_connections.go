@@ -3,12 +3,14 @@ package tcp
 import (
 	"context"
 	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/crypto/signer"
 	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
 	"github.com/orbs-network/orbs-network-go/services/gossip/adapter"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol/gossipmessages"
 	"github.com/orbs-network/scribe/log"
 	"github.com/pkg/errors"
+	"net"
 	"sync"
 )
 
@@ -30,6 +32,9 @@ type outgoingConnections struct {
 	config            timingsConfig
 	metricRegistry    metric.Registry
 	nodeAddress       primitives.NodeAddress
+	receiver          connectionReceiver // only in bidirectional mode
+	signer            signer.Signer      // authenticates bidirectional handshakes
+	peerEndpoints     map[string]*peerEndpoint
 }
 
 func newOutgoingConnections(logger log.Logger, registry metric.Registry, config config.GossipTransportConfig) *outgoingConnections {
@@ -37,6 +42,7 @@ func newOutgoingConnections(logger log.Logger, registry metric.Registry, config
 		logger:            logger,
 		activeConnections: make(map[string]*outgoingConnection),
 		peerTopology:      make(adapter.GossipPeers),
+		peerEndpoints:     make(map[string]*peerEndpoint),
 		metrics:           createOutgoingConnectionMetrics(registry),
 		metricRegistry:    registry,
 		nodeAddress:       config.NodeAddress(),
@@ -85,6 +91,9 @@ func (c *outgoingConnections) connectForever(bgCtx context.Context, peerNodeAddr
 	if c.nodeAddress.KeyForMap() != peerNodeAddress {
 		c.peerTopology[peerNodeAddress] = peer
 		client := newOutgoingConnection(peer, c.logger, c.metricRegistry, c.metrics, c.config)
+		if c.receiver != nil {
+			client.enableBidirectionalMode(c.nodeAddress, primitives.NodeAddress(peerNodeAddress), c.receiver, c.signer)
+		}
 		c.activeConnections[peerNodeAddress] = client
 		client.connect(bgCtx)
 	}
@@ -100,6 +109,23 @@ func (c *outgoingConnections) updateTopology(bgCtx context.Context, newPeers ada
 	for peerNodeAddress, peer := range peersToAdd {
 		c.connectForever(bgCtx, peerNodeAddress, peer)
 	}
+
+	c.resolvePeerEndpoints(newPeers)
+}
+
+// endpoints given by name are resolved here rather than on the accept path, which matches every incoming connection
+// against them; a peer whose endpoint changes address is matched by its new address from the next topology update
+func (c *outgoingConnections) resolvePeerEndpoints(peers adapter.GossipPeers) {
+	peerEndpoints := make(map[string]*peerEndpoint, len(peers))
+	for peerNodeAddress, peer := range peers {
+		if c.nodeAddress.KeyForMap() != peerNodeAddress {
+			peerEndpoints[peerNodeAddress] = resolvePeerEndpoint(peer.GossipEndpoint())
+		}
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	c.peerEndpoints = peerEndpoints
 }
 
 func (c *outgoingConnections) disconnectAll(ctx context.Context, peersToDisconnect adapter.GossipPeers) {
@@ -149,3 +175,42 @@ func (c *outgoingConnections) send(ctx context.Context, data *adapter.TransportD
 	}
 	return errors.Errorf("unknown recipient mode: %s", data.RecipientMode.String())
 }
+
+// returns the topology peers whose gossip endpoint is the host
+func (c *outgoingConnections) nodeAddressesAtHost(host string) (res []primitives.NodeAddress) {
+	c.RLock()
+	defer c.RUnlock()
+
+	for peerNodeAddress, endpoint := range c.peerEndpoints {
+		if endpoint.isHost(host) {
+			res = append(res, primitives.NodeAddress(peerNodeAddress))
+		}
+	}
+	return
+}
+
+type peerEndpoint struct {
+	endpoint string
+	ips      []net.IP
+}
+
+func resolvePeerEndpoint(endpoint string) *peerEndpoint {
+	if ip := net.ParseIP(endpoint); ip != nil {
+		return &peerEndpoint{endpoint: endpoint, ips: []net.IP{ip}}
+	}
+	ips, _ := net.LookupIP(endpoint) // an endpoint which cannot be resolved is only matched by name
+	return &peerEndpoint{endpoint: endpoint, ips: ips}
+}
+
+func (e *peerEndpoint) isHost(host string) bool {
+	hostIP := net.ParseIP(host)
+	if hostIP == nil {
+		return e.endpoint == host
+	}
+	for _, ip := range e.ips {
+		if ip.Equal(hostIP) {
+			return true
+		}
+	}
+	return false
+}
@@ -15,6 +15,8 @@ import (
 	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
 	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
 	"github.com/orbs-network/orbs-network-go/services/gossip/adapter"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol/gossipmessages"
 	"github.com/orbs-network/scribe/log"
 	"github.com/pkg/errors"
 	"net"
@@ -24,10 +26,16 @@ import (
 )
 
 type serverConfig interface {
+	inboundRateLimitConfig
 	GossipListenPort() uint16
 	GossipNetworkTimeout() time.Duration
 }
 
+// implemented by outgoingConnections, which holds this node's copy of the topology
+type topologyHosts interface {
+	nodeAddressesAtHost(host string) []primitives.NodeAddress
+}
+
 type transportServer struct {
 	govnr.TreeSupervisor
 
@@ -40,20 +48,31 @@ type transportServer struct {
 	metrics        incomingConnectionMetrics
 	config         serverConfig
 	shutdownServer context.CancelFunc
+	bannedPeers    *bannedPeers
+	rateLimiters   *inboundPeerRateLimiters
+	topology       topologyHosts // nil if incoming connections cannot be matched against the topology
+
+	acceptedConnections acceptedConnectionHandler // only in bidirectional mode
 }
 
 type incomingConnectionMetrics struct {
-	acceptSuccesses   *metric.Gauge
-	acceptErrors      *metric.Gauge
-	transportErrors   *metric.Gauge
-	activeConnections *metric.Gauge
+	acceptSuccesses     *metric.Gauge
+	acceptErrors        *metric.Gauge
+	transportErrors     *metric.Gauge
+	activeConnections   *metric.Gauge
+	rateLimitExceeded   *metric.Gauge
+	invalidHeaders      *metric.Gauge
+	bannedPeers         *metric.Gauge
+	rejectedBannedPeers *metric.Gauge
 }
 
 func newServer(config serverConfig, logger log.Logger, registry metric.Registry) *transportServer {
 	server := &transportServer{
-		config:  config,
-		logger:  logger,
-		metrics: createServerMetrics(registry),
+		config:       config,
+		logger:       logger,
+		metrics:      createServerMetrics(registry),
+		bannedPeers:  newBannedPeers(),
+		rateLimiters: newInboundPeerRateLimiters(config),
 	}
 
 	return server
@@ -61,10 +80,14 @@ func newServer(config serverConfig, logger log.Logger, registry metric.Registry)
 
 func createServerMetrics(registry metric.Registry) incomingConnectionMetrics {
 	return incomingConnectionMetrics{
-		acceptSuccesses:   registry.NewGauge("Gossip.IncomingConnection.ListeningOnTCPPortSuccess.Count"),
-		acceptErrors:      registry.NewGauge("Gossip.IncomingConnection.ListeningOnTCPPortErrors.Count"),
-		transportErrors:   registry.NewGauge("Gossip.IncomingConnection.TransportErrors.Count"),
-		activeConnections: registry.NewGauge("Gossip.IncomingConnection.Active.Count"),
+		acceptSuccesses:     registry.NewGauge("Gossip.IncomingConnection.ListeningOnTCPPortSuccess.Count"),
+		acceptErrors:        registry.NewGauge("Gossip.IncomingConnection.ListeningOnTCPPortErrors.Count"),
+		transportErrors:     registry.NewGauge("Gossip.IncomingConnection.TransportErrors.Count"),
+		activeConnections:   registry.NewGauge("Gossip.IncomingConnection.Active.Count"),
+		rateLimitExceeded:   registry.NewGauge("Gossip.IncomingConnection.RateLimitExceeded.Count"),
+		invalidHeaders:      registry.NewGauge("Gossip.IncomingConnection.InvalidHeaders.Count"),
+		bannedPeers:         registry.NewGauge("Gossip.IncomingConnection.BannedPeers.Count"),
+		rejectedBannedPeers: registry.NewGauge("Gossip.IncomingConnection.RejectedBannedPeers.Count"),
 	}
 }
 
@@ -151,10 +174,32 @@ func (t *transportServer) handleIncomingConnection(ctx context.Context, conn net
 	t.logger.Info("successful incoming gossip transport connection", log.String("peer", conn.RemoteAddr().String()), trace.LogFieldFrom(ctx))
 	// TODO(https://github.com/orbs-network/orbs-network-go/issues/182): add a white list for IPs we're willing to accept connections from
 	// TODO(https://github.com/orbs-network/orbs-network-go/issues/182): make sure each IP from the white list connects only once
+	defer func() { _ = conn.Close() }()
+
+	peer := t.inboundPeerOf(conn)
+	if banKey, canBan := peer.banKey(); canBan && t.config.GossipInboundRateLimitEnabled() && t.bannedPeers.isBanned(banKey, time.Now()) {
+		t.metrics.rejectedBannedPeers.Inc()
+		t.logger.Info("rejecting incoming connection from temporarily banned peer", append(peer.logFields(), trace.LogFieldFrom(ctx))...)
+		return
+	}
+
 	t.metrics.activeConnections.Inc()
 	defer t.metrics.activeConnections.Dec()
 
-	defer func() { _ = conn.Close() }()
+	t.receiveFromPeer(ctx, conn, peer, t.acceptedConnections != nil)
+}
+
+// also reads the connections outgoing connections dial in bidirectional mode
+func (t *transportServer) receiveFromConnection(ctx context.Context, conn net.Conn, acceptHandshake bool) {
+	t.receiveFromPeer(ctx, conn, t.inboundPeerOf(conn), acceptHandshake)
+}
+
+// the first message on an incoming connection may be a bidirectional handshake, after which the connection is also
+// used for sending to the peer
+func (t *transportServer) receiveFromPeer(ctx context.Context, conn net.Conn, peer *inboundPeer, acceptHandshake bool) {
+	rateLimiter := t.rateLimiters.acquire(peer, time.Now())
+	defer func() { t.rateLimiters.release(rateLimiter) }()
+
 	for {
 		payloads, err := t.receiveTransportData(ctx, conn)
 		if err != nil {
@@ -166,15 +211,82 @@ func (t *transportServer) handleIncomingConnection(ctx context.Context, conn net
 
 		// notify if not keepalive
 		if len(payloads) > 0 {
+			if acceptHandshake {
+				acceptHandshake = false
+				if peerNodeAddress, isHandshake := parseBidirectionalHandshake(payloads); isHandshake {
+					if err := t.authenticateBidirectionalPeer(ctx, conn, peer.host, peerNodeAddress); err != nil {
+						t.logger.Info("rejecting unauthenticated bidirectional connection", append(peer.logFields(), log.Error(err), trace.LogFieldFrom(ctx))...)
+						return
+					}
+					peer = &inboundPeer{host: peer.host, nodeAddress: peerNodeAddress}
+					t.rateLimiters.release(rateLimiter)
+					rateLimiter = t.rateLimiters.acquire(peer, time.Now())
+
+					release, err := t.acceptedConnections.acceptConnection(peerNodeAddress, conn)
+					if err != nil {
+						t.logger.Info("rejecting bidirectional connection", log.Error(err), log.String("peer", conn.RemoteAddr().String()), trace.LogFieldFrom(ctx))
+						return
+					}
+					defer release()
+					continue
+				}
+			}
+
+			if t.config.GossipInboundRateLimitEnabled() {
+				if topic, err := rateLimiter.admit(payloads, time.Now()); err != nil {
+					t.banPeer(ctx, peer, topic, err)
+					return
+				}
+			}
+
 			ctxWithPeer := context.WithValue(ctx, "peer-ip", conn.RemoteAddr().String())
+			if peer.nodeAddress != nil {
+				ctxWithPeer = adapter.ContextWithSenderNodeAddress(ctxWithPeer, peer.nodeAddress)
+			}
 			t.notifyListener(ctxWithPeer, payloads)
 		}
 	}
 }
 
+func (t *transportServer) inboundPeerOf(conn net.Conn) *inboundPeer {
+	host := peerHostOf(conn.RemoteAddr())
+	if t.topology == nil {
+		return newInboundPeer(host, nil)
+	}
+	return newInboundPeer(host, t.topology.nodeAddressesAtHost(host))
+}
+
+func (t *transportServer) banPeer(ctx context.Context, peer *inboundPeer, topic gossipmessages.HeaderTopic, err error) {
+	if isInvalidHeader(err) {
+		t.metrics.invalidHeaders.Inc()
+	} else {
+		t.metrics.rateLimitExceeded.Inc()
+	}
+
+	banKey, canBan := peer.banKey()
+	if !canBan {
+		t.logger.Info("disconnecting misbehaving peer without banning the topology peers sharing its host", append(peer.logFields(), log.Error(err),
+			log.Int("topic", int(topic)),
+			trace.LogFieldFrom(ctx))...)
+		return
+	}
+
+	now := time.Now()
+	t.bannedPeers.ban(banKey, now, t.config.GossipInboundPeerBanDuration())
+	t.metrics.bannedPeers.Update(int64(t.bannedPeers.count(now)))
+
+	t.logger.Info("disconnecting and temporarily banning misbehaving peer", append(peer.logFields(), log.Error(err),
+		log.Int("topic", int(topic)),
+		log.Stringable("ban-duration", t.config.GossipInboundPeerBanDuration()),
+		trace.LogFieldFrom(ctx))...)
+}
+
 func (t *transportServer) receiveTransportData(ctx context.Context, conn net.Conn) ([][]byte, error) {
 	// TODO(https://github.com/orbs-network/orbs-network-go/issues/182): think about timeout policy on receive, we might not want it
-	timeout := t.config.GossipNetworkTimeout()
+	return readTransportData(ctx, conn, t.config.GossipNetworkTimeout())
+}
+
+func readTransportData(ctx context.Context, conn net.Conn, timeout time.Duration) ([][]byte, error) {
 	var res [][]byte
 
 	// receive num payloads
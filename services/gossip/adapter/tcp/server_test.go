@@ -12,6 +12,7 @@ import (
 	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
 	"github.com/orbs-network/orbs-network-go/test"
 	"github.com/orbs-network/orbs-network-go/test/with"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/stretchr/testify/require"
 	"net"
 	"testing"
@@ -169,7 +170,11 @@ func TestDirectIncoming_TimeoutDuringReceiveCausesDisconnect(t *testing.T) {
 }
 
 type serverCfg struct {
-	port uint16
+	port               uint16
+	rateLimitPerSecond uint32
+	rateLimitBurst     uint32
+	consensusRateLimit uint32
+	banDuration        time.Duration
 }
 
 func (s *serverCfg) GossipListenPort() uint16 {
@@ -180,6 +185,30 @@ func (s *serverCfg) GossipNetworkTimeout() time.Duration {
 	return 100 * time.Millisecond
 }
 
+func (s *serverCfg) GossipInboundRateLimitEnabled() bool {
+	return s.rateLimitPerSecond > 0
+}
+
+func (s *serverCfg) GossipInboundRateLimitMessagesPerSecond() uint32 {
+	return s.rateLimitPerSecond
+}
+
+func (s *serverCfg) GossipInboundRateLimitBurst() uint32 {
+	return s.rateLimitBurst
+}
+
+func (s *serverCfg) GossipInboundConsensusRateLimitMessagesPerSecond() uint32 {
+	return s.consensusRateLimit
+}
+
+func (s *serverCfg) GossipInboundConsensusRateLimitBurst() uint32 {
+	return s.consensusRateLimit
+}
+
+func (s *serverCfg) GossipInboundPeerBanDuration() time.Duration {
+	return s.banDuration
+}
+
 func TestDirectServer_PanicsOnPortAlreadyInUse(t *testing.T) {
 	with.Concurrency(t, func(ctx context.Context, harness *with.ConcurrencyHarness) {
 
@@ -283,3 +312,87 @@ func TestDirectServer_DoesNotAcceptNewConnections_AfterShutdown(t *testing.T) {
 		require.Error(t, err, "should not have succeeded connecting to server")
 	})
 }
+
+func TestDirectServer_DisconnectsAndBansPeer_WhenSendingInvalidHeader(t *testing.T) {
+	with.Concurrency(t, func(ctx context.Context, harness *with.ConcurrencyHarness) {
+		cfg := &serverCfg{rateLimitPerSecond: 10, rateLimitBurst: 10, banDuration: 1 * time.Minute}
+
+		server := newServer(cfg, harness.Logger, metric.NewRegistry())
+		harness.Supervise(server)
+		server.startSupervisedMainLoop(ctx)
+		defer server.GracefulShutdown(context.Background())
+
+		require.True(t, test.Eventually(100*time.Millisecond, func() bool {
+			return server.IsListening()
+		}))
+
+		conn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", server.getPort()))
+		require.NoError(t, err, "failed connecting to server")
+		defer conn.Close()
+
+		buffer := exampleWireProtocolEncoding_Payloads_0x11_0x2233() // first payload is not a valid gossip header
+		_, err = conn.Write(buffer)
+		require.NoError(t, err, "test peer could not write to server")
+
+		_, err = conn.Read([]byte{0})
+		require.Error(t, err, "test peer should be disconnected from server")
+		require.EqualValues(t, 1, server.metrics.invalidHeaders.Value(), "invalid header should be counted")
+
+		conn, err = net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", server.getPort()))
+		require.NoError(t, err, "failed connecting to server")
+		defer conn.Close()
+
+		_, err = conn.Read([]byte{0})
+		require.Error(t, err, "banned test peer should be disconnected immediately")
+		require.True(t, test.Eventually(100*time.Millisecond, func() bool {
+			return server.metrics.rejectedBannedPeers.Value() == 1
+		}), "rejected connection of banned peer should be counted")
+	})
+}
+
+type topologyHostsOf []primitives.NodeAddress
+
+func (nodeAddresses topologyHostsOf) nodeAddressesAtHost(host string) []primitives.NodeAddress {
+	return nodeAddresses
+}
+
+func TestDirectServer_DisconnectsWithoutBanning_WhenHostIsSharedByTopologyPeers(t *testing.T) {
+	with.Concurrency(t, func(ctx context.Context, harness *with.ConcurrencyHarness) {
+		cfg := &serverCfg{rateLimitPerSecond: 10, rateLimitBurst: 10, banDuration: 1 * time.Minute}
+
+		server := newServer(cfg, harness.Logger, metric.NewRegistry())
+		server.topology = topologyHostsOf{{0x01}, {0x02}}
+		harness.Supervise(server)
+		server.startSupervisedMainLoop(ctx)
+		defer server.GracefulShutdown(context.Background())
+
+		require.True(t, test.Eventually(100*time.Millisecond, func() bool {
+			return server.IsListening()
+		}))
+
+		conn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", server.getPort()))
+		require.NoError(t, err, "failed connecting to server")
+		defer conn.Close()
+
+		_, err = conn.Write(exampleWireProtocolEncoding_Payloads_0x11_0x2233())
+		require.NoError(t, err, "test peer could not write to server")
+
+		_, err = conn.Read([]byte{0})
+		require.Error(t, err, "test peer should be disconnected from server")
+		require.EqualValues(t, 1, server.metrics.invalidHeaders.Value(), "invalid header should be counted")
+		require.EqualValues(t, 0, server.metrics.bannedPeers.Value(), "the topology peers sharing the host should not be banned")
+
+		conn, err = net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", server.getPort()))
+		require.NoError(t, err, "failed connecting to server")
+		defer conn.Close()
+
+		_, err = conn.Write(exampleWireProtocolEncoding_Payloads_0x11_0x2233())
+		require.NoError(t, err, "test peer could not write to server")
+		_, err = conn.Read([]byte{0})
+		require.Error(t, err)
+		require.True(t, test.Eventually(100*time.Millisecond, func() bool {
+			return server.metrics.invalidHeaders.Value() == 2
+		}), "a new connection from the shared host should be read again")
+		require.EqualValues(t, 0, server.metrics.rejectedBannedPeers.Value())
+	})
+}
@@ -30,6 +30,23 @@ type Transport interface {
 	UpdateTopology(bgCtx context.Context, newPeers GossipPeers)
 }
 
+// implemented by transports which know whether they are currently connected to a peer
+type PeerConnectivity interface {
+	IsConnectedTo(nodeAddress primitives.NodeAddress) bool
+}
+
+type senderNodeAddressKey struct{}
+
+// transports put the node address of the sender on the context of a received message when they know it
+func ContextWithSenderNodeAddress(ctx context.Context, nodeAddress primitives.NodeAddress) context.Context {
+	return context.WithValue(ctx, senderNodeAddressKey{}, nodeAddress)
+}
+
+func SenderNodeAddressOf(ctx context.Context) (primitives.NodeAddress, bool) {
+	nodeAddress, ok := ctx.Value(senderNodeAddressKey{}).(primitives.NodeAddress)
+	return nodeAddress, ok && len(nodeAddress) > 0
+}
+
 type TransportListener interface {
 	fmt.Stringer // TODO smelly
 	OnTransportMessageReceived(ctx context.Context, payloads [][]byte)
@@ -16,6 +16,7 @@ import (
 	"github.com/orbs-network/orbs-spec/types/go/protocol/gossipmessages"
 	"github.com/orbs-network/scribe/log"
 	"github.com/pkg/errors"
+	"time"
 )
 
 type handlerFunc func(ctx context.Context, header *gossipmessages.Header, payloads [][]byte)
@@ -27,12 +28,13 @@ type gossipMessage struct {
 }
 
 type meteredTopicChannel struct {
-	ch              chan gossipMessage
-	size            *metric.Gauge
-	inQueue         *metric.Gauge
-	droppedMessages *metric.Gauge
-	logger          log.Logger
-	name            string
+	ch                chan gossipMessage
+	size              *metric.Gauge
+	inQueue           *metric.Gauge
+	droppedMessages   *metric.Gauge
+	duplicateMessages *metric.Gauge
+	logger            log.Logger
+	name              string
 }
 
 func (c *meteredTopicChannel) send(ctx context.Context, header *gossipmessages.Header, payloads [][]byte) error {
@@ -85,12 +87,13 @@ func newMeteredTopicChannel(name string, registry metric.Registry, logger log.Lo
 	sizeGauge := registry.NewGauge("Gossip.Topic." + name + ".QueueSize")
 	sizeGauge.Update(int64(topicBufferSize))
 	return &meteredTopicChannel{
-		ch:              make(chan gossipMessage, topicBufferSize),
-		size:            sizeGauge,
-		inQueue:         registry.NewGauge("Gossip.Topic." + name + ".MessagesInQueue"),
-		droppedMessages: registry.NewGauge("Gossip.Topic." + name + ".DroppedMessages"),
-		name:            fmt.Sprintf("%s topic handler", name),
-		logger:          logger.WithTags(log.String("gossip-topic", name)),
+		ch:                make(chan gossipMessage, topicBufferSize),
+		size:              sizeGauge,
+		inQueue:           registry.NewGauge("Gossip.Topic." + name + ".MessagesInQueue"),
+		droppedMessages:   registry.NewGauge("Gossip.Topic." + name + ".DroppedMessages"),
+		duplicateMessages: registry.NewGauge("Gossip.Topic." + name + ".DuplicateMessages"),
+		name:              fmt.Sprintf("%s topic handler", name),
+		logger:            logger.WithTags(log.String("gossip-topic", name)),
 	}
 }
 
@@ -99,18 +102,22 @@ type gossipMessageDispatcher struct {
 	blockSync          *meteredTopicChannel
 	leanHelix          *meteredTopicChannel
 	benchmarkConsensus *meteredTopicChannel
+
+	duplicates *duplicateFilter
 }
 
 // These channels are buffered because we don't want to assume that the topic consumers behave nicely
 // In fact, Block Sync should create a new one-off goroutine per "server request", Consensus should read messages immediately and store them in its own queue,
 // and Transaction Relay shouldn't block for long anyway.
-func newMessageDispatcher(registry metric.Registry, logger log.Logger) (d *gossipMessageDispatcher) {
+func newMessageDispatcher(duplicates *duplicateFilter, registry metric.Registry, logger log.Logger) (d *gossipMessageDispatcher) {
 
 	d = &gossipMessageDispatcher{
 		transactionRelay:   newMeteredTopicChannel("TransactionRelay", registry, logger, 200),   // transaction pool might block on adding new transactions, for instance while committing a block
 		blockSync:          newMeteredTopicChannel("BlockSync", registry, logger, 10),           // low value assuming that handling block sync messages doesn't block
 		leanHelix:          newMeteredTopicChannel("LeanHelixConsensus", registry, logger, 100), // handlers performs I/O operations and require buffering of requests
 		benchmarkConsensus: newMeteredTopicChannel("BenchmarkConsensus", registry, logger, 20),  // under heavy load benchmark consensus has been observed to slow down, failing to pick messages up from the topic fast enough
+
+		duplicates: duplicates,
 	}
 	return
 }
@@ -122,9 +129,21 @@ func (d *gossipMessageDispatcher) dispatch(ctx context.Context, logger log.Logge
 		return
 	}
 
+	key, suppressible := d.duplicates.suppressionKeyOf(header, payloads)
+	if suppressible && d.duplicates.isDuplicate(key, time.Now()) {
+		ch.duplicateMessages.Inc()
+		logger.Info("dropping duplicate message", log.Stringable("header", header), log.String("topic", stringTopic(header)))
+		return
+	}
+
 	err = ch.send(ctx, header, payloads)
 	if err != nil {
 		logger.Error("message dropped", log.Error(err), log.Stringable("header", header), log.String("topic", stringTopic(header)))
+		return
+	}
+
+	if suppressible {
+		d.duplicates.remember(key, time.Now())
 	}
 }
 
@@ -0,0 +1,60 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package gossip
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-network-go/test/with"
+	"github.com/orbs-network/orbs-spec/types/go/protocol/gossipmessages"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type duplicateSuppressionCfg struct{}
+
+func (c *duplicateSuppressionCfg) GossipDuplicateSuppressionWindow() time.Duration {
+	return 1 * time.Minute
+}
+
+func (c *duplicateSuppressionCfg) GossipDuplicateSuppressionMaxEntries() uint32 {
+	return 100
+}
+
+func (c *duplicateSuppressionCfg) GossipDuplicateSuppressionExemptions() string {
+	return ""
+}
+
+func TestGossipMessageDispatcher_DoesNotFilterTheRetransmitOfADroppedMessage(t *testing.T) {
+	with.Logging(t, func(harness *with.LoggingHarness) {
+		harness.AllowErrorsMatching("message dropped")
+		registry := metric.NewRegistry()
+		d := newMessageDispatcher(newDuplicateFilter(&duplicateSuppressionCfg{}), registry, harness.Logger)
+		header := (&gossipmessages.HeaderBuilder{
+			Topic:         gossipmessages.HEADER_TOPIC_BLOCK_SYNC,
+			BlockSync:     gossipmessages.BLOCK_SYNC_RESPONSE,
+			RecipientMode: gossipmessages.RECIPIENT_LIST_MODE_BROADCAST,
+		}).Build()
+		payloads := [][]byte{{0x01, 0x02}}
+
+		for len(d.blockSync.ch) < cap(d.blockSync.ch) {
+			d.blockSync.ch <- gossipMessage{}
+		}
+		d.dispatch(context.Background(), harness.Logger, header, payloads)
+		require.EqualValues(t, 1, d.blockSync.droppedMessages.Value(), "message should be dropped when the topic buffer is full")
+
+		d.blockSync.drain()
+		d.dispatch(context.Background(), harness.Logger, header, payloads)
+		require.Len(t, d.blockSync.ch, 1, "retransmit of a dropped message should be dispatched")
+		require.EqualValues(t, 0, d.blockSync.duplicateMessages.Value())
+
+		d.dispatch(context.Background(), harness.Logger, header, payloads)
+		require.Len(t, d.blockSync.ch, 1)
+		require.EqualValues(t, 1, d.blockSync.duplicateMessages.Value(), "copy of a dispatched message should be filtered")
+	})
+}
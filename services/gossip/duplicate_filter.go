@@ -0,0 +1,93 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package gossip
+
+import (
+	"github.com/orbs-network/orbs-spec/types/go/protocol/gossipmessages"
+	"strings"
+	"time"
+)
+
+type duplicateSuppressionConfig interface {
+	GossipDuplicateSuppressionWindow() time.Duration
+	GossipDuplicateSuppressionMaxEntries() uint32
+	GossipDuplicateSuppressionExemptions() string // comma separated message types, e.g. BLOCK_SYNC_REQUEST
+}
+
+// drops repeated copies of messages: relayed messages by their relay message id, so that a message relayed to a node
+// twice is handled and relayed once, and other transaction relay and block sync messages by content before they reach
+// the topic handlers, which would otherwise verify and process every copy again. Both share one bounded cache.
+type duplicateFilter struct {
+	recent *recentMessages
+	exempt map[string]bool
+}
+
+// returns nil (which never filters anything) if duplicate suppression is disabled
+func newDuplicateFilter(config duplicateSuppressionConfig) *duplicateFilter {
+	if config.GossipDuplicateSuppressionWindow() == 0 {
+		return nil
+	}
+
+	exempt := make(map[string]bool)
+	for _, messageType := range strings.Split(config.GossipDuplicateSuppressionExemptions(), ",") {
+		if messageType = strings.TrimSpace(messageType); messageType != "" {
+			exempt[messageType] = true
+		}
+	}
+
+	return &duplicateFilter{
+		recent: newRecentMessages(config.GossipDuplicateSuppressionWindow(), int(config.GossipDuplicateSuppressionMaxEntries())),
+		exempt: exempt,
+	}
+}
+
+func (f *duplicateFilter) isDuplicateRelay(message *relayedMessage, now time.Time) bool {
+	if f == nil {
+		return false
+	}
+
+	return f.recent.seenBefore(message.key(), now)
+}
+
+// returns the key a message is filtered by, or false if it is never filtered
+func (f *duplicateFilter) suppressionKeyOf(header *gossipmessages.Header, payloads [][]byte) (string, bool) {
+	if f == nil {
+		return "", false
+	}
+
+	if _, isRelayed := relayedMessageOf(header); isRelayed { // already filtered by its relay message id
+		return "", false
+	}
+
+	messageType, suppressible := suppressibleMessageType(header)
+	if !suppressible || f.exempt[messageType] {
+		return "", false
+	}
+
+	return messageContentKey(append([][]byte{header.Raw()}, payloads...)), true
+}
+
+func (f *duplicateFilter) isDuplicate(key string, now time.Time) bool {
+	return f.recent.contains(key, now)
+}
+
+// a message is only remembered once it was handed to its topic handler, so that a copy of a dropped message isn't
+// filtered as its duplicate
+func (f *duplicateFilter) remember(key string, now time.Time) {
+	f.recent.record(key, now)
+}
+
+func suppressibleMessageType(header *gossipmessages.Header) (string, bool) {
+	switch header.Topic() {
+	case gossipmessages.HEADER_TOPIC_TRANSACTION_RELAY:
+		return header.TransactionRelay().String(), true
+	case gossipmessages.HEADER_TOPIC_BLOCK_SYNC:
+		return header.BlockSync().String(), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package gossip
+
+import (
+	"container/list"
+	"github.com/orbs-network/orbs-network-go/crypto/hash"
+	"sync"
+	"time"
+)
+
+// remembers the content hashes of recently seen messages, bounded both in time and in number of entries
+type recentMessages struct {
+	sync.Mutex
+	window     time.Duration
+	maxEntries int
+	seenAt     map[string]*list.Element
+	order      *list.List
+}
+
+type recentMessage struct {
+	key    string
+	seenAt time.Time
+}
+
+func newRecentMessages(window time.Duration, maxEntries int) *recentMessages {
+	return &recentMessages{
+		window:     window,
+		maxEntries: maxEntries,
+		seenAt:     make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+func messageContentKey(payloads [][]byte) string {
+	return string(hash.CalcSha256(payloads...))
+}
+
+// returns true if a message with the same content was seen within the window, otherwise records it and returns false
+func (r *recentMessages) seenBefore(key string, now time.Time) bool {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.containsLocked(key, now) {
+		return true
+	}
+	r.recordLocked(key, now)
+	return false
+}
+
+// returns true if a message with the same content was seen within the window, without recording it
+func (r *recentMessages) contains(key string, now time.Time) bool {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.containsLocked(key, now)
+}
+
+func (r *recentMessages) record(key string, now time.Time) {
+	r.Lock()
+	defer r.Unlock()
+
+	if !r.containsLocked(key, now) {
+		r.recordLocked(key, now)
+	}
+}
+
+func (r *recentMessages) containsLocked(key string, now time.Time) bool {
+	r.evictOlderThan(now.Add(-r.window))
+
+	_, found := r.seenAt[key]
+	return found
+}
+
+func (r *recentMessages) recordLocked(key string, now time.Time) {
+	r.seenAt[key] = r.order.PushBack(&recentMessage{key: key, seenAt: now})
+	for r.order.Len() > r.maxEntries {
+		r.remove(r.order.Front())
+	}
+}
+
+func (r *recentMessages) evictOlderThan(cutoff time.Time) {
+	for e := r.order.Front(); e != nil && e.Value.(*recentMessage).seenAt.Before(cutoff); e = r.order.Front() {
+		r.remove(e)
+	}
+}
+
+func (r *recentMessages) remove(e *list.Element) {
+	delete(r.seenAt, e.Value.(*recentMessage).key)
+	r.order.Remove(e)
+}
+
+func (r *recentMessages) size() int {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.order.Len()
+}
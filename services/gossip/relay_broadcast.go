@@ -0,0 +1,290 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package gossip
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
+	"github.com/orbs-network/orbs-network-go/services/gossip/adapter"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol/gossipmessages"
+	"github.com/orbs-network/scribe/log"
+	"sort"
+	"sync"
+	"time"
+)
+
+const RELAY_MESSAGE_ID_SIZE = 32
+
+// In relay mode the origin sends a message only to its children in a k-ary tree rooted at the origin, and every node
+// that receives it forwards it to its own children. Broadcasts are relayed over the sorted topology and marked by a
+// header with RECIPIENT_LIST_MODE_ALL_BUT_LIST, messages to a list of recipients (Lean Helix messages carrying a block,
+// e.g. proposals) are relayed over the sorted recipients and keep RECIPIENT_LIST_MODE_LIST. Either way the recipient
+// list of the header ends with the origin followed by a random message id, longer than any node address. Relays drop
+// copies of a message by that id (see duplicateFilter), so a message which is sent again, e.g. a benchmark consensus commit resend, is relayed
+// again. A child the transport is not connected to is bypassed by sending directly to its own children.
+type relayConfig interface {
+	GossipRelayBroadcastEnabled() bool
+	GossipRelayBroadcastFanout() uint32
+}
+
+type relayMetrics struct {
+	originated *metric.Gauge
+	forwarded  *metric.Gauge
+	duplicates *metric.Gauge
+	bypassed   *metric.Gauge
+}
+
+type relayTopology struct {
+	sync.RWMutex
+	nodes []primitives.NodeAddress // sorted, includes this node
+}
+
+func newRelayMetrics(registry metric.Registry) *relayMetrics {
+	return &relayMetrics{
+		originated: registry.NewGauge("Gossip.Relay.OriginatedMessages.Count"),
+		forwarded:  registry.NewGauge("Gossip.Relay.ForwardedMessages.Count"),
+		duplicates: registry.NewGauge("Gossip.Relay.DuplicateMessages.Count"),
+		bypassed:   registry.NewGauge("Gossip.Relay.BypassedChildren.Count"),
+	}
+}
+
+func (t *relayTopology) update(self primitives.NodeAddress, peers adapter.GossipPeers) {
+	nodes := []primitives.NodeAddress{self}
+	for key := range peers {
+		if nodeAddress := primitives.NodeAddress(key); !nodeAddress.Equal(self) {
+			nodes = append(nodes, nodeAddress)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return bytes.Compare(nodes[i], nodes[j]) < 0
+	})
+
+	t.Lock()
+	defer t.Unlock()
+	t.nodes = nodes
+}
+
+func (t *relayTopology) size() int {
+	t.RLock()
+	defer t.RUnlock()
+
+	return len(t.nodes)
+}
+
+func (t *relayTopology) all() []primitives.NodeAddress {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.nodes
+}
+
+// returns the nodes node should forward to in the tree rooted at origin, nil if either is not part of the topology
+func (t *relayTopology) childrenOf(origin primitives.NodeAddress, node primitives.NodeAddress, fanout int) []primitives.NodeAddress {
+	t.RLock()
+	defer t.RUnlock()
+
+	return relayChildren(t.nodes, origin, node, fanout)
+}
+
+// nodes must be sorted
+func relayChildren(nodes []primitives.NodeAddress, origin primitives.NodeAddress, node primitives.NodeAddress, fanout int) []primitives.NodeAddress {
+	n := len(nodes)
+	originIndex, nodeIndex := indexOf(nodes, origin), indexOf(nodes, node)
+	if originIndex < 0 || nodeIndex < 0 || fanout < 1 {
+		return nil
+	}
+
+	position := (nodeIndex - originIndex + n) % n
+	var children []primitives.NodeAddress
+	for childPosition := position*fanout + 1; childPosition <= position*fanout+fanout && childPosition < n; childPosition++ {
+		children = append(children, nodes[(originIndex+childPosition)%n])
+	}
+	return children
+}
+
+func indexOf(nodes []primitives.NodeAddress, nodeAddress primitives.NodeAddress) int {
+	i := sort.Search(len(nodes), func(i int) bool {
+		return bytes.Compare(nodes[i], nodeAddress) >= 0
+	})
+	if i < len(nodes) && nodes[i].Equal(nodeAddress) {
+		return i
+	}
+	return -1
+}
+
+func sortedNodes(nodes []primitives.NodeAddress) []primitives.NodeAddress {
+	unique := make(map[string]bool)
+	var res []primitives.NodeAddress
+	for _, nodeAddress := range nodes {
+		if !unique[nodeAddress.KeyForMap()] {
+			unique[nodeAddress.KeyForMap()] = true
+			res = append(res, nodeAddress)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return bytes.Compare(res[i], res[j]) < 0
+	})
+	return res
+}
+
+type relayedMessage struct {
+	origin     primitives.NodeAddress
+	id         []byte
+	broadcast  bool
+	recipients []primitives.NodeAddress // of a message which is not a broadcast
+}
+
+func newRelayMessageId() primitives.NodeAddress {
+	id := make([]byte, RELAY_MESSAGE_ID_SIZE)
+	_, _ = rand.Read(id)
+	return id
+}
+
+func relayedMessageOf(header *gossipmessages.Header) (*relayedMessage, bool) {
+	mode := header.RecipientMode()
+	if mode != gossipmessages.RECIPIENT_LIST_MODE_ALL_BUT_LIST && mode != gossipmessages.RECIPIENT_LIST_MODE_LIST {
+		return nil, false
+	}
+
+	var entries []primitives.NodeAddress
+	for i := header.RecipientNodeAddressesIterator(); i.HasNext(); {
+		entries = append(entries, i.NextRecipientNodeAddresses())
+	}
+	n := len(entries)
+	if n < 2 || len(entries[n-1]) != RELAY_MESSAGE_ID_SIZE {
+		return nil, false
+	}
+
+	message := &relayedMessage{origin: entries[n-2], id: entries[n-1]}
+	if mode == gossipmessages.RECIPIENT_LIST_MODE_ALL_BUT_LIST {
+		if n != 2 {
+			return nil, false
+		}
+		message.broadcast = true
+	} else {
+		message.recipients = entries[:n-2]
+	}
+	return message, true
+}
+
+func (m *relayedMessage) key() string {
+	return "relay:" + string(m.id)
+}
+
+func (s *Service) relayBroadcastActive() bool {
+	return s.config.GossipRelayBroadcastEnabled() && s.relayTopology.size() > int(s.config.GossipRelayBroadcastFanout())+1
+}
+
+func (s *Service) relayToListActive(recipients []primitives.NodeAddress) bool {
+	return s.config.GossipRelayBroadcastEnabled() && len(recipients)+1 > int(s.config.GossipRelayBroadcastFanout())+1
+}
+
+// all broadcasts are built through here so that the header reflects whether the message is going to be relayed
+func (s *Service) broadcastHeader(builder *gossipmessages.HeaderBuilder) *gossipmessages.Header {
+	if s.relayBroadcastActive() {
+		builder.RecipientMode = gossipmessages.RECIPIENT_LIST_MODE_ALL_BUT_LIST
+		builder.RecipientNodeAddresses = []primitives.NodeAddress{s.config.NodeAddress(), newRelayMessageId()}
+	} else {
+		builder.RecipientMode = gossipmessages.RECIPIENT_LIST_MODE_BROADCAST
+	}
+	builder.VirtualChainId = s.config.VirtualChainId()
+	return builder.Build()
+}
+
+// builds the header of a message to recipients which is relayed through them; send it with broadcast
+func (s *Service) relayedListHeader(builder *gossipmessages.HeaderBuilder, recipients []primitives.NodeAddress) *gossipmessages.Header {
+	builder.RecipientMode = gossipmessages.RECIPIENT_LIST_MODE_LIST
+	builder.RecipientNodeAddresses = append(append([]primitives.NodeAddress{}, recipients...), s.config.NodeAddress(), newRelayMessageId())
+	builder.VirtualChainId = s.config.VirtualChainId()
+	return builder.Build()
+}
+
+func (s *Service) broadcast(ctx context.Context, header *gossipmessages.Header, payloads [][]byte) error {
+	message, isRelayed := relayedMessageOf(header)
+	if !isRelayed {
+		return s.send(ctx, &adapter.TransportData{
+			SenderNodeAddress: s.config.NodeAddress(),
+			RecipientMode:     gossipmessages.RECIPIENT_LIST_MODE_BROADCAST,
+			Payloads:          payloads,
+		})
+	}
+
+	s.duplicates.isDuplicateRelay(message, time.Now()) // so that it's not relayed back to us
+	s.relayMetrics.originated.Inc()
+	return s.sendToRelayChildren(ctx, message, payloads)
+}
+
+// returns false if the message was already relayed through this node and should be dropped
+func (s *Service) relayIfNeeded(ctx context.Context, logger log.Logger, header *gossipmessages.Header, payloads [][]byte) bool {
+	message, isRelayed := relayedMessageOf(header)
+	if !isRelayed {
+		return true
+	}
+
+	if s.duplicates.isDuplicateRelay(message, time.Now()) {
+		s.relayMetrics.duplicates.Inc()
+		logger.Info("dropping duplicate relayed message", log.Stringable("origin", message.origin), log.String("topic", stringTopic(header)))
+		return false
+	}
+
+	if err := s.sendToRelayChildren(ctx, message, payloads); err != nil {
+		logger.Info("failed forwarding relayed message", log.Error(err), log.Stringable("origin", message.origin), log.String("topic", stringTopic(header)))
+	}
+	return true
+}
+
+func (s *Service) sendToRelayChildren(ctx context.Context, message *relayedMessage, payloads [][]byte) error {
+	nodes := s.relayTopology.all()
+	if !message.broadcast {
+		nodes = sortedNodes(append(append([]primitives.NodeAddress{}, message.recipients...), message.origin))
+	}
+
+	children := s.connectedRelayChildren(nodes, message.origin)
+	if len(children) == 0 {
+		return nil
+	}
+
+	s.relayMetrics.forwarded.Add(int64(len(children)))
+	s.logger.Info("relaying message", trace.LogFieldFrom(ctx), log.Stringable("origin", message.origin), log.StringableSlice("children", children))
+	return s.send(ctx, &adapter.TransportData{
+		SenderNodeAddress:      s.config.NodeAddress(),
+		RecipientMode:          gossipmessages.RECIPIENT_LIST_MODE_LIST,
+		RecipientNodeAddresses: children,
+		Payloads:               payloads,
+	})
+}
+
+func (s *Service) connectedRelayChildren(nodes []primitives.NodeAddress, origin primitives.NodeAddress) []primitives.NodeAddress {
+	isConnected := func(primitives.NodeAddress) bool { return true }
+	if connectivity, ok := s.transport.(adapter.PeerConnectivity); ok {
+		isConnected = connectivity.IsConnectedTo
+	}
+
+	children, bypassed := connectedRelayChildren(nodes, origin, s.config.NodeAddress(), int(s.config.GossipRelayBroadcastFanout()), isConnected)
+	s.relayMetrics.bypassed.Add(int64(bypassed))
+	return children
+}
+
+// a child which is not connected is replaced by its own children, so that its subtree still gets the message
+func connectedRelayChildren(nodes []primitives.NodeAddress, origin primitives.NodeAddress, node primitives.NodeAddress, fanout int, isConnected func(primitives.NodeAddress) bool) (children []primitives.NodeAddress, bypassed int) {
+	pending := relayChildren(nodes, origin, node, fanout)
+	for len(pending) > 0 {
+		child := pending[0]
+		pending = pending[1:]
+		if isConnected(child) {
+			children = append(children, child)
+		} else {
+			bypassed++
+			pending = append(pending, relayChildren(nodes, origin, child, fanout)...)
+		}
+	}
+	return
+}
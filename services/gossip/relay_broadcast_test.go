@@ -0,0 +1,89 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package gossip
+
+import (
+	"github.com/orbs-network/orbs-network-go/services/gossip/adapter"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func relayTopologyOf(numNodes int) (*relayTopology, []primitives.NodeAddress) {
+	peers := make(adapter.GossipPeers)
+	var nodes []primitives.NodeAddress
+	for i := 0; i < numNodes; i++ {
+		nodeAddress := primitives.NodeAddress{byte(numNodes - i)} // deliberately unsorted
+		nodes = append(nodes, nodeAddress)
+		peers[nodeAddress.KeyForMap()] = adapter.NewGossipPeer(0, "", nodeAddress.String())
+	}
+	topology := &relayTopology{}
+	topology.update(nodes[0], peers)
+	return topology, nodes
+}
+
+func TestRelayTopology_TreeCoversEveryNodeExactlyOnceFromAnyOrigin(t *testing.T) {
+	for _, fanout := range []int{1, 2, 3, 10} {
+		topology, nodes := relayTopologyOf(22)
+
+		for _, origin := range nodes {
+			reached := map[string]int{origin.KeyForMap(): 1}
+			pending := []primitives.NodeAddress{origin}
+			for len(pending) > 0 {
+				node := pending[0]
+				pending = pending[1:]
+				for _, child := range topology.childrenOf(origin, node, fanout) {
+					reached[child.KeyForMap()]++
+					pending = append(pending, child)
+				}
+			}
+
+			require.Len(t, reached, len(nodes), "every node should be reached (fanout %d)", fanout)
+			for _, count := range reached {
+				require.Equal(t, 1, count, "no node should be reached twice (fanout %d)", fanout)
+			}
+			require.True(t, len(topology.childrenOf(origin, origin, fanout)) <= fanout, "origin should upload to at most fanout nodes")
+		}
+	}
+}
+
+func TestRelayTopology_NoChildrenForUnknownNodes(t *testing.T) {
+	topology, nodes := relayTopologyOf(5)
+
+	require.Nil(t, topology.childrenOf(primitives.NodeAddress{0x99}, nodes[0], 2))
+	require.Nil(t, topology.childrenOf(nodes[0], primitives.NodeAddress{0x99}, 2))
+}
+
+func TestConnectedRelayChildren_BypassesChildrenWhichAreNotConnected(t *testing.T) {
+	nodes := sortedNodes([]primitives.NodeAddress{{0x01}, {0x02}, {0x03}, {0x04}, {0x05}, {0x06}, {0x07}})
+	origin := nodes[0]
+	down := relayChildren(nodes, origin, origin, 2)[0]
+
+	children, bypassed := connectedRelayChildren(nodes, origin, origin, 2, func(nodeAddress primitives.NodeAddress) bool {
+		return !nodeAddress.Equal(down)
+	})
+
+	require.Equal(t, 1, bypassed)
+	require.NotContains(t, children, down)
+	require.ElementsMatch(t, append(relayChildren(nodes, origin, origin, 2)[1:], relayChildren(nodes, origin, down, 2)...), children,
+		"the children of a child which is not connected should be sent to directly")
+}
+
+func TestRecentMessages_ForgetsMessagesOutsideWindowOrOverCapacity(t *testing.T) {
+	now := time.Now()
+	recent := newRecentMessages(1*time.Minute, 2)
+
+	require.False(t, recent.seenBefore("a", now))
+	require.True(t, recent.seenBefore("a", now.Add(30*time.Second)))
+	require.False(t, recent.seenBefore("a", now.Add(2*time.Minute)), "message should be forgotten after window")
+
+	require.False(t, recent.seenBefore("b", now.Add(2*time.Minute)))
+	require.False(t, recent.seenBefore("c", now.Add(2*time.Minute)))
+	require.Equal(t, 2, recent.size())
+	require.False(t, recent.seenBefore("a", now.Add(2*time.Minute)), "oldest message should be evicted over capacity")
+}
@@ -18,11 +18,14 @@ import (
 	"github.com/orbs-network/orbs-spec/types/go/services/gossiptopics"
 	"github.com/orbs-network/scribe/log"
 	"sync"
+	"time"
 )
 
 var LogTag = log.Service("gossip")
 
 type Config interface {
+	relayConfig
+	duplicateSuppressionConfig
 	NodeAddress() primitives.NodeAddress
 	VirtualChainId() primitives.VirtualChainId
 }
@@ -46,11 +49,18 @@ type Service struct {
 
 	messageDispatcher             *gossipMessageDispatcher
 	forwarededTransactionFailures *metric.Gauge
+
+	relayTopology *relayTopology
+	duplicates    *duplicateFilter
+	relayMetrics  *relayMetrics
+
+	traffic *trafficAccounting
 }
 
 func NewGossip(ctx context.Context, transport adapter.Transport, config Config, parent log.Logger, metricRegistry metric.Registry) *Service {
 	logger := parent.WithTags(LogTag)
-	dispatcher := newMessageDispatcher(metricRegistry, logger)
+	duplicates := newDuplicateFilter(config)
+	dispatcher := newMessageDispatcher(duplicates, metricRegistry, logger)
 	s := &Service{
 		transport:       transport,
 		config:          config,
@@ -60,6 +70,12 @@ func NewGossip(ctx context.Context, transport adapter.Transport, config Config,
 
 		messageDispatcher:             dispatcher,
 		forwarededTransactionFailures: metricRegistry.NewGauge("Gossip.Topic.TransactionRelay.Errors.Count"),
+
+		relayTopology: &relayTopology{},
+		duplicates:    duplicates,
+		relayMetrics:  newRelayMetrics(metricRegistry),
+
+		traffic: newTrafficAccounting(metricRegistry),
 	}
 	transport.RegisterListener(s, s.config.NodeAddress())
 	s.Supervise(dispatcher.runHandler(ctx, logger, gossipmessages.HEADER_TOPIC_TRANSACTION_RELAY, s.receivedTransactionRelayMessage))
@@ -71,6 +87,7 @@ func NewGossip(ctx context.Context, transport adapter.Transport, config Config,
 }
 
 func (s *Service) UpdateTopology(bgCtx context.Context, newPeers adapter.GossipPeers) {
+	s.relayTopology.update(s.config.NodeAddress(), newPeers)
 	s.transport.UpdateTopology(bgCtx, newPeers)
 }
 
@@ -95,6 +112,12 @@ func (s *Service) OnTransportMessageReceived(ctx context.Context, payloads [][]b
 		return
 	}
 
+	s.traffic.recordInbound(inboundPeerOf(ctx), header, payloads, time.Now())
+
+	if !s.relayIfNeeded(ctx, logger, header, payloads) {
+		return
+	}
+
 	s.messageDispatcher.dispatch(ctx, logger, header, payloads[1:])
 }
 
@@ -0,0 +1,218 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package test
+
+import (
+	"context"
+	"github.com/orbs-network/go-mock"
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-network-go/services/gossip"
+	"github.com/orbs-network/orbs-network-go/services/gossip/adapter"
+	"github.com/orbs-network/orbs-network-go/services/gossip/adapter/memory"
+	"github.com/orbs-network/orbs-network-go/services/transactionpool"
+	"github.com/orbs-network/orbs-network-go/test"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-network-go/test/with"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol/gossipmessages"
+	"github.com/orbs-network/orbs-spec/types/go/services/gossiptopics"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type relayNetwork struct {
+	nodeAddresses []primitives.NodeAddress
+	services      []*gossip.Service
+	registries    []metric.Registry
+}
+
+func aRelayNetwork(ctx context.Context, harness *with.ConcurrencyHarness, numNodes int, fanout uint32) *relayNetwork {
+	genesisValidatorNodes := make(map[string]config.ValidatorNode)
+	peers := make(adapter.GossipPeers)
+	network := &relayNetwork{}
+	for i := 1; i <= numNodes; i++ {
+		address := primitives.NodeAddress{byte(i)}
+		network.nodeAddresses = append(network.nodeAddresses, address)
+		genesisValidatorNodes[address.KeyForMap()] = config.NewHardCodedValidatorNode(address)
+		peers[address.KeyForMap()] = adapter.NewGossipPeer(0, "", address.String())
+	}
+	transport := memory.NewTransport(ctx, harness.Logger, genesisValidatorNodes)
+	harness.Supervise(transport)
+
+	for _, address := range network.nodeAddresses {
+		registry := metric.NewRegistry()
+		g := gossip.NewGossip(ctx, transport, &conf{nodeAddress: address, relayEnabled: true, relayFanout: fanout, duplicateSuppressionWindow: 1 * time.Minute}, harness.Logger, registry)
+		g.UpdateTopology(ctx, peers)
+		harness.Supervise(g)
+
+		network.services = append(network.services, g)
+		network.registries = append(network.registries, registry)
+	}
+	return network
+}
+
+func TestRelayBroadcast_ReachesEveryNodeExactlyOnce(t *testing.T) {
+	with.Concurrency(t, func(ctx context.Context, harness *with.ConcurrencyHarness) {
+		network := aRelayNetwork(ctx, harness, 9, 2)
+		services, registries, nodeAddresses := network.services, network.registries, network.nodeAddresses
+
+		var handlers []*gossiptopics.MockTransactionRelayHandler
+		for _, g := range services {
+			trh := &gossiptopics.MockTransactionRelayHandler{}
+			g.RegisterTransactionRelayHandler(trh)
+			handlers = append(handlers, trh)
+		}
+
+		handlers[0].When("HandleForwardedTransactions", mock.Any, mock.Any).Return(&gossiptopics.EmptyOutput{}, nil).Times(0)
+		for _, trh := range handlers[1:] {
+			trh.When("HandleForwardedTransactions", mock.Any, mock.Any).Return(&gossiptopics.EmptyOutput{}, nil).Times(1)
+		}
+
+		_, err := services[0].BroadcastForwardedTransactions(ctx, &gossiptopics.ForwardedTransactionsInput{
+			Message: &gossipmessages.ForwardedTransactionsMessage{
+				Sender: (&gossipmessages.SenderSignatureBuilder{
+					SenderNodeAddress: nodeAddresses[0],
+					Signature:         []byte{0x04, 0x05, 0x06},
+				}).Build(),
+				SignedTransactions: transactionpool.Transactions{builders.TransferTransaction().Build()},
+			},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, test.EventuallyVerify(1*time.Second, handlers[1], handlers[2], handlers[3], handlers[4], handlers[5], handlers[6], handlers[7], handlers[8]))
+		require.NoError(t, test.ConsistentlyVerify(100*time.Millisecond, handlers[0], handlers[1], handlers[2], handlers[3], handlers[4], handlers[5], handlers[6], handlers[7], handlers[8]))
+
+		uploads := registries[0].Get("Gossip.Relay.ForwardedMessages.Count").(*metric.Gauge)
+		require.EqualValues(t, 2, uploads.Value(), "origin should upload the message only to fanout peers")
+	})
+}
+
+func TestRelayToList_LeanHelixMessageWithBlockReachesEveryRecipientExactlyOnce(t *testing.T) {
+	with.Concurrency(t, func(ctx context.Context, harness *with.ConcurrencyHarness) {
+		network := aRelayNetwork(ctx, harness, 9, 2)
+
+		var handlers []*gossiptopics.MockLeanHelixHandler
+		for _, g := range network.services {
+			lhh := &gossiptopics.MockLeanHelixHandler{}
+			g.RegisterLeanHelixHandler(lhh)
+			handlers = append(handlers, lhh)
+		}
+		handlers[0].When("HandleLeanHelixMessage", mock.Any, mock.Any).Return(&gossiptopics.EmptyOutput{}, nil).Times(0)
+		handlers[8].When("HandleLeanHelixMessage", mock.Any, mock.Any).Return(&gossiptopics.EmptyOutput{}, nil).Times(0)
+		for _, lhh := range handlers[1:8] {
+			lhh.When("HandleLeanHelixMessage", mock.Any, mock.Any).Return(&gossiptopics.EmptyOutput{}, nil).Times(1)
+		}
+
+		_, err := network.services[0].SendLeanHelixMessage(ctx, &gossiptopics.LeanHelixInput{
+			RecipientsList: &gossiptopics.RecipientsList{
+				RecipientMode:          gossipmessages.RECIPIENT_LIST_MODE_LIST,
+				RecipientNodeAddresses: network.nodeAddresses[1:8], // the last node is not a committee member
+			},
+			Message: &gossipmessages.LeanHelixMessage{
+				Content:   []byte{0x01, 0x02},
+				BlockPair: builders.BlockPair().Build(),
+			},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, test.EventuallyVerify(1*time.Second, handlers[1], handlers[2], handlers[3], handlers[4], handlers[5], handlers[6], handlers[7]))
+		require.NoError(t, test.ConsistentlyVerify(100*time.Millisecond, handlers[0], handlers[1], handlers[2], handlers[3], handlers[4], handlers[5], handlers[6], handlers[7], handlers[8]))
+
+		uploads := network.registries[0].Get("Gossip.Relay.ForwardedMessages.Count").(*metric.Gauge)
+		require.EqualValues(t, 2, uploads.Value(), "origin should upload the proposal only to fanout recipients")
+	})
+}
+
+func TestRelayBroadcast_DropsDuplicateRelayedMessages(t *testing.T) {
+	with.Concurrency(t, func(ctx context.Context, harness *with.ConcurrencyHarness) {
+		nodeAddresses := []primitives.NodeAddress{{0x01}, {0x02}}
+		cfg := &conf{duplicateSuppressionWindow: 1 * time.Minute}
+
+		genesisValidatorNodes := make(map[string]config.ValidatorNode)
+		for _, address := range nodeAddresses {
+			genesisValidatorNodes[address.KeyForMap()] = config.NewHardCodedValidatorNode(address)
+		}
+		transport := memory.NewTransport(ctx, harness.Logger, genesisValidatorNodes)
+		harness.Supervise(transport)
+
+		registry := metric.NewRegistry()
+		g := gossip.NewGossip(ctx, transport, cfg, harness.Logger, registry)
+		harness.Supervise(g)
+
+		trh := &gossiptopics.MockTransactionRelayHandler{}
+		g.RegisterTransactionRelayHandler(trh)
+		trh.When("HandleForwardedTransactions", mock.Any, mock.Any).Return(&gossiptopics.EmptyOutput{}, nil).Times(1)
+
+		payloads := aRelayedTransactionRelayRequest(t, nodeAddresses[1], aRelayMessageId(1))
+		for i := 0; i < 2; i++ {
+			require.NoError(t, transport.Send(ctx, &adapter.TransportData{
+				SenderNodeAddress:      nodeAddresses[1],
+				RecipientMode:          gossipmessages.RECIPIENT_LIST_MODE_LIST,
+				RecipientNodeAddresses: []primitives.NodeAddress{cfg.NodeAddress()},
+				Payloads:               payloads,
+			}))
+		}
+
+		require.NoError(t, test.EventuallyVerify(1*time.Second, trh))
+		require.NoError(t, test.ConsistentlyVerify(100*time.Millisecond, trh))
+		require.True(t, test.Eventually(1*time.Second, func() bool {
+			return registry.Get("Gossip.Relay.DuplicateMessages.Count").(*metric.Gauge).Value() == 1
+		}), "duplicate should be counted")
+	})
+}
+
+func TestRelayBroadcast_RelaysResentMessageAgain(t *testing.T) {
+	with.Concurrency(t, func(ctx context.Context, harness *with.ConcurrencyHarness) {
+		nodeAddresses := []primitives.NodeAddress{{0x01}, {0x02}}
+		cfg := &conf{duplicateSuppressionWindow: 1 * time.Minute}
+
+		genesisValidatorNodes := make(map[string]config.ValidatorNode)
+		for _, address := range nodeAddresses {
+			genesisValidatorNodes[address.KeyForMap()] = config.NewHardCodedValidatorNode(address)
+		}
+		transport := memory.NewTransport(ctx, harness.Logger, genesisValidatorNodes)
+		harness.Supervise(transport)
+
+		g := gossip.NewGossip(ctx, transport, cfg, harness.Logger, metric.NewRegistry())
+		harness.Supervise(g)
+
+		trh := &gossiptopics.MockTransactionRelayHandler{}
+		g.RegisterTransactionRelayHandler(trh)
+		trh.When("HandleForwardedTransactions", mock.Any, mock.Any).Return(&gossiptopics.EmptyOutput{}, nil).Times(2)
+
+		for i := byte(1); i <= 2; i++ { // same content sent twice, each send has its own message id
+			require.NoError(t, transport.Send(ctx, &adapter.TransportData{
+				SenderNodeAddress:      nodeAddresses[1],
+				RecipientMode:          gossipmessages.RECIPIENT_LIST_MODE_LIST,
+				RecipientNodeAddresses: []primitives.NodeAddress{cfg.NodeAddress()},
+				Payloads:               aRelayedTransactionRelayRequest(t, nodeAddresses[1], aRelayMessageId(i)),
+			}))
+		}
+
+		require.NoError(t, test.EventuallyVerify(1*time.Second, trh))
+	})
+}
+
+func aRelayMessageId(seed byte) primitives.NodeAddress {
+	id := make([]byte, gossip.RELAY_MESSAGE_ID_SIZE)
+	id[0] = seed
+	return id
+}
+
+func aRelayedTransactionRelayRequest(t testing.TB, origin primitives.NodeAddress, messageId primitives.NodeAddress) [][]byte {
+	payloads := aTransactionRelayRequest(t)
+	payloads[0] = (&gossipmessages.HeaderBuilder{
+		Topic:                  gossipmessages.HEADER_TOPIC_TRANSACTION_RELAY,
+		TransactionRelay:       gossipmessages.TRANSACTION_RELAY_FORWARDED_TRANSACTIONS,
+		RecipientMode:          gossipmessages.RECIPIENT_LIST_MODE_ALL_BUT_LIST,
+		RecipientNodeAddresses: []primitives.NodeAddress{origin, messageId},
+		VirtualChainId:         42,
+	}).Build().Raw()
+	return payloads
+}
@@ -23,9 +23,17 @@ import (
 )
 
 type conf struct {
+	nodeAddress                primitives.NodeAddress
+	relayEnabled               bool
+	relayFanout                uint32
+	duplicateSuppressionWindow time.Duration
+	duplicateExemptions        string
 }
 
 func (c *conf) NodeAddress() primitives.NodeAddress {
+	if c.nodeAddress != nil {
+		return c.nodeAddress
+	}
 	return []byte{0x01}
 }
 
@@ -33,6 +41,26 @@ func (c *conf) VirtualChainId() primitives.VirtualChainId {
 	return 42
 }
 
+func (c *conf) GossipRelayBroadcastEnabled() bool {
+	return c.relayEnabled
+}
+
+func (c *conf) GossipRelayBroadcastFanout() uint32 {
+	return c.relayFanout
+}
+
+func (c *conf) GossipDuplicateSuppressionWindow() time.Duration {
+	return c.duplicateSuppressionWindow
+}
+
+func (c *conf) GossipDuplicateSuppressionMaxEntries() uint32 {
+	return 100
+}
+
+func (c *conf) GossipDuplicateSuppressionExemptions() string {
+	return c.duplicateExemptions
+}
+
 func TestDifferentTopicsDoNotBlockEachOtherForSamePeer(t *testing.T) {
 	with.Concurrency(t, func(ctx context.Context, harness *with.ConcurrencyHarness) {
 		nodeAddresses := []primitives.NodeAddress{{0x01}, {0x02}}
@@ -166,3 +194,45 @@ func aTransactionRelayRequest(t testing.TB) [][]byte {
 	require.NoError(t, err, "encoding failed")
 	return payloads
 }
+
+func TestGossipDispatcherDropsDuplicateMessagesUnlessExempt(t *testing.T) {
+	with.Concurrency(t, func(ctx context.Context, harness *with.ConcurrencyHarness) {
+		nodeAddresses := []primitives.NodeAddress{{0x01}, {0x02}}
+		cfg := &conf{duplicateSuppressionWindow: 1 * time.Minute, duplicateExemptions: "BLOCK_SYNC_AVAILABILITY_REQUEST"}
+
+		genesisValidatorNodes := make(map[string]config.ValidatorNode)
+		for _, address := range nodeAddresses {
+			genesisValidatorNodes[address.KeyForMap()] = config.NewHardCodedValidatorNode(primitives.NodeAddress(address))
+		}
+		transport := memory.NewTransport(ctx, harness.Logger, genesisValidatorNodes)
+		registry := metric.NewRegistry()
+		g := gossip.NewGossip(ctx, transport, cfg, harness.Logger, registry)
+
+		harness.Supervise(transport)
+		harness.Supervise(g)
+
+		trh := &gossiptopics.MockTransactionRelayHandler{}
+		bsh := &gossiptopics.MockBlockSyncHandler{}
+		g.RegisterTransactionRelayHandler(trh)
+		g.RegisterBlockSyncHandler(bsh)
+
+		trh.When("HandleForwardedTransactions", mock.Any, mock.Any).Return(&gossiptopics.EmptyOutput{}, nil).Times(1)
+		bsh.When("HandleBlockAvailabilityRequest", mock.Any, mock.Any).Return(&gossiptopics.EmptyOutput{}, nil).Times(2)
+
+		for _, payloads := range [][][]byte{aTransactionRelayRequest(t), aBlockSyncRequest(t)} {
+			for i := 0; i < 2; i++ {
+				require.NoError(t, transport.Send(ctx, &adapter.TransportData{
+					SenderNodeAddress:      []byte{0x02},
+					RecipientMode:          gossipmessages.RECIPIENT_LIST_MODE_LIST,
+					RecipientNodeAddresses: []primitives.NodeAddress{cfg.NodeAddress()},
+					Payloads:               payloads,
+				}))
+			}
+		}
+
+		require.NoError(t, test.EventuallyVerify(1*time.Second, trh, bsh), "duplicate transaction relay should be dropped while exempt block sync request should not")
+		require.NoError(t, test.ConsistentlyVerify(100*time.Millisecond, trh, bsh))
+		require.EqualValues(t, 1, registry.Get("Gossip.Topic.TransactionRelay.DuplicateMessages").(*metric.Gauge).Value())
+		require.EqualValues(t, 0, registry.Get("Gossip.Topic.BlockSync.DuplicateMessages").(*metric.Gauge).Value())
+	})
+}
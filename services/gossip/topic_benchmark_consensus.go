@@ -35,23 +35,17 @@ func (s *Service) receivedBenchmarkConsensusMessage(ctx context.Context, header
 }
 
 func (s *Service) BroadcastBenchmarkConsensusCommit(ctx context.Context, input *gossiptopics.BenchmarkConsensusCommitInput) (*gossiptopics.EmptyOutput, error) {
-	header := (&gossipmessages.HeaderBuilder{
+	header := s.broadcastHeader(&gossipmessages.HeaderBuilder{
 		Topic:              gossipmessages.HEADER_TOPIC_BENCHMARK_CONSENSUS,
 		BenchmarkConsensus: consensus.BENCHMARK_CONSENSUS_COMMIT,
-		RecipientMode:      gossipmessages.RECIPIENT_LIST_MODE_BROADCAST,
-		VirtualChainId:     s.config.VirtualChainId(),
-	}).Build()
+	})
 
 	payloads, err := codec.EncodeBenchmarkConsensusCommitMessage(header, input.Message)
 	if err != nil {
 		return nil, err
 	}
 
-	return nil, s.transport.Send(ctx, &adapter.TransportData{
-		SenderNodeAddress: s.config.NodeAddress(),
-		RecipientMode:     gossipmessages.RECIPIENT_LIST_MODE_BROADCAST,
-		Payloads:          payloads,
-	})
+	return nil, s.broadcast(ctx, header, payloads)
 }
 
 func (s *Service) receivedBenchmarkConsensusCommit(ctx context.Context, header *gossipmessages.Header, payloads [][]byte) {
@@ -86,7 +80,7 @@ func (s *Service) SendBenchmarkConsensusCommitted(ctx context.Context, input *go
 		return nil, err
 	}
 
-	return nil, s.transport.Send(ctx, &adapter.TransportData{
+	return nil, s.send(ctx, &adapter.TransportData{
 		SenderNodeAddress:      s.config.NodeAddress(),
 		RecipientMode:          gossipmessages.RECIPIENT_LIST_MODE_LIST,
 		RecipientNodeAddresses: []primitives.NodeAddress{input.RecipientNodeAddress},
@@ -52,21 +52,15 @@ func createBlockSyncServerChildContextFrom(ctx context.Context) context.Context
 }
 
 func (s *Service) BroadcastBlockAvailabilityRequest(ctx context.Context, input *gossiptopics.BlockAvailabilityRequestInput) (*gossiptopics.EmptyOutput, error) {
-	header := (&gossipmessages.HeaderBuilder{
-		Topic:          gossipmessages.HEADER_TOPIC_BLOCK_SYNC,
-		BlockSync:      gossipmessages.BLOCK_SYNC_AVAILABILITY_REQUEST,
-		RecipientMode:  gossipmessages.RECIPIENT_LIST_MODE_BROADCAST,
-		VirtualChainId: s.config.VirtualChainId(),
-	}).Build()
+	header := s.broadcastHeader(&gossipmessages.HeaderBuilder{
+		Topic:     gossipmessages.HEADER_TOPIC_BLOCK_SYNC,
+		BlockSync: gossipmessages.BLOCK_SYNC_AVAILABILITY_REQUEST,
+	})
 	payloads, err := codec.EncodeBlockAvailabilityRequest(header, input.Message)
 	if err != nil {
 		return nil, err
 	}
-	return nil, s.transport.Send(ctx, &adapter.TransportData{
-		SenderNodeAddress: s.config.NodeAddress(),
-		RecipientMode:     gossipmessages.RECIPIENT_LIST_MODE_BROADCAST,
-		Payloads:          payloads,
-	})
+	return nil, s.broadcast(ctx, header, payloads)
 }
 
 func (s *Service) receivedBlockSyncAvailabilityRequest(ctx context.Context, header *gossipmessages.Header, payloads [][]byte) {
@@ -99,7 +93,7 @@ func (s *Service) SendBlockAvailabilityResponse(ctx context.Context, input *goss
 		return nil, err
 	}
 
-	return nil, s.transport.Send(ctx, &adapter.TransportData{
+	return nil, s.send(ctx, &adapter.TransportData{
 		SenderNodeAddress:      s.config.NodeAddress(),
 		RecipientMode:          gossipmessages.RECIPIENT_LIST_MODE_LIST,
 		RecipientNodeAddresses: []primitives.NodeAddress{input.RecipientNodeAddress},
@@ -137,7 +131,7 @@ func (s *Service) SendBlockSyncRequest(ctx context.Context, input *gossiptopics.
 		return nil, err
 	}
 
-	return nil, s.transport.Send(ctx, &adapter.TransportData{
+	return nil, s.send(ctx, &adapter.TransportData{
 		SenderNodeAddress:      s.config.NodeAddress(),
 		RecipientMode:          gossipmessages.RECIPIENT_LIST_MODE_LIST,
 		RecipientNodeAddresses: []primitives.NodeAddress{input.RecipientNodeAddress},
@@ -179,7 +173,7 @@ func (s *Service) SendBlockSyncResponse(ctx context.Context, input *gossiptopics
 		return nil, err
 	}
 
-	return nil, s.transport.Send(ctx, &adapter.TransportData{
+	return nil, s.send(ctx, &adapter.TransportData{
 		SenderNodeAddress:      s.config.NodeAddress(),
 		RecipientMode:          gossipmessages.RECIPIENT_LIST_MODE_LIST,
 		RecipientNodeAddresses: []primitives.NodeAddress{input.RecipientNodeAddress},
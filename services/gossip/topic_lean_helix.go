@@ -39,20 +39,32 @@ func (s *Service) receivedLeanHelixMessage(ctx context.Context, header *gossipme
 	}
 }
 
+// messages carrying a block, e.g. proposals, are relayed through their recipients when relay mode is active
 func (s *Service) SendLeanHelixMessage(ctx context.Context, input *gossiptopics.LeanHelixInput) (*gossiptopics.EmptyOutput, error) {
-	header := (&gossipmessages.HeaderBuilder{
+	builder := &gossipmessages.HeaderBuilder{
 		Topic:                  gossipmessages.HEADER_TOPIC_LEAN_HELIX,
 		RecipientMode:          input.RecipientsList.RecipientMode,
 		RecipientNodeAddresses: input.RecipientsList.RecipientNodeAddresses,
 		VirtualChainId:         s.config.VirtualChainId(),
-	}).Build()
+	}
+
+	relayed := input.Message.BlockPair != nil && input.RecipientsList.RecipientMode == gossipmessages.RECIPIENT_LIST_MODE_LIST && s.relayToListActive(input.RecipientsList.RecipientNodeAddresses)
+	var header *gossipmessages.Header
+	if relayed {
+		header = s.relayedListHeader(builder, input.RecipientsList.RecipientNodeAddresses)
+	} else {
+		header = builder.Build()
+	}
 
 	payloads, err := codec.EncodeLeanHelixMessage(header, input.Message)
 	if err != nil {
 		return nil, err
 	}
 
-	return nil, s.transport.Send(ctx, &adapter.TransportData{
+	if relayed {
+		return nil, s.broadcast(ctx, header, payloads)
+	}
+	return nil, s.send(ctx, &adapter.TransportData{
 		SenderNodeAddress:      s.config.NodeAddress(),
 		RecipientMode:          input.RecipientsList.RecipientMode,
 		RecipientNodeAddresses: input.RecipientsList.RecipientNodeAddresses,
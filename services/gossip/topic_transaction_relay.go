@@ -12,6 +12,7 @@ import (
 	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
 	"github.com/orbs-network/orbs-network-go/services/gossip/adapter"
 	"github.com/orbs-network/orbs-network-go/services/gossip/codec"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol/gossipmessages"
 	"github.com/orbs-network/orbs-spec/types/go/services/gossiptopics"
 	"github.com/orbs-network/scribe/log"
@@ -37,11 +38,33 @@ func (s *Service) BroadcastForwardedTransactions(ctx context.Context, input *gos
 		log.Stringable("sender", input.Message.Sender),
 		log.StringableSlice("transactions", digest.CalcTxHashsFromSignedTransactions(input.Message.SignedTransactions)))
 
-	header := (&gossipmessages.HeaderBuilder{
+	header := s.broadcastHeader(&gossipmessages.HeaderBuilder{
 		Topic:            gossipmessages.HEADER_TOPIC_TRANSACTION_RELAY,
 		TransactionRelay: gossipmessages.TRANSACTION_RELAY_FORWARDED_TRANSACTIONS,
-		RecipientMode:    gossipmessages.RECIPIENT_LIST_MODE_BROADCAST,
-		VirtualChainId:   s.config.VirtualChainId(),
+	})
+
+	payloads, err := codec.EncodeForwardedTransactions(header, input.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, s.broadcast(ctx, header, payloads)
+}
+
+// SendForwardedTransactions forwards transactions only to the given nodes, such as the upcoming consensus leaders
+func (s *Service) SendForwardedTransactions(ctx context.Context, recipients []primitives.NodeAddress, input *gossiptopics.ForwardedTransactionsInput) (*gossiptopics.EmptyOutput, error) {
+	s.logger.Info("sending forwarded transactions",
+		trace.LogFieldFrom(ctx),
+		log.Stringable("sender", input.Message.Sender),
+		log.StringableSlice("recipients", recipients),
+		log.StringableSlice("transactions", digest.CalcTxHashsFromSignedTransactions(input.Message.SignedTransactions)))
+
+	header := (&gossipmessages.HeaderBuilder{
+		Topic:                  gossipmessages.HEADER_TOPIC_TRANSACTION_RELAY,
+		TransactionRelay:       gossipmessages.TRANSACTION_RELAY_FORWARDED_TRANSACTIONS,
+		RecipientMode:          gossipmessages.RECIPIENT_LIST_MODE_LIST,
+		RecipientNodeAddresses: recipients,
+		VirtualChainId:         s.config.VirtualChainId(),
 	}).Build()
 
 	payloads, err := codec.EncodeForwardedTransactions(header, input.Message)
@@ -49,10 +72,11 @@ func (s *Service) BroadcastForwardedTransactions(ctx context.Context, input *gos
 		return nil, err
 	}
 
-	return nil, s.transport.Send(ctx, &adapter.TransportData{
-		SenderNodeAddress: s.config.NodeAddress(),
-		RecipientMode:     gossipmessages.RECIPIENT_LIST_MODE_BROADCAST,
-		Payloads:          payloads,
+	return nil, s.send(ctx, &adapter.TransportData{
+		SenderNodeAddress:      s.config.NodeAddress(),
+		RecipientMode:          gossipmessages.RECIPIENT_LIST_MODE_LIST,
+		RecipientNodeAddresses: recipients,
+		Payloads:               payloads,
 	})
 }
 
@@ -0,0 +1,359 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package gossip
+
+import (
+	"context"
+	"fmt"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-network-go/services/gossip/adapter"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol/gossipmessages"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	TRAFFIC_DIRECTION_INBOUND  = "Inbound"
+	TRAFFIC_DIRECTION_OUTBOUND = "Outbound"
+	TRAFFIC_UNKNOWN_PEER       = "unknown"
+	TRAFFIC_OTHER_PEERS        = "others"
+	TRAFFIC_MAX_EXPORTED_PEERS = 256
+	TRAFFIC_ROUND_TRIP_MAX     = 30 * time.Second
+)
+
+// a response is matched with the request of the corresponding type sent to the peer it came from, block sync requests
+// are further told apart by the first block height of their chunk range, which the response echoes; gossip messages
+// carry no request id otherwise. A broadcast request such as block availability is timed separately for every peer
+var roundTripRequestTypes = map[string]string{
+	gossipmessages.BLOCK_SYNC_AVAILABILITY_RESPONSE.String(): gossipmessages.BLOCK_SYNC_AVAILABILITY_REQUEST.String(),
+	gossipmessages.BLOCK_SYNC_RESPONSE.String():              gossipmessages.BLOCK_SYNC_REQUEST.String(),
+}
+
+// TrafficReport is the breakdown of gossip traffic served on the /debug/gossip http endpoint
+type TrafficReport struct {
+	Peers      []*PeerTraffic      `json:"peers"`
+	RoundTrips []*RoundTripTraffic `json:"roundTrips"`
+}
+
+type PeerTraffic struct {
+	Peer     string            `json:"peer"`
+	Inbound  []*MessageTraffic `json:"inbound"`
+	Outbound []*MessageTraffic `json:"outbound"`
+}
+
+type MessageTraffic struct {
+	Topic       string `json:"topic"`
+	MessageType string `json:"messageType"`
+	Messages    uint64 `json:"messages"`
+	Bytes       uint64 `json:"bytes"`
+}
+
+type RoundTripTraffic struct {
+	RequestType string  `json:"requestType"`
+	Samples     uint64  `json:"samples"`
+	AvgMillis   float64 `json:"avgMillis"`
+	MaxMillis   float64 `json:"maxMillis"`
+}
+
+type trafficKey struct {
+	direction   string
+	peer        string
+	topic       string
+	messageType string
+}
+
+type trafficCounters struct {
+	messages uint64
+	bytes    uint64
+}
+
+type roundTripCounters struct {
+	samples uint64
+	total   time.Duration
+	max     time.Duration
+	latency *metric.Histogram
+}
+
+type roundTripRequest struct {
+	requestType string
+	peer        string
+	requestId   primitives.BlockHeight
+}
+
+// counts gossip messages and bytes by direction, peer, topic and message type; the full breakdown is kept in memory for
+// the debug endpoint while the metric registry gets the breakdown by message type and, separately, by peer. Peers past
+// the first TRAFFIC_MAX_EXPORTED_PEERS are exported together, since hosts outside the topology may connect as well
+type trafficAccounting struct {
+	sync.Mutex
+	registry        metric.Registry
+	counters        map[trafficKey]*trafficCounters
+	gauges          map[string]*metric.Gauge
+	exportedPeers   map[string]bool
+	roundTrips      map[string]*roundTripCounters
+	pendingRequests map[roundTripRequest]time.Time
+}
+
+func newTrafficAccounting(registry metric.Registry) *trafficAccounting {
+	roundTrips := make(map[string]*roundTripCounters)
+	for _, requestType := range roundTripRequestTypes {
+		roundTrips[requestType] = &roundTripCounters{
+			latency: registry.NewLatency(fmt.Sprintf("Gossip.Traffic.RoundTrip.%s.Millis", requestType), TRAFFIC_ROUND_TRIP_MAX),
+		}
+	}
+
+	return &trafficAccounting{
+		registry:        registry,
+		counters:        make(map[trafficKey]*trafficCounters),
+		gauges:          make(map[string]*metric.Gauge),
+		exportedPeers:   make(map[string]bool),
+		roundTrips:      roundTrips,
+		pendingRequests: make(map[roundTripRequest]time.Time),
+	}
+}
+
+func (a *trafficAccounting) recordInbound(peer string, header *gossipmessages.Header, payloads [][]byte, now time.Time) {
+	a.Lock()
+	defer a.Unlock()
+
+	messageType := messageTypeOf(header)
+	a.add(TRAFFIC_DIRECTION_INBOUND, peer, topicNameOf(header.Topic()), messageType, totalSize(payloads))
+
+	if requestType, isResponse := roundTripRequestTypes[messageType]; isResponse {
+		request := roundTripRequest{requestType: requestType, peer: peer, requestId: roundTripRequestIdOf(header, payloads)}
+		if sentAt, found := a.pendingRequests[request]; found {
+			a.roundTrips[requestType].observe(now.Sub(sentAt))
+			delete(a.pendingRequests, request)
+		}
+	}
+}
+
+func (a *trafficAccounting) recordOutbound(peers []string, header *gossipmessages.Header, payloads [][]byte, now time.Time) {
+	a.Lock()
+	defer a.Unlock()
+
+	messageType := messageTypeOf(header)
+	size := totalSize(payloads)
+	for _, peer := range peers {
+		a.add(TRAFFIC_DIRECTION_OUTBOUND, peer, topicNameOf(header.Topic()), messageType, size)
+	}
+
+	if _, isRequest := a.roundTrips[messageType]; isRequest {
+		a.forgetUnansweredRequests(now.Add(-TRAFFIC_ROUND_TRIP_MAX))
+		requestId := roundTripRequestIdOf(header, payloads)
+		for _, peer := range peers {
+			a.pendingRequests[roundTripRequest{requestType: messageType, peer: peer, requestId: requestId}] = now
+		}
+	}
+}
+
+func (a *trafficAccounting) forgetUnansweredRequests(cutoff time.Time) {
+	for request, sentAt := range a.pendingRequests {
+		if sentAt.Before(cutoff) {
+			delete(a.pendingRequests, request)
+		}
+	}
+}
+
+// block sync requests and responses start with the chunk range of the request, other messages have no request id
+func roundTripRequestIdOf(header *gossipmessages.Header, payloads [][]byte) primitives.BlockHeight {
+	if header.Topic() != gossipmessages.HEADER_TOPIC_BLOCK_SYNC || len(payloads) < 2 {
+		return 0
+	}
+	switch header.BlockSync() {
+	case gossipmessages.BLOCK_SYNC_REQUEST, gossipmessages.BLOCK_SYNC_RESPONSE:
+		if chunkRange := gossipmessages.BlockSyncRangeReader(payloads[1]); chunkRange.IsValid() {
+			return chunkRange.FirstBlockHeight()
+		}
+	}
+	return 0
+}
+
+func (a *trafficAccounting) add(direction string, peer string, topic string, messageType string, size int) {
+	key := trafficKey{direction: direction, peer: peer, topic: topic, messageType: messageType}
+	counters, found := a.counters[key]
+	if !found {
+		counters = &trafficCounters{}
+		a.counters[key] = counters
+	}
+	counters.messages++
+	counters.bytes += uint64(size)
+
+	a.gauge(fmt.Sprintf("Gossip.Traffic.%s.%s.%s.Messages.Count", direction, topic, messageType)).Inc()
+	a.gauge(fmt.Sprintf("Gossip.Traffic.%s.%s.%s.Bytes.Count", direction, topic, messageType)).Add(int64(size))
+
+	exportedPeer := a.exportedPeerOf(peer)
+	a.gauge(fmt.Sprintf("Gossip.Traffic.%s.PeerMessages.%s.Count", direction, exportedPeer)).Inc()
+	a.gauge(fmt.Sprintf("Gossip.Traffic.%s.PeerBytes.%s.Count", direction, exportedPeer)).Add(int64(size))
+}
+
+func (a *trafficAccounting) exportedPeerOf(peer string) string {
+	if !a.exportedPeers[peer] {
+		if len(a.exportedPeers) >= TRAFFIC_MAX_EXPORTED_PEERS {
+			return TRAFFIC_OTHER_PEERS
+		}
+		a.exportedPeers[peer] = true
+	}
+	return peer
+}
+
+func (a *trafficAccounting) gauge(name string) *metric.Gauge {
+	g, found := a.gauges[name]
+	if !found {
+		g = a.registry.NewGauge(name)
+		a.gauges[name] = g
+	}
+	return g
+}
+
+func (r *roundTripCounters) observe(d time.Duration) {
+	r.latency.Record(d.Nanoseconds())
+	r.samples++
+	r.total += d
+	if d > r.max {
+		r.max = d
+	}
+}
+
+func (a *trafficAccounting) report() *TrafficReport {
+	a.Lock()
+	defer a.Unlock()
+
+	peers := make(map[string]*PeerTraffic)
+	for key, counters := range a.counters {
+		peer, found := peers[key.peer]
+		if !found {
+			peer = &PeerTraffic{Peer: key.peer, Inbound: []*MessageTraffic{}, Outbound: []*MessageTraffic{}}
+			peers[key.peer] = peer
+		}
+		traffic := &MessageTraffic{Topic: key.topic, MessageType: key.messageType, Messages: counters.messages, Bytes: counters.bytes}
+		if key.direction == TRAFFIC_DIRECTION_INBOUND {
+			peer.Inbound = append(peer.Inbound, traffic)
+		} else {
+			peer.Outbound = append(peer.Outbound, traffic)
+		}
+	}
+
+	report := &TrafficReport{Peers: []*PeerTraffic{}, RoundTrips: []*RoundTripTraffic{}}
+	for _, peer := range peers {
+		sortMessageTraffic(peer.Inbound)
+		sortMessageTraffic(peer.Outbound)
+		report.Peers = append(report.Peers, peer)
+	}
+	sort.Slice(report.Peers, func(i, j int) bool {
+		return report.Peers[i].Peer < report.Peers[j].Peer
+	})
+
+	for requestType, roundTrip := range a.roundTrips {
+		traffic := &RoundTripTraffic{RequestType: requestType, Samples: roundTrip.samples, MaxMillis: durationToMillis(roundTrip.max)}
+		if roundTrip.samples > 0 {
+			traffic.AvgMillis = durationToMillis(roundTrip.total) / float64(roundTrip.samples)
+		}
+		report.RoundTrips = append(report.RoundTrips, traffic)
+	}
+	sort.Slice(report.RoundTrips, func(i, j int) bool {
+		return report.RoundTrips[i].RequestType < report.RoundTrips[j].RequestType
+	})
+
+	return report
+}
+
+func sortMessageTraffic(traffic []*MessageTraffic) {
+	sort.Slice(traffic, func(i, j int) bool {
+		if traffic[i].Topic != traffic[j].Topic {
+			return traffic[i].Topic < traffic[j].Topic
+		}
+		return traffic[i].MessageType < traffic[j].MessageType
+	})
+}
+
+func durationToMillis(d time.Duration) float64 {
+	return float64(d.Nanoseconds()) / 1e+6
+}
+
+func totalSize(payloads [][]byte) (res int) {
+	for _, payload := range payloads {
+		res += len(payload)
+	}
+	return
+}
+
+func topicNameOf(topic gossipmessages.HeaderTopic) string {
+	switch topic {
+	case gossipmessages.HEADER_TOPIC_TRANSACTION_RELAY:
+		return "TransactionRelay"
+	case gossipmessages.HEADER_TOPIC_BLOCK_SYNC:
+		return "BlockSync"
+	case gossipmessages.HEADER_TOPIC_LEAN_HELIX:
+		return "LeanHelixConsensus"
+	case gossipmessages.HEADER_TOPIC_BENCHMARK_CONSENSUS:
+		return "BenchmarkConsensus"
+	default:
+		return "Unknown"
+	}
+}
+
+func messageTypeOf(header *gossipmessages.Header) string {
+	switch header.Topic() {
+	case gossipmessages.HEADER_TOPIC_TRANSACTION_RELAY:
+		return header.TransactionRelay().String()
+	case gossipmessages.HEADER_TOPIC_BLOCK_SYNC:
+		return header.BlockSync().String()
+	case gossipmessages.HEADER_TOPIC_LEAN_HELIX:
+		return header.LeanHelix().String()
+	case gossipmessages.HEADER_TOPIC_BENCHMARK_CONSENSUS:
+		return header.BenchmarkConsensus().String()
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// peers are identified by their node address; the tcp transport only knows it for a connection from a host of a single
+// topology peer, otherwise the message is accounted to the remote host
+func inboundPeerOf(ctx context.Context) string {
+	if nodeAddress, ok := adapter.SenderNodeAddressOf(ctx); ok {
+		return nodeAddress.String()
+	}
+	remoteAddress, ok := ctx.Value("peer-ip").(string)
+	if !ok || remoteAddress == "" {
+		return TRAFFIC_UNKNOWN_PEER
+	}
+	if host, _, err := net.SplitHostPort(remoteAddress); err == nil {
+		return host
+	}
+	return remoteAddress
+}
+
+func (s *Service) outboundPeersOf(data *adapter.TransportData) (peers []string) {
+	recipients := data.RecipientNodeAddresses
+	if data.RecipientMode == gossipmessages.RECIPIENT_LIST_MODE_BROADCAST {
+		recipients = s.relayTopology.all()
+	}
+
+	for _, recipient := range recipients {
+		if !recipient.Equal(s.config.NodeAddress()) {
+			peers = append(peers, recipient.String())
+		}
+	}
+	return
+}
+
+// all outgoing messages go through here so that they are accounted for
+func (s *Service) send(ctx context.Context, data *adapter.TransportData) error {
+	if len(data.Payloads) > 0 {
+		if header := gossipmessages.HeaderReader(data.Payloads[0]); header.IsValid() {
+			s.traffic.recordOutbound(s.outboundPeersOf(data), header, data.Payloads, time.Now())
+		}
+	}
+	return s.transport.Send(ctx, data)
+}
+
+func (s *Service) TrafficReport() *TrafficReport {
+	return s.traffic.report()
+}
@@ -0,0 +1,118 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package gossip
+
+import (
+	"fmt"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol/gossipmessages"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func blockSyncHeader(messageType gossipmessages.BlockSyncMessageType) *gossipmessages.Header {
+	return (&gossipmessages.HeaderBuilder{
+		Topic:         gossipmessages.HEADER_TOPIC_BLOCK_SYNC,
+		BlockSync:     messageType,
+		RecipientMode: gossipmessages.RECIPIENT_LIST_MODE_BROADCAST,
+	}).Build()
+}
+
+func TestTrafficAccounting_CountsMessagesAndBytesByPeerTopicAndMessageType(t *testing.T) {
+	registry := metric.NewRegistry()
+	traffic := newTrafficAccounting(registry)
+	now := time.Now()
+
+	header := blockSyncHeader(gossipmessages.BLOCK_SYNC_AVAILABILITY_REQUEST)
+	traffic.recordOutbound([]string{"a1a1a1a1", "b2b2b2b2"}, header, [][]byte{header.Raw(), make([]byte, 10)}, now)
+	traffic.recordInbound("10.0.0.1", header, [][]byte{header.Raw(), make([]byte, 3)}, now)
+	traffic.recordInbound("10.0.0.1", header, [][]byte{header.Raw(), make([]byte, 3)}, now)
+
+	size := int64(len(header.Raw()))
+	require.EqualValues(t, 2, registry.Get("Gossip.Traffic.Outbound.BlockSync.BLOCK_SYNC_AVAILABILITY_REQUEST.Messages.Count").(*metric.Gauge).Value())
+	require.EqualValues(t, 2*(size+10), registry.Get("Gossip.Traffic.Outbound.BlockSync.BLOCK_SYNC_AVAILABILITY_REQUEST.Bytes.Count").(*metric.Gauge).Value())
+	require.EqualValues(t, 1, registry.Get("Gossip.Traffic.Outbound.PeerMessages.a1a1a1a1.Count").(*metric.Gauge).Value())
+	require.EqualValues(t, size+10, registry.Get("Gossip.Traffic.Outbound.PeerBytes.a1a1a1a1.Count").(*metric.Gauge).Value())
+	require.EqualValues(t, 2, registry.Get("Gossip.Traffic.Inbound.PeerMessages.10.0.0.1.Count").(*metric.Gauge).Value())
+	require.EqualValues(t, 2*(size+3), registry.Get("Gossip.Traffic.Inbound.PeerBytes.10.0.0.1.Count").(*metric.Gauge).Value())
+
+	report := traffic.report()
+	require.Len(t, report.Peers, 3)
+	require.Equal(t, "10.0.0.1", report.Peers[0].Peer, "peers should be sorted")
+	require.Empty(t, report.Peers[0].Outbound)
+	require.Equal(t, &MessageTraffic{Topic: "BlockSync", MessageType: "BLOCK_SYNC_AVAILABILITY_REQUEST", Messages: 2, Bytes: uint64(2 * (size + 3))}, report.Peers[0].Inbound[0])
+	require.Equal(t, &MessageTraffic{Topic: "BlockSync", MessageType: "BLOCK_SYNC_AVAILABILITY_REQUEST", Messages: 1, Bytes: uint64(size + 10)}, report.Peers[1].Outbound[0])
+}
+
+func TestTrafficAccounting_ExportsPeersPastTheLimitTogether(t *testing.T) {
+	registry := metric.NewRegistry()
+	traffic := newTrafficAccounting(registry)
+	header := blockSyncHeader(gossipmessages.BLOCK_SYNC_AVAILABILITY_REQUEST)
+
+	for i := 0; i < TRAFFIC_MAX_EXPORTED_PEERS+2; i++ {
+		traffic.recordInbound(fmt.Sprintf("10.0.%d.%d", i/256, i%256), header, [][]byte{header.Raw()}, time.Now())
+	}
+
+	require.EqualValues(t, 2, registry.Get("Gossip.Traffic.Inbound.PeerMessages.others.Count").(*metric.Gauge).Value())
+	require.Len(t, traffic.report().Peers, TRAFFIC_MAX_EXPORTED_PEERS+2, "the debug endpoint should still break down every peer")
+}
+
+func blockSyncPayloads(messageType gossipmessages.BlockSyncMessageType, firstBlockHeight primitives.BlockHeight) [][]byte {
+	header := blockSyncHeader(messageType)
+	chunkRange := (&gossipmessages.BlockSyncRangeBuilder{FirstBlockHeight: firstBlockHeight, LastBlockHeight: firstBlockHeight + 10}).Build()
+	return [][]byte{header.Raw(), chunkRange.Raw()}
+}
+
+func roundTripOf(traffic *trafficAccounting, requestType string) *RoundTripTraffic {
+	for _, r := range traffic.report().RoundTrips {
+		if r.RequestType == requestType {
+			return r
+		}
+	}
+	return nil
+}
+
+func TestTrafficAccounting_MeasuresRoundTripOfEveryRequestFromItsPeer(t *testing.T) {
+	traffic := newTrafficAccounting(metric.NewRegistry())
+	now := time.Now()
+
+	request := blockSyncHeader(gossipmessages.BLOCK_SYNC_REQUEST)
+	response := blockSyncHeader(gossipmessages.BLOCK_SYNC_RESPONSE)
+
+	traffic.recordInbound("a1a1a1a1", response, blockSyncPayloads(gossipmessages.BLOCK_SYNC_RESPONSE, 1), now) // unsolicited, not measured
+	traffic.recordOutbound([]string{"a1a1a1a1"}, request, blockSyncPayloads(gossipmessages.BLOCK_SYNC_REQUEST, 1), now)
+	traffic.recordOutbound([]string{"b2b2b2b2"}, request, blockSyncPayloads(gossipmessages.BLOCK_SYNC_REQUEST, 11), now.Add(10*time.Millisecond))
+	traffic.recordInbound("b2b2b2b2", response, blockSyncPayloads(gossipmessages.BLOCK_SYNC_RESPONSE, 1), now.Add(20*time.Millisecond)) // not the request sent to this peer
+	traffic.recordInbound("a1a1a1a1", response, blockSyncPayloads(gossipmessages.BLOCK_SYNC_RESPONSE, 1), now.Add(40*time.Millisecond))
+	traffic.recordInbound("b2b2b2b2", response, blockSyncPayloads(gossipmessages.BLOCK_SYNC_RESPONSE, 11), now.Add(30*time.Millisecond))
+	traffic.recordInbound("a1a1a1a1", response, blockSyncPayloads(gossipmessages.BLOCK_SYNC_RESPONSE, 1), now.Add(90*time.Millisecond)) // already answered, not measured
+
+	roundTrip := roundTripOf(traffic, "BLOCK_SYNC_REQUEST")
+	require.NotNil(t, roundTrip)
+	require.EqualValues(t, 2, roundTrip.Samples)
+	require.EqualValues(t, 30, roundTrip.AvgMillis)
+	require.EqualValues(t, 40, roundTrip.MaxMillis)
+}
+
+func TestTrafficAccounting_MeasuresRoundTripOfBroadcastRequestForEveryPeer(t *testing.T) {
+	traffic := newTrafficAccounting(metric.NewRegistry())
+	now := time.Now()
+
+	request := blockSyncHeader(gossipmessages.BLOCK_SYNC_AVAILABILITY_REQUEST)
+	response := blockSyncHeader(gossipmessages.BLOCK_SYNC_AVAILABILITY_RESPONSE)
+
+	traffic.recordOutbound([]string{"a1a1a1a1", "b2b2b2b2"}, request, [][]byte{request.Raw()}, now)
+	traffic.recordInbound("a1a1a1a1", response, [][]byte{response.Raw()}, now.Add(10*time.Millisecond))
+	traffic.recordInbound("b2b2b2b2", response, [][]byte{response.Raw()}, now.Add(50*time.Millisecond))
+
+	roundTrip := roundTripOf(traffic, "BLOCK_SYNC_AVAILABILITY_REQUEST")
+	require.EqualValues(t, 2, roundTrip.Samples)
+	require.EqualValues(t, 30, roundTrip.AvgMillis)
+	require.EqualValues(t, 50, roundTrip.MaxMillis)
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/orbs-network/orbs-network-go/crypto/hash"
 	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
 	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
+	"github.com/orbs-network/orbs-network-go/services/processor/native/metering"
 	"github.com/orbs-network/orbs-network-go/test/contracts"
 	"github.com/orbs-network/scribe/log"
 	"github.com/pkg/errors"
@@ -103,6 +104,11 @@ func (c *nativeCompiler) Compile(ctx context.Context, code ...string) (*sdkConte
 
 	artifactsPath := c.config.ProcessorArtifactPath()
 
+	code, err := instrumentForMetering(code)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not instrument source code for metering")
+	}
+
 	hashOfCode := getHashOfCode(code)
 
 	logger.Info("writing source code to disk", log.String("artifact-path", artifactsPath), log.String("hash-of-code", hashOfCode))
@@ -137,6 +143,19 @@ func (c *nativeCompiler) Compile(ctx context.Context, code ...string) (*sdkConte
 	return so, err
 }
 
+// deployed contracts are instrumented before they are built so that their computation is limited and metered
+func instrumentForMetering(code []string) ([]string, error) {
+	var meteredCode []string
+	for i, codePart := range code {
+		meteredCodePart, err := metering.Instrument(codePart, i == 0)
+		if err != nil {
+			return nil, err
+		}
+		meteredCode = append(meteredCode, meteredCodePart)
+	}
+	return meteredCode, nil
+}
+
 func getHashOfCode(code []string) string {
 	var buffer string
 	for _, c := range code {
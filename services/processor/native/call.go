@@ -7,18 +7,30 @@
 package native
 
 import (
+	"fmt"
 	"github.com/orbs-network/orbs-network-go/services/processor/native/types"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
 	"github.com/pkg/errors"
 	"reflect"
+	"runtime/debug"
 )
 
+// returned as the contract's error when it panics, which includes a failed sdk call
+type contractPanicError struct {
+	message string
+	stack   []byte
+}
+
+func (e *contractPanicError) Error() string {
+	return e.message
+}
+
 func processMethodCall(executionContextId primitives.ExecutionContextId, contractInstance *types.ContractInstance, methodInstance types.MethodInstance, args *protocol.ArgumentArray, functionNameForErrors string) (contractOutputArgs *protocol.ArgumentArray, contractOutputErr error, err error) {
 
 	defer func() {
 		if r := recover(); r != nil {
-			contractOutputErr = errors.Errorf("%s", r)
+			contractOutputErr = &contractPanicError{message: fmt.Sprintf("%s", r), stack: debug.Stack()}
 			contractOutputArgs = createMethodOutputArgsWithString(contractOutputErr.Error())
 		}
 	}()
@@ -20,6 +20,7 @@ import (
 	"github.com/orbs-network/orbs-spec/types/go/services/handlers"
 	"github.com/orbs-network/scribe/log"
 	"github.com/pkg/errors"
+	"sync"
 	"time"
 )
 
@@ -41,6 +42,24 @@ func (c *CompositeRepository) ContractInfo(ctx context.Context, executionContext
 	return nil, nil
 }
 
+// ContractVersion asks the versioned repositories about contracts which the repositories before them do not have
+func (c *CompositeRepository) ContractVersion(ctx context.Context, executionContextId primitives.ExecutionContextId, contractName string) (uint32, error) {
+	for _, repo := range c.Nested {
+		if versioned, ok := repo.(VersionedRepository); ok {
+			return versioned.ContractVersion(ctx, executionContextId, contractName)
+		}
+		contractInfo, err := repo.ContractInfo(ctx, executionContextId, contractName)
+		if err != nil {
+			return 0, err
+		}
+		if contractInfo != nil {
+			return 0, nil
+		}
+	}
+
+	return 0, nil
+}
+
 func NewCompilingRepository(compiler adapter.Compiler, cfg config.NativeProcessorConfig, logger log.Logger, metricFactory metric.Factory) *CompilingRepository {
 	compilingRepository := &CompilingRepository{
 		compiler:                compiler,
@@ -49,6 +68,7 @@ func NewCompilingRepository(compiler adapter.Compiler, cfg config.NativeProcesso
 		sanitizer:               createSanitizer(),
 		deployedContracts:       metricFactory.NewGauge("Processor.Native.DeployedContracts.Count"),
 		contractCompilationTime: metricFactory.NewLatency("Processor.Native.ContractCompilationTime.Millis", 10*time.Second),
+		versions:                &contractVersions{versions: make(map[string]contractVersionOfBlock)},
 	}
 	return compilingRepository
 }
@@ -64,6 +84,20 @@ type CompilingRepository struct {
 	processCallTime         *metric.Histogram
 	contractCompilationTime *metric.Histogram
 	config                  config.NativeProcessorConfig
+
+	versions *contractVersions
+}
+
+// the version of a deployed contract does not change during a block since an upgrade is executed from the block after its own,
+// so _Deployments.getCodeVersion is called once per contract and block height
+type contractVersions struct {
+	sync.RWMutex
+	versions map[string]contractVersionOfBlock
+}
+
+type contractVersionOfBlock struct {
+	blockHeight uint64
+	version     uint32
 }
 
 func (r *CompilingRepository) SetSdkHandler(handler handlers.ContractSdkCallHandler) {
@@ -74,6 +108,49 @@ func (r *CompilingRepository) ContractInfo(ctx context.Context, executionContext
 	return r.retrieveDeployedContractInfoFromState(ctx, executionContextId, contractName)
 }
 
+// ContractVersion is the version of the deployed code executed in the current block
+func (r *CompilingRepository) ContractVersion(ctx context.Context, executionContextId primitives.ExecutionContextId, contractName string) (uint32, error) {
+	blockHeight, err := r.getBlockHeight(ctx, executionContextId)
+	if err != nil {
+		return 0, err
+	}
+	if version, found := r.versions.of(contractName, blockHeight); found {
+		return version, nil
+	}
+
+	arg0, err := r.callDeploymentSystemContract(ctx, executionContextId, deployments_systemcontract.METHOD_GET_CODE_VERSION, contractName)
+	if err != nil {
+		return 0, err
+	}
+	if !arg0.IsTypeUint32Value() {
+		return 0, errors.Errorf("callMethod Sdk.Service of _Deployments.getCodeVersion returned corrupt output value")
+	}
+
+	// a contract which is not deployed yet may be deployed later in the block
+	if arg0.Uint32Value() != 0 {
+		r.versions.set(contractName, blockHeight, arg0.Uint32Value())
+	}
+	return arg0.Uint32Value(), nil
+}
+
+func (v *contractVersions) of(contractName string, blockHeight uint64) (uint32, bool) {
+	v.RLock()
+	defer v.RUnlock()
+
+	versionOfBlock, found := v.versions[contractName]
+	if !found || versionOfBlock.blockHeight != blockHeight {
+		return 0, false
+	}
+	return versionOfBlock.version, true
+}
+
+func (v *contractVersions) set(contractName string, blockHeight uint64, version uint32) {
+	v.Lock()
+	defer v.Unlock()
+
+	v.versions[contractName] = contractVersionOfBlock{blockHeight: blockHeight, version: version}
+}
+
 func (r *CompilingRepository) retrieveDeployedContractInfoFromState(ctx context.Context, executionContextId primitives.ExecutionContextId, contractName string) (*sdkContext.ContractInfo, error) {
 	start := time.Now()
 
@@ -237,3 +314,63 @@ func (r *CompilingRepository) getCodeParts(ctx context.Context, executionContext
 
 	return arg0.Uint32Value(), nil
 }
+
+func (r *CompilingRepository) getBlockHeight(ctx context.Context, executionContextId primitives.ExecutionContextId) (uint64, error) {
+	output, err := r.sdkHandler.HandleSdkCall(ctx, &handlers.HandleSdkCallInput{
+		ContextId:       executionContextId,
+		OperationName:   sdk.SDK_OPERATION_NAME_ENV,
+		MethodName:      "getBlockHeight",
+		InputArguments:  []*protocol.Argument{},
+		PermissionScope: protocol.PERMISSION_SCOPE_SYSTEM,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(output.OutputArguments) != 1 || !output.OutputArguments[0].IsTypeUint64Value() {
+		return 0, errors.Errorf("getBlockHeight Sdk.Env returned corrupt output value")
+	}
+	return output.OutputArguments[0].Uint64Value(), nil
+}
+
+func (r *CompilingRepository) callDeploymentSystemContract(ctx context.Context, executionContextId primitives.ExecutionContextId, methodName string, args ...interface{}) (*protocol.Argument, error) {
+	inputArguments, err := protocol.ArgumentArrayFromNatives(args)
+	if err != nil {
+		panic(errors.Wrap(err, "input arguments"))
+	}
+
+	output, err := r.sdkHandler.HandleSdkCall(ctx, &handlers.HandleSdkCallInput{
+		ContextId:     executionContextId,
+		OperationName: sdk.SDK_OPERATION_NAME_SERVICE,
+		MethodName:    "callMethod",
+		InputArguments: []*protocol.Argument{
+			(&protocol.ArgumentBuilder{
+				// serviceName
+				Type:        protocol.ARGUMENT_TYPE_STRING_VALUE,
+				StringValue: deployments_systemcontract.CONTRACT_NAME,
+			}).Build(),
+			(&protocol.ArgumentBuilder{
+				// methodName
+				Type:        protocol.ARGUMENT_TYPE_STRING_VALUE,
+				StringValue: methodName,
+			}).Build(),
+			(&protocol.ArgumentBuilder{
+				// inputArgs
+				Type:       protocol.ARGUMENT_TYPE_BYTES_VALUE,
+				BytesValue: inputArguments.Raw(),
+			}).Build(),
+		},
+		PermissionScope: protocol.PERMISSION_SCOPE_SYSTEM,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(output.OutputArguments) != 1 || !output.OutputArguments[0].IsTypeBytesValue() {
+		return nil, errors.Errorf("callMethod Sdk.Service of _Deployments.%s returned corrupt output value", methodName)
+	}
+	argIterator := protocol.ArgumentArrayReader(output.OutputArguments[0].BytesValue()).ArgumentsIterator()
+	if !argIterator.HasNext() {
+		return nil, errors.Errorf("callMethod Sdk.Service of _Deployments.%s returned corrupt output value", methodName)
+	}
+	return argIterator.NextArguments(), nil
+}
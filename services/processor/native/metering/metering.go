@@ -0,0 +1,129 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package metering
+
+import (
+	"bytes"
+	"github.com/pkg/errors"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+const (
+	meteredIdentifierPrefix     = "__orbs"
+	computationStepFunctionName = "__orbsComputationStep"
+	computationStepImportName   = "__orbsSdkContext"
+	computationStepImportPath   = "github.com/orbs-network/orbs-contract-sdk/go/context"
+)
+
+// declared once per contract, it reports a step to the sdk handler of the running call (see sdk.SdkComputationStep),
+// code running outside of a call (like package initialization) has no context and is not metered
+const computationStepFunctionsSource = `
+func __orbsComputationStep() {
+	contextId, handler, ok := __orbsComputationContext()
+	if !ok {
+		return
+	}
+	if stepper, ok := handler.(interface {
+		SdkComputationStep(executionContextId __orbsSdkContext.ContextId)
+	}); ok {
+		stepper.SdkComputationStep(contextId)
+	}
+}
+
+func __orbsComputationContext() (contextId __orbsSdkContext.ContextId, handler __orbsSdkContext.SdkHandler, ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	contextId, handler, _ = __orbsSdkContext.GetContext()
+	return contextId, handler, true
+}
+`
+
+// Instrument adds a computation step at the start of every function and loop body of a deployed contract source file,
+// so that the computation of the contract is limited and metered deterministically. The step functions are declared in
+// the first source file of the contract only (declareStepFunctions).
+func Instrument(code string, declareStepFunctions bool) (string, error) {
+	fset := token.NewFileSet()
+
+	astFile, err := parser.ParseFile(fset, "", code, 0)
+	if err != nil {
+		return "", errors.Wrap(err, "metering instrumentation cannot parse source file")
+	}
+
+	err = verifyMeterable(astFile)
+	if err != nil {
+		return "", errors.Wrap(err, "metering instrumentation error")
+	}
+
+	ast.Inspect(astFile, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.FuncDecl:
+			if n.Body != nil {
+				n.Body.List = withComputationStep(n.Body.List)
+			}
+		case *ast.FuncLit:
+			n.Body.List = withComputationStep(n.Body.List)
+		case *ast.ForStmt:
+			n.Body.List = withComputationStep(n.Body.List)
+		case *ast.RangeStmt:
+			n.Body.List = withComputationStep(n.Body.List)
+		}
+		return true
+	})
+
+	if declareStepFunctions {
+		astFile.Decls = append([]ast.Decl{&ast.GenDecl{
+			Tok: token.IMPORT,
+			Specs: []ast.Spec{&ast.ImportSpec{
+				Name: ast.NewIdent(computationStepImportName),
+				Path: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(computationStepImportPath)},
+			}},
+		}}, astFile.Decls...)
+	}
+
+	var resBuffer bytes.Buffer
+	err = printer.Fprint(&resBuffer, fset, astFile)
+	if err != nil {
+		return "", errors.Wrap(err, "metering instrumentation cannot print source")
+	}
+	if declareStepFunctions {
+		resBuffer.WriteString(computationStepFunctionsSource)
+	}
+
+	return resBuffer.String(), nil
+}
+
+// a goto can loop without a loop statement, and the identifiers of the instrumentation are reserved
+func verifyMeterable(astFile *ast.File) error {
+	var err error
+	ast.Inspect(astFile, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.BranchStmt:
+			if n.Tok == token.GOTO {
+				err = errors.New("goto not allowed")
+			}
+		case *ast.Ident:
+			if strings.HasPrefix(n.Name, meteredIdentifierPrefix) {
+				err = errors.Errorf("identifier %s not allowed, the prefix %s is reserved", n.Name, meteredIdentifierPrefix)
+			}
+		}
+		return err == nil
+	})
+	return err
+}
+
+func withComputationStep(statements []ast.Stmt) []ast.Stmt {
+	step := &ast.ExprStmt{X: &ast.CallExpr{Fun: ast.NewIdent(computationStepFunctionName)}}
+	return append([]ast.Stmt{step}, statements...)
+}
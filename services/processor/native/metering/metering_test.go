@@ -0,0 +1,71 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package metering
+
+import (
+	"github.com/stretchr/testify/require"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+const meteredContractForTests = `package main
+
+func sum(values []uint64) (total uint64) {
+	for _, value := range values {
+		total += value
+	}
+	add := func(a, b uint64) uint64 {
+		return a + b
+	}
+	for i := 0; i < 3; i++ {
+		total = add(total, 1)
+	}
+	return
+}
+`
+
+func countComputationSteps(t *testing.T, code string) int {
+	astFile, err := parser.ParseFile(token.NewFileSet(), "", code, 0)
+	require.NoError(t, err, "instrumented code should parse")
+	steps := 0
+	ast.Inspect(astFile, func(node ast.Node) bool {
+		if call, ok := node.(*ast.CallExpr); ok {
+			if ident, ok := call.Fun.(*ast.Ident); ok && ident.Name == computationStepFunctionName {
+				steps++
+			}
+		}
+		return true
+	})
+	return steps
+}
+
+func TestInstrumentForComputationMetering_AddsAStepToEveryFunctionAndLoopBody(t *testing.T) {
+	code, err := Instrument(meteredContractForTests, false)
+	require.NoError(t, err)
+
+	require.Equal(t, 4, countComputationSteps(t, code), "the function, the function literal and both loops should be metered")
+	require.NotContains(t, code, computationStepImportPath, "the step function should be declared in the first source file only")
+}
+
+func TestInstrumentForComputationMetering_DeclaresTheStepFunctionInTheFirstSourceFile(t *testing.T) {
+	code, err := Instrument(meteredContractForTests, true)
+	require.NoError(t, err)
+
+	require.Contains(t, code, computationStepImportPath)
+	require.Contains(t, code, "func "+computationStepFunctionName+"()")
+	require.Equal(t, 4, countComputationSteps(t, code), "the step function itself should not be metered")
+}
+
+func TestInstrumentForComputationMetering_RejectsUnmeterableCode(t *testing.T) {
+	_, err := Instrument("package main\n\nfunc loop() {\nstart:\n\tgoto start\n}\n", false)
+	require.Error(t, err, "goto should be rejected")
+
+	_, err = Instrument("package main\n\nfunc __orbsComputationStep() {}\n", false)
+	require.Error(t, err, "reserved identifiers should be rejected")
+}
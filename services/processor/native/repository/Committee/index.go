@@ -13,6 +13,7 @@ import (
 // helpers for avoiding reliance on strings throughout the system
 const CONTRACT_NAME = "_Committee"
 const METHOD_GET_ORDERED_COMMITTEE = "getOrderedCommittee" // used with election
+const METHOD_GET_NEXT_ORDERED_COMMITTEE = "getNextOrderedCommittee"
 const METHOD_UPDATE_MISSES = "updateMisses"
 
 var PUBLIC = sdk.Export(getOrderedCommittee, getNextOrderedCommittee, getReputation, getAllCommitteeReputations, getMisses, getAllCommitteeMisses, updateMisses)
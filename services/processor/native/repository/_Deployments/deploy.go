@@ -7,6 +7,7 @@
 package deployments_systemcontract
 
 import (
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/address"
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/service"
 	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
 	"github.com/orbs-network/orbs-network-go/services/processor/native/repository/Committee"
@@ -18,29 +19,26 @@ import (
 	"strconv"
 )
 
-func getInfo(serviceName string) uint32 {
+// the status is returned as well so a call to a service only needs getInfo to find whether it may run
+func getInfo(serviceName string) (uint32, uint32) {
 	if IsImplicitlyDeployed(serviceName) {
-		return uint32(protocol.PROCESSOR_TYPE_NATIVE)
+		return uint32(protocol.PROCESSOR_TYPE_NATIVE), SERVICE_STATUS_ACTIVE
 	}
 
 	processorType := _readProcessor(serviceName)
 	if processorType == 0 {
 		panic("contract not deployed")
 	}
-	return processorType
+	return processorType, _readStatus(serviceName)
 }
 
 func getCode(serviceName string) []byte {
 	return getCodePart(serviceName, 0)
 }
 
+// the code parts of a contract are those of the version executed in the current block
 func getCodePart(serviceName string, index uint32) []byte {
-	code := _readCode(serviceName, index)
-	if len(code) == 0 {
-		panic("contract code not available")
-	}
-
-	return code
+	return getCodePartOfVersion(serviceName, _effectiveVersion(serviceName), index)
 }
 
 func getCodeParts(serviceName string) uint32 {
@@ -48,7 +46,7 @@ func getCodeParts(serviceName string) uint32 {
 	if processorType == 0 {
 		panic("contract not deployed")
 	}
-	return _codeCounter(serviceName) + 1
+	return getCodePartsOfVersion(serviceName, _effectiveVersion(serviceName))
 }
 
 func deployService(serviceName string, processorType uint32, code ...[]byte) {
@@ -67,6 +65,11 @@ func deployService(serviceName string, processorType uint32, code ...[]byte) {
 	}
 
 	_writeProcessor(serviceName, processorType)
+	_writeDeployer(serviceName, address.GetSignerAddress())
+	// the signer of a transaction which the virtual machine deployed a pre-built contract on its first call to does not own the contract
+	if !_isPreBuilt(code) {
+		_writeOwner(serviceName, address.GetSignerAddress())
+	}
 
 	if len(code) > 0 {
 		for i, c := range code {
@@ -79,6 +82,16 @@ func deployService(serviceName string, processorType uint32, code ...[]byte) {
 	service.CallMethod(serviceName, "_init")
 }
 
+// pre-built contracts are deployed without code, the virtual machine deploys them on their first call
+func _isPreBuilt(code [][]byte) bool {
+	return len(code) == 1 && len(code[0]) == 0
+}
+
+// contracts deployed before owners were recorded do not tell whether they are pre-built from their owner, only from their code
+func _isPreBuiltDeployment(serviceName string) bool {
+	return _codeCounter(serviceName) == 0 && len(_readCode(serviceName, 0)) == 0
+}
+
 // Function was made go "public" to allow testing, it is not public in the contract.
 func IsImplicitlyDeployed(serviceName string) bool {
 	switch serviceName {
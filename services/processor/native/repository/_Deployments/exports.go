@@ -12,6 +12,19 @@ var PUBLIC = sdk.Export(getInfo,
 	getCode,
 	getCodePart,
 	getCodeParts,
+	getCodeVersion,
+	getCodePartOfVersion,
+	getCodePartsOfVersion,
+	getDeployer,
+	getOwner,
 	deployService,
+	upgradeService,
+	getStatus,
+	pauseService,
+	unpauseService,
+	disableService,
+	transferOwnership,
 	lockNativeDeployment,
 	unlockNativeDeployment)
+
+var SYSTEM = sdk.Export(setPreBuiltServiceStatus)
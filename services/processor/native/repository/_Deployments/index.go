@@ -12,4 +12,20 @@ const METHOD_GET_INFO = "getInfo"
 const METHOD_GET_CODE = "getCode"
 const METHOD_GET_CODE_PART = "getCodePart"
 const METHOD_GET_CODE_PARTS = "getCodeParts"
+const METHOD_GET_CODE_VERSION = "getCodeVersion"
+const METHOD_GET_CODE_PART_OF_VERSION = "getCodePartOfVersion"
+const METHOD_GET_CODE_PARTS_OF_VERSION = "getCodePartsOfVersion"
 const METHOD_DEPLOY_SERVICE = "deployService"
+const METHOD_UPGRADE_SERVICE = "upgradeService"
+const METHOD_GET_STATUS = "getStatus"
+const METHOD_PAUSE_SERVICE = "pauseService"
+const METHOD_UNPAUSE_SERVICE = "unpauseService"
+const METHOD_DISABLE_SERVICE = "disableService"
+const METHOD_TRANSFER_OWNERSHIP = "transferOwnership"
+const METHOD_SET_PRE_BUILT_SERVICE_STATUS = "setPreBuiltServiceStatus"
+
+// the status of a service is the second output of getInfo
+const SERVICE_STATUS_ACTIVE = uint32(0)
+const SERVICE_STATUS_PAUSED = uint32(1)
+const SERVICE_STATUS_PAUSED_ALLOWING_QUERIES = uint32(2)
+const SERVICE_STATUS_DISABLED = uint32(3)
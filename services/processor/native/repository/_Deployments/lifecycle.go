@@ -0,0 +1,100 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package deployments_systemcontract
+
+import (
+	"fmt"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
+)
+
+// a paused service can be unpaused by its owner, a disabled service can never be called, upgraded or transferred again
+func pauseService(serviceName string, allowQueries bool) {
+	_validateServiceLifecycleChange(serviceName)
+
+	status := SERVICE_STATUS_PAUSED
+	if allowQueries {
+		status = SERVICE_STATUS_PAUSED_ALLOWING_QUERIES
+	}
+	_writeStatus(serviceName, status)
+}
+
+func unpauseService(serviceName string) {
+	_validateServiceLifecycleChange(serviceName)
+
+	if _readStatus(serviceName) == SERVICE_STATUS_ACTIVE {
+		panic("contract is not paused")
+	}
+	_writeStatus(serviceName, SERVICE_STATUS_ACTIVE)
+}
+
+func disableService(serviceName string) {
+	_validateServiceLifecycleChange(serviceName)
+
+	_writeStatus(serviceName, SERVICE_STATUS_DISABLED)
+}
+
+func transferOwnership(serviceName string, newOwner []byte) {
+	_validateServiceLifecycleChange(serviceName)
+
+	if len(newOwner) != 20 {
+		panic("new owner must be an address of 20 bytes")
+	}
+	_writeOwner(serviceName, newOwner)
+}
+
+// pre-built contracts belong to the node rather than to the signer who deployed them on their first call,
+// only system contracts change their status
+func setPreBuiltServiceStatus(serviceName string, status uint32) {
+	if _readProcessor(serviceName) == 0 {
+		panic("contract not deployed")
+	}
+	if !_isPreBuiltDeployment(serviceName) {
+		panic("contract is not pre-built")
+	}
+	_validateNotDisabled(serviceName)
+
+	if status > SERVICE_STATUS_DISABLED {
+		panic(fmt.Sprintf("unknown status %d", status))
+	}
+	_writeStatus(serviceName, status)
+}
+
+func getStatus(serviceName string) uint32 {
+	if IsImplicitlyDeployed(serviceName) {
+		return SERVICE_STATUS_ACTIVE
+	}
+	if _readProcessor(serviceName) == 0 {
+		panic("contract not deployed")
+	}
+	return _readStatus(serviceName)
+}
+
+func _validateServiceLifecycleChange(serviceName string) {
+	if _readProcessor(serviceName) == 0 {
+		panic("contract not deployed")
+	}
+	_validateNotDisabled(serviceName)
+	_validateOwner(serviceName)
+}
+
+func _validateNotDisabled(serviceName string) {
+	if _readStatus(serviceName) == SERVICE_STATUS_DISABLED {
+		panic("contract is disabled")
+	}
+}
+
+func _readStatus(serviceName string) uint32 {
+	return state.ReadUint32(_statusKey(serviceName))
+}
+
+func _writeStatus(serviceName string, status uint32) {
+	state.WriteUint32(_statusKey(serviceName), status)
+}
+
+func _statusKey(serviceName string) []byte {
+	return []byte(serviceName + ".Status")
+}
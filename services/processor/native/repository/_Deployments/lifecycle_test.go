@@ -0,0 +1,139 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package deployments_systemcontract
+
+import (
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/address"
+	. "github.com/orbs-network/orbs-contract-sdk/go/testing/unit"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestPauseAndUnpauseService(t *testing.T) {
+	InSystemScope(AnAddress(), nil, func(m Mockery) {
+		m.MockServiceCallMethod("hello", "_init", nil)
+		deployService("hello", 2, []byte("contract"))
+
+		require.PanicsWithValue(t, "contract is not paused", func() {
+			unpauseService("hello")
+		})
+
+		pauseService("hello", false)
+		processorType, status := getInfo("hello")
+		require.EqualValues(t, 2, processorType)
+		require.EqualValues(t, SERVICE_STATUS_PAUSED, status)
+
+		pauseService("hello", true)
+		require.EqualValues(t, SERVICE_STATUS_PAUSED_ALLOWING_QUERIES, getStatus("hello"))
+
+		unpauseService("hello")
+		require.EqualValues(t, SERVICE_STATUS_ACTIVE, getStatus("hello"))
+	})
+}
+
+func TestTransferOwnership(t *testing.T) {
+	owner := AnAddress()
+	newOwner := AnAddress()
+	InSystemScope(owner, nil, func(m Mockery) {
+		m.MockServiceCallMethod("hello", "_init", nil)
+		deployService("hello", 2, []byte("contract"))
+
+		require.PanicsWithValue(t, "new owner must be an address of 20 bytes", func() {
+			transferOwnership("hello", []byte{0x01, 0x02})
+		})
+
+		transferOwnership("hello", newOwner)
+		require.EqualValues(t, newOwner, getOwner("hello"))
+		require.EqualValues(t, owner, getDeployer("hello"))
+
+		require.Panics(t, func() {
+			pauseService("hello", false)
+		}, "the previous owner should no longer control the contract")
+	})
+}
+
+func TestLifecycleChangesRequireTheOwner(t *testing.T) {
+	InSystemScope(nil, nil, func(m Mockery) {
+		m.MockServiceCallMethod("hello", "_init", nil)
+
+		require.PanicsWithValue(t, "contract not deployed", func() {
+			pauseService("hello", false)
+		})
+
+		deployService("hello", 2, []byte("contract"))
+		_writeOwner("hello", []byte{0x01, 0x02})
+
+		require.PanicsWithValue(t, "contract is owned by 0x0102", func() {
+			pauseService("hello", false)
+		})
+		require.PanicsWithValue(t, "contract is owned by 0x0102", func() {
+			disableService("hello")
+		})
+		require.EqualValues(t, SERVICE_STATUS_ACTIVE, getStatus("hello"))
+	})
+}
+
+func TestDisabledServiceCannotBeChangedAgain(t *testing.T) {
+	InSystemScope(AnAddress(), nil, func(m Mockery) {
+		m.MockServiceCallMethod("hello", "_init", nil)
+		m.MockEnvBlockHeight(10)
+		deployService("hello", 2, []byte("contract"))
+
+		disableService("hello")
+		require.EqualValues(t, SERVICE_STATUS_DISABLED, getStatus("hello"))
+
+		require.PanicsWithValue(t, "contract is disabled", func() {
+			unpauseService("hello")
+		})
+		require.PanicsWithValue(t, "contract is disabled", func() {
+			transferOwnership("hello", AnAddress())
+		})
+		require.PanicsWithValue(t, "contract is disabled", func() {
+			upgradeService("hello", []byte("fixed contract"))
+		})
+	})
+}
+
+func TestStatusOfImplicitlyDeployedContract(t *testing.T) {
+	InSystemScope(nil, nil, func(m Mockery) {
+		require.EqualValues(t, SERVICE_STATUS_ACTIVE, getStatus(CONTRACT_NAME))
+		require.PanicsWithValue(t, "contract not deployed", func() {
+			getStatus("hello")
+		})
+	})
+}
+
+func TestPreBuiltServiceIsControlledBySystemContractsOnly(t *testing.T) {
+	InSystemScope(AnAddress(), nil, func(m Mockery) {
+		m.MockServiceCallMethod("hello", "_init", nil)
+		deployService("hello", 1, []byte{})
+		_writeOwner("hello", address.GetSignerAddress()) // recorded before pre-built contracts were deployed without an owner
+
+		require.PanicsWithValue(t, "pre-built contract is controlled by system contracts only", func() {
+			pauseService("hello", false)
+		})
+		require.PanicsWithValue(t, "pre-built contract is controlled by system contracts only", func() {
+			upgradeService("hello", []byte("contract"))
+		})
+
+		setPreBuiltServiceStatus("hello", SERVICE_STATUS_PAUSED)
+		require.EqualValues(t, SERVICE_STATUS_PAUSED, getStatus("hello"))
+		setPreBuiltServiceStatus("hello", SERVICE_STATUS_ACTIVE)
+		require.EqualValues(t, SERVICE_STATUS_ACTIVE, getStatus("hello"))
+	})
+}
+
+func TestSetPreBuiltServiceStatusRequiresAPreBuiltService(t *testing.T) {
+	InSystemScope(AnAddress(), nil, func(m Mockery) {
+		m.MockServiceCallMethod("hello", "_init", nil)
+		deployService("hello", 2, []byte("contract"))
+
+		require.PanicsWithValue(t, "contract is not pre-built", func() {
+			setPreBuiltServiceStatus("hello", SERVICE_STATUS_PAUSED)
+		})
+	})
+}
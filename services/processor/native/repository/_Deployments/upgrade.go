@@ -0,0 +1,160 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package deployments_systemcontract
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/address"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/env"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
+	"github.com/orbs-network/orbs-network-go/crypto/encoding"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"strconv"
+)
+
+// the code of version 1 is kept under the keys it had before upgrades were supported,
+// every upgrade adds a version which is executed from the block after the one it was committed in
+
+// upgradeService replaces the code of a deployed service and keeps its state, only its owner can upgrade it
+func upgradeService(serviceName string, code ...[]byte) {
+	processorType := _readProcessor(serviceName)
+	if processorType == 0 {
+		panic("contract not deployed")
+	}
+	if processorType == uint32(protocol.PROCESSOR_TYPE_NATIVE) {
+		_validateNativeDeploymentLock()
+	}
+	_validateNotDisabled(serviceName)
+	_validateOwner(serviceName)
+
+	if len(code) == 0 {
+		panic("contract doesn't have any code")
+	}
+
+	blockHeight := env.GetBlockHeight()
+	if _readVersionBlockHeight(serviceName) > blockHeight {
+		panic("contract was already upgraded in this block")
+	}
+
+	version := _latestVersion(serviceName) + 1
+	for i, c := range code {
+		state.WriteBytes(_versionedCodeKey(serviceName, version, uint32(i)), c)
+	}
+	state.WriteUint32(_versionedCodePartsKey(serviceName, version), uint32(len(code)))
+	state.WriteUint32(_versionKey(serviceName), version)
+	state.WriteUint64(_versionBlockHeightKey(serviceName), blockHeight+1)
+}
+
+// getCodeVersion is the version executed in the current block, zero for contracts which are not deployed or are implicitly deployed
+func getCodeVersion(serviceName string) uint32 {
+	if _readProcessor(serviceName) == 0 {
+		return 0
+	}
+	return _effectiveVersion(serviceName)
+}
+
+func getCodePartOfVersion(serviceName string, version uint32, index uint32) []byte {
+	var code []byte
+	if version == 1 {
+		code = _readCode(serviceName, index)
+	} else if version > 1 && version <= _latestVersion(serviceName) {
+		code = state.ReadBytes(_versionedCodeKey(serviceName, version, index))
+	}
+	if len(code) == 0 {
+		panic("contract code not available")
+	}
+	return code
+}
+
+func getCodePartsOfVersion(serviceName string, version uint32) uint32 {
+	if _readProcessor(serviceName) == 0 {
+		panic("contract not deployed")
+	}
+	if version == 1 {
+		return _codeCounter(serviceName) + 1
+	}
+	if version == 0 || version > _latestVersion(serviceName) {
+		panic(fmt.Sprintf("contract has no version %d", version))
+	}
+	return state.ReadUint32(_versionedCodePartsKey(serviceName, version))
+}
+
+// contracts deployed before deployers were recorded have neither a deployer nor an owner
+func getDeployer(serviceName string) []byte {
+	return state.ReadBytes(_deployerKey(serviceName))
+}
+
+func getOwner(serviceName string) []byte {
+	return state.ReadBytes(_ownerKey(serviceName))
+}
+
+func _validateOwner(serviceName string) {
+	if _isPreBuiltDeployment(serviceName) {
+		panic("pre-built contract is controlled by system contracts only")
+	}
+	owner := getOwner(serviceName)
+	if len(owner) == 0 {
+		panic("contract has no owner")
+	}
+	if !bytes.Equal(owner, address.GetSignerAddress()) {
+		panic(fmt.Sprintf("contract is owned by %s", encoding.EncodeHex(owner)))
+	}
+}
+
+func _effectiveVersion(serviceName string) uint32 {
+	version := _latestVersion(serviceName)
+	if version > 1 && env.GetBlockHeight() < _readVersionBlockHeight(serviceName) {
+		return version - 1
+	}
+	return version
+}
+
+// contracts which were never upgraded have no version recorded
+func _latestVersion(serviceName string) uint32 {
+	version := state.ReadUint32(_versionKey(serviceName))
+	if version == 0 {
+		return 1
+	}
+	return version
+}
+
+func _readVersionBlockHeight(serviceName string) uint64 {
+	return state.ReadUint64(_versionBlockHeightKey(serviceName))
+}
+
+func _writeDeployer(serviceName string, deployer []byte) {
+	state.WriteBytes(_deployerKey(serviceName), deployer)
+}
+
+func _writeOwner(serviceName string, owner []byte) {
+	state.WriteBytes(_ownerKey(serviceName), owner)
+}
+
+func _versionedCodeKey(serviceName string, version uint32, index uint32) []byte {
+	return []byte(serviceName + ".Code.v" + strconv.FormatInt(int64(version), 10) + "." + strconv.FormatInt(int64(index), 10))
+}
+
+func _versionedCodePartsKey(serviceName string, version uint32) []byte {
+	return []byte(serviceName + ".CodeParts.v" + strconv.FormatInt(int64(version), 10))
+}
+
+func _versionKey(serviceName string) []byte {
+	return []byte(serviceName + ".Version")
+}
+
+func _versionBlockHeightKey(serviceName string) []byte {
+	return []byte(serviceName + ".VersionBlockHeight")
+}
+
+func _deployerKey(serviceName string) []byte {
+	return []byte(serviceName + ".Deployer")
+}
+
+func _ownerKey(serviceName string) []byte {
+	return []byte(serviceName + ".Owner")
+}
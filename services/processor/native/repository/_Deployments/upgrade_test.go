@@ -0,0 +1,82 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package deployments_systemcontract
+
+import (
+	. "github.com/orbs-network/orbs-contract-sdk/go/testing/unit"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestUpgradeServiceTakesEffectFromTheNextBlock(t *testing.T) {
+	owner := AnAddress()
+	InSystemScope(owner, nil, func(m Mockery) {
+		m.MockServiceCallMethod("hello", "_init", nil)
+		m.MockEnvBlockHeight(10)
+
+		deployService("hello", 2, []byte("contract"))
+		require.EqualValues(t, owner, getDeployer("hello"))
+		require.EqualValues(t, owner, getOwner("hello"))
+		require.EqualValues(t, 1, getCodeVersion("hello"))
+
+		upgradeService("hello", []byte("fixed contract"), []byte("more fixed contract"))
+		require.EqualValues(t, 1, getCodeVersion("hello"), "the upgrade should not take effect in its own block")
+		require.EqualValues(t, []byte("contract"), getCode("hello"))
+		require.EqualValues(t, 1, getCodeParts("hello"))
+
+		require.PanicsWithValue(t, "contract was already upgraded in this block", func() {
+			upgradeService("hello", []byte("another contract"))
+		})
+
+		m.MockEnvBlockHeight(11)
+		require.EqualValues(t, 2, getCodeVersion("hello"))
+		require.EqualValues(t, 2, getCodeParts("hello"))
+		require.EqualValues(t, []byte("fixed contract"), getCodePart("hello", 0))
+		require.EqualValues(t, []byte("more fixed contract"), getCodePart("hello", 1))
+
+		require.EqualValues(t, 1, getCodePartsOfVersion("hello", 1), "old versions should remain readable")
+		require.EqualValues(t, []byte("contract"), getCodePartOfVersion("hello", 1, 0))
+		require.PanicsWithValue(t, "contract has no version 3", func() {
+			getCodePartsOfVersion("hello", 3)
+		})
+	})
+}
+
+func TestPreBuiltContractDeployedOnItsFirstCallHasNoOwner(t *testing.T) {
+	InSystemScope(AnAddress(), nil, func(m Mockery) {
+		m.MockServiceCallMethod("hello", "_init", nil)
+
+		deployService("hello", 1, []byte{})
+		require.Empty(t, getOwner("hello"), "the signer of the first call should not own a pre-built contract")
+	})
+}
+
+func TestUpgradeServiceRequiresTheOwner(t *testing.T) {
+	InSystemScope(nil, nil, func(m Mockery) {
+		m.MockServiceCallMethod("hello", "_init", nil)
+		m.MockEnvBlockHeight(10)
+
+		require.PanicsWithValue(t, "contract not deployed", func() {
+			upgradeService("hello", []byte("contract"))
+		})
+
+		deployService("hello", 2, []byte("contract"))
+		_writeOwner("hello", []byte{0x01, 0x02})
+
+		require.PanicsWithValue(t, "contract is owned by 0x0102", func() {
+			upgradeService("hello", []byte("fixed contract"))
+		})
+		require.EqualValues(t, 1, getCodeVersion("hello"))
+	})
+}
+
+func TestGetCodeVersionOfImplicitlyDeployedContract(t *testing.T) {
+	InSystemScope(nil, nil, func(m Mockery) {
+		require.EqualValues(t, 0, getCodeVersion(CONTRACT_NAME))
+		require.EqualValues(t, 0, getCodeVersion("hello"))
+	})
+}
@@ -29,6 +29,7 @@ func NewPrebuilt() *prebuiltRepository {
 			},
 			deployments_systemcontract.CONTRACT_NAME: {
 				PublicMethods: deployments_systemcontract.PUBLIC,
+				SystemMethods: deployments_systemcontract.SYSTEM,
 				Permission:    sdkContext.PERMISSION_SCOPE_SYSTEM,
 			},
 			info_systemcontract.CONTRACT_NAME: {
@@ -29,10 +29,20 @@ import (
 
 var LogTag = log.Service("processor-native")
 
+// implemented by the sdk of the processor, deployed contracts are instrumented to count their steps (see metering.Instrument)
+type computationCharger interface {
+	ChargeComputation(executionContextId sdkContext.ContextId) error
+}
+
 type Repository interface {
 	ContractInfo(ctx context.Context, executionContextId primitives.ExecutionContextId, contractName string) (*sdkContext.ContractInfo, error)
 }
 
+// VersionedRepository is implemented by repositories of contracts which can be upgraded, a version of zero means the contract cannot be
+type VersionedRepository interface {
+	ContractVersion(ctx context.Context, executionContextId primitives.ExecutionContextId, contractName string) (uint32, error)
+}
+
 type service struct {
 	logger     log.Logger
 	config     config.NativeProcessorConfig
@@ -47,12 +57,16 @@ type service struct {
 }
 
 type metrics struct {
-	processCallTime *metric.Histogram
+	processCallTime   *metric.Histogram
+	stepLimitExceeded *metric.Gauge
+	panics            *metric.Gauge
 }
 
 func getMetrics(m metric.Factory) *metrics {
 	return &metrics{
-		processCallTime: m.NewLatency("Processor.Native.ProcessCallTime.Millis", 10*time.Second),
+		processCallTime:   m.NewLatency("Processor.Native.ProcessCallTime.Millis", 10*time.Second),
+		stepLimitExceeded: m.NewGauge("Processor.Native.ExecutionStepLimitExceeded.Count"),
+		panics:            m.NewGauge("Processor.Native.ContractPanics.Count"),
 	}
 }
 
@@ -116,8 +130,9 @@ func (s *service) ProcessCall(ctx context.Context, input *services.ProcessCallIn
 		}, err
 	}
 
-	// setup context for the contract sdk
-	sdkContext.PushContext(sdkContext.ContextId(input.ContextId), sdk.NewSDK(s.sdkHandler, s.config), contractInfo.Permission)
+	// setup context for the contract sdk, calls made by the contract to other contracts keep its sdk and share its steps
+	callSdk := sdk.NewSDKWithStepLimit(s.sdkHandler, s.config, uint64(s.config.ProcessorNativeExecutionStepLimit()))
+	sdkContext.PushContext(sdkContext.ContextId(input.ContextId), callSdk, contractInfo.Permission)
 	defer sdkContext.PopContext(sdkContext.ContextId(input.ContextId))
 
 	start := time.Now()
@@ -141,10 +156,27 @@ func (s *service) ProcessCall(ctx context.Context, input *services.ProcessCallIn
 		}, err
 	}
 
+	// the computation steps of the contract are charged in batches, the ones left are charged before the result is known,
+	// so a contract past its step limit or out of gas fails even if it then recovered
+	if charger, ok := callSdk.(computationCharger); ok {
+		if chargeErr := charger.ChargeComputation(sdkContext.ContextId(input.ContextId)); chargeErr != nil {
+			if errors.Cause(chargeErr) == sdk.ErrExecutionStepLimitExceeded {
+				s.metrics.stepLimitExceeded.Inc()
+				logger.Info("contract exceeded its execution step limit", log.Stringable("contract", input.ContractName), log.Stringable("method", input.MethodName), log.Uint32("step-limit", s.config.ProcessorNativeExecutionStepLimit()))
+			}
+			outputArgs, contractErr = createMethodOutputArgsWithString(chargeErr.Error()), chargeErr
+		}
+	}
+
 	// result
 	callResult := protocol.EXECUTION_RESULT_SUCCESS
 	if contractErr != nil {
-		logger.Info("contract returned error", log.Stringable("contract", input.ContractName), log.Stringable("method", input.MethodName), log.Error(contractErr))
+		if panicErr, panicked := contractErr.(*contractPanicError); panicked {
+			s.metrics.panics.Inc()
+			logger.Info("contract panicked", log.Stringable("contract", input.ContractName), log.Stringable("method", input.MethodName), log.Error(contractErr), log.String("stack-trace", string(panicErr.stack)))
+		} else {
+			logger.Info("contract returned error", log.Stringable("contract", input.ContractName), log.Stringable("method", input.MethodName), log.Error(contractErr))
+		}
 
 		callResult = protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT
 	}
@@ -190,13 +222,22 @@ func (s *service) retrieveContractAndMethodInstances(contractInfo *sdkContext.Co
 	return nil, nil, errors.Errorf("method '%s' not found on contract '%s'", methodName, contractName)
 }
 
+// an upgraded contract is retrieved again once its new version is the one executed
 func (s *service) retrieveContractInfo(ctx context.Context, executionContextId primitives.ExecutionContextId, contractName string) (*sdkContext.ContractInfo, error) {
-	contractInfo := s.cache.infoByName(contractName)
-	if contractInfo != nil {
+	contractInfo, cachedVersion := s.cache.infoByName(contractName)
+	if contractInfo != nil && cachedVersion == 0 {
 		return contractInfo, nil
 	}
 
-	contractInfo, err := s.repository.ContractInfo(ctx, executionContextId, contractName)
+	version, err := s.contractVersion(ctx, executionContextId, contractName)
+	if err != nil {
+		return nil, err
+	}
+	if contractInfo != nil && cachedVersion == version {
+		return contractInfo, nil
+	}
+
+	contractInfo, err = s.repository.ContractInfo(ctx, executionContextId, contractName)
 	if err != nil {
 		return nil, err
 	}
@@ -204,10 +245,17 @@ func (s *service) retrieveContractInfo(ctx context.Context, executionContextId p
 		return nil, errors.Errorf("Contract %s was not found", contractName)
 	}
 
-	s.cache.addInfo(contractName, contractInfo)
+	s.cache.addInfo(contractName, contractInfo, version)
 	return contractInfo, err
 }
 
+func (s *service) contractVersion(ctx context.Context, executionContextId primitives.ExecutionContextId, contractName string) (uint32, error) {
+	if versioned, ok := s.repository.(VersionedRepository); ok {
+		return versioned.ContractVersion(ctx, executionContextId, contractName)
+	}
+	return 0, nil
+}
+
 func (s *service) getContractInstance(contractInfo *sdkContext.ContractInfo, contractName string) (*types.ContractInstance, error) {
 	contractInstance := s.cache.instanceByNam(contractName)
 	if contractInstance != nil {
@@ -222,18 +270,23 @@ func (s *service) getContractInstance(contractInfo *sdkContext.ContractInfo, con
 	return contractInstance, nil
 }
 
-func (c *contractCache) infoByName(contractName string) *sdkContext.ContractInfo {
+func (c *contractCache) infoByName(contractName string) (*sdkContext.ContractInfo, uint32) {
 	c.RLock()
 	defer c.RUnlock()
 
-	return c.contractInfo[contractName]
+	return c.contractInfo[contractName], c.contractVersions[contractName]
 }
 
-func (c *contractCache) addInfo(contractName string, contractInfo *sdkContext.ContractInfo) {
+// replacing the info of a contract drops the instance created from the previous one
+func (c *contractCache) addInfo(contractName string, contractInfo *sdkContext.ContractInfo, version uint32) {
 	c.Lock()
 	defer c.Unlock()
 
+	if c.contractInfo[contractName] != contractInfo {
+		delete(c.contractInstances, contractName)
+	}
 	c.contractInfo[contractName] = contractInfo
+	c.contractVersions[contractName] = version
 }
 
 func (c *contractCache) instanceByNam(contractName string) *types.ContractInstance {
@@ -253,12 +306,14 @@ func (c *contractCache) addInstance(contractName string, contractInstance *types
 type contractCache struct {
 	sync.RWMutex
 	contractInfo      map[string]*sdkContext.ContractInfo
+	contractVersions  map[string]uint32 // zero for contracts which cannot be upgraded
 	contractInstances map[string]*types.ContractInstance
 }
 
 func newContractCache() *contractCache {
 	return &contractCache{
 		contractInfo:      make(map[string]*sdkContext.ContractInfo),
+		contractVersions:  make(map[string]uint32),
 		contractInstances: make(map[string]*types.ContractInstance),
 	}
 }
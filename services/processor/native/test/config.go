@@ -6,9 +6,12 @@
 
 package test
 
-import "github.com/orbs-network/orbs-spec/types/go/primitives"
+import (
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+)
 
 type NativeProcessorConfigForTests struct {
+	executionStepLimit uint32
 }
 
 func (c *NativeProcessorConfigForTests) ProcessorSanitizeDeployedContracts() bool {
@@ -18,3 +21,7 @@ func (c *NativeProcessorConfigForTests) ProcessorSanitizeDeployedContracts() boo
 func (c *NativeProcessorConfigForTests) VirtualChainId() primitives.VirtualChainId {
 	return 42
 }
+
+func (c *NativeProcessorConfigForTests) ProcessorNativeExecutionStepLimit() uint32 {
+	return c.executionStepLimit
+}
@@ -22,6 +22,8 @@ func TestProcessCall_WithUnknownContractFails(t *testing.T) {
 		with.Logging(t, func(parent *with.LoggingHarness) {
 			h := newHarness(parent.Logger)
 			input := ProcessCallInput().WithUnknownContract().Build()
+			h.expectSdkCallMadeWithEnvGetBlockHeight(10)
+			h.expectSdkCallMadeWithServiceCallMethod(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_CODE_VERSION, builders.ArgumentsArray(string(input.ContractName)), builders.ArgumentsArray(uint32(0)), nil)
 			h.expectSdkCallMadeWithServiceCallMethod(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_CODE_PARTS, builders.ArgumentsArray(string(input.ContractName)), builders.ArgumentsArray(), errors.New("contract not deployed"))
 
 			_, err := h.service.ProcessCall(ctx, input)
@@ -37,6 +39,8 @@ func TestGetContractInfo_WithUnknownContractFails(t *testing.T) {
 		with.Logging(t, func(parent *with.LoggingHarness) {
 			h := newHarness(parent.Logger)
 			input := getContractInfoInput().WithUnknownContract().Build()
+			h.expectSdkCallMadeWithEnvGetBlockHeight(10)
+			h.expectSdkCallMadeWithServiceCallMethod(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_CODE_VERSION, builders.ArgumentsArray(string(input.ContractName)), builders.ArgumentsArray(uint32(0)), nil)
 			h.expectSdkCallMadeWithServiceCallMethod(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_CODE_PARTS, builders.ArgumentsArray(string(input.ContractName)), builders.ArgumentsArray(), errors.New("contract not deployed"))
 
 			_, err := h.service.GetContractInfo(ctx, input)
@@ -55,6 +59,8 @@ func TestProcessCall_WithDeployableContractThatCompiles(t *testing.T) {
 
 			input := ProcessCallInput().WithDeployableCounterContract(contracts.MOCK_COUNTER_CONTRACT_START_FROM).Build()
 			codeOutput := builders.ArgumentsArray([]byte(contracts.NativeSourceCodeForCounter(contracts.MOCK_COUNTER_CONTRACT_START_FROM)))
+			h.expectSdkCallMadeWithEnvGetBlockHeight(10)
+			h.expectSdkCallMadeWithServiceCallMethod(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_CODE_VERSION, builders.ArgumentsArray(string(input.ContractName)), builders.ArgumentsArray(uint32(1)), nil)
 			h.expectSdkCallMadeWithServiceCallMethod(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_CODE_PART, builders.ArgumentsArray(string(input.ContractName), uint32(0)), codeOutput, nil)
 			h.expectSdkCallMadeWithServiceCallMethod(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_CODE_PARTS, builders.ArgumentsArray(string(input.ContractName)), builders.ArgumentsArray(uint32(1)), nil)
 
@@ -65,12 +71,46 @@ func TestProcessCall_WithDeployableContractThatCompiles(t *testing.T) {
 			t.Log("First call (not compiled) should getCode for compilation")
 			h.verifySdkCallMade(t)
 
+			h.expectSdkCallMadeWithEnvGetBlockHeight(11)
+			h.expectSdkCallMadeWithServiceCallMethod(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_CODE_VERSION, builders.ArgumentsArray(string(input.ContractName)), builders.ArgumentsArray(uint32(1)), nil)
 			output, err = h.service.ProcessCall(ctx, input)
 			require.NoError(t, err, "call should succeed")
 			require.Equal(t, contracts.MOCK_COUNTER_CONTRACT_START_FROM, output.OutputArgumentArray.ArgumentsIterator().NextArguments().Uint64Value(), "call return value should be counter value")
 
 			t.Log("Make sure second call (already compiled) does not getCode again")
 			h.verifySdkCallMade(t)
+
+			h.expectSdkCallMadeWithEnvGetBlockHeight(11)
+			_, err = h.service.ProcessCall(ctx, input)
+			require.NoError(t, err, "call should succeed")
+
+			t.Log("Make sure third call in the same block does not getCodeVersion again")
+			h.verifySdkCallMade(t)
+		})
+	})
+}
+
+func TestProcessCall_WithUpgradedDeployableContractRecompiles(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+			h := newHarness(parent.Logger)
+			h.compiler.ProvideFakeContract(contracts.MockForCounter(), string(contracts.NativeSourceCodeForCounter(contracts.MOCK_COUNTER_CONTRACT_START_FROM)))
+			h.compiler.ProvideFakeContract(contracts.MockForCounter(), string(contracts.NativeSourceCodeForCounter(contracts.MOCK_COUNTER_CONTRACT_START_FROM+1)))
+
+			input := ProcessCallInput().WithDeployableCounterContract(contracts.MOCK_COUNTER_CONTRACT_START_FROM).Build()
+			for version, startFrom := range []uint64{contracts.MOCK_COUNTER_CONTRACT_START_FROM, contracts.MOCK_COUNTER_CONTRACT_START_FROM + 1} {
+				codeOutput := builders.ArgumentsArray([]byte(contracts.NativeSourceCodeForCounter(startFrom)))
+				h.expectSdkCallMadeWithEnvGetBlockHeight(uint64(10+version))
+				h.expectSdkCallMadeWithServiceCallMethod(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_CODE_VERSION, builders.ArgumentsArray(string(input.ContractName)), builders.ArgumentsArray(uint32(version+1)), nil)
+				h.expectSdkCallMadeWithServiceCallMethod(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_CODE_PART, builders.ArgumentsArray(string(input.ContractName), uint32(0)), codeOutput, nil)
+				h.expectSdkCallMadeWithServiceCallMethod(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_CODE_PARTS, builders.ArgumentsArray(string(input.ContractName)), builders.ArgumentsArray(uint32(1)), nil)
+
+				_, err := h.service.ProcessCall(ctx, input)
+				require.NoError(t, err, "call should succeed")
+
+				t.Logf("Version %d should getCode for compilation", version+1)
+				h.verifySdkCallMade(t)
+			}
 		})
 	})
 }
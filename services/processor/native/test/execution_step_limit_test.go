@@ -0,0 +1,93 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package test
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-contract-sdk/go/sdk/v1/state"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-network-go/services/processor/native"
+	"github.com/orbs-network/orbs-network-go/services/processor/native/testkit"
+	"github.com/orbs-network/orbs-network-go/test/with"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/orbs-network/orbs-spec/types/go/services/handlers"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type stateReadSdkCallHandlerStub struct{}
+
+func (h *stateReadSdkCallHandlerStub) HandleSdkCall(ctx context.Context, input *handlers.HandleSdkCallInput) (*handlers.HandleSdkCallOutput, error) {
+	return &handlers.HandleSdkCallOutput{
+		OutputArguments: []*protocol.Argument{(&protocol.ArgumentBuilder{Type: protocol.ARGUMENT_TYPE_BYTES_VALUE, BytesValue: []byte{0x01}}).Build()},
+	}, nil
+}
+
+func readForever() {
+	for {
+		state.ReadBytes([]byte{0x01})
+	}
+}
+
+func readForeverAndRecover() (res uint64) {
+	defer func() {
+		if recover() != nil {
+			res = 1
+		}
+	}()
+	readForever()
+	return 0
+}
+
+func dereferenceNil() {
+	var counters map[string]uint64
+	counters["calls"]++
+}
+
+func newProcessorWithStepLimitTestContracts(logger *with.LoggingHarness, registry metric.Registry) services.Processor {
+	repository := testkit.NewRepository()
+	repository.Register("Contract1", []interface{}{readForever, readForeverAndRecover, dereferenceNil}, nil, nil)
+
+	processor := native.NewProcessorWithContractRepository(repository, &NativeProcessorConfigForTests{executionStepLimit: 100}, logger.Logger, registry)
+	processor.RegisterContractSdkCallHandler(&stateReadSdkCallHandlerStub{})
+	return processor
+}
+
+func TestProcessCall_ContractThatExceedsItsStepLimitFails(t *testing.T) {
+	for _, method := range []string{"readForever", "readForeverAndRecover"} {
+		t.Run(method, func(t *testing.T) {
+			with.Context(func(ctx context.Context) {
+				with.Logging(t, func(parent *with.LoggingHarness) {
+					registry := metric.NewRegistry()
+					processor := newProcessorWithStepLimitTestContracts(parent, registry)
+
+					output, err := processor.ProcessCall(ctx, ProcessCallInput().WithMethod("Contract1", primitives.MethodName(method)).Build())
+					require.Error(t, err, "call should fail")
+					require.Equal(t, protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT, output.CallResult, "call should fail, even if the contract recovered")
+					require.EqualValues(t, 1, registry.Get("Processor.Native.ExecutionStepLimitExceeded.Count").(*metric.Gauge).Value())
+				})
+			})
+		})
+	}
+}
+
+func TestProcessCall_ContractPanicIsIsolated(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+			registry := metric.NewRegistry()
+			processor := newProcessorWithStepLimitTestContracts(parent, registry)
+
+			output, err := processor.ProcessCall(ctx, ProcessCallInput().WithMethod("Contract1", "dereferenceNil").Build())
+			require.Error(t, err, "call should fail")
+			require.Equal(t, protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT, output.CallResult, "a panic should fail only the call")
+			require.EqualValues(t, 1, registry.Get("Processor.Native.ContractPanics.Count").(*metric.Gauge).Value())
+			require.EqualValues(t, 0, registry.Get("Processor.Native.ExecutionStepLimitExceeded.Count").(*metric.Gauge).Value())
+		})
+	})
+}
@@ -106,6 +106,21 @@ func (h *harness) expectSdkCallMadeWithServiceCallMethod(expectedContractName st
 	h.sdkCallHandler.When("HandleSdkCall", mock.Any, mock.AnyIf("Contract equals Sdk.Service, method equals callMethod and 3 args match", serviceCallMethodCallMatcher)).Return(returnOutput, returnError).Times(1)
 }
 
+func (h *harness) expectSdkCallMadeWithEnvGetBlockHeight(returnHeight uint64) {
+	envGetBlockHeightCallMatcher := func(i interface{}) bool {
+		input, ok := i.(*handlers.HandleSdkCallInput)
+		return ok &&
+			input.OperationName == sdk.SDK_OPERATION_NAME_ENV &&
+			input.MethodName == "getBlockHeight"
+	}
+	outputArgs, _ := protocol.ArgumentsFromNatives(builders.VarsToSlice(returnHeight))
+	returnOutput := &handlers.HandleSdkCallOutput{
+		OutputArguments: outputArgs,
+	}
+
+	h.sdkCallHandler.When("HandleSdkCall", mock.Any, mock.AnyIf("Contract equals Sdk.Env, method equals getBlockHeight", envGetBlockHeightCallMatcher)).Return(returnOutput, nil).Times(1)
+}
+
 func (h *harness) expectSdkCallMadeWithAddressGetCaller(returnAddress []byte) {
 	addressGetCallerCallMatcher := func(i interface{}) bool {
 		input, ok := i.(*handlers.HandleSdkCallInput)
@@ -7,9 +7,11 @@
 package sdk
 
 import (
+	"context"
 	sdkContext "github.com/orbs-network/orbs-contract-sdk/go/context"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/services/handlers"
+	"github.com/pkg/errors"
 )
 
 type SDKConfig interface {
@@ -19,11 +21,28 @@ type SDKConfig interface {
 type service struct {
 	sdkHandler handlers.ContractSdkCallHandler
 	config     SDKConfig
+	stepLimit  uint64
+	steps      executionSteps
 }
 
 func NewSDK(handler handlers.ContractSdkCallHandler, config SDKConfig) sdkContext.SdkHandler {
+	return NewSDKWithStepLimit(handler, config, 0)
+}
+
+// NewSDKWithStepLimit limits the function calls, loop iterations and sdk calls of a contract call, including the calls
+// it makes to other contracts, once past the limit every sdk call and step of the contract fails. Zero is no limit.
+func NewSDKWithStepLimit(handler handlers.ContractSdkCallHandler, config SDKConfig, stepLimit uint64) sdkContext.SdkHandler {
 	return &service{
 		sdkHandler: handler,
 		config:     config,
+		stepLimit:  stepLimit,
+	}
+}
+
+// the contract panics on any sdk call error, which is how a contract past its step limit is stopped
+func (s *service) handleSdkCall(input *handlers.HandleSdkCallInput) (*handlers.HandleSdkCallOutput, error) {
+	if err := s.step(); err != nil {
+		return nil, errors.Wrapf(err, "sdk call %s.%s not made", input.OperationName, input.MethodName)
 	}
+	return s.sdkHandler.HandleSdkCall(context.TODO(), input)
 }
@@ -7,7 +7,6 @@
 package sdk
 
 import (
-	"context"
 	sdkContext "github.com/orbs-network/orbs-contract-sdk/go/context"
 	"github.com/orbs-network/orbs-network-go/crypto/digest"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
@@ -19,7 +18,7 @@ const SDK_OPERATION_NAME_ADDRESS = "Sdk.Address"
 
 // TODO(https://github.com/orbs-network/orbs-network-go/issues/584): fix context here
 func (s *service) SdkAddressGetSignerAddress(executionContextId sdkContext.ContextId, permissionScope sdkContext.PermissionScope) []byte {
-	output, err := s.sdkHandler.HandleSdkCall(context.TODO(), &handlers.HandleSdkCallInput{
+	output, err := s.handleSdkCall(&handlers.HandleSdkCallInput{
 		ContextId:       primitives.ExecutionContextId(executionContextId),
 		OperationName:   SDK_OPERATION_NAME_ADDRESS,
 		MethodName:      "getSignerAddress",
@@ -36,7 +35,7 @@ func (s *service) SdkAddressGetSignerAddress(executionContextId sdkContext.Conte
 }
 
 func (s *service) SdkAddressGetCallerAddress(executionContextId sdkContext.ContextId, permissionScope sdkContext.PermissionScope) []byte {
-	output, err := s.sdkHandler.HandleSdkCall(context.TODO(), &handlers.HandleSdkCallInput{
+	output, err := s.handleSdkCall(&handlers.HandleSdkCallInput{
 		ContextId:       primitives.ExecutionContextId(executionContextId),
 		OperationName:   SDK_OPERATION_NAME_ADDRESS,
 		MethodName:      "getCallerAddress",
@@ -53,7 +52,7 @@ func (s *service) SdkAddressGetCallerAddress(executionContextId sdkContext.Conte
 }
 
 func (s *service) SdkAddressGetOwnAddress(executionContextId sdkContext.ContextId, permissionScope sdkContext.PermissionScope) []byte {
-	output, err := s.sdkHandler.HandleSdkCall(context.TODO(), &handlers.HandleSdkCallInput{
+	output, err := s.handleSdkCall(&handlers.HandleSdkCallInput{
 		ContextId:       primitives.ExecutionContextId(executionContextId),
 		OperationName:   SDK_OPERATION_NAME_ADDRESS,
 		MethodName:      "getOwnAddress",
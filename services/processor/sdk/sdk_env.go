@@ -7,7 +7,6 @@
 package sdk
 
 import (
-	"context"
 	sdkContext "github.com/orbs-network/orbs-contract-sdk/go/context"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
@@ -17,7 +16,7 @@ import (
 const SDK_OPERATION_NAME_ENV = "Sdk.Env"
 
 func (s *service) SdkEnvGetBlockHeight(executionContextId sdkContext.ContextId, permissionScope sdkContext.PermissionScope) uint64 {
-	output, err := s.sdkHandler.HandleSdkCall(context.TODO(), &handlers.HandleSdkCallInput{
+	output, err := s.handleSdkCall(&handlers.HandleSdkCallInput{
 		ContextId:       primitives.ExecutionContextId(executionContextId),
 		OperationName:   SDK_OPERATION_NAME_ENV,
 		MethodName:      "getBlockHeight",
@@ -34,7 +33,7 @@ func (s *service) SdkEnvGetBlockHeight(executionContextId sdkContext.ContextId,
 }
 
 func (s *service) SdkEnvGetBlockTimestamp(executionContextId sdkContext.ContextId, permissionScope sdkContext.PermissionScope) uint64 {
-	output, err := s.sdkHandler.HandleSdkCall(context.TODO(), &handlers.HandleSdkCallInput{
+	output, err := s.handleSdkCall(&handlers.HandleSdkCallInput{
 		ContextId:       primitives.ExecutionContextId(executionContextId),
 		OperationName:   SDK_OPERATION_NAME_ENV,
 		MethodName:      "getBlockTimestamp",
@@ -55,7 +54,7 @@ func (s *service) SdkEnvGetVirtualChainId(executionContextId sdkContext.ContextI
 }
 
 func (s *service) SdkEnvGetBlockProposerAddress(executionContextId sdkContext.ContextId, permissionScope sdkContext.PermissionScope) []byte {
-	output, err := s.sdkHandler.HandleSdkCall(context.TODO(), &handlers.HandleSdkCallInput{
+	output, err := s.handleSdkCall(&handlers.HandleSdkCallInput{
 		ContextId:       primitives.ExecutionContextId(executionContextId),
 		OperationName:   SDK_OPERATION_NAME_ENV,
 		MethodName:      "getBlockProposerAddress",
@@ -72,7 +71,7 @@ func (s *service) SdkEnvGetBlockProposerAddress(executionContextId sdkContext.Co
 }
 
 func (s *service) SdkEnvGetBlockCommittee(executionContextId sdkContext.ContextId, permissionScope sdkContext.PermissionScope) [][]byte {
-	output, err := s.sdkHandler.HandleSdkCall(context.TODO(), &handlers.HandleSdkCallInput{
+	output, err := s.handleSdkCall(&handlers.HandleSdkCallInput{
 		ContextId:       primitives.ExecutionContextId(executionContextId),
 		OperationName:   SDK_OPERATION_NAME_ENV,
 		MethodName:      "getBlockCommittee",
@@ -7,7 +7,6 @@
 package sdk
 
 import (
-	"context"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	sdkContext "github.com/orbs-network/orbs-contract-sdk/go/context"
 	"github.com/orbs-network/orbs-network-go/services/crosschainconnector/ethereum"
@@ -30,7 +29,7 @@ func (s *service) SdkEthereumCallMethod(executionContextId sdkContext.ContextId,
 		panic(err.Error())
 	}
 
-	output, err := s.sdkHandler.HandleSdkCall(context.TODO(), &handlers.HandleSdkCallInput{
+	output, err := s.handleSdkCall(&handlers.HandleSdkCallInput{
 		ContextId:     primitives.ExecutionContextId(executionContextId),
 		OperationName: SDK_OPERATION_NAME_ETHEREUM,
 		MethodName:    "callMethod",
@@ -82,7 +81,7 @@ func (s *service) SdkEthereumGetTransactionLog(executionContextId sdkContext.Con
 		panic(err.Error())
 	}
 
-	output, err := s.sdkHandler.HandleSdkCall(context.TODO(), &handlers.HandleSdkCallInput{
+	output, err := s.handleSdkCall(&handlers.HandleSdkCallInput{
 		ContextId:     primitives.ExecutionContextId(executionContextId),
 		OperationName: SDK_OPERATION_NAME_ETHEREUM,
 		MethodName:    "getTransactionLog",
@@ -128,7 +127,7 @@ func (s *service) SdkEthereumGetTransactionLog(executionContextId sdkContext.Con
 }
 
 func (s *service) SdkEthereumGetBlockNumber(executionContextId sdkContext.ContextId, permissionScope sdkContext.PermissionScope) (ethBlockNumber uint64) {
-	output, err := s.sdkHandler.HandleSdkCall(context.TODO(), &handlers.HandleSdkCallInput{
+	output, err := s.handleSdkCall(&handlers.HandleSdkCallInput{
 		ContextId:       primitives.ExecutionContextId(executionContextId),
 		OperationName:   SDK_OPERATION_NAME_ETHEREUM,
 		MethodName:      "getBlockNumber",
@@ -146,7 +145,7 @@ func (s *service) SdkEthereumGetBlockNumber(executionContextId sdkContext.Contex
 }
 
 func (s *service) SdkEthereumGetBlockNumberByTime(executionContextId sdkContext.ContextId, permissionScope sdkContext.PermissionScope, ethBlockTimestamp uint64) (ethBlockNumber uint64) {
-	output, err := s.sdkHandler.HandleSdkCall(context.TODO(), &handlers.HandleSdkCallInput{
+	output, err := s.handleSdkCall(&handlers.HandleSdkCallInput{
 		ContextId:     primitives.ExecutionContextId(executionContextId),
 		OperationName: SDK_OPERATION_NAME_ETHEREUM,
 		MethodName:    "getBlockNumberByTime",
@@ -170,7 +169,7 @@ func (s *service) SdkEthereumGetBlockNumberByTime(executionContextId sdkContext.
 }
 
 func (s *service) SdkEthereumGetBlockTime(executionContextId sdkContext.ContextId, permissionScope sdkContext.PermissionScope) (ethBlockTimestamp uint64) {
-	output, err := s.sdkHandler.HandleSdkCall(context.TODO(), &handlers.HandleSdkCallInput{
+	output, err := s.handleSdkCall(&handlers.HandleSdkCallInput{
 		ContextId:       primitives.ExecutionContextId(executionContextId),
 		OperationName:   SDK_OPERATION_NAME_ETHEREUM,
 		MethodName:      "getBlockTime",
@@ -188,7 +187,7 @@ func (s *service) SdkEthereumGetBlockTime(executionContextId sdkContext.ContextI
 }
 
 func (s *service) SdkEthereumGetBlockTimeByNumber(executionContextId sdkContext.ContextId, permissionScope sdkContext.PermissionScope, ethBlockNumber uint64) (ethBlockTimestamp uint64) {
-	output, err := s.sdkHandler.HandleSdkCall(context.TODO(), &handlers.HandleSdkCallInput{
+	output, err := s.handleSdkCall(&handlers.HandleSdkCallInput{
 		ContextId:     primitives.ExecutionContextId(executionContextId),
 		OperationName: SDK_OPERATION_NAME_ETHEREUM,
 		MethodName:    "getBlockTimeByNumber",
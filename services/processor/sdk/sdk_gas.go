@@ -0,0 +1,80 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package sdk
+
+import (
+	"context"
+	sdkContext "github.com/orbs-network/orbs-contract-sdk/go/context"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services/handlers"
+	"github.com/pkg/errors"
+)
+
+const SDK_OPERATION_NAME_GAS = "Sdk.Gas"
+
+// the computation steps of a contract are charged in batches to save sdk calls
+const COMPUTATION_STEPS_PER_CHARGE = 1000
+
+var ErrExecutionStepLimitExceeded = errors.New("contract exceeded its execution step limit")
+
+type executionSteps struct {
+	made      uint64 // computation steps and sdk calls, limited by the step limit
+	uncharged uint64 // computation steps not charged yet
+	err       error
+}
+
+func (s *service) step() error {
+	if s.steps.err != nil {
+		return s.steps.err
+	}
+	s.steps.made++
+	if s.stepLimit > 0 && s.steps.made > s.stepLimit {
+		s.steps.err = ErrExecutionStepLimitExceeded
+	}
+	return s.steps.err
+}
+
+// SdkComputationStep is called by deployed contracts, which the processor instruments, at every function call and loop
+// iteration, it is not part of the contract sdk so contracts reach it through an interface of their own
+func (s *service) SdkComputationStep(executionContextId sdkContext.ContextId) {
+	if err := s.step(); err != nil {
+		panic(err.Error())
+	}
+	s.steps.uncharged++
+	if s.steps.uncharged >= COMPUTATION_STEPS_PER_CHARGE {
+		if err := s.ChargeComputation(executionContextId); err != nil {
+			panic(err.Error())
+		}
+	}
+}
+
+// ChargeComputation charges the steps made since the last charge, once a contract ran out of gas or steps every further
+// step fails too
+func (s *service) ChargeComputation(executionContextId sdkContext.ContextId) error {
+	if s.steps.err != nil {
+		return s.steps.err
+	}
+	if s.steps.uncharged == 0 {
+		return nil
+	}
+	steps := s.steps.uncharged
+	s.steps.uncharged = 0
+	_, err := s.sdkHandler.HandleSdkCall(context.TODO(), &handlers.HandleSdkCallInput{
+		ContextId:     primitives.ExecutionContextId(executionContextId),
+		OperationName: SDK_OPERATION_NAME_GAS,
+		MethodName:    "chargeComputation",
+		InputArguments: []*protocol.Argument{(&protocol.ArgumentBuilder{
+			// steps
+			Type:        protocol.ARGUMENT_TYPE_UINT_64_VALUE,
+			Uint64Value: steps,
+		}).Build()},
+		PermissionScope: protocol.PERMISSION_SCOPE_SYSTEM,
+	})
+	s.steps.err = err
+	return err
+}
@@ -0,0 +1,79 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package sdk
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services/handlers"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSdkGas_ChargesComputationStepsInBatches(t *testing.T) {
+	handler := &contractSdkGasCallHandlerStub{}
+	s := &service{sdkHandler: handler}
+
+	for i := 0; i < COMPUTATION_STEPS_PER_CHARGE+5; i++ {
+		s.SdkComputationStep(EXAMPLE_CONTEXT)
+	}
+	require.Equal(t, []uint64{COMPUTATION_STEPS_PER_CHARGE}, handler.charged, "a full batch of steps should be charged")
+
+	require.NoError(t, s.ChargeComputation(EXAMPLE_CONTEXT))
+	require.Equal(t, []uint64{COMPUTATION_STEPS_PER_CHARGE, 5}, handler.charged, "the steps left should be charged")
+
+	require.NoError(t, s.ChargeComputation(EXAMPLE_CONTEXT))
+	require.Len(t, handler.charged, 2, "no steps should be charged twice")
+}
+
+func TestSdkGas_FailsEveryStepOnceChargingFailed(t *testing.T) {
+	handler := &contractSdkGasCallHandlerStub{err: errors.New("out of gas")}
+	s := &service{sdkHandler: handler}
+
+	s.SdkComputationStep(EXAMPLE_CONTEXT)
+	require.Error(t, s.ChargeComputation(EXAMPLE_CONTEXT))
+
+	require.Panics(t, func() {
+		s.SdkComputationStep(EXAMPLE_CONTEXT)
+	}, "a contract should not run once out of gas")
+	require.Error(t, s.ChargeComputation(EXAMPLE_CONTEXT))
+	require.Len(t, handler.charged, 1, "charging should not be retried")
+}
+
+func TestSdkGas_FailsOnceTheStepLimitIsExceeded(t *testing.T) {
+	handler := &contractSdkGasCallHandlerStub{}
+	s := NewSDKWithStepLimit(handler, nil, 3).(*service)
+
+	for i := 0; i < 3; i++ {
+		s.SdkComputationStep(EXAMPLE_CONTEXT)
+	}
+	require.Panics(t, func() {
+		s.SdkComputationStep(EXAMPLE_CONTEXT)
+	}, "a contract should not run past its step limit")
+
+	_, err := s.handleSdkCall(&handlers.HandleSdkCallInput{OperationName: SDK_OPERATION_NAME_GAS, MethodName: "chargeComputation"})
+	require.Error(t, err, "sdk calls should fail past the step limit")
+	require.Equal(t, ErrExecutionStepLimitExceeded, errors.Cause(s.ChargeComputation(EXAMPLE_CONTEXT)), "the contract should fail even if it recovered")
+	require.Empty(t, handler.charged, "steps past the limit should not be charged")
+}
+
+type contractSdkGasCallHandlerStub struct {
+	charged []uint64
+	err     error
+}
+
+func (c *contractSdkGasCallHandlerStub) HandleSdkCall(ctx context.Context, input *handlers.HandleSdkCallInput) (*handlers.HandleSdkCallOutput, error) {
+	if input.PermissionScope != protocol.PERMISSION_SCOPE_SYSTEM {
+		panic("permissions passed to SDK are incorrect")
+	}
+	if input.OperationName != SDK_OPERATION_NAME_GAS || input.MethodName != "chargeComputation" {
+		panic("unexpected sdk call")
+	}
+	c.charged = append(c.charged, input.InputArguments[0].Uint64Value())
+	return &handlers.HandleSdkCallOutput{OutputArguments: []*protocol.Argument{}}, c.err
+}
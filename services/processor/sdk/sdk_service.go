@@ -7,7 +7,6 @@
 package sdk
 
 import (
-	"context"
 	sdkContext "github.com/orbs-network/orbs-contract-sdk/go/context"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
@@ -23,7 +22,7 @@ func (s *service) SdkServiceCallMethod(executionContextId sdkContext.ContextId,
 		panic(errors.Wrap(err, "input arguments"))
 	}
 
-	output, err := s.sdkHandler.HandleSdkCall(context.TODO(), &handlers.HandleSdkCallInput{
+	output, err := s.handleSdkCall(&handlers.HandleSdkCallInput{
 		ContextId:     primitives.ExecutionContextId(executionContextId),
 		OperationName: SDK_OPERATION_NAME_SERVICE,
 		MethodName:    "callMethod",
@@ -7,7 +7,6 @@
 package sdk
 
 import (
-	"context"
 	sdkContext "github.com/orbs-network/orbs-contract-sdk/go/context"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
@@ -17,7 +16,7 @@ import (
 const SDK_OPERATION_NAME_STATE = "Sdk.State"
 
 func (s *service) SdkStateReadBytes(executionContextId sdkContext.ContextId, permissionScope sdkContext.PermissionScope, key []byte) []byte {
-	output, err := s.sdkHandler.HandleSdkCall(context.TODO(), &handlers.HandleSdkCallInput{
+	output, err := s.handleSdkCall(&handlers.HandleSdkCallInput{
 		ContextId:     primitives.ExecutionContextId(executionContextId),
 		OperationName: SDK_OPERATION_NAME_STATE,
 		MethodName:    "read",
@@ -40,7 +39,7 @@ func (s *service) SdkStateReadBytes(executionContextId sdkContext.ContextId, per
 }
 
 func (s *service) SdkStateWriteBytes(executionContextId sdkContext.ContextId, permissionScope sdkContext.PermissionScope, key []byte, value []byte) {
-	_, err := s.sdkHandler.HandleSdkCall(context.TODO(), &handlers.HandleSdkCallInput{
+	_, err := s.handleSdkCall(&handlers.HandleSdkCallInput{
 		ContextId:     primitives.ExecutionContextId(executionContextId),
 		OperationName: SDK_OPERATION_NAME_STATE,
 		MethodName:    "write",
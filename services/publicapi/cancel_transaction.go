@@ -0,0 +1,93 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package publicapi
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/protocol/client"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/orbs-network/scribe/log"
+	"github.com/pkg/errors"
+)
+
+// TransactionCanceller is offered by the public api service beside services.PublicApi, it cancels a pending
+// transaction with a cancellation signed by its signer (a call to _TransactionPool.cancelTransaction with the hash of
+// the transaction) and optionally adds a replacement transaction of the same signer in its place
+type TransactionCanceller interface {
+	CancelTransaction(ctx context.Context, input *CancelTransactionInput) (*CancelTransactionOutput, error)
+}
+
+type CancelTransactionInput struct {
+	ClientRequest *client.SendTransactionRequest // carries the signed cancellation
+	Replacement   *client.SendTransactionRequest // optional
+}
+
+// CancellationResponse has TRANSACTION_STATUS_PRE_ORDER_VALID as its status if the transaction was cancelled;
+// ReplacementResponse is nil if there is no replacement
+type CancelTransactionOutput struct {
+	CancellationResponse *client.SendTransactionResponse
+	ReplacementResponse  *client.SendTransactionResponse
+}
+
+// implemented by the transaction pool service, which handles a cancellation without replacement in AddNewTransaction
+type replacingTransactionPool interface {
+	ReplaceTransaction(ctx context.Context, cancellation *services.AddNewTransactionInput, replacement *services.AddNewTransactionInput) (*services.AddNewTransactionOutput, *services.AddNewTransactionOutput, error)
+}
+
+func (s *service) CancelTransaction(parentCtx context.Context, input *CancelTransactionInput) (*CancelTransactionOutput, error) {
+	ctx := trace.NewContext(parentCtx, "PublicApi.CancelTransaction")
+	logger := s.logger.WithTags(trace.LogFieldFrom(ctx), log.String("flow", "checkpoint"))
+
+	if input.ClientRequest == nil {
+		return nil, errors.Errorf("client request is nil")
+	}
+
+	for _, request := range []*client.SendTransactionRequest{input.ClientRequest, input.Replacement} {
+		if request == nil {
+			continue
+		}
+		tx := request.SignedTransaction().Transaction()
+		if txStatus, err := validateRequest(s.config, tx.ProtocolVersion(), tx.VirtualChainId()); err != nil {
+			s.metrics.totalTransactionsErrInvalidRequest.Inc()
+			logger.Info("cancel transaction received invalid input", log.Error(err))
+			return &CancelTransactionOutput{CancellationResponse: toSendTxOutput(&txOutput{transactionStatus: txStatus}).ClientResponse}, err
+		}
+	}
+
+	cancellation := &services.AddNewTransactionInput{SignedTransaction: input.ClientRequest.SignedTransaction()}
+	if input.Replacement == nil {
+		output, err := s.transactionPool.AddNewTransaction(ctx, cancellation)
+		if output == nil {
+			return nil, err
+		}
+		logger.Info("cancel transaction request handled", log.Stringable("tx-status", output.TransactionStatus), log.Error(err))
+		return &CancelTransactionOutput{CancellationResponse: toSendTxOutput(addOutputToTxOutput(output)).ClientResponse}, err
+	}
+
+	pool, ok := s.transactionPool.(replacingTransactionPool)
+	if !ok {
+		return nil, errors.Errorf("transaction pool does not support replacing transactions")
+	}
+
+	s.metrics.totalTransactionsFromClients.Inc()
+	s.metrics.transactionsPerSecond.Measure(1)
+	cancelled, replaced, err := pool.ReplaceTransaction(ctx, cancellation, &services.AddNewTransactionInput{SignedTransaction: input.Replacement.SignedTransaction()})
+	if cancelled == nil || replaced == nil {
+		return nil, err
+	}
+	logger.Info("replace transaction request handled", log.Stringable("tx-status", replaced.TransactionStatus), log.Error(err))
+	if replaced.TransactionStatus != protocol.TRANSACTION_STATUS_PENDING {
+		s.metrics.totalTransactionsErrAddingToTxPool.Inc()
+	}
+
+	return &CancelTransactionOutput{
+		CancellationResponse: toSendTxOutput(addOutputToTxOutput(cancelled)).ClientResponse,
+		ReplacementResponse:  toSendTxOutput(addOutputToTxOutput(replaced)).ClientResponse,
+	}, err
+}
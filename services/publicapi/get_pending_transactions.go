@@ -0,0 +1,51 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package publicapi
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
+	"github.com/orbs-network/orbs-network-go/services/transactionpool"
+	"github.com/orbs-network/scribe/log"
+	"github.com/pkg/errors"
+)
+
+// PendingTransactionsInspector is offered by the public api service beside services.PublicApi, it lists a page of the
+// transactions waiting in the pool along with aggregate stats of the pool
+type PendingTransactionsInspector interface {
+	GetPendingTransactions(ctx context.Context, input *GetPendingTransactionsInput) (*GetPendingTransactionsOutput, error)
+}
+
+type GetPendingTransactionsInput struct {
+	Query *transactionpool.PendingTransactionsQuery
+}
+
+type GetPendingTransactionsOutput struct {
+	Report *transactionpool.PendingTransactionsReport
+}
+
+// implemented by the transaction pool service, other pools cannot be inspected
+type inspectableTransactionPool interface {
+	PendingTransactions(query *transactionpool.PendingTransactionsQuery) *transactionpool.PendingTransactionsReport
+}
+
+func (s *service) GetPendingTransactions(parentCtx context.Context, input *GetPendingTransactionsInput) (*GetPendingTransactionsOutput, error) {
+	ctx := trace.NewContext(parentCtx, "PublicApi.GetPendingTransactions")
+
+	pool, ok := s.transactionPool.(inspectableTransactionPool)
+	if !ok {
+		return nil, errors.Errorf("transaction pool does not support listing pending transactions")
+	}
+	if input.Query == nil {
+		err := errors.Errorf("query is nil")
+		s.logger.Info("get pending transactions received missing input", log.Error(err), trace.LogFieldFrom(ctx))
+		return nil, err
+	}
+
+	s.metrics.queriesPerSecond.Measure(1)
+	return &GetPendingTransactionsOutput{Report: pool.PendingTransactions(input.Query)}, nil
+}
@@ -8,6 +8,7 @@ package publicapi
 
 import (
 	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/services/virtualmachine"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
 	"github.com/orbs-network/orbs-spec/types/go/services"
@@ -81,6 +82,8 @@ func translateTransactionStatusToRequestStatus(txStatus protocol.TransactionStat
 		return protocol.REQUEST_STATUS_CONGESTION
 	case protocol.TRANSACTION_STATUS_REJECTED_NODE_OUT_OF_SYNC:
 		return protocol.REQUEST_STATUS_OUT_OF_SYNC
+	case protocol.TRANSACTION_STATUS_PRE_ORDER_VALID: // only answered to a cancellation that was applied
+		return protocol.REQUEST_STATUS_COMPLETED
 	}
 	return protocol.REQUEST_STATUS_RESERVED
 }
@@ -91,6 +94,8 @@ func translateExecutionStatusToRequestStatus(executionResult protocol.ExecutionR
 		return protocol.REQUEST_STATUS_COMPLETED
 	case protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT:
 		return protocol.REQUEST_STATUS_COMPLETED
+	case virtualmachine.EXECUTION_RESULT_ERROR_OUT_OF_GAS:
+		return protocol.REQUEST_STATUS_COMPLETED
 	case protocol.EXECUTION_RESULT_ERROR_INPUT:
 		return protocol.REQUEST_STATUS_BAD_REQUEST
 	case protocol.EXECUTION_RESULT_ERROR_CONTRACT_NOT_DEPLOYED:
@@ -9,6 +9,7 @@ package publicapi
 import (
 	"fmt"
 	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/services/virtualmachine"
 	"github.com/orbs-network/orbs-network-go/test/builders"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
@@ -37,7 +38,7 @@ func TestResults_TranslateTransactionStatusToRequestStatus(t *testing.T) {
 		{"TRANSACTION_STATUS_DUPLICATE_TRANSACTION_ALREADY_COMMITTED+EXECUTION_RESULT_ERROR_UNEXPECTED", protocol.REQUEST_STATUS_SYSTEM_ERROR, protocol.TRANSACTION_STATUS_DUPLICATE_TRANSACTION_ALREADY_COMMITTED, protocol.EXECUTION_RESULT_ERROR_UNEXPECTED},
 		{"TRANSACTION_STATUS_PENDING", protocol.REQUEST_STATUS_IN_PROCESS, protocol.TRANSACTION_STATUS_PENDING, protocol.EXECUTION_RESULT_RESERVED},
 		{"TRANSACTION_STATUS_DUPLICATE_TRANSACTION_ALREADY_PENDING", protocol.REQUEST_STATUS_IN_PROCESS, protocol.TRANSACTION_STATUS_DUPLICATE_TRANSACTION_ALREADY_PENDING, protocol.EXECUTION_RESULT_RESERVED},
-		{"TRANSACTION_STATUS_PRE_ORDER_VALID", protocol.REQUEST_STATUS_RESERVED, protocol.TRANSACTION_STATUS_PRE_ORDER_VALID, protocol.EXECUTION_RESULT_RESERVED},
+		{"TRANSACTION_STATUS_PRE_ORDER_VALID", protocol.REQUEST_STATUS_COMPLETED, protocol.TRANSACTION_STATUS_PRE_ORDER_VALID, protocol.EXECUTION_RESULT_RESERVED},
 		{"TRANSACTION_STATUS_NO_RECORD_FOUND", protocol.REQUEST_STATUS_NOT_FOUND, protocol.TRANSACTION_STATUS_NO_RECORD_FOUND, protocol.EXECUTION_RESULT_RESERVED},
 		{"TRANSACTION_STATUS_REJECTED_UNSUPPORTED_VERSION", protocol.REQUEST_STATUS_BAD_REQUEST, protocol.TRANSACTION_STATUS_REJECTED_UNSUPPORTED_VERSION, protocol.EXECUTION_RESULT_RESERVED},
 		{"TRANSACTION_STATUS_REJECTED_VIRTUAL_CHAIN_MISMATCH", protocol.REQUEST_STATUS_BAD_REQUEST, protocol.TRANSACTION_STATUS_REJECTED_VIRTUAL_CHAIN_MISMATCH, protocol.EXECUTION_RESULT_RESERVED},
@@ -72,6 +73,7 @@ func TestResults_TranslateExecutionStatusToRequestStatus(t *testing.T) {
 		{"EXECUTION_RESULT_ERROR_INPUT", protocol.REQUEST_STATUS_BAD_REQUEST, protocol.EXECUTION_RESULT_ERROR_INPUT},
 		{"EXECUTION_RESULT_ERROR_CONTRACT_NOT_DEPLOYED", protocol.REQUEST_STATUS_BAD_REQUEST, protocol.EXECUTION_RESULT_ERROR_CONTRACT_NOT_DEPLOYED},
 		{"EXECUTION_RESULT_ERROR_UNEXPECTED", protocol.REQUEST_STATUS_SYSTEM_ERROR, protocol.EXECUTION_RESULT_ERROR_UNEXPECTED},
+		{"EXECUTION_RESULT_ERROR_OUT_OF_GAS", protocol.REQUEST_STATUS_COMPLETED, virtualmachine.EXECUTION_RESULT_ERROR_OUT_OF_GAS},
 	}
 	for i := range tests {
 		currTest := tests[i] // this is so that we can run tests in parallel, see https://gist.github.com/posener/92a55c4cd441fc5e5e85f27bca008721
@@ -81,7 +81,7 @@ func (s *service) sendTransaction(ctx context.Context, request *client.SendTrans
 		return addOutputToTxOutput(addResp), nil
 	}
 
-	if asyncMode {
+	if asyncMode || addResp.TransactionStatus == protocol.TRANSACTION_STATUS_PRE_ORDER_VALID { // a cancellation is applied right away
 		s.waiter.deleteByChannel(waitResult)
 		return addOutputToTxOutput(addResp), nil
 	}
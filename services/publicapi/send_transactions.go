@@ -0,0 +1,169 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package publicapi
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/instrumentation/logfields"
+	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
+	"github.com/orbs-network/orbs-network-go/services/transactionpool"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/protocol/client"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/orbs-network/scribe/log"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// BatchTransactionSender is offered by the public api service beside services.PublicApi, it submits many transactions
+// in one call and returns a response per transaction in the order of the requests
+type BatchTransactionSender interface {
+	SendTransactions(ctx context.Context, input *SendTransactionsInput) (*SendTransactionsOutput, error)
+}
+
+type SendTransactionsInput struct {
+	ClientRequests []*client.SendTransactionRequest
+	WaitForCommit  bool
+}
+
+type SendTransactionsOutput struct {
+	ClientResponses []*client.SendTransactionResponse
+}
+
+// implemented by the transaction pool service, other pools get the transactions one at a time
+type batchTransactionPool interface {
+	AddNewTransactions(ctx context.Context, input *transactionpool.AddNewTransactionsInput) (*transactionpool.AddNewTransactionsOutput, error)
+}
+
+func (s *service) SendTransactions(parentCtx context.Context, input *SendTransactionsInput) (*SendTransactionsOutput, error) {
+	ctx := trace.NewContext(parentCtx, "PublicApi.SendTransactions")
+	logger := s.logger.WithTags(trace.LogFieldFrom(ctx), log.String("flow", "checkpoint"))
+	start := time.Now()
+
+	count := len(input.ClientRequests)
+	s.metrics.totalTransactionsFromClients.Add(int64(count))
+	s.metrics.transactionsPerSecond.Measure(int64(count))
+	if max := s.config.PublicApiMaxTransactionsPerBatch(); max > 0 && count > int(max) {
+		s.metrics.totalTransactionsErrInvalidRequest.Add(int64(count))
+		return nil, errors.Errorf("batch of %d transactions exceeds the maximum of %d", count, max)
+	}
+
+	outputs := make([]*txOutput, count)
+	waitResults := make([]*waiterChannel, count)
+	var transactions []*protocol.SignedTransaction
+	var indexes []int
+	for i, request := range input.ClientRequests {
+		if request == nil {
+			s.metrics.totalTransactionsErrNilRequest.Inc()
+			outputs[i] = &txOutput{transactionStatus: protocol.TRANSACTION_STATUS_RESERVED}
+			continue
+		}
+
+		tx := request.SignedTransaction().Transaction()
+		if txStatus, err := validateRequest(s.config, tx.ProtocolVersion(), tx.VirtualChainId()); err != nil {
+			s.metrics.totalTransactionsErrInvalidRequest.Inc()
+			logger.Info("send transactions received invalid transaction", log.Error(err), logfields.Transaction(digest.CalcTxHash(tx)))
+			outputs[i] = &txOutput{transactionStatus: txStatus}
+			continue
+		}
+
+		if input.WaitForCommit {
+			waitResults[i] = s.waiter.add(digest.CalcTxHash(tx).KeyForMap())
+		}
+		transactions = append(transactions, request.SignedTransaction())
+		indexes = append(indexes, i)
+	}
+
+	logger.Info("send transactions request received", log.Int("num-transactions", count), log.Int("num-valid", len(transactions)))
+
+	for j, addResp := range s.addNewTransactions(ctx, transactions) {
+		i := indexes[j]
+		outputs[i] = addOutputToTxOutput(addResp)
+		if addResp.TransactionStatus != protocol.TRANSACTION_STATUS_PENDING {
+			if addResp.TransactionStatus == protocol.TRANSACTION_STATUS_DUPLICATE_TRANSACTION_ALREADY_COMMITTED {
+				s.metrics.totalTransactionsErrDuplicate.Inc()
+			} else {
+				s.metrics.totalTransactionsErrAddingToTxPool.Inc()
+			}
+			if waitResults[i] != nil {
+				s.waiter.deleteByChannel(waitResults[i])
+				waitResults[i] = nil
+			}
+		}
+	}
+
+	var err error
+	if input.WaitForCommit {
+		err = s.waitForTransactions(ctx, outputs, waitResults)
+		s.metrics.sendTransactionTime.RecordSince(start)
+	}
+
+	responses := make([]*client.SendTransactionResponse, count)
+	for i, out := range outputs {
+		responses[i] = toSendTxOutput(out).ClientResponse
+	}
+	return &SendTransactionsOutput{ClientResponses: responses}, err
+}
+
+func (s *service) addNewTransactions(ctx context.Context, transactions []*protocol.SignedTransaction) []*services.AddNewTransactionOutput {
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	if pool, ok := s.transactionPool.(batchTransactionPool); ok {
+		output, err := pool.AddNewTransactions(ctx, &transactionpool.AddNewTransactionsInput{SignedTransactions: transactions})
+		if err == nil && len(output.Results) == len(transactions) {
+			return output.Results
+		}
+		s.logger.Info("adding transactions to TransactionPool failed", log.Error(err), trace.LogFieldFrom(ctx))
+		return rejectedOutputs(len(transactions), protocol.TRANSACTION_STATUS_RESERVED)
+	}
+
+	results := make([]*services.AddNewTransactionOutput, len(transactions))
+	for i, tx := range transactions {
+		addResp, err := s.transactionPool.AddNewTransaction(ctx, &services.AddNewTransactionInput{SignedTransaction: tx})
+		if addResp == nil {
+			s.logger.Info("adding transaction to TransactionPool failed", log.Error(err), trace.LogFieldFrom(ctx), logfields.Transaction(digest.CalcTxHash(tx.Transaction())))
+			addResp = &services.AddNewTransactionOutput{TransactionStatus: protocol.TRANSACTION_STATUS_RESERVED}
+		}
+		results[i] = addResp
+	}
+	return results
+}
+
+// all transactions share the send transaction timeout, those that do not commit in time keep their pending output
+func (s *service) waitForTransactions(ctx context.Context, outputs []*txOutput, waitResults []*waiterChannel) error {
+	ctx, cancel := context.WithTimeout(ctx, s.config.PublicApiSendTransactionTimeout())
+	defer cancel()
+
+	timedOut := 0
+	for i, waitResult := range waitResults {
+		if waitResult == nil {
+			continue
+		}
+		obj, err := s.waiter.wait(ctx, waitResult)
+		if err != nil {
+			timedOut++
+			continue
+		}
+		outputs[i] = obj.(*txOutput)
+	}
+
+	if timedOut > 0 {
+		return errors.Errorf("%d transactions were not processed before the timeout", timedOut)
+	}
+	return nil
+}
+
+func rejectedOutputs(count int, status protocol.TransactionStatus) []*services.AddNewTransactionOutput {
+	results := make([]*services.AddNewTransactionOutput, count)
+	for i := range results {
+		results[i] = &services.AddNewTransactionOutput{TransactionStatus: status}
+	}
+	return results
+}
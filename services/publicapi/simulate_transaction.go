@@ -0,0 +1,90 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package publicapi
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/crypto/validators"
+	"github.com/orbs-network/orbs-network-go/instrumentation/logfields"
+	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
+	"github.com/orbs-network/orbs-network-go/services/virtualmachine"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/protocol/client"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/orbs-network/scribe/log"
+	"github.com/pkg/errors"
+)
+
+// TransactionSimulator is offered by the public api service beside services.PublicApi, it shows what a transaction
+// would do if it were committed in the next block without sending it, nodes enable it in their config
+type TransactionSimulator interface {
+	SimulateTransaction(ctx context.Context, input *SimulateTransactionInput) (*SimulateTransactionOutput, error)
+}
+
+type SimulateTransactionInput struct {
+	ClientRequest *client.SendTransactionRequest // the transaction does not have to be signed, if it is the signature is verified
+	Trace         bool
+}
+
+// the response's QueryResult holds the execution result, output arguments and events of the transaction
+type SimulateTransactionOutput struct {
+	ClientResponse     *client.RunQueryResponse
+	ContractStateDiffs []*protocol.ContractStateDiff
+	Trace              *virtualmachine.ExecutionTrace // nil unless requested
+}
+
+func (s *service) SimulateTransaction(parentCtx context.Context, input *SimulateTransactionInput) (*SimulateTransactionOutput, error) {
+	ctx := trace.NewContext(parentCtx, "PublicApi.SimulateTransaction")
+
+	if !s.config.PublicApiSimulateTransactionEnabled() {
+		return nil, errors.Errorf("simulating transactions is disabled on this node")
+	}
+	simulator, ok := s.virtualMachine.(virtualmachine.TransactionSimulator)
+	if !ok {
+		return nil, errors.Errorf("virtual machine does not support simulating transactions")
+	}
+	if input.ClientRequest == nil {
+		err := errors.Errorf("client request is nil")
+		s.logger.Info("simulate transaction received missing input", log.Error(err))
+		return nil, err
+	}
+
+	signedTx := input.ClientRequest.SignedTransaction()
+	tx := signedTx.Transaction()
+	logger := s.logger.WithTags(trace.LogFieldFrom(ctx), logfields.Transaction(digest.CalcTxHash(tx)), log.String("flow", "checkpoint"))
+
+	if _, err := validateRequest(s.config, tx.ProtocolVersion(), tx.VirtualChainId()); err != nil {
+		logger.Info("simulate transaction received input failed", log.Error(err))
+		return &SimulateTransactionOutput{ClientResponse: toRunQueryOutput(&queryOutput{requestStatus: protocol.REQUEST_STATUS_BAD_REQUEST}).ClientResponse}, err
+	}
+
+	if len(signedTx.Signature()) != 0 {
+		if status := validators.VerifyTransactionSignature(signedTx); status != protocol.TRANSACTION_STATUS_PRE_ORDER_VALID {
+			err := errors.Errorf("transaction signature is invalid: %s", status)
+			logger.Info("simulate transaction received input failed", log.Error(err))
+			return &SimulateTransactionOutput{ClientResponse: toRunQueryOutput(&queryOutput{requestStatus: protocol.REQUEST_STATUS_BAD_REQUEST}).ClientResponse}, err
+		}
+	}
+
+	logger.Info("simulate transaction request received")
+	s.metrics.queriesPerSecond.Measure(1)
+
+	output, err := simulator.SimulateTransaction(ctx, &virtualmachine.SimulateTransactionInput{Transaction: tx, Trace: input.Trace})
+	if err != nil {
+		logger.Info("simulate transaction request failed", log.Error(err))
+	}
+
+	response := toRunQueryOutput(&queryOutput{callOutput: &services.ProcessQueryOutput{
+		CallResult:              output.CallResult,
+		OutputArgumentArray:     output.OutputArgumentArray,
+		OutputEventsArray:       output.OutputEventsArray,
+		ReferenceBlockHeight:    output.ReferenceBlockHeight,
+		ReferenceBlockTimestamp: output.ReferenceBlockTimestamp,
+	}}).ClientResponse
+	return &SimulateTransactionOutput{ClientResponse: response, ContractStateDiffs: output.ContractStateDiffs, Trace: output.Trace}, err
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package test
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-network-go/services/publicapi"
+	"github.com/orbs-network/orbs-network-go/services/transactionpool"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-network-go/test/with"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type inspectablePoolMock struct {
+	*services.MockTransactionPool
+	query *transactionpool.PendingTransactionsQuery
+}
+
+func (p *inspectablePoolMock) PendingTransactions(query *transactionpool.PendingTransactionsQuery) *transactionpool.PendingTransactionsReport {
+	p.query = query
+	return &transactionpool.PendingTransactionsReport{Stats: &transactionpool.PendingPoolStats{Count: 3}, Matching: 1}
+}
+
+func TestGetPendingTransactions_ReturnsTheReportOfThePool(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+			cfg := config.ForPublicApiTests(uint32(builders.DEFAULT_TEST_VIRTUAL_CHAIN_ID), time.Second, time.Minute)
+			pool := &inspectablePoolMock{MockTransactionPool: makeTxMock()}
+			papi := publicapi.NewPublicApi(cfg, pool, &services.MockVirtualMachine{}, &services.MockBlockStorage{}, parent.Logger, metric.NewRegistry())
+
+			query := &transactionpool.PendingTransactionsQuery{ContractName: "BenchmarkToken", Limit: 5}
+			output, err := papi.(publicapi.PendingTransactionsInspector).GetPendingTransactions(ctx, &publicapi.GetPendingTransactionsInput{Query: query})
+
+			require.NoError(t, err)
+			require.Equal(t, query, pool.query, "the query should be passed to the pool")
+			require.Equal(t, 3, output.Report.Stats.Count)
+			require.Equal(t, 1, output.Report.Matching)
+		})
+	})
+}
+
+func TestGetPendingTransactions_FailsWhenThePoolCannotBeInspected(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+			harness := newPublicApiHarness(parent.Logger, time.Second, time.Minute)
+
+			_, err := harness.papi.(publicapi.PendingTransactionsInspector).GetPendingTransactions(ctx, &publicapi.GetPendingTransactionsInput{Query: &transactionpool.PendingTransactionsQuery{}})
+
+			require.Error(t, err)
+		})
+	})
+}
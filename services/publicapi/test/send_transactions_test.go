@@ -0,0 +1,100 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package test
+
+import (
+	"context"
+	"github.com/orbs-network/go-mock"
+	"github.com/orbs-network/orbs-network-go/services/publicapi"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-network-go/test/with"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/protocol/client"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/orbs-network/orbs-spec/types/go/services/handlers"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func sendTransactionRequests(builders ...*protocol.SignedTransactionBuilder) (requests []*client.SendTransactionRequest) {
+	for _, builder := range builders {
+		requests = append(requests, (&client.SendTransactionRequestBuilder{SignedTransaction: builder}).Build())
+	}
+	return
+}
+
+func transactionStatusesOf(output *publicapi.SendTransactionsOutput) (statuses []protocol.TransactionStatus) {
+	for _, response := range output.ClientResponses {
+		statuses = append(statuses, response.TransactionStatus())
+	}
+	return
+}
+
+func TestSendTransactions_BlocksUntilAllTransactionsComplete(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+			harness := newPublicApiHarness(parent.Logger, time.Second, time.Minute)
+
+			harness.txpMock.When("AddNewTransaction", mock.Any, mock.Any).Times(2).
+				Call(func(ctx context.Context, input *services.AddNewTransactionInput) (*services.AddNewTransactionOutput, error) {
+					go func() {
+						time.Sleep(1 * time.Millisecond)
+						harness.papi.HandleTransactionResults(ctx, &handlers.HandleTransactionResultsInput{
+							TransactionReceipts: []*protocol.TransactionReceipt{builders.TransactionReceipt().WithTransaction(input.SignedTransaction.Transaction()).Build()},
+						})
+					}()
+					return &services.AddNewTransactionOutput{TransactionStatus: protocol.TRANSACTION_STATUS_PENDING}, nil
+				})
+
+			result, err := harness.papi.(publicapi.BatchTransactionSender).SendTransactions(ctx, &publicapi.SendTransactionsInput{
+				ClientRequests: sendTransactionRequests(builders.Transaction().Builder(), builders.Transaction().Builder()),
+				WaitForCommit:  true,
+			})
+
+			harness.verifyMocks(t)
+
+			require.NoError(t, err, "error happened when it should not")
+			require.Equal(t, []protocol.TransactionStatus{protocol.TRANSACTION_STATUS_COMMITTED, protocol.TRANSACTION_STATUS_COMMITTED}, transactionStatusesOf(result))
+		})
+	})
+}
+
+func TestSendTransactions_ReturnsStatusPerTransactionWithoutWaiting(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+			harness := newPublicApiHarness(parent.Logger, time.Second, time.Minute)
+			harness.txpMock.When("AddNewTransaction", mock.Any, mock.Any).Return(&services.AddNewTransactionOutput{TransactionStatus: protocol.TRANSACTION_STATUS_PENDING}, nil).Times(1)
+
+			result, err := harness.papi.(publicapi.BatchTransactionSender).SendTransactions(ctx, &publicapi.SendTransactionsInput{
+				ClientRequests: sendTransactionRequests(builders.Transaction().WithVirtualChainId(17).Builder(), builders.Transaction().Builder()),
+			})
+
+			harness.verifyMocks(t)
+
+			require.NoError(t, err, "error happened when it should not")
+			require.Equal(t, []protocol.TransactionStatus{protocol.TRANSACTION_STATUS_REJECTED_VIRTUAL_CHAIN_MISMATCH, protocol.TRANSACTION_STATUS_PENDING}, transactionStatusesOf(result))
+		})
+	})
+}
+
+func TestSendTransactions_ReportsTransactionsThatDidNotCompleteInTime(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+			harness := newPublicApiHarness(parent.Logger, 10*time.Millisecond, time.Minute)
+			harness.txpMock.When("AddNewTransaction", mock.Any, mock.Any).Return(&services.AddNewTransactionOutput{TransactionStatus: protocol.TRANSACTION_STATUS_PENDING}, nil).Times(1)
+
+			result, err := harness.papi.(publicapi.BatchTransactionSender).SendTransactions(ctx, &publicapi.SendTransactionsInput{
+				ClientRequests: sendTransactionRequests(builders.Transaction().Builder()),
+				WaitForCommit:  true,
+			})
+
+			require.Error(t, err, "should report the transaction that timed out")
+			require.Equal(t, []protocol.TransactionStatus{protocol.TRANSACTION_STATUS_PENDING}, transactionStatusesOf(result))
+		})
+	})
+}
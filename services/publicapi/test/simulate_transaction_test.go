@@ -0,0 +1,80 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package test
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-network-go/services/publicapi"
+	"github.com/orbs-network/orbs-network-go/services/virtualmachine"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-network-go/test/crypto/keys"
+	"github.com/orbs-network/orbs-network-go/test/with"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/protocol/client"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type simulatingVirtualMachineStub struct {
+	*services.MockVirtualMachine
+	simulated int
+}
+
+func (vm *simulatingVirtualMachineStub) SimulateTransaction(ctx context.Context, input *virtualmachine.SimulateTransactionInput) (*virtualmachine.SimulateTransactionOutput, error) {
+	vm.simulated++
+	return &virtualmachine.SimulateTransactionOutput{
+		CallResult:          protocol.EXECUTION_RESULT_SUCCESS,
+		OutputArgumentArray: protocol.ArgumentsArrayEmpty().RawArgumentsArray(),
+	}, nil
+}
+
+func simulate(ctx context.Context, papi services.PublicApi, signedTransaction *protocol.SignedTransactionBuilder) (*publicapi.SimulateTransactionOutput, error) {
+	request := (&client.SendTransactionRequestBuilder{SignedTransaction: signedTransaction}).Build()
+	return papi.(publicapi.TransactionSimulator).SimulateTransaction(ctx, &publicapi.SimulateTransactionInput{ClientRequest: request})
+}
+
+func TestSimulateTransaction_IsDisabledByDefault(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+			harness := newPublicApiHarness(parent.Logger, time.Second, time.Minute)
+
+			output, err := simulate(ctx, harness.papi, builders.Transaction().Builder())
+
+			require.Error(t, err, "simulations should be enabled in the config")
+			require.Nil(t, output)
+		})
+	})
+}
+
+func TestSimulateTransaction_VerifiesTheSignatureOfSignedTransactions(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+			cfg := config.ForPublicApiSimulationTests(uint32(builders.DEFAULT_TEST_VIRTUAL_CHAIN_ID))
+			vm := &simulatingVirtualMachineStub{MockVirtualMachine: &services.MockVirtualMachine{}}
+			papi := publicapi.NewPublicApi(cfg, makeTxMock(), vm, &services.MockBlockStorage{}, parent.Logger, metric.NewRegistry())
+
+			output, err := simulate(ctx, papi, builders.Transaction().WithInvalidEd25519Signer(keys.Ed25519KeyPairForTests(1)).Builder())
+			require.Error(t, err, "a transaction with an invalid signature should not be simulated")
+			require.Equal(t, protocol.REQUEST_STATUS_BAD_REQUEST, output.ClientResponse.RequestResult().RequestStatus())
+			require.Zero(t, vm.simulated)
+
+			output, err = simulate(ctx, papi, builders.Transaction().Builder())
+			require.NoError(t, err, "a validly signed transaction should be simulated")
+			require.Equal(t, protocol.EXECUTION_RESULT_SUCCESS, output.ClientResponse.QueryResult().ExecutionResult())
+
+			unsigned := builders.Transaction().Builder()
+			unsigned.Signature = nil
+			_, err = simulate(ctx, papi, unsigned)
+			require.NoError(t, err, "an unsigned transaction should be simulated")
+			require.Equal(t, 2, vm.simulated)
+		})
+	})
+}
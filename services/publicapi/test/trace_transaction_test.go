@@ -0,0 +1,80 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package test
+
+import (
+	"context"
+	"github.com/orbs-network/go-mock"
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-network-go/services/publicapi"
+	"github.com/orbs-network/orbs-network-go/services/virtualmachine"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-network-go/test/with"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/orbs-network/scribe/log"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type tracingVirtualMachineStub struct {
+	*services.MockVirtualMachine
+	result protocol.ExecutionResult
+}
+
+func (vm *tracingVirtualMachineStub) TraceTransaction(ctx context.Context, input *virtualmachine.TraceTransactionInput) (*virtualmachine.TraceTransactionOutput, error) {
+	transaction := input.SignedTransactions[input.TransactionIndex].Transaction()
+	receipt := builders.TransactionReceipt().WithTransaction(transaction).Builder()
+	receipt.ExecutionResult = vm.result
+	return &virtualmachine.TraceTransactionOutput{Trace: &virtualmachine.ExecutionTrace{}, Receipt: receipt.Build()}, nil
+}
+
+func traceCommittedTransaction(ctx context.Context, logger log.Logger, vm *tracingVirtualMachineStub) (*publicapi.TraceTransactionOutput, error) {
+	cfg := config.ForPublicApiTests(uint32(builders.DEFAULT_TEST_VIRTUAL_CHAIN_ID), time.Second, time.Minute)
+	blockStorage := &services.MockBlockStorage{}
+	papi := publicapi.NewPublicApi(cfg, makeTxMock(), vm, blockStorage, logger, metric.NewRegistry())
+
+	signedTransaction := builders.Transaction().Build()
+	blockStorage.When("GetTransactionReceipt", mock.Any, mock.Any).Return(&services.GetTransactionReceiptOutput{
+		TransactionReceipt: builders.TransactionReceipt().WithTransaction(signedTransaction.Transaction()).Build(),
+		BlockHeight:        3,
+	}, nil).Times(1)
+	blockStorage.When("GetBlockPair", mock.Any, mock.Any).Return(&services.GetBlockPairOutput{
+		BlockPair: builders.BlockPair().WithHeight(3).WithTransactionsArray([]*protocol.SignedTransaction{signedTransaction}).Build(),
+	}, nil).Times(1)
+
+	return papi.(publicapi.TransactionTracer).TraceTransaction(ctx, &publicapi.TraceTransactionInput{
+		Txhash:               digest.CalcTxHash(signedTransaction.Transaction()),
+		TransactionTimestamp: signedTransaction.Transaction().Timestamp(),
+	})
+}
+
+func TestTraceTransaction_ReturnsTheTraceOfACommittedTransaction(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+			output, err := traceCommittedTransaction(ctx, parent.Logger, &tracingVirtualMachineStub{result: protocol.EXECUTION_RESULT_SUCCESS})
+
+			require.NoError(t, err)
+			require.EqualValues(t, 3, output.BlockHeight)
+			require.NotNil(t, output.Trace)
+		})
+	})
+}
+
+func TestTraceTransaction_FailsWhenTheReExecutionDoesNotReproduceTheCommittedReceipt(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+			output, err := traceCommittedTransaction(ctx, parent.Logger, &tracingVirtualMachineStub{result: protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT})
+
+			require.Error(t, err, "a trace under a config other than the one the block was committed under should not be returned")
+			require.Nil(t, output)
+		})
+	})
+}
@@ -0,0 +1,97 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package publicapi
+
+import (
+	"bytes"
+	"context"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/instrumentation/logfields"
+	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
+	"github.com/orbs-network/orbs-network-go/services/virtualmachine"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/orbs-network/scribe/log"
+	"github.com/pkg/errors"
+)
+
+// TransactionTracer is offered by the public api service beside services.PublicApi for debugging committed transactions
+type TransactionTracer interface {
+	TraceTransaction(ctx context.Context, input *TraceTransactionInput) (*TraceTransactionOutput, error)
+}
+
+type TraceTransactionInput struct {
+	Txhash               primitives.Sha256
+	TransactionTimestamp primitives.TimestampNano
+}
+
+type TraceTransactionOutput struct {
+	BlockHeight primitives.BlockHeight
+	Trace       *virtualmachine.ExecutionTrace
+}
+
+// TraceTransaction re-executes a committed transaction on top of the transactions that preceded it in its block, it fails
+// for blocks older than the state storage history and when the re-execution does not reproduce the committed receipt
+func (s *service) TraceTransaction(parentCtx context.Context, input *TraceTransactionInput) (*TraceTransactionOutput, error) {
+	ctx := trace.NewContext(parentCtx, "PublicApi.TraceTransaction")
+	logger := s.logger.WithTags(trace.LogFieldFrom(ctx), logfields.Transaction(input.Txhash), log.String("flow", "checkpoint"))
+
+	tracer, ok := s.virtualMachine.(virtualmachine.TransactionTracer)
+	if !ok {
+		return nil, errors.Errorf("virtual machine does not support tracing transactions")
+	}
+
+	logger.Info("trace transaction request received")
+
+	receipt, err := s.blockStorage.GetTransactionReceipt(ctx, &services.GetTransactionReceiptInput{
+		Txhash:               input.Txhash,
+		TransactionTimestamp: input.TransactionTimestamp,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed looking up the transaction in block storage")
+	}
+	if receipt.TransactionReceipt == nil {
+		return nil, errors.Errorf("transaction %s is not committed", input.Txhash)
+	}
+
+	blockPair, err := s.blockStorage.GetBlockPair(ctx, &services.GetBlockPairInput{BlockHeight: receipt.BlockHeight})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed reading block %d", receipt.BlockHeight)
+	}
+	if blockPair.BlockPair == nil {
+		return nil, errors.Errorf("block %d was not found", receipt.BlockHeight)
+	}
+
+	transactionsBlock := blockPair.BlockPair.TransactionsBlock
+	for i, signedTransaction := range transactionsBlock.SignedTransactions {
+		if !digest.CalcTxHash(signedTransaction.Transaction()).Equal(input.Txhash) {
+			continue
+		}
+
+		output, err := tracer.TraceTransaction(ctx, &virtualmachine.TraceTransactionInput{
+			SignedTransactions:    transactionsBlock.SignedTransactions,
+			TransactionIndex:      i,
+			CurrentBlockHeight:    transactionsBlock.Header.BlockHeight(),
+			CurrentBlockTimestamp: transactionsBlock.Header.Timestamp(),
+			BlockProposerAddress:  transactionsBlock.Header.BlockProposerAddress(),
+		})
+		if err != nil {
+			logger.Info("trace transaction request failed", log.Error(err), logfields.BlockHeight(receipt.BlockHeight))
+			return nil, err
+		}
+
+		// the re-execution runs under the gas and sequencing config the node runs now, a trace of a block committed under a different config is not the transaction's trace
+		if !bytes.Equal(output.Receipt.Raw(), receipt.TransactionReceipt.Raw()) {
+			err := errors.Errorf("tracing did not reproduce the committed receipt of the transaction, the node's config has changed since block %d", receipt.BlockHeight)
+			logger.Info("trace transaction request failed", log.Error(err), logfields.BlockHeight(receipt.BlockHeight))
+			return nil, err
+		}
+		return &TraceTransactionOutput{BlockHeight: receipt.BlockHeight, Trace: output.Trace}, nil
+	}
+
+	return nil, errors.Errorf("transaction %s is missing from block %d", input.Txhash, receipt.BlockHeight)
+}
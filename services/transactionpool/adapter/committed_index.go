@@ -0,0 +1,19 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package adapter
+
+import "github.com/orbs-network/orbs-spec/types/go/primitives"
+
+// CommittedTransactionIndex remembers the hashes of all committed transactions, unlike the committed pool which forgets
+// them once they expire, so that resubmissions of committed transactions are rejected at any age regardless of clocks.
+// Add is called with the transactions of each block in order and ignores blocks up to LastBlockHeight; Contains never
+// returns false for a transaction of an added block.
+type CommittedTransactionIndex interface {
+	Add(blockHeight primitives.BlockHeight, txHashes []primitives.Sha256) error
+	Contains(txHash primitives.Sha256) (bool, error)
+	LastBlockHeight() primitives.BlockHeight
+}
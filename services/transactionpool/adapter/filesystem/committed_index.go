@@ -0,0 +1,452 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package filesystem
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/crypto/bloom"
+	"github.com/orbs-network/orbs-network-go/crypto/hash"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/scribe/log"
+	"github.com/pkg/errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const committedIndexDirname = "committed-transactions"
+const committedIndexHeightFilename = "height"
+const committedIndexBloomFilename = "bloom"
+const committedIndexBucketCount = 256
+const committedIndexBloomHashCount = 7
+const committedIndexInitialSlotCount = 256 // per bucket, a power of two
+const committedIndexReadChunkSize = 4096 * hash.SHA256_HASH_SIZE_BYTES
+
+// The index is a bloom filter in memory in front of an exact set of hashes on disk, so memory use is fixed by the bloom
+// filter size however many transactions were committed. The exact set is split by the first byte of the hash into
+// bucket files, each an open addressing hash table of raw hashes (an empty slot is all zeros) which is looked up in a
+// few reads when the bloom filter matches, and is rewritten at double its size when it gets half full. Lookups do not
+// hold the index lock while reading a table, and Add writes and syncs the tables and then the block height before
+// publishing the block in the bloom filter. A crash before the height is synced makes block storage sync the block to
+// the transaction pool again, which adds its hashes again without duplicating them. The filter is saved on graceful
+// shutdown and rebuilt from the tables if it is missing or stale.
+type CommittedTransactionIndex struct {
+	addMutex        sync.Mutex // serializes Add, which does its disk writes without holding the lock below
+	sync.RWMutex               // guards the bloom filter, the tables and the block height, never held while reading a table
+	dir             string
+	tables          [committedIndexBucketCount]*committedIndexTable
+	heightFile      *os.File
+	bloom           *bloom.HashBloomFilter
+	lastBlockHeight primitives.BlockHeight
+	logger          log.Logger
+
+	metrics struct {
+		transactions   *metric.Gauge
+		falsePositives *metric.Gauge
+	}
+}
+
+// a table file holds slotCount slots of one hash each, hashes are placed by their bytes after the bucket byte and
+// probed linearly; count and writes are only touched by Add
+type committedIndexTable struct {
+	file      *os.File
+	slotCount uint64
+	count     uint64
+	readers   sync.WaitGroup // lookups reading the file, which is closed once it is replaced and they are done
+}
+
+func NewCommittedTransactionIndex(conf config.FilesystemCommittedIndexConfig, parent log.Logger, metricFactory metric.Factory) (*CommittedTransactionIndex, error) {
+	dir := filepath.Join(conf.BlockStorageFileSystemDataDir(), committedIndexDirname)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, errors.Wrapf(err, "failed to verify committed transactions index directory exists %s", dir)
+	}
+
+	i := &CommittedTransactionIndex{
+		dir:    dir,
+		logger: parent.WithTags(log.String("adapter", "transaction-pool"), log.String("dir", dir)),
+	}
+	i.metrics.transactions = metricFactory.NewGauge("TransactionPool.CommittedIndex.Transactions.Count")
+	i.metrics.falsePositives = metricFactory.NewGauge("TransactionPool.CommittedIndex.BloomFalsePositives.Count")
+
+	if err := i.open(); err != nil {
+		i.closeFiles()
+		return nil, err
+	}
+
+	if err := i.loadOrRebuildBloom(conf.TransactionPoolCommittedIndexBloomSizeInBytes()); err != nil {
+		i.closeFiles()
+		return nil, err
+	}
+
+	i.logger.Info("opened committed transactions index", log.Uint64("last-block-height", uint64(i.lastBlockHeight)), log.Int64("num-transactions", i.metrics.transactions.Value()))
+	return i, nil
+}
+
+func (i *CommittedTransactionIndex) Add(blockHeight primitives.BlockHeight, txHashes []primitives.Sha256) error {
+	i.addMutex.Lock()
+	defer i.addMutex.Unlock()
+
+	if blockHeight <= i.LastBlockHeight() {
+		return nil
+	}
+
+	var byBucket [committedIndexBucketCount][]primitives.Sha256
+	for _, txHash := range txHashes {
+		if len(txHash) != hash.SHA256_HASH_SIZE_BYTES || isEmptySlot(txHash) {
+			return errors.Errorf("invalid transaction hash %s", txHash)
+		}
+		byBucket[txHash[0]] = append(byBucket[txHash[0]], txHash)
+	}
+
+	var added uint64
+	for bucket, bucketHashes := range byBucket {
+		if len(bucketHashes) == 0 {
+			continue
+		}
+		if err := i.growIfNeeded(bucket, uint64(len(bucketHashes))); err != nil {
+			return err
+		}
+
+		table := i.tables[bucket]
+		for _, txHash := range bucketHashes {
+			inserted, err := table.insert(txHash)
+			if err != nil {
+				return errors.Wrapf(err, "failed to write committed transactions index bucket %d", bucket)
+			}
+			if inserted {
+				added++
+			}
+		}
+		if err := table.file.Sync(); err != nil {
+			return errors.Wrapf(err, "failed to sync committed transactions index bucket %d", bucket)
+		}
+	}
+
+	if err := i.writeHeight(blockHeight); err != nil {
+		return err
+	}
+
+	i.Lock()
+	for _, txHash := range txHashes {
+		i.bloom.Add(txHash)
+	}
+	i.lastBlockHeight = blockHeight
+	i.Unlock()
+
+	i.metrics.transactions.Add(int64(added))
+	return nil
+}
+
+func (i *CommittedTransactionIndex) Contains(txHash primitives.Sha256) (bool, error) {
+	if len(txHash) != hash.SHA256_HASH_SIZE_BYTES {
+		return false, nil
+	}
+
+	i.RLock()
+	if !i.bloom.Test(txHash) {
+		i.RUnlock()
+		return false, nil
+	}
+	table := i.tables[txHash[0]]
+	table.readers.Add(1)
+	i.RUnlock()
+	defer table.readers.Done()
+
+	found, err := table.contains(txHash)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to read committed transactions index bucket %d", txHash[0])
+	}
+	if !found {
+		i.metrics.falsePositives.Inc()
+	}
+	return found, nil
+}
+
+func (i *CommittedTransactionIndex) LastBlockHeight() primitives.BlockHeight {
+	i.RLock()
+	defer i.RUnlock()
+
+	return i.lastBlockHeight
+}
+
+func (i *CommittedTransactionIndex) GracefulShutdown(shutdownContext context.Context) {
+	i.addMutex.Lock()
+	defer i.addMutex.Unlock()
+	i.Lock()
+	defer i.Unlock()
+
+	if err := i.saveBloom(); err != nil {
+		i.logger.Error("failed to save committed transactions bloom filter, it will be rebuilt on startup", log.Error(err))
+	}
+	i.closeFiles()
+	i.logger.Info("closed committed transactions index")
+}
+
+func (i *CommittedTransactionIndex) open() error {
+	heightFilename := filepath.Join(i.dir, committedIndexHeightFilename)
+	heightFile, err := os.OpenFile(heightFilename, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open %s", heightFilename)
+	}
+	i.heightFile = heightFile
+
+	height := make([]byte, 8)
+	if _, err := heightFile.ReadAt(height, 0); err == nil {
+		i.lastBlockHeight = primitives.BlockHeight(binary.LittleEndian.Uint64(height))
+	} else if err != io.EOF {
+		return errors.Wrapf(err, "failed to read %s", heightFilename)
+	}
+
+	for bucket := range i.tables {
+		filename := i.tableFilename(bucket)
+		_ = os.Remove(filename + ".tmp") // left by a crash while growing the table
+		table, err := openCommittedIndexTable(filename, committedIndexInitialSlotCount)
+		if err != nil {
+			return err
+		}
+		i.tables[bucket] = table
+		i.metrics.transactions.Add(int64(table.count))
+	}
+	return nil
+}
+
+// the saved filter is [block height][bits], it is used only if it was saved at the index's block height
+func (i *CommittedTransactionIndex) loadOrRebuildBloom(sizeInBytes uint32) error {
+	saved, err := ioutil.ReadFile(filepath.Join(i.dir, committedIndexBloomFilename))
+	if err == nil && len(saved) > 8 && primitives.BlockHeight(binary.LittleEndian.Uint64(saved)) == i.lastBlockHeight {
+		if filter, err := bloom.NewHashBloomFilterFromRaw(saved[8:], committedIndexBloomHashCount); err == nil && filter.SizeInBytes() == bloom.NewHashBloomFilter(sizeInBytes, committedIndexBloomHashCount).SizeInBytes() {
+			i.bloom = filter
+			return nil
+		}
+	}
+
+	i.logger.Info("rebuilding committed transactions bloom filter")
+	i.bloom = bloom.NewHashBloomFilter(sizeInBytes, committedIndexBloomHashCount)
+	for bucket, table := range i.tables {
+		if err := table.forEach(func(txHash []byte) {
+			i.bloom.Add(txHash)
+		}); err != nil {
+			return errors.Wrapf(err, "failed to read committed transactions index bucket %d", bucket)
+		}
+	}
+	return nil
+}
+
+func (i *CommittedTransactionIndex) saveBloom() error {
+	filename := filepath.Join(i.dir, committedIndexBloomFilename)
+	tmpFilename := filename + ".tmp"
+
+	content := make([]byte, 8, 8+i.bloom.SizeInBytes())
+	binary.LittleEndian.PutUint64(content, uint64(i.lastBlockHeight))
+	content = append(content, i.bloom.Raw()...)
+	if err := ioutil.WriteFile(tmpFilename, content, 0600); err != nil {
+		return errors.Wrapf(err, "failed to write %s", tmpFilename)
+	}
+	return os.Rename(tmpFilename, filename)
+}
+
+func (i *CommittedTransactionIndex) writeHeight(blockHeight primitives.BlockHeight) error {
+	height := make([]byte, 8)
+	binary.LittleEndian.PutUint64(height, uint64(blockHeight))
+	if _, err := i.heightFile.WriteAt(height, 0); err != nil {
+		return errors.Wrap(err, "failed to write committed transactions index block height")
+	}
+	if err := i.heightFile.Sync(); err != nil {
+		return errors.Wrap(err, "failed to sync committed transactions index block height")
+	}
+	return nil
+}
+
+// rewrites the bucket's table at a size which keeps it at most half full after adding, and replaces it once lookups of
+// the old table are done
+func (i *CommittedTransactionIndex) growIfNeeded(bucket int, adding uint64) error {
+	old := i.tables[bucket]
+	slotCount := old.slotCount
+	for (old.count+adding)*2 > slotCount {
+		slotCount *= 2
+	}
+	if slotCount == old.slotCount {
+		return nil
+	}
+
+	filename := i.tableFilename(bucket)
+	tmpFilename := filename + ".tmp"
+	grown, err := createCommittedIndexTable(tmpFilename, slotCount)
+	if err != nil {
+		return err
+	}
+	if err := i.writeGrownTable(old, grown, tmpFilename, filename); err != nil {
+		closeSilently(grown.file, i.logger)
+		return errors.Wrapf(err, "failed to grow committed transactions index bucket %d", bucket)
+	}
+
+	i.Lock()
+	i.tables[bucket] = grown
+	i.Unlock()
+
+	old.readers.Wait()
+	closeSilently(old.file, i.logger)
+	return nil
+}
+
+func (i *CommittedTransactionIndex) writeGrownTable(old *committedIndexTable, grown *committedIndexTable, tmpFilename string, filename string) error {
+	var insertErr error
+	if err := old.forEach(func(txHash []byte) {
+		if insertErr == nil {
+			_, insertErr = grown.insert(txHash)
+		}
+	}); err != nil {
+		return err
+	}
+	if insertErr != nil {
+		return insertErr
+	}
+	if err := grown.file.Sync(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpFilename, filename); err != nil {
+		return err
+	}
+	return syncDir(i.dir)
+}
+
+func (i *CommittedTransactionIndex) tableFilename(bucket int) string {
+	return filepath.Join(i.dir, fmt.Sprintf("%02x.table", bucket))
+}
+
+func (i *CommittedTransactionIndex) closeFiles() {
+	if i.heightFile != nil {
+		closeSilently(i.heightFile, i.logger)
+	}
+	for _, table := range i.tables {
+		if table != nil {
+			table.readers.Wait()
+			closeSilently(table.file, i.logger)
+		}
+	}
+}
+
+func openCommittedIndexTable(filename string, initialSlotCount uint64) (*committedIndexTable, error) {
+	info, err := os.Stat(filename)
+	if os.IsNotExist(err) {
+		return createCommittedIndexTable(filename, initialSlotCount)
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "failed to stat %s", filename)
+	}
+
+	slotCount := uint64(info.Size()) / hash.SHA256_HASH_SIZE_BYTES
+	if slotCount == 0 || slotCount&(slotCount-1) != 0 || uint64(info.Size())%hash.SHA256_HASH_SIZE_BYTES != 0 {
+		return nil, errors.Errorf("committed transactions index table %s has an invalid size %d", filename, info.Size())
+	}
+
+	file, err := os.OpenFile(filename, os.O_RDWR, 0600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open %s", filename)
+	}
+	table := &committedIndexTable{file: file, slotCount: slotCount}
+	if err := table.forEach(func([]byte) { table.count++ }); err != nil {
+		_ = file.Close()
+		return nil, errors.Wrapf(err, "failed to read %s", filename)
+	}
+	return table, nil
+}
+
+func createCommittedIndexTable(filename string, slotCount uint64) (*committedIndexTable, error) {
+	file, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create %s", filename)
+	}
+	if err := file.Truncate(int64(slotCount * hash.SHA256_HASH_SIZE_BYTES)); err != nil {
+		_ = file.Close()
+		return nil, errors.Wrapf(err, "failed to allocate %s", filename)
+	}
+	return &committedIndexTable{file: file, slotCount: slotCount}, nil
+}
+
+// returns false if the hash is already in the table
+func (t *committedIndexTable) insert(txHash []byte) (bool, error) {
+	slot := make([]byte, hash.SHA256_HASH_SIZE_BYTES)
+	for probe, offset := uint64(0), t.firstSlot(txHash); probe < t.slotCount; probe, offset = probe+1, (offset+1)&(t.slotCount-1) {
+		if _, err := t.file.ReadAt(slot, int64(offset*hash.SHA256_HASH_SIZE_BYTES)); err != nil {
+			return false, err
+		}
+		if bytes.Equal(slot, txHash) {
+			return false, nil
+		}
+		if isEmptySlot(slot) {
+			if _, err := t.file.WriteAt(txHash, int64(offset*hash.SHA256_HASH_SIZE_BYTES)); err != nil {
+				return false, err
+			}
+			t.count++
+			return true, nil
+		}
+	}
+	return false, errors.New("committed transactions index table is full")
+}
+
+func (t *committedIndexTable) contains(txHash []byte) (bool, error) {
+	slot := make([]byte, hash.SHA256_HASH_SIZE_BYTES)
+	for probe, offset := uint64(0), t.firstSlot(txHash); probe < t.slotCount; probe, offset = probe+1, (offset+1)&(t.slotCount-1) {
+		if _, err := t.file.ReadAt(slot, int64(offset*hash.SHA256_HASH_SIZE_BYTES)); err != nil {
+			return false, err
+		}
+		if bytes.Equal(slot, txHash) {
+			return true, nil
+		}
+		if isEmptySlot(slot) {
+			return false, nil
+		}
+	}
+	return false, nil
+}
+
+// the first byte selects the bucket, the next ones are as uniformly distributed
+func (t *committedIndexTable) firstSlot(txHash []byte) uint64 {
+	return binary.BigEndian.Uint64(txHash[1:9]) & (t.slotCount - 1)
+}
+
+// reads the table from its start without moving the file offset
+func (t *committedIndexTable) forEach(f func(txHash []byte)) error {
+	r := bufio.NewReaderSize(io.NewSectionReader(t.file, 0, int64(t.slotCount*hash.SHA256_HASH_SIZE_BYTES)), committedIndexReadChunkSize)
+	slot := make([]byte, hash.SHA256_HASH_SIZE_BYTES)
+	for {
+		if _, err := io.ReadFull(r, slot); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if !isEmptySlot(slot) {
+			f(slot)
+		}
+	}
+}
+
+func isEmptySlot(slot []byte) bool {
+	for _, b := range slot {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = d.Close() }()
+	return d.Sync()
+}
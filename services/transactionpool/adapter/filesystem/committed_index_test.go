@@ -0,0 +1,122 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package filesystem
+
+import (
+	"context"
+	"encoding/binary"
+	"github.com/orbs-network/orbs-network-go/crypto/hash"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-network-go/test/with"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type committedIndexConfig struct {
+	journalConfig
+}
+
+func (c *committedIndexConfig) TransactionPoolCommittedIndexBloomSizeInBytes() uint32 {
+	return 1024
+}
+
+func txHashForIndexTests(i int) primitives.Sha256 {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, uint64(i))
+	return hash.CalcSha256(b)
+}
+
+func openCommittedIndex(t *testing.T, conf *journalConfig, harness *with.LoggingHarness) *CommittedTransactionIndex {
+	index, err := NewCommittedTransactionIndex(&committedIndexConfig{*conf}, harness.Logger, metric.NewRegistry())
+	require.NoError(t, err)
+	return index
+}
+
+func TestCommittedTransactionIndex_ContainsAddedTransactionsAcrossRestarts(t *testing.T) {
+	withJournalDir(t, func(conf *journalConfig, harness *with.LoggingHarness) {
+		index := openCommittedIndex(t, conf, harness)
+		require.NoError(t, index.Add(1, []primitives.Sha256{txHashForIndexTests(1), txHashForIndexTests(2)}))
+		require.NoError(t, index.Add(2, []primitives.Sha256{txHashForIndexTests(3)}))
+		require.NoError(t, index.Add(2, []primitives.Sha256{txHashForIndexTests(4)}), "an already indexed block should be ignored")
+
+		for _, i := range []int{1, 2, 3} {
+			found, err := index.Contains(txHashForIndexTests(i))
+			require.NoError(t, err)
+			require.True(t, found, "transaction %d should be found", i)
+		}
+		for i := 4; i < 200; i++ {
+			found, err := index.Contains(txHashForIndexTests(i))
+			require.NoError(t, err)
+			require.False(t, found, "transaction %d was not added", i)
+		}
+		index.GracefulShutdown(context.Background())
+
+		reopened := openCommittedIndex(t, conf, harness)
+		defer reopened.GracefulShutdown(context.Background())
+		require.EqualValues(t, 2, reopened.LastBlockHeight())
+		found, err := reopened.Contains(txHashForIndexTests(3))
+		require.NoError(t, err)
+		require.True(t, found, "transactions should be found after a restart")
+	})
+}
+
+func TestCommittedTransactionIndex_RebuildsBloomFilterAfterCrash(t *testing.T) {
+	withJournalDir(t, func(conf *journalConfig, harness *with.LoggingHarness) {
+		index := openCommittedIndex(t, conf, harness)
+		require.NoError(t, index.Add(1, []primitives.Sha256{txHashForIndexTests(1)}))
+		index.GracefulShutdown(context.Background())
+
+		crashed := openCommittedIndex(t, conf, harness)
+		require.NoError(t, crashed.Add(2, []primitives.Sha256{txHashForIndexTests(2)}))
+		crashed.closeFiles() // no graceful shutdown, the saved bloom filter is of block 1
+
+		leftover := filepath.Join(conf.dir, committedIndexDirname, "00.table.tmp")
+		require.NoError(t, ioutil.WriteFile(leftover, []byte{1, 2, 3}, 0600), "a crash while growing a table leaves a partial table behind")
+
+		reopened := openCommittedIndex(t, conf, harness)
+		defer reopened.GracefulShutdown(context.Background())
+		found, err := reopened.Contains(txHashForIndexTests(2))
+		require.NoError(t, err)
+		require.True(t, found, "transactions added after the bloom filter was saved should be found")
+		require.EqualValues(t, 2, reopened.metrics.transactions.Value())
+		_, err = os.Stat(leftover)
+		require.True(t, os.IsNotExist(err), "partial table should be removed")
+	})
+}
+
+func TestCommittedTransactionIndex_GrowsBucketTablesWithoutLosingTransactions(t *testing.T) {
+	withJournalDir(t, func(conf *journalConfig, harness *with.LoggingHarness) {
+		index := openCommittedIndex(t, conf, harness)
+
+		var sameBucket []primitives.Sha256
+		for i := 0; len(sameBucket) < 3*committedIndexInitialSlotCount; i++ {
+			if txHash := txHashForIndexTests(i); txHash[0] == 7 {
+				sameBucket = append(sameBucket, txHash)
+			}
+		}
+		third := len(sameBucket) / 3
+		require.NoError(t, index.Add(1, sameBucket[:third]))
+		require.NoError(t, index.Add(2, sameBucket[third:]))
+		require.NoError(t, index.Add(3, sameBucket[:third]), "transactions added again should not be duplicated")
+		require.Greater(t, index.tables[7].slotCount, uint64(2*len(sameBucket)-1), "table should be kept at most half full")
+		require.EqualValues(t, len(sameBucket), index.metrics.transactions.Value())
+		index.GracefulShutdown(context.Background())
+
+		reopened := openCommittedIndex(t, conf, harness)
+		defer reopened.GracefulShutdown(context.Background())
+		require.EqualValues(t, len(sameBucket), reopened.metrics.transactions.Value())
+		for _, txHash := range sameBucket {
+			found, err := reopened.Contains(txHash)
+			require.NoError(t, err)
+			require.True(t, found, "transaction %s should be found", txHash)
+		}
+	})
+}
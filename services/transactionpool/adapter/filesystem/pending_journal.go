@@ -0,0 +1,279 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package filesystem
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/services/transactionpool/adapter"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/scribe/log"
+	"github.com/pkg/errors"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const pendingJournalFilename = "pending-transactions.journal"
+
+const journalRecordMagic = uint32(0x4a585450) // "PTXJ"
+const journalRecordHeaderSize = 12
+const journalRecordChecksumSize = 4
+const journalMaxRecordSize = 64 * 1024 * 1024
+
+const (
+	journalRecordAdd    = uint32(1)
+	journalRecordRemove = uint32(2)
+)
+
+// The journal is an append-only log of add and remove records, each [magic][type][size][payload][crc32] in little endian.
+// Records are not synced to disk one by one, so the journal survives a process crash but may lose its tail on a
+// machine crash; a torn or corrupt tail is truncated on load.
+type PendingTransactionJournal struct {
+	sync.Mutex
+	filename string
+	file     *os.File
+	records  int
+	logger   log.Logger
+}
+
+func NewPendingTransactionJournal(conf config.FilesystemPendingJournalConfig, parent log.Logger) (*PendingTransactionJournal, error) {
+	dir := conf.BlockStorageFileSystemDataDir()
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, errors.Wrapf(err, "failed to verify data directory exists %s", dir)
+	}
+
+	filename := filepath.Join(dir, pendingJournalFilename)
+	file, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open pending transactions journal %s", filename)
+	}
+
+	return &PendingTransactionJournal{
+		filename: filename,
+		file:     file,
+		logger:   parent.WithTags(log.String("adapter", "transaction-pool"), log.String("filename", filename)),
+	}, nil
+}
+
+func (j *PendingTransactionJournal) Add(transaction *adapter.JournaledTransaction) error {
+	j.Lock()
+	defer j.Unlock()
+
+	return j.append(journalRecordAdd, encodeJournaledTransaction(transaction))
+}
+
+func (j *PendingTransactionJournal) Remove(txHash primitives.Sha256) error {
+	j.Lock()
+	defer j.Unlock()
+
+	return j.append(journalRecordRemove, txHash)
+}
+
+func (j *PendingTransactionJournal) RecordCount() int {
+	j.Lock()
+	defer j.Unlock()
+
+	return j.records
+}
+
+func (j *PendingTransactionJournal) Load() ([]*adapter.JournaledTransaction, error) {
+	j.Lock()
+	defer j.Unlock()
+
+	if _, err := j.file.Seek(0, io.SeekStart); err != nil {
+		return nil, errors.Wrap(err, "failed to seek to start of pending transactions journal")
+	}
+
+	var transactions []*adapter.JournaledTransaction
+	indexByHash := make(map[string]int)
+	records, validSize := 0, int64(0)
+	r := bufio.NewReader(j.file)
+	for {
+		recordType, payload, err := readJournalRecord(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			j.logger.Error("pending transactions journal is corrupt, truncating it after the last valid record", log.Error(err), log.Int64("offset", validSize))
+			break
+		}
+
+		switch recordType {
+		case journalRecordAdd:
+			transaction, err := decodeJournaledTransaction(payload)
+			if err != nil {
+				j.logger.Error("skipping invalid transaction in pending transactions journal", log.Error(err))
+				break
+			}
+			indexByHash[digest.CalcTxHash(transaction.Transaction.Transaction()).KeyForMap()] = len(transactions)
+			transactions = append(transactions, transaction)
+		case journalRecordRemove:
+			if i, found := indexByHash[primitives.Sha256(payload).KeyForMap()]; found {
+				transactions[i] = nil
+				delete(indexByHash, primitives.Sha256(payload).KeyForMap())
+			}
+		}
+
+		records++
+		validSize += int64(journalRecordHeaderSize + len(payload) + journalRecordChecksumSize)
+	}
+
+	if err := j.file.Truncate(validSize); err != nil {
+		return nil, errors.Wrap(err, "failed to truncate pending transactions journal")
+	}
+	if _, err := j.file.Seek(validSize, io.SeekStart); err != nil {
+		return nil, errors.Wrap(err, "failed to seek to end of pending transactions journal")
+	}
+	j.records = records
+
+	var res []*adapter.JournaledTransaction
+	for _, transaction := range transactions {
+		if transaction != nil {
+			res = append(res, transaction)
+		}
+	}
+	return res, nil
+}
+
+// replaces the journal with one that holds only the given transactions, atomically through a rename
+func (j *PendingTransactionJournal) Rewrite(transactions []*adapter.JournaledTransaction) error {
+	j.Lock()
+	defer j.Unlock()
+
+	tmpFilename := j.filename + ".tmp"
+	tmpFile, err := os.OpenFile(tmpFilename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s", tmpFilename)
+	}
+
+	w := bufio.NewWriter(tmpFile)
+	for _, transaction := range transactions {
+		if _, err := w.Write(encodeJournalRecord(journalRecordAdd, encodeJournaledTransaction(transaction))); err != nil {
+			closeSilently(tmpFile, j.logger)
+			return errors.Wrapf(err, "failed to write %s", tmpFilename)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		closeSilently(tmpFile, j.logger)
+		return errors.Wrapf(err, "failed to write %s", tmpFilename)
+	}
+	if err := tmpFile.Sync(); err != nil {
+		closeSilently(tmpFile, j.logger)
+		return errors.Wrapf(err, "failed to sync %s", tmpFilename)
+	}
+
+	if err := os.Rename(tmpFilename, j.filename); err != nil {
+		closeSilently(tmpFile, j.logger)
+		return errors.Wrapf(err, "failed to replace pending transactions journal with %s", tmpFilename)
+	}
+
+	closeSilently(j.file, j.logger)
+	j.file = tmpFile // the file was opened with O_RDWR and is positioned at its end, ready for appending
+	j.records = len(transactions)
+	return nil
+}
+
+func (j *PendingTransactionJournal) GracefulShutdown(shutdownContext context.Context) {
+	j.Lock()
+	defer j.Unlock()
+
+	if err := j.file.Close(); err != nil {
+		j.logger.Error("failed to close pending transactions journal", log.Error(err))
+		return
+	}
+	j.logger.Info("closed pending transactions journal")
+}
+
+func (j *PendingTransactionJournal) append(recordType uint32, payload []byte) error {
+	if _, err := j.file.Write(encodeJournalRecord(recordType, payload)); err != nil {
+		return errors.Wrap(err, "failed to append to pending transactions journal")
+	}
+	j.records++
+	return nil
+}
+
+func encodeJournalRecord(recordType uint32, payload []byte) []byte {
+	record := make([]byte, journalRecordHeaderSize+len(payload)+journalRecordChecksumSize)
+	binary.LittleEndian.PutUint32(record[0:], journalRecordMagic)
+	binary.LittleEndian.PutUint32(record[4:], recordType)
+	binary.LittleEndian.PutUint32(record[8:], uint32(len(payload)))
+	copy(record[journalRecordHeaderSize:], payload)
+	binary.LittleEndian.PutUint32(record[journalRecordHeaderSize+len(payload):], crc32.ChecksumIEEE(payload))
+	return record
+}
+
+// returns io.EOF only if there are no more records, a partial record is an error
+func readJournalRecord(r io.Reader) (uint32, []byte, error) {
+	header := make([]byte, journalRecordHeaderSize)
+	if n, err := io.ReadFull(r, header); err != nil {
+		if err == io.EOF && n == 0 {
+			return 0, nil, io.EOF
+		}
+		return 0, nil, errors.Wrap(err, "failed reading record header")
+	}
+
+	if magic := binary.LittleEndian.Uint32(header[0:]); magic != journalRecordMagic {
+		return 0, nil, errors.Errorf("invalid record magic %x", magic)
+	}
+	size := binary.LittleEndian.Uint32(header[8:])
+	if size > journalMaxRecordSize {
+		return 0, nil, errors.Errorf("record size %d exceeds maximum", size)
+	}
+
+	body := make([]byte, size+journalRecordChecksumSize)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, errors.Wrap(err, "failed reading record")
+	}
+
+	payload := body[:size]
+	if checksum := binary.LittleEndian.Uint32(body[size:]); checksum != crc32.ChecksumIEEE(payload) {
+		return 0, nil, errors.New("record checksum mismatch")
+	}
+	return binary.LittleEndian.Uint32(header[4:]), payload, nil
+}
+
+func encodeJournaledTransaction(transaction *adapter.JournaledTransaction) []byte {
+	raw := transaction.Transaction.Raw()
+	payload := make([]byte, 4+len(transaction.GatewayNodeAddress)+len(raw))
+	binary.LittleEndian.PutUint32(payload, uint32(len(transaction.GatewayNodeAddress)))
+	copy(payload[4:], transaction.GatewayNodeAddress)
+	copy(payload[4+len(transaction.GatewayNodeAddress):], raw)
+	return payload
+}
+
+func decodeJournaledTransaction(payload []byte) (*adapter.JournaledTransaction, error) {
+	if len(payload) < 4 {
+		return nil, errors.New("transaction record too short")
+	}
+	gatewaySize := int(binary.LittleEndian.Uint32(payload))
+	if len(payload) < 4+gatewaySize {
+		return nil, errors.New("transaction record too short for gateway node address")
+	}
+
+	transaction := protocol.SignedTransactionReader(payload[4+gatewaySize:])
+	if !transaction.IsValid() {
+		return nil, errors.New("transaction record holds an invalid transaction")
+	}
+
+	return &adapter.JournaledTransaction{
+		Transaction:        transaction,
+		GatewayNodeAddress: primitives.NodeAddress(payload[4 : 4+gatewaySize]),
+	}, nil
+}
+
+func closeSilently(file *os.File, logger log.Logger) {
+	if err := file.Close(); err != nil {
+		logger.Error("failed to close file", log.Error(err), log.String("filename", file.Name()))
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package filesystem
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/services/transactionpool/adapter"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-network-go/test/crypto/keys"
+	"github.com/orbs-network/orbs-network-go/test/with"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type journalConfig struct {
+	dir string
+}
+
+func (c *journalConfig) BlockStorageFileSystemDataDir() string {
+	return c.dir
+}
+
+func TestPendingTransactionJournal_ReloadsAddedTransactionsThatWereNotRemoved(t *testing.T) {
+	withJournalDir(t, func(conf *journalConfig, harness *with.LoggingHarness) {
+		gateway := keys.EcdsaSecp256K1KeyPairForTests(1).NodeAddress()
+		tx1 := builders.TransferTransaction().Build()
+		tx2 := builders.TransferTransaction().Build()
+		tx3 := builders.TransferTransaction().Build()
+
+		journal := openJournal(t, conf, harness)
+		for _, tx := range []*adapter.JournaledTransaction{{Transaction: tx1, GatewayNodeAddress: gateway}, {Transaction: tx2, GatewayNodeAddress: gateway}, {Transaction: tx3, GatewayNodeAddress: gateway}} {
+			require.NoError(t, journal.Add(tx))
+		}
+		require.NoError(t, journal.Remove(digest.CalcTxHash(tx2.Transaction())))
+		journal.GracefulShutdown(context.Background())
+
+		reopened := openJournal(t, conf, harness)
+		defer reopened.GracefulShutdown(context.Background())
+		transactions, err := reopened.Load()
+		require.NoError(t, err)
+		require.Len(t, transactions, 2)
+		require.Equal(t, tx1.Raw(), transactions[0].Transaction.Raw(), "transactions should be loaded oldest first")
+		require.Equal(t, tx3.Raw(), transactions[1].Transaction.Raw())
+		require.Equal(t, gateway, transactions[0].GatewayNodeAddress)
+		require.Equal(t, 4, reopened.RecordCount())
+	})
+}
+
+func TestPendingTransactionJournal_TruncatesCorruptTail(t *testing.T) {
+	withJournalDir(t, func(conf *journalConfig, harness *with.LoggingHarness) {
+		harness.AllowErrorsMatching("pending transactions journal is corrupt")
+		tx := builders.TransferTransaction().Build()
+
+		journal := openJournal(t, conf, harness)
+		require.NoError(t, journal.Add(&adapter.JournaledTransaction{Transaction: tx}))
+		journal.GracefulShutdown(context.Background())
+
+		file, err := os.OpenFile(filepath.Join(conf.dir, pendingJournalFilename), os.O_APPEND|os.O_WRONLY, 0600)
+		require.NoError(t, err)
+		_, err = file.Write(encodeJournalRecord(journalRecordAdd, []byte{1, 2, 3})[:10]) // torn write
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		reopened := openJournal(t, conf, harness)
+		transactions, err := reopened.Load()
+		require.NoError(t, err)
+		require.Len(t, transactions, 1)
+
+		tx2 := builders.TransferTransaction().Build()
+		require.NoError(t, reopened.Add(&adapter.JournaledTransaction{Transaction: tx2}))
+		reopened.GracefulShutdown(context.Background())
+
+		reopened = openJournal(t, conf, harness)
+		defer reopened.GracefulShutdown(context.Background())
+		transactions, err = reopened.Load()
+		require.NoError(t, err)
+		require.Len(t, transactions, 2, "records appended after truncation should be readable")
+	})
+}
+
+func TestPendingTransactionJournal_RewriteReplacesContents(t *testing.T) {
+	withJournalDir(t, func(conf *journalConfig, harness *with.LoggingHarness) {
+		tx1 := builders.TransferTransaction().Build()
+		tx2 := builders.TransferTransaction().Build()
+
+		journal := openJournal(t, conf, harness)
+		require.NoError(t, journal.Add(&adapter.JournaledTransaction{Transaction: tx1}))
+		require.NoError(t, journal.Rewrite([]*adapter.JournaledTransaction{{Transaction: tx2}}))
+		require.Equal(t, 1, journal.RecordCount())
+		require.NoError(t, journal.Remove(digest.CalcTxHash(tx1.Transaction())))
+		journal.GracefulShutdown(context.Background())
+
+		reopened := openJournal(t, conf, harness)
+		defer reopened.GracefulShutdown(context.Background())
+		transactions, err := reopened.Load()
+		require.NoError(t, err)
+		require.Len(t, transactions, 1)
+		require.Equal(t, tx2.Raw(), transactions[0].Transaction.Raw())
+	})
+}
+
+func withJournalDir(t *testing.T, f func(conf *journalConfig, harness *with.LoggingHarness)) {
+	dir, err := ioutil.TempDir("", "pending-journal")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	with.Logging(t, func(harness *with.LoggingHarness) {
+		f(&journalConfig{dir: dir}, harness)
+	})
+}
+
+func openJournal(t *testing.T, conf *journalConfig, harness *with.LoggingHarness) *PendingTransactionJournal {
+	journal, err := NewPendingTransactionJournal(conf, harness.Logger)
+	require.NoError(t, err)
+	return journal
+}
@@ -0,0 +1,27 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package adapter
+
+import (
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+)
+
+type JournaledTransaction struct {
+	Transaction        *protocol.SignedTransaction
+	GatewayNodeAddress primitives.NodeAddress
+}
+
+// PendingTransactionJournal persists the pending pool so that accepted transactions survive a node restart.
+// Load returns the transactions that were added and not removed since the last Rewrite, oldest first.
+type PendingTransactionJournal interface {
+	Add(transaction *JournaledTransaction) error
+	Remove(txHash primitives.Sha256) error
+	Load() ([]*JournaledTransaction, error)
+	Rewrite(transactions []*JournaledTransaction) error
+	RecordCount() int
+}
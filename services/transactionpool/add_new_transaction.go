@@ -28,16 +28,45 @@ func (s *Service) AddNewTransaction(ctx context.Context, input *services.AddNewT
 
 	logger := s.logger.WithTags(logfields.Transaction(txHash), trace.LogFieldFrom(ctx), log.Stringable("transaction", input.SignedTransaction))
 
+	if isCancellation(input.SignedTransaction.Transaction()) {
+		return s.addCancellation(ctx, input.SignedTransaction)
+	}
+
+	if output, err := s.validateNewTransaction(ctx, input.SignedTransaction, logger); err != nil {
+		return output, err
+	}
+
+	// TK: this was originally in the body of this function but extracted to a function to make s.addCommitLock more fine grained
+	output, err := s.addToPendingPoolAfterCheckingCommitted(txHash, logger, func() *ErrTransactionRejected {
+		return s.addUnlessCancelled(ctx, input.SignedTransaction, s.config.NodeAddress())
+	})
+	if output != nil {
+		return output, err
+	}
+
+	logger.Info("adding new transaction to the pool", log.String("flow", "checkpoint"))
+
+	s.transactionForwarder.submit(input.SignedTransaction)
+
+	return s.addTransactionOutputFor(nil, protocol.TRANSACTION_STATUS_PENDING), nil
+}
+
+// the checks a new transaction passes before it is added to the pending pool
+func (s *Service) validateNewTransaction(ctx context.Context, transaction *protocol.SignedTransaction, logger log.Logger) (*services.AddNewTransactionOutput, error) {
 	currentTime := time.Now()
 	lastCommittedBlockHeight, lastCommittedBlockTimestamp := s.lastCommittedBlockHeightAndTime()
 
-	if err := s.validationContext.ValidateAddedTransaction(input.SignedTransaction, currentTime, lastCommittedBlockTimestamp); err != nil {
+	if err := s.validationContext.ValidateAddedTransaction(transaction, currentTime, lastCommittedBlockTimestamp); err != nil {
 		logger.Info("transaction is invalid", log.Error(err), logfields.BlockHeight(lastCommittedBlockHeight), logfields.TimestampNano("last-committed", lastCommittedBlockTimestamp))
 		return s.addTransactionOutputFor(nil, err.TransactionStatus), err
 	}
 
+	if err := s.admissionChain.admit(ctx, transaction, logger); err != nil {
+		return s.addTransactionOutputFor(nil, err.TransactionStatus), err
+	}
+
 	// TK: this was originally after the check in the committed pool but moved here to make s.addCommitLock more fine grained
-	if err := s.validateSingleTransactionForPreOrder(ctx, input.SignedTransaction); err != nil {
+	if err := s.validateSingleTransactionForPreOrder(ctx, transaction); err != nil {
 		status := protocol.TRANSACTION_STATUS_REJECTED_SMART_CONTRACT_PRE_ORDER // TODO(https://github.com/orbs-network/orbs-network-go/issues/1017): change to system error
 		if errRejected, ok := err.(*ErrTransactionRejected); ok {
 			status = errRejected.TransactionStatus
@@ -47,20 +76,10 @@ func (s *Service) AddNewTransaction(ctx context.Context, input *services.AddNewT
 		return s.addTransactionOutputFor(nil, status), err
 	}
 
-	// TK: this was originally in the body of this function but extracted to a function to make s.addCommitLock more fine grained
-	output, err := s.addToPendingPoolAfterCheckingCommitted(input.SignedTransaction, txHash, logger)
-	if output != nil {
-		return output, err
-	}
-
-	logger.Info("adding new transaction to the pool", log.String("flow", "checkpoint"))
-
-	s.transactionForwarder.submit(input.SignedTransaction)
-
-	return s.addTransactionOutputFor(nil, protocol.TRANSACTION_STATUS_PENDING), nil
+	return nil, nil
 }
 
-func (s *Service) addToPendingPoolAfterCheckingCommitted(tx *protocol.SignedTransaction, txHash primitives.Sha256, logger log.Logger) (*services.AddNewTransactionOutput, error) {
+func (s *Service) addToPendingPoolAfterCheckingCommitted(txHash primitives.Sha256, logger log.Logger, addToPendingPool func() *ErrTransactionRejected) (*services.AddNewTransactionOutput, error) {
 	// TODO(https://github.com/orbs-network/orbs-network-go/issues/1020): improve addCommitLock workaround
 	s.addCommitLock.RLock()
 	defer s.addCommitLock.RUnlock()
@@ -70,8 +89,16 @@ func (s *Service) addToPendingPoolAfterCheckingCommitted(tx *protocol.SignedTran
 		return s.addTransactionOutputFor(alreadyCommitted.receipt, protocol.TRANSACTION_STATUS_DUPLICATE_TRANSACTION_ALREADY_COMMITTED), nil
 	}
 
-	address := s.config.NodeAddress()
-	if _, err := s.pendingPool.add(tx, address); err != nil {
+	if committed, err := s.committedAtAnyAge(txHash); err != nil {
+		// a transaction which may have been committed is not added
+		logger.Error("error checking if transaction was committed before the committed pool window", log.Error(err))
+		return s.addTransactionOutputFor(nil, protocol.TRANSACTION_STATUS_REJECTED_SMART_CONTRACT_PRE_ORDER), err // TODO(https://github.com/orbs-network/orbs-network-go/issues/1017): change to system error
+	} else if committed {
+		logger.Info("transaction already committed before the committed pool window")
+		return s.addTransactionOutputFor(nil, protocol.TRANSACTION_STATUS_DUPLICATE_TRANSACTION_ALREADY_COMMITTED), nil
+	}
+
+	if err := addToPendingPool(); err != nil {
 		logger.Error("error adding transaction to pending pool", log.Error(err))
 		return s.addTransactionOutputFor(nil, err.TransactionStatus), err
 	}
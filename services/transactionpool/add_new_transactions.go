@@ -0,0 +1,118 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package transactionpool
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/instrumentation/logfields"
+	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/orbs-network/scribe/log"
+	"time"
+)
+
+type AddNewTransactionsInput struct {
+	SignedTransactions []*protocol.SignedTransaction
+}
+
+// Results are in the order of the input transactions
+type AddNewTransactionsOutput struct {
+	Results []*services.AddNewTransactionOutput
+}
+
+// AddNewTransactions is the batch form of AddNewTransaction: the whole batch takes a single concurrency slot, goes
+// through pre-order checks in one virtual machine call and is handed to the forwarder at once
+func (s *Service) AddNewTransactions(ctx context.Context, input *AddNewTransactionsInput) (*AddNewTransactionsOutput, error) {
+	s.addNewTransactionConcurrencyLimiter.RequestSlot()
+	defer s.addNewTransactionConcurrencyLimiter.ReleaseSlot()
+
+	logger := s.logger.WithTags(trace.LogFieldFrom(ctx))
+
+	currentTime := time.Now()
+	lastCommittedBlockHeight, lastCommittedBlockTimestamp := s.lastCommittedBlockHeightAndTime()
+
+	results := make([]*services.AddNewTransactionOutput, len(input.SignedTransactions))
+	txHashes := make([]primitives.Sha256, len(input.SignedTransactions))
+	var valid Transactions
+	var validIndexes []int
+	for i, tx := range input.SignedTransactions {
+		txHashes[i] = digest.CalcTxHash(tx.Transaction())
+		if isCancellation(tx.Transaction()) {
+			results[i], _ = s.addCancellation(ctx, tx)
+			continue
+		}
+		if err := s.validationContext.ValidateAddedTransaction(tx, currentTime, lastCommittedBlockTimestamp); err != nil {
+			logger.Info("transaction is invalid", log.Error(err), logfields.Transaction(txHashes[i]), logfields.BlockHeight(lastCommittedBlockHeight), logfields.TimestampNano("last-committed", lastCommittedBlockTimestamp))
+			results[i] = s.addTransactionOutputFor(nil, err.TransactionStatus)
+			continue
+		}
+		if err := s.admissionChain.admit(ctx, tx, logger.WithTags(logfields.Transaction(txHashes[i]))); err != nil {
+			results[i] = s.addTransactionOutputFor(nil, err.TransactionStatus)
+			continue
+		}
+		valid = append(valid, tx)
+		validIndexes = append(validIndexes, i)
+	}
+
+	preOrderStatuses := s.validateTransactionsForPreOrder(ctx, valid, logger)
+
+	var added Transactions
+	for j, i := range validIndexes {
+		tx, txLogger := input.SignedTransactions[i], logger.WithTags(logfields.Transaction(txHashes[i]))
+		if preOrderStatuses[j] != protocol.TRANSACTION_STATUS_PRE_ORDER_VALID {
+			txLogger.Info("transaction failed pre order checks", log.Stringable("tx-status", preOrderStatuses[j]))
+			results[i] = s.addTransactionOutputFor(nil, preOrderStatuses[j])
+			continue
+		}
+
+		output, _ := s.addToPendingPoolAfterCheckingCommitted(txHashes[i], txLogger, func() *ErrTransactionRejected {
+			return s.addUnlessCancelled(ctx, tx, s.config.NodeAddress())
+		})
+		if output != nil {
+			results[i] = output
+			continue
+		}
+
+		txLogger.Info("adding new transaction to the pool", log.String("flow", "checkpoint"))
+		results[i] = s.addTransactionOutputFor(nil, protocol.TRANSACTION_STATUS_PENDING)
+		added = append(added, tx)
+	}
+
+	if len(added) > 0 {
+		s.transactionForwarder.submit(added...)
+	}
+
+	return &AddNewTransactionsOutput{Results: results}, nil
+}
+
+// returns a pre-order status per transaction, all of them rejected if the checks could not run
+func (s *Service) validateTransactionsForPreOrder(ctx context.Context, transactions Transactions, logger log.Logger) []protocol.TransactionStatus {
+	statuses := make([]protocol.TransactionStatus, len(transactions))
+	if len(transactions) == 0 {
+		return statuses
+	}
+
+	lastCommittedBlockHeight, _ := s.lastCommittedBlockHeightAndTime()
+	output, err := s.virtualMachine.TransactionSetPreOrder(ctx, &services.TransactionSetPreOrderInput{
+		SignedTransactions:    transactions,
+		CurrentBlockHeight:    lastCommittedBlockHeight + 1,
+		CurrentBlockTimestamp: primitives.TimestampNano(time.Now().UnixNano()),
+	})
+	if err != nil || output == nil || len(output.PreOrderResults) != len(transactions) {
+		logger.Error("error validating transactions for preorder", log.Error(err), log.Int("num-transactions", len(transactions)))
+		for i := range statuses {
+			statuses[i] = protocol.TRANSACTION_STATUS_REJECTED_SMART_CONTRACT_PRE_ORDER
+		}
+		return statuses
+	}
+
+	copy(statuses, output.PreOrderResults)
+	return statuses
+}
@@ -0,0 +1,71 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package transactionpool
+
+import (
+	"context"
+	"github.com/orbs-network/go-mock"
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-network-go/test/crypto/keys"
+	"github.com/orbs-network/orbs-network-go/test/with"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAddNewTransactionsChecksTheBatchTogetherAndForwardsTheAddedTransactionsAtOnce(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(harness *with.LoggingHarness) {
+			vm := &services.MockVirtualMachine{}
+			s := &Service{
+				config:                              config.ForTransactionPoolTests(100000, keys.EcdsaSecp256K1KeyPairForTests(8), 0),
+				logger:                              harness.Logger,
+				virtualMachine:                      vm,
+				pendingPool:                         makePendingPool(),
+				committedPool:                       NewCommittedPool(func() time.Duration { return 3 * time.Minute }, metric.NewRegistry()),
+				cancelledTransactions:               newCancelledTransactions(func() time.Duration { return 3 * time.Minute }),
+				admissionChain:                      &admissionChain{metricFactory: metric.NewRegistry()},
+				transactionForwarder:                &transactionForwarder{forwardQueueMutex: &sync.Mutex{}, transactionAdded: make(chan uint16, 1)},
+				addNewTransactionConcurrencyLimiter: NewRequestConcurrencyLimiter(1),
+			}
+			s.validationContext = s.createValidationContext()
+			s.lastCommitted.timestamp = primitives.TimestampNano(time.Now().UnixNano())
+
+			valid := builders.TransferTransaction().Build()
+			wrongChain := builders.TransferTransaction().WithVirtualChainId(17).Build()
+			rejectedByContract := builders.TransferTransaction().Build()
+			vm.When("TransactionSetPreOrder", mock.Any, mock.Any).Return(&services.TransactionSetPreOrderOutput{
+				PreOrderResults: []protocol.TransactionStatus{protocol.TRANSACTION_STATUS_PRE_ORDER_VALID, protocol.TRANSACTION_STATUS_REJECTED_SMART_CONTRACT_PRE_ORDER},
+			}, nil).Times(1)
+
+			output, err := s.AddNewTransactions(ctx, &AddNewTransactionsInput{SignedTransactions: []*protocol.SignedTransaction{valid, wrongChain, rejectedByContract}})
+			require.NoError(t, err)
+
+			var statuses []protocol.TransactionStatus
+			for _, result := range output.Results {
+				statuses = append(statuses, result.TransactionStatus)
+			}
+			require.Equal(t, []protocol.TransactionStatus{
+				protocol.TRANSACTION_STATUS_PENDING,
+				protocol.TRANSACTION_STATUS_REJECTED_VIRTUAL_CHAIN_MISMATCH,
+				protocol.TRANSACTION_STATUS_REJECTED_SMART_CONTRACT_PRE_ORDER,
+			}, statuses)
+
+			ok, verifyErr := vm.Verify()
+			require.True(t, ok, "pre order checks should run once for the batch: %v", verifyErr)
+			require.True(t, s.pendingPool.has(valid))
+			require.False(t, s.pendingPool.has(rejectedByContract))
+			require.Equal(t, []*protocol.SignedTransaction{valid}, s.transactionForwarder.drainQueue(), "only the added transaction should be forwarded")
+		})
+	})
+}
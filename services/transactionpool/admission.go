@@ -0,0 +1,221 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package transactionpool
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/orbs-network/scribe/log"
+	"github.com/pkg/errors"
+	"strings"
+	"sync"
+)
+
+// An AdmissionFilter decides whether a transaction submitted to this node may enter the pending pool. Filters are a
+// local policy of the node: they run after the transaction is validated and before its pre-order checks, and are not
+// applied to transactions forwarded from other nodes or when validating blocks.
+type AdmissionFilter interface {
+	Name() string
+	Admit(ctx context.Context, transaction *protocol.SignedTransaction) *ErrTransactionRejected
+}
+
+type admissionConfig interface {
+	TransactionPoolAdmissionAllowedContracts() string
+	TransactionPoolAdmissionDeniedContracts() string
+	TransactionPoolAdmissionAllowedSigners() string
+	TransactionPoolAdmissionDeniedSigners() string
+	TransactionPoolAdmissionMaxArgumentsSize() uint32
+	TransactionPoolAdmissionPrecheckContracts() string
+	TransactionPoolAdmissionPrecheckMethod() string
+}
+
+type admissionChain struct {
+	sync.RWMutex
+	metricFactory metric.Factory
+	filters       []AdmissionFilter
+	rejected      []*metric.Gauge
+}
+
+func newAdmissionChain(config admissionConfig, virtualMachine services.VirtualMachine, metricFactory metric.Factory) (*admissionChain, error) {
+	chain := &admissionChain{metricFactory: metricFactory}
+
+	allowedContracts, deniedContracts := splitList(config.TransactionPoolAdmissionAllowedContracts()), splitList(config.TransactionPoolAdmissionDeniedContracts())
+	if len(allowedContracts) > 0 || len(deniedContracts) > 0 {
+		chain.add(&contractListFilter{allowed: setOf(allowedContracts), denied: setOf(deniedContracts)})
+	}
+
+	allowedSigners, err := clientAddressSetOf(config.TransactionPoolAdmissionAllowedSigners())
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid allowed signers")
+	}
+	deniedSigners, err := clientAddressSetOf(config.TransactionPoolAdmissionDeniedSigners())
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid denied signers")
+	}
+	if len(allowedSigners) > 0 || len(deniedSigners) > 0 {
+		chain.add(&signerListFilter{allowed: allowedSigners, denied: deniedSigners})
+	}
+
+	if maxSize := config.TransactionPoolAdmissionMaxArgumentsSize(); maxSize > 0 {
+		chain.add(&argumentsSizeFilter{maxSize: maxSize})
+	}
+
+	if contracts := splitList(config.TransactionPoolAdmissionPrecheckContracts()); len(contracts) > 0 {
+		method := config.TransactionPoolAdmissionPrecheckMethod()
+		if method == "" {
+			return nil, errors.New("precheck contracts are configured without a precheck method")
+		}
+		chain.add(&precheckFilter{virtualMachine: virtualMachine, contracts: setOf(contracts), method: primitives.MethodName(method)})
+	}
+
+	return chain, nil
+}
+
+func (c *admissionChain) add(filter AdmissionFilter) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.filters = append(c.filters, filter)
+	c.rejected = append(c.rejected, c.metricFactory.NewGauge(fmt.Sprintf("TransactionPool.Admission.%s.Rejected.Count", filter.Name())))
+}
+
+// returns the rejection of the first filter that does not admit the transaction
+func (c *admissionChain) admit(ctx context.Context, transaction *protocol.SignedTransaction, logger log.Logger) *ErrTransactionRejected {
+	c.RLock()
+	defer c.RUnlock()
+
+	for i, filter := range c.filters {
+		if err := filter.Admit(ctx, transaction); err != nil {
+			c.rejected[i].Inc()
+			logger.Info("transaction not admitted", log.Error(err), log.String("admission-filter", filter.Name()))
+			return err
+		}
+	}
+	return nil
+}
+
+// AddAdmissionFilter appends a filter to the ones built from configuration, it runs on transactions added from then on
+func (s *Service) AddAdmissionFilter(filter AdmissionFilter) {
+	s.admissionChain.add(filter)
+}
+
+type contractListFilter struct {
+	allowed map[string]bool // empty allows all contracts
+	denied  map[string]bool
+}
+
+func (f *contractListFilter) Name() string {
+	return "ContractList"
+}
+
+func (f *contractListFilter) Admit(ctx context.Context, transaction *protocol.SignedTransaction) *ErrTransactionRejected {
+	contractName := string(transaction.Transaction().ContractName())
+	if f.denied[contractName] || (len(f.allowed) > 0 && !f.allowed[contractName]) {
+		return &ErrTransactionRejected{TransactionStatus: protocol.TRANSACTION_STATUS_REJECTED_SMART_CONTRACT_PRE_ORDER, Actual: log.String("contract", contractName)}
+	}
+	return nil
+}
+
+type signerListFilter struct {
+	allowed map[string]bool // empty allows all signers
+	denied  map[string]bool
+}
+
+func (f *signerListFilter) Name() string {
+	return "SignerList"
+}
+
+func (f *signerListFilter) Admit(ctx context.Context, transaction *protocol.SignedTransaction) *ErrTransactionRejected {
+	address, err := digest.CalcClientAddressOfSigner(transaction.Transaction().Signer())
+	if err != nil || f.denied[string(address)] || (len(f.allowed) > 0 && !f.allowed[string(address)]) {
+		return &ErrTransactionRejected{TransactionStatus: protocol.TRANSACTION_STATUS_REJECTED_GLOBAL_PRE_ORDER, Actual: log.String("signer", signerAddressOf(transaction.Transaction().Signer()))}
+	}
+	return nil
+}
+
+type argumentsSizeFilter struct {
+	maxSize uint32
+}
+
+func (f *argumentsSizeFilter) Name() string {
+	return "ArgumentsSize"
+}
+
+// like the per-signer bytes quota this is a limit on what the node takes in rather than a rule of the virtual chain,
+// so it rejects with congestion
+func (f *argumentsSizeFilter) Admit(ctx context.Context, transaction *protocol.SignedTransaction) *ErrTransactionRejected {
+	if size := uint32(len(transaction.Transaction().RawInputArgumentArray())); size > f.maxSize {
+		return &ErrTransactionRejected{protocol.TRANSACTION_STATUS_REJECTED_CONGESTION, log.Uint32("max-arguments-size", f.maxSize), log.Uint32("arguments-size", size)}
+	}
+	return nil
+}
+
+// runs a read only method of the called contract, with the called method name and its packed arguments, against the
+// latest committed state; the transaction is admitted only if the query succeeds, which lets a contract cheaply turn
+// away calls that would fail anyway
+type precheckFilter struct {
+	virtualMachine services.VirtualMachine
+	contracts      map[string]bool
+	method         primitives.MethodName
+}
+
+func (f *precheckFilter) Name() string {
+	return "Precheck"
+}
+
+func (f *precheckFilter) Admit(ctx context.Context, transaction *protocol.SignedTransaction) *ErrTransactionRejected {
+	tx := transaction.Transaction()
+	if !f.contracts[string(tx.ContractName())] {
+		return nil
+	}
+
+	inputArgs, err := protocol.PackedInputArgumentsFromNatives([]interface{}{string(tx.MethodName()), tx.RawInputArgumentArray()})
+	if err != nil {
+		return &ErrTransactionRejected{TransactionStatus: protocol.TRANSACTION_STATUS_REJECTED_SMART_CONTRACT_PRE_ORDER}
+	}
+
+	output, err := f.virtualMachine.ProcessQuery(ctx, &services.ProcessQueryInput{
+		BlockHeight: 0, // latest committed
+		SignedQuery: (&protocol.SignedQueryBuilder{
+			Query: &protocol.QueryBuilder{
+				ProtocolVersion:    tx.ProtocolVersion(),
+				VirtualChainId:     tx.VirtualChainId(),
+				Timestamp:          tx.Timestamp(),
+				Signer:             protocol.SignerBuilderFromRaw(tx.Signer().Raw()),
+				ContractName:       tx.ContractName(),
+				MethodName:         f.method,
+				InputArgumentArray: inputArgs,
+			},
+		}).Build(),
+	})
+	if err != nil || output == nil || output.CallResult != protocol.EXECUTION_RESULT_SUCCESS {
+		result := protocol.EXECUTION_RESULT_RESERVED
+		if output != nil {
+			result = output.CallResult
+		}
+		return &ErrTransactionRejected{protocol.TRANSACTION_STATUS_REJECTED_SMART_CONTRACT_PRE_ORDER, log.Stringable("precheck-result", protocol.EXECUTION_RESULT_SUCCESS), log.Stringable("precheck-result", result)}
+	}
+	return nil
+}
+
+func clientAddressSetOf(list string) (map[string]bool, error) {
+	res := make(map[string]bool)
+	for _, item := range splitList(list) {
+		address, err := hex.DecodeString(strings.TrimPrefix(item, "0x"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid client address %s", item)
+		}
+		res[string(address)] = true
+	}
+	return res, nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package transactionpool
+
+import (
+	"context"
+	"github.com/orbs-network/go-mock"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-network-go/test/crypto/keys"
+	"github.com/orbs-network/orbs-network-go/test/with"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type admissionConfigForTests struct {
+	allowedContracts  string
+	deniedContracts   string
+	allowedSigners    string
+	deniedSigners     string
+	maxArgumentsSize  uint32
+	precheckContracts string
+}
+
+func (c *admissionConfigForTests) TransactionPoolAdmissionAllowedContracts() string {
+	return c.allowedContracts
+}
+
+func (c *admissionConfigForTests) TransactionPoolAdmissionDeniedContracts() string {
+	return c.deniedContracts
+}
+
+func (c *admissionConfigForTests) TransactionPoolAdmissionAllowedSigners() string {
+	return c.allowedSigners
+}
+
+func (c *admissionConfigForTests) TransactionPoolAdmissionDeniedSigners() string {
+	return c.deniedSigners
+}
+
+func (c *admissionConfigForTests) TransactionPoolAdmissionMaxArgumentsSize() uint32 {
+	return c.maxArgumentsSize
+}
+
+func (c *admissionConfigForTests) TransactionPoolAdmissionPrecheckContracts() string {
+	return c.precheckContracts
+}
+
+func (c *admissionConfigForTests) TransactionPoolAdmissionPrecheckMethod() string {
+	return "precheck"
+}
+
+type denyAllFilter struct{}
+
+func (f *denyAllFilter) Name() string {
+	return "DenyAll"
+}
+
+func (f *denyAllFilter) Admit(ctx context.Context, transaction *protocol.SignedTransaction) *ErrTransactionRejected {
+	return &ErrTransactionRejected{TransactionStatus: protocol.TRANSACTION_STATUS_REJECTED_CONGESTION}
+}
+
+func statusOfAdmission(err *ErrTransactionRejected) protocol.TransactionStatus {
+	if err == nil {
+		return protocol.TRANSACTION_STATUS_PENDING
+	}
+	return err.TransactionStatus
+}
+
+func TestAdmissionChain_FiltersByContractSignerAndArgumentsSize(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(harness *with.LoggingHarness) {
+			deniedSigner := keys.Ed25519KeyPairForTests(3)
+			deniedAddress, err := digest.CalcClientAddressOfEd25519PublicKey(deniedSigner.PublicKey())
+			require.NoError(t, err)
+
+			chain, err := newAdmissionChain(&admissionConfigForTests{
+				deniedContracts:  "Blocked",
+				deniedSigners:    "0x" + deniedAddress.String(),
+				maxArgumentsSize: 100,
+			}, nil, metric.NewRegistry())
+			require.NoError(t, err)
+			require.Len(t, chain.filters, 3)
+
+			admit := func(tx *protocol.SignedTransaction) protocol.TransactionStatus {
+				return statusOfAdmission(chain.admit(ctx, tx, harness.Logger))
+			}
+
+			require.Equal(t, protocol.TRANSACTION_STATUS_PENDING, admit(builders.TransferTransaction().Build()))
+			require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_SMART_CONTRACT_PRE_ORDER, admit(builders.Transaction().WithContract("Blocked").Build()))
+			require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_GLOBAL_PRE_ORDER, admit(builders.TransferTransaction().WithEd25519Signer(deniedSigner).Build()))
+			require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_CONGESTION, admit(builders.Transaction().WithArgs(make([]byte, 200)).Build()))
+
+			for _, rejected := range chain.rejected {
+				require.EqualValues(t, 1, rejected.Value(), "each filter should count its rejections")
+			}
+		})
+	})
+}
+
+func TestAdmissionChain_AllowListsRejectEverythingElse(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(harness *with.LoggingHarness) {
+			allowedSigner := keys.Ed25519KeyPairForTests(4)
+			allowedAddress, err := digest.CalcClientAddressOfEd25519PublicKey(allowedSigner.PublicKey())
+			require.NoError(t, err)
+
+			chain, err := newAdmissionChain(&admissionConfigForTests{
+				allowedContracts: "Allowed",
+				allowedSigners:   allowedAddress.String(),
+			}, nil, metric.NewRegistry())
+			require.NoError(t, err)
+
+			admit := func(tx *protocol.SignedTransaction) protocol.TransactionStatus {
+				return statusOfAdmission(chain.admit(ctx, tx, harness.Logger))
+			}
+
+			require.Equal(t, protocol.TRANSACTION_STATUS_PENDING, admit(builders.Transaction().WithContract("Allowed").WithEd25519Signer(allowedSigner).Build()))
+			require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_SMART_CONTRACT_PRE_ORDER, admit(builders.Transaction().WithContract("Other").WithEd25519Signer(allowedSigner).Build()))
+			require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_GLOBAL_PRE_ORDER, admit(builders.Transaction().WithContract("Allowed").Build()))
+		})
+	})
+}
+
+func TestAdmissionChain_PrecheckQueriesTheCalledContract(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(harness *with.LoggingHarness) {
+			vm := &services.MockVirtualMachine{}
+			chain, err := newAdmissionChain(&admissionConfigForTests{precheckContracts: "Checked"}, vm, metric.NewRegistry())
+			require.NoError(t, err)
+
+			vm.When("ProcessQuery", mock.Any, mock.AnyIf("a precheck query to the called contract", func(i interface{}) bool {
+				query := i.(*services.ProcessQueryInput).SignedQuery.Query()
+				return query.ContractName() == "Checked" && query.MethodName() == "precheck"
+			})).Return(&services.ProcessQueryOutput{CallResult: protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT}, nil).Times(1)
+
+			require.Equal(t, protocol.TRANSACTION_STATUS_PENDING, statusOfAdmission(chain.admit(ctx, builders.Transaction().WithContract("Unchecked").Build(), harness.Logger)))
+			require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_SMART_CONTRACT_PRE_ORDER, statusOfAdmission(chain.admit(ctx, builders.Transaction().WithContract("Checked").Build(), harness.Logger)))
+
+			ok, verifyErr := vm.Verify()
+			require.True(t, ok, "precheck should only query the configured contracts: %v", verifyErr)
+		})
+	})
+}
+
+func TestAdmissionChain_RunsCustomFilters(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(harness *with.LoggingHarness) {
+			chain, err := newAdmissionChain(&admissionConfigForTests{}, nil, metric.NewRegistry())
+			require.NoError(t, err)
+			require.Nil(t, chain.admit(ctx, builders.TransferTransaction().Build(), harness.Logger), "no filters should be configured by default")
+
+			s := &Service{admissionChain: chain}
+			s.AddAdmissionFilter(&denyAllFilter{})
+			require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_CONGESTION, statusOfAdmission(chain.admit(ctx, builders.TransferTransaction().Build(), harness.Logger)))
+		})
+	})
+}
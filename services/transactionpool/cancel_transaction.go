@@ -0,0 +1,312 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package transactionpool
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/crypto/hash"
+	"github.com/orbs-network/orbs-network-go/crypto/validators"
+	"github.com/orbs-network/orbs-network-go/instrumentation/logfields"
+	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/orbs-network/scribe/log"
+	"sync"
+	"time"
+)
+
+// A pending transaction is cancelled with a cancellation: a transaction signed by the same signer that calls
+// CANCELLATION_METHOD_NAME of CANCELLATION_CONTRACT_NAME with the hash of the pending transaction as its only argument.
+// Cancellations are handled by AddNewTransaction instead of being added to the pool, and are never executed. The node
+// that receives one removes the pending transaction and relays the cancellation to all nodes as a forwarded
+// transaction, which verify it on their own before removing it too. Every node keeps the hashes it saw cancelled for
+// the expiration window and rejects the transaction if it arrives later, e.g. when a relayed cancellation overtakes the
+// forwarded transaction.
+//
+// The protocol has no statuses for cancellations yet: an applied cancellation is answered with
+// TRANSACTION_STATUS_PRE_ORDER_VALID, and the cancelled transaction is rejected with
+// TRANSACTION_STATUS_REJECTED_GLOBAL_PRE_ORDER for those waiting for it and whoever adds it again.
+const (
+	CANCELLATION_CONTRACT_NAME = primitives.ContractName("_TransactionPool")
+	CANCELLATION_METHOD_NAME   = primitives.MethodName("cancelTransaction")
+)
+
+// hashes of cancelled transactions along with the signer of their cancellation, a cancellation of another signer does not
+// stop the transaction
+type cancelledTransactions struct {
+	sync.RWMutex
+	transactions map[string]primitives.TimestampNano // kept until the expiration window passes this timestamp
+
+	futureTimestampGraceTimeout func() time.Duration
+}
+
+func newCancelledTransactions(futureTimestampGraceTimeout func() time.Duration) *cancelledTransactions {
+	return &cancelledTransactions{
+		transactions:                make(map[string]primitives.TimestampNano),
+		futureTimestampGraceTimeout: futureTimestampGraceTimeout,
+	}
+}
+
+func cancelledTransactionKey(txHash primitives.Sha256, signer *protocol.Signer) string {
+	return txHash.KeyForMap() + string(signer.Raw())
+}
+
+// returns false if the transaction was already cancelled
+func (c *cancelledTransactions) add(txHash primitives.Sha256, signer *protocol.Signer, timestamp primitives.TimestampNano) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	key := cancelledTransactionKey(txHash, signer)
+	if _, exists := c.transactions[key]; exists {
+		return false
+	}
+	c.transactions[key] = timestamp
+	return true
+}
+
+func (c *cancelledTransactions) remove(txHash primitives.Sha256, signer *protocol.Signer) {
+	c.Lock()
+	defer c.Unlock()
+
+	delete(c.transactions, cancelledTransactionKey(txHash, signer))
+}
+
+func (c *cancelledTransactions) has(transaction *protocol.Transaction) bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	_, ok := c.transactions[cancelledTransactionKey(digest.CalcTxHash(transaction), transaction.Signer())]
+	return ok
+}
+
+func (c *cancelledTransactions) clearTransactionsOlderThan(ctx context.Context, timestamp primitives.TimestampNano) {
+	c.Lock()
+	defer c.Unlock()
+
+	futureTimestampGrace := primitives.TimestampNano(c.futureTimestampGraceTimeout().Nanoseconds())
+	for key, cancelledAt := range c.transactions {
+		if cancelledAt+futureTimestampGrace < timestamp {
+			delete(c.transactions, key)
+		}
+	}
+}
+
+func isCancellation(transaction *protocol.Transaction) bool {
+	return transaction.ContractName() == CANCELLATION_CONTRACT_NAME && transaction.MethodName() == CANCELLATION_METHOD_NAME
+}
+
+func (s *Service) addCancellation(ctx context.Context, cancellation *protocol.SignedTransaction) (*services.AddNewTransactionOutput, error) {
+	logger := s.logger.WithTags(trace.LogFieldFrom(ctx))
+
+	txHash, err := s.cancel(ctx, cancellation, time.Now())
+	if err != nil {
+		logger.Info("transaction cancellation rejected", log.Error(err), logfields.Transaction(txHash))
+		return s.addTransactionOutputFor(nil, err.TransactionStatus), err
+	}
+
+	logger.Info("cancelled transaction", log.String("flow", "checkpoint"), logfields.Transaction(txHash))
+	s.transactionForwarder.forwardCancellation(ctx, cancellation)
+
+	return s.addTransactionOutputFor(nil, protocol.TRANSACTION_STATUS_PRE_ORDER_VALID), nil
+}
+
+// ReplaceTransaction cancels a pending transaction and adds another transaction of the same signer in its place. The
+// replacement is validated first and swapped in the pending pool in one step, if it is rejected the cancellation is
+// not applied either and both outputs carry the rejection
+func (s *Service) ReplaceTransaction(ctx context.Context, cancellation *services.AddNewTransactionInput, replacement *services.AddNewTransactionInput) (*services.AddNewTransactionOutput, *services.AddNewTransactionOutput, error) {
+	s.addNewTransactionConcurrencyLimiter.RequestSlot()
+	defer s.addNewTransactionConcurrencyLimiter.ReleaseSlot()
+
+	replacementHash := digest.CalcTxHash(replacement.SignedTransaction.Transaction())
+	logger := s.logger.WithTags(logfields.Transaction(replacementHash), trace.LogFieldFrom(ctx), log.Stringable("transaction", replacement.SignedTransaction))
+	rejected := func(output *services.AddNewTransactionOutput, err error) (*services.AddNewTransactionOutput, *services.AddNewTransactionOutput, error) {
+		return output, output, err
+	}
+
+	signer := cancellation.SignedTransaction.Transaction().Signer()
+	if !signer.Equal(replacement.SignedTransaction.Transaction().Signer()) || isCancellation(replacement.SignedTransaction.Transaction()) {
+		err := &ErrTransactionRejected{TransactionStatus: protocol.TRANSACTION_STATUS_REJECTED_SIGNATURE_MISMATCH}
+		return rejected(s.addTransactionOutputFor(nil, err.TransactionStatus), err)
+	}
+
+	now := time.Now()
+	txHash, pending, err := s.validateCancellation(cancellation.SignedTransaction, now)
+	if err != nil {
+		logger.Info("transaction cancellation rejected", log.Error(err), logfields.Transaction(txHash))
+		return rejected(s.addTransactionOutputFor(nil, err.TransactionStatus), err)
+	}
+
+	if output, err := s.validateNewTransaction(ctx, replacement.SignedTransaction, logger); err != nil {
+		return rejected(output, err)
+	}
+
+	output, addErr := s.addToPendingPoolAfterCheckingCommitted(replacementHash, logger, func() *ErrTransactionRejected {
+		if s.cancelledTransactions.has(replacement.SignedTransaction.Transaction()) {
+			return &ErrTransactionRejected{TransactionStatus: protocol.TRANSACTION_STATUS_REJECTED_GLOBAL_PRE_ORDER}
+		}
+
+		recorded := s.recordCancellation(txHash, cancellation.SignedTransaction, pending)
+		if _, err := s.pendingPool.replace(ctx, txHash, protocol.TRANSACTION_STATUS_REJECTED_GLOBAL_PRE_ORDER, replacement.SignedTransaction, s.config.NodeAddress()); err != nil {
+			if recorded {
+				s.cancelledTransactions.remove(txHash, signer)
+			}
+			return err
+		}
+		return nil
+	})
+	if output != nil {
+		return rejected(output, addErr)
+	}
+
+	logger.Info("replaced pending transaction", log.String("flow", "checkpoint"), log.Stringable("cancelled", txHash))
+	s.metrics.cancelledCount.Inc()
+	s.transactionForwarder.removeFromQueue(txHash)
+	s.transactionForwarder.forwardCancellation(ctx, cancellation.SignedTransaction)
+	s.transactionForwarder.submit(replacement.SignedTransaction)
+
+	return s.addTransactionOutputFor(nil, protocol.TRANSACTION_STATUS_PRE_ORDER_VALID), s.addTransactionOutputFor(nil, protocol.TRANSACTION_STATUS_PENDING), nil
+}
+
+// a transaction cancelled by its signer is rejected, also if its cancellation arrives while it is added
+func (s *Service) addUnlessCancelled(ctx context.Context, transaction *protocol.SignedTransaction, gatewayNodeAddress primitives.NodeAddress) *ErrTransactionRejected {
+	if s.cancelledTransactions.has(transaction.Transaction()) {
+		return &ErrTransactionRejected{TransactionStatus: protocol.TRANSACTION_STATUS_REJECTED_GLOBAL_PRE_ORDER}
+	}
+
+	txHash, err := s.pendingPool.add(transaction, gatewayNodeAddress)
+	if err != nil {
+		return err
+	}
+
+	if s.cancelledTransactions.has(transaction.Transaction()) {
+		s.pendingPool.remove(ctx, txHash, protocol.TRANSACTION_STATUS_REJECTED_GLOBAL_PRE_ORDER)
+		return &ErrTransactionRejected{TransactionStatus: protocol.TRANSACTION_STATUS_REJECTED_GLOBAL_PRE_ORDER}
+	}
+	return nil
+}
+
+// returns the hash of the cancelled transaction, or of the one the cancellation refers to if it was rejected. A
+// transaction that is not pending on this node is cancelled as well, it may still arrive from another node
+func (s *Service) cancel(ctx context.Context, cancellation *protocol.SignedTransaction, now time.Time) (primitives.Sha256, *ErrTransactionRejected) {
+	txHash, pending, err := s.validateCancellation(cancellation, now)
+	if err != nil {
+		return txHash, err
+	}
+
+	s.recordCancellation(txHash, cancellation, pending)
+	s.transactionForwarder.removeFromQueue(txHash)
+	if s.pendingPool.remove(ctx, txHash, protocol.TRANSACTION_STATUS_REJECTED_GLOBAL_PRE_ORDER) != nil {
+		s.metrics.cancelledCount.Inc()
+	}
+	return txHash, nil
+}
+
+// returns the hash of the transaction the cancellation refers to and the transaction if it is pending
+func (s *Service) validateCancellation(cancellation *protocol.SignedTransaction, now time.Time) (primitives.Sha256, *protocol.SignedTransaction, *ErrTransactionRejected) {
+	if err := s.validationContext.ValidateTransactionForOrdering(cancellation, primitives.TimestampNano(now.UnixNano())); err != nil {
+		return nil, nil, err
+	}
+
+	txHash, err := cancelledTxHashOf(cancellation)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !s.verifyTransactionSignature(cancellation) {
+		return txHash, nil, &ErrTransactionRejected{TransactionStatus: protocol.TRANSACTION_STATUS_REJECTED_SIGNATURE_MISMATCH}
+	}
+
+	if s.committedPool.has(txHash) {
+		return txHash, nil, &ErrTransactionRejected{TransactionStatus: protocol.TRANSACTION_STATUS_DUPLICATE_TRANSACTION_ALREADY_COMMITTED}
+	}
+
+	pending := s.pendingPool.get(txHash)
+	if pending != nil && !pending.Transaction().Signer().Equal(cancellation.Transaction().Signer()) {
+		return txHash, nil, &ErrTransactionRejected{protocol.TRANSACTION_STATUS_REJECTED_SIGNATURE_MISMATCH, log.Stringable("signer", pending.Transaction().Signer()), log.Stringable("signer", cancellation.Transaction().Signer())}
+	}
+	return txHash, pending, nil
+}
+
+// the cancelled transaction is kept until neither it nor its cancellation can still be valid, returns false if it was
+// already cancelled
+func (s *Service) recordCancellation(txHash primitives.Sha256, cancellation *protocol.SignedTransaction, pending *protocol.SignedTransaction) bool {
+	timestamp := cancellation.Transaction().Timestamp()
+	if pending != nil && pending.Transaction().Timestamp() > timestamp {
+		timestamp = pending.Transaction().Timestamp()
+	}
+	return s.cancelledTransactions.add(txHash, cancellation.Transaction().Signer(), timestamp)
+}
+
+func cancelledTxHashOf(cancellation *protocol.SignedTransaction) (primitives.Sha256, *ErrTransactionRejected) {
+	tx := cancellation.Transaction()
+	if !isCancellation(tx) {
+		return nil, &ErrTransactionRejected{protocol.TRANSACTION_STATUS_REJECTED_GLOBAL_PRE_ORDER, log.String("method", string(CANCELLATION_CONTRACT_NAME)+"."+string(CANCELLATION_METHOD_NAME)), log.String("method", string(tx.ContractName())+"."+string(tx.MethodName()))}
+	}
+
+	args := protocol.ArgumentArrayReader(tx.RawInputArgumentArrayWithHeader()).ArgumentsIterator()
+	if !args.HasNext() {
+		return nil, &ErrTransactionRejected{TransactionStatus: protocol.TRANSACTION_STATUS_REJECTED_GLOBAL_PRE_ORDER}
+	}
+	arg := args.NextArguments()
+	if !arg.IsTypeBytesValue() || len(arg.BytesValue()) != hash.SHA256_HASH_SIZE_BYTES || args.HasNext() {
+		return nil, &ErrTransactionRejected{TransactionStatus: protocol.TRANSACTION_STATUS_REJECTED_GLOBAL_PRE_ORDER}
+	}
+	return primitives.Sha256(arg.BytesValue()), nil
+}
+
+// A TransactionSignatureVerifier is shared with the virtual machine, so a cancellation verified here is not verified again
+type TransactionSignatureVerifier interface {
+	VerifyTransactionSignature(signedTransaction *protocol.SignedTransaction) protocol.TransactionStatus
+}
+
+// SetTransactionSignatureVerifier replaces verifying the signatures of cancellations on their own
+func (s *Service) SetTransactionSignatureVerifier(verifier TransactionSignatureVerifier) {
+	s.signatureVerifier.Lock()
+	defer s.signatureVerifier.Unlock()
+
+	s.signatureVerifier.verifier = verifier
+}
+
+func (s *Service) verifyTransactionSignature(transaction *protocol.SignedTransaction) bool {
+	s.signatureVerifier.RLock()
+	verifier := s.signatureVerifier.verifier
+	s.signatureVerifier.RUnlock()
+
+	if verifier == nil {
+		return validators.VerifyTransactionSignature(transaction) == protocol.TRANSACTION_STATUS_PRE_ORDER_VALID
+	}
+	return verifier.VerifyTransactionSignature(transaction) == protocol.TRANSACTION_STATUS_PRE_ORDER_VALID
+}
+
+func (f *transactionForwarder) removeFromQueue(txHash primitives.Sha256) {
+	f.forwardQueueMutex.Lock()
+	defer f.forwardQueueMutex.Unlock()
+
+	for i, tx := range f.forwardQueue {
+		if digest.CalcTxHash(tx.Transaction()).Equal(txHash) {
+			f.forwardQueue = append(f.forwardQueue[:i:i], f.forwardQueue[i+1:]...)
+			return
+		}
+	}
+}
+
+// cancellations are rare and should reach every node that may hold the transaction, so they are broadcast right away
+func (f *transactionForwarder) forwardCancellation(ctx context.Context, cancellation *protocol.SignedTransaction) {
+	logger := f.logger.WithTags(trace.LogFieldFrom(ctx))
+
+	input, _, err := f.signedForwardedTransactions(ctx, []*protocol.SignedTransaction{cancellation})
+	if err != nil {
+		logger.Error("error signing cancellation", log.Error(err))
+		return
+	}
+
+	if _, err := f.gossip.BroadcastForwardedTransactions(ctx, input); err != nil {
+		logger.Info("failed relaying transaction cancellation", log.Error(err))
+	}
+}
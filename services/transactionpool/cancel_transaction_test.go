@@ -0,0 +1,290 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package transactionpool
+
+import (
+	"context"
+	"github.com/orbs-network/go-mock"
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/crypto/signer"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-network-go/test/crypto/keys"
+	"github.com/orbs-network/orbs-network-go/test/with"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/orbs-network/orbs-spec/types/go/services/gossiptopics"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+type cancellationRelayForTests struct {
+	gossiptopics.MockTransactionRelay
+	relayed []*gossiptopics.ForwardedTransactionsInput
+}
+
+func (r *cancellationRelayForTests) BroadcastForwardedTransactions(ctx context.Context, input *gossiptopics.ForwardedTransactionsInput) (*gossiptopics.EmptyOutput, error) {
+	r.relayed = append(r.relayed, input)
+	return nil, nil
+}
+
+type removedTransaction struct {
+	txHash primitives.Sha256
+	reason protocol.TransactionStatus
+}
+
+func serviceForCancellationTests(t *testing.T, harness *with.LoggingHarness, relay gossiptopics.TransactionRelay, removed *[]removedTransaction) *Service {
+	keyPair := keys.EcdsaSecp256K1KeyPairForTests(8)
+	nodeSigner, err := signer.New(&signerConfig{keyPair})
+	require.NoError(t, err)
+
+	cfg := config.ForTransactionPoolTests(100000, keyPair, 0)
+	s := &Service{
+		config:                cfg,
+		logger:                harness.Logger,
+		pendingPool:           makePendingPool(),
+		committedPool:         NewCommittedPool(func() time.Duration { return 3 * time.Minute }, metric.NewRegistry()),
+		cancelledTransactions: newCancelledTransactions(func() time.Duration { return 3 * time.Minute }),
+		admissionChain:        &admissionChain{metricFactory: metric.NewRegistry()},
+		transactionForwarder: &transactionForwarder{
+			logger:            harness.Logger,
+			config:            cfg,
+			gossip:            relay,
+			signer:            nodeSigner,
+			forwardQueueMutex: &sync.Mutex{},
+			transactionAdded:  make(chan uint16, 1),
+		},
+		addNewTransactionConcurrencyLimiter: NewRequestConcurrencyLimiter(1),
+	}
+	s.metrics.cancelledCount = metric.NewRegistry().NewGauge("TransactionPool.CancelledTransactions.Count")
+	s.pendingPool.onTransactionRemoved = func(ctx context.Context, txHash primitives.Sha256, reason protocol.TransactionStatus) {
+		*removed = append(*removed, removedTransaction{txHash, reason})
+	}
+	s.validationContext = s.createValidationContext()
+	s.lastCommitted.timestamp = primitives.TimestampNano(time.Now().UnixNano())
+	return s
+}
+
+func cancellationOf(tx *protocol.SignedTransaction, signerSetIndex int) *protocol.SignedTransaction {
+	return builders.Transaction().
+		WithEd25519Signer(keys.Ed25519KeyPairForTests(signerSetIndex)).
+		WithMethod(CANCELLATION_CONTRACT_NAME, CANCELLATION_METHOD_NAME).
+		WithArgs([]byte(digest.CalcTxHash(tx.Transaction()))).
+		Build()
+}
+
+func cancel(ctx context.Context, s *Service, cancellation *protocol.SignedTransaction) (*services.AddNewTransactionOutput, error) {
+	return s.AddNewTransaction(ctx, &services.AddNewTransactionInput{SignedTransaction: cancellation})
+}
+
+func TestCancelTransaction_RemovesThePendingTransactionAndRelaysTheCancellation(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(harness *with.LoggingHarness) {
+			var removed []removedTransaction
+			relay := &cancellationRelayForTests{}
+			s := serviceForCancellationTests(t, harness, relay, &removed)
+
+			tx := builders.TransferTransaction().WithEd25519Signer(keys.Ed25519KeyPairForTests(1)).Build()
+			txHash := digest.CalcTxHash(tx.Transaction())
+			add(s.pendingPool, tx)
+			s.transactionForwarder.appendToQueue(Transactions{tx})
+
+			output, err := cancel(ctx, s, cancellationOf(tx, 1))
+			require.NoError(t, err)
+			require.Equal(t, protocol.TRANSACTION_STATUS_PRE_ORDER_VALID, output.TransactionStatus)
+
+			require.False(t, s.pendingPool.has(tx), "cancelled transaction should be removed from the pending pool")
+			require.Empty(t, s.transactionForwarder.drainQueue(), "cancelled transaction should not be forwarded")
+			require.Equal(t, []removedTransaction{{txHash, protocol.TRANSACTION_STATUS_REJECTED_GLOBAL_PRE_ORDER}}, removed, "those waiting for the transaction should be notified")
+			require.Len(t, relay.relayed, 1, "cancellation should be relayed to the other nodes")
+			require.EqualValues(t, 1, s.metrics.cancelledCount.Value())
+
+			err = s.addUnlessCancelled(ctx, tx, s.config.NodeAddress())
+			require.Error(t, err, "a cancelled transaction should not be added again")
+			require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_GLOBAL_PRE_ORDER, err.(*ErrTransactionRejected).TransactionStatus)
+		})
+	})
+}
+
+func TestCancelTransaction_RejectsCancellationsOfAnotherSigner(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(harness *with.LoggingHarness) {
+			var removed []removedTransaction
+			relay := &cancellationRelayForTests{}
+			s := serviceForCancellationTests(t, harness, relay, &removed)
+
+			tx := builders.TransferTransaction().WithEd25519Signer(keys.Ed25519KeyPairForTests(1)).Build()
+			add(s.pendingPool, tx)
+
+			output, err := cancel(ctx, s, cancellationOf(tx, 2))
+			require.Error(t, err)
+			require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_SIGNATURE_MISMATCH, output.TransactionStatus)
+
+			forged := builders.Transaction().
+				WithInvalidEd25519Signer(keys.Ed25519KeyPairForTests(1)).
+				WithMethod(CANCELLATION_CONTRACT_NAME, CANCELLATION_METHOD_NAME).
+				WithArgs([]byte(digest.CalcTxHash(tx.Transaction()))).
+				Build()
+			output, err = cancel(ctx, s, forged)
+			require.Error(t, err)
+			require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_SIGNATURE_MISMATCH, output.TransactionStatus, "the cancellation must be signed by the signer")
+
+			_, err = s.cancel(ctx, builders.TransferTransaction().WithEd25519Signer(keys.Ed25519KeyPairForTests(1)).Build(), time.Now())
+			require.Error(t, err, "only calls to the cancellation method are cancellations")
+
+			require.True(t, s.pendingPool.has(tx))
+			require.Empty(t, removed)
+			require.Empty(t, relay.relayed)
+		})
+	})
+}
+
+func TestCancelTransaction_CancellationOfAnotherSignerDoesNotStopTheTransactionFromArriving(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(harness *with.LoggingHarness) {
+			var removed []removedTransaction
+			s := serviceForCancellationTests(t, harness, &cancellationRelayForTests{}, &removed)
+
+			tx := builders.TransferTransaction().WithEd25519Signer(keys.Ed25519KeyPairForTests(1)).Build()
+			_, err := cancel(ctx, s, cancellationOf(tx, 2))
+			require.NoError(t, err, "the signer of a transaction that is not pending cannot be checked")
+
+			require.Nil(t, s.addUnlessCancelled(ctx, tx, s.config.NodeAddress()))
+			require.True(t, s.pendingPool.has(tx))
+		})
+	})
+}
+
+func TestHandleForwardedTransactions_CancelsTransactionsCancelledOnOtherNodes(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(harness *with.LoggingHarness) {
+			var removedOnSender, removedOnReceiver, removedOnLateReceiver []removedTransaction
+			relay := &cancellationRelayForTests{}
+			sender := serviceForCancellationTests(t, harness, relay, &removedOnSender)
+			receiver := serviceForCancellationTests(t, harness, &cancellationRelayForTests{}, &removedOnReceiver)
+			lateReceiver := serviceForCancellationTests(t, harness, &cancellationRelayForTests{}, &removedOnLateReceiver)
+			harness.AllowErrorsMatching("error adding forwarded transaction to pending pool")
+
+			tx := builders.TransferTransaction().WithEd25519Signer(keys.Ed25519KeyPairForTests(1)).Build()
+			add(sender.pendingPool, tx)
+			add(receiver.pendingPool, tx)
+
+			_, err := cancel(ctx, sender, cancellationOf(tx, 1))
+			require.NoError(t, err)
+			require.Len(t, relay.relayed, 1)
+
+			_, err = receiver.HandleForwardedTransactions(ctx, relay.relayed[0])
+			require.NoError(t, err)
+			require.False(t, receiver.pendingPool.has(tx), "relayed cancellation should remove the transaction")
+			require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_GLOBAL_PRE_ORDER, removedOnReceiver[0].reason)
+
+			_, err = lateReceiver.HandleForwardedTransactions(ctx, relay.relayed[0])
+			require.NoError(t, err)
+			forwarded, _, err := sender.transactionForwarder.signedForwardedTransactions(ctx, Transactions{tx})
+			require.NoError(t, err)
+			_, err = lateReceiver.HandleForwardedTransactions(ctx, forwarded)
+			require.NoError(t, err)
+			require.False(t, lateReceiver.pendingPool.has(tx), "a transaction forwarded after its cancellation should not be added")
+		})
+	})
+}
+
+func TestReplaceTransaction_AddsTheReplacementInPlaceOfTheCancelledTransaction(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(harness *with.LoggingHarness) {
+			var removed []removedTransaction
+			relay := &cancellationRelayForTests{}
+			s := serviceForCancellationTests(t, harness, relay, &removed)
+			vm := &services.MockVirtualMachine{}
+			vm.When("TransactionSetPreOrder", mock.Any, mock.Any).Return(&services.TransactionSetPreOrderOutput{
+				PreOrderResults: []protocol.TransactionStatus{protocol.TRANSACTION_STATUS_PRE_ORDER_VALID},
+			}, nil)
+			s.virtualMachine = vm
+
+			tx := builders.TransferTransaction().WithEd25519Signer(keys.Ed25519KeyPairForTests(1)).Build()
+			add(s.pendingPool, tx)
+
+			replacementOfOtherSigner := builders.TransferTransaction().WithEd25519Signer(keys.Ed25519KeyPairForTests(2)).Build()
+			_, _, err := s.ReplaceTransaction(ctx, &services.AddNewTransactionInput{SignedTransaction: cancellationOf(tx, 1)}, &services.AddNewTransactionInput{SignedTransaction: replacementOfOtherSigner})
+			require.Error(t, err, "a transaction may only be replaced by one of the same signer")
+			require.True(t, s.pendingPool.has(tx))
+
+			replacement := builders.TransferTransaction().WithEd25519Signer(keys.Ed25519KeyPairForTests(1)).WithAmountAndTargetAddress(20, builders.ClientAddressForEd25519SignerForTests(3)).Build()
+			cancelled, replaced, err := s.ReplaceTransaction(ctx, &services.AddNewTransactionInput{SignedTransaction: cancellationOf(tx, 1)}, &services.AddNewTransactionInput{SignedTransaction: replacement})
+			require.NoError(t, err)
+			require.Equal(t, protocol.TRANSACTION_STATUS_PRE_ORDER_VALID, cancelled.TransactionStatus)
+			require.Equal(t, protocol.TRANSACTION_STATUS_PENDING, replaced.TransactionStatus)
+			require.False(t, s.pendingPool.has(tx))
+			require.True(t, s.pendingPool.has(replacement))
+			require.Equal(t, []removedTransaction{{digest.CalcTxHash(tx.Transaction()), protocol.TRANSACTION_STATUS_REJECTED_GLOBAL_PRE_ORDER}}, removed)
+			require.Len(t, relay.relayed, 1, "cancellation should be relayed to the other nodes")
+			require.Error(t, s.addUnlessCancelled(ctx, tx, s.config.NodeAddress()), "a replaced transaction should not be added again")
+		})
+	})
+}
+
+func TestReplaceTransaction_KeepsThePendingTransactionWhenTheReplacementIsRejected(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(harness *with.LoggingHarness) {
+			var removed []removedTransaction
+			relay := &cancellationRelayForTests{}
+			s := serviceForCancellationTests(t, harness, relay, &removed)
+			harness.AllowErrorsMatching("error validating transaction for preorder")
+			vm := &services.MockVirtualMachine{}
+			vm.When("TransactionSetPreOrder", mock.Any, mock.Any).Return(&services.TransactionSetPreOrderOutput{
+				PreOrderResults: []protocol.TransactionStatus{protocol.TRANSACTION_STATUS_REJECTED_SMART_CONTRACT_PRE_ORDER},
+			}, nil)
+			s.virtualMachine = vm
+
+			tx := builders.TransferTransaction().WithEd25519Signer(keys.Ed25519KeyPairForTests(1)).Build()
+			add(s.pendingPool, tx)
+
+			replacement := builders.TransferTransaction().WithEd25519Signer(keys.Ed25519KeyPairForTests(1)).WithAmountAndTargetAddress(20, builders.ClientAddressForEd25519SignerForTests(3)).Build()
+			cancelled, replaced, err := s.ReplaceTransaction(ctx, &services.AddNewTransactionInput{SignedTransaction: cancellationOf(tx, 1)}, &services.AddNewTransactionInput{SignedTransaction: replacement})
+			require.Error(t, err)
+			require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_SMART_CONTRACT_PRE_ORDER, cancelled.TransactionStatus)
+			require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_SMART_CONTRACT_PRE_ORDER, replaced.TransactionStatus)
+
+			require.True(t, s.pendingPool.has(tx), "the transaction should not be cancelled when its replacement is rejected")
+			require.False(t, s.pendingPool.has(replacement))
+			require.Empty(t, removed)
+			require.Empty(t, relay.relayed)
+			require.False(t, s.cancelledTransactions.has(tx.Transaction()))
+		})
+	})
+}
+
+func TestPendingPoolReplace_AddsTheRemovedTransactionBackWhenTheReplacementIsRejected(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		p := makePendingPoolWithSignerSequencing()
+		var removed []removedTransaction
+		p.onTransactionRemoved = func(ctx context.Context, txHash primitives.Sha256, reason protocol.TransactionStatus) {
+			removed = append(removed, removedTransaction{txHash, reason})
+		}
+
+		tx := transactionOfSignerWithNonce(1, 1)
+		add(p, tx)
+		txHash := digest.CalcTxHash(tx.Transaction())
+
+		misplacedNonce := builders.TransferTransaction().WithEd25519Signer(keys.Ed25519KeyPairForTests(1)).WithArgs(uint64(10), digest.SignerNonceArgument(1)).Build()
+		_, err := p.replace(ctx, txHash, protocol.TRANSACTION_STATUS_REJECTED_GLOBAL_PRE_ORDER, misplacedNonce, nodeAddress)
+		require.NotNil(t, err, "a replacement with a misplaced signer nonce should be rejected")
+		require.True(t, p.has(tx))
+		require.Empty(t, removed, "those waiting for the transaction should not be notified")
+
+		replacement := builders.TransferTransaction().WithEd25519Signer(keys.Ed25519KeyPairForTests(1)).WithAmountAndTargetAddress(7, builders.ClientAddressForEd25519SignerForTests(2)).WithSignerNonce(1).Build()
+		_, err = p.replace(ctx, txHash, protocol.TRANSACTION_STATUS_REJECTED_GLOBAL_PRE_ORDER, replacement, nodeAddress)
+		require.Nil(t, err, "the replacement takes the nonce of the transaction it replaces")
+		require.False(t, p.has(tx))
+		require.True(t, p.has(replacement))
+		require.Equal(t, []removedTransaction{{txHash, protocol.TRANSACTION_STATUS_REJECTED_GLOBAL_PRE_ORDER}}, removed)
+	})
+}
@@ -37,6 +37,7 @@ func (s *Service) CommitTransactionReceipts(ctx context.Context, input *services
 	c := &committer{logger: logger, adder: s.committedPool, remover: s.pendingPool, nodeAddress: s.config.NodeAddress(), blockHeight: newBh, blockTime: ts}
 
 	c.commit(ctx, input.TransactionReceipts...)
+	s.indexCommittedTransactions(newBh, input.TransactionReceipts, logger)
 
 	s.blockTracker.IncrementTo(newBh)
 	s.blockHeightReporter.IncrementTo(newBh)
@@ -0,0 +1,48 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package transactionpool
+
+import (
+	"github.com/orbs-network/orbs-network-go/instrumentation/logfields"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/scribe/log"
+	"github.com/pkg/errors"
+)
+
+// the committed pool only holds transactions committed within the expiration window, the committed index (if there is
+// one) holds all of them. The index is optional per node, so it is only consulted when admitting new transactions and
+// never when validating blocks, which must not depend on it.
+func (s *Service) committedAtAnyAge(txHash primitives.Sha256) (bool, error) {
+	if s.committedIndex == nil {
+		return false, nil
+	}
+
+	committed, err := s.committedIndex.Contains(txHash)
+	if err != nil {
+		return false, errors.Wrap(err, "failed looking up committed transactions index")
+	}
+	if committed {
+		s.metrics.indexedDuplicates.Inc()
+	}
+	return committed, nil
+}
+
+// must be called in block height order
+func (s *Service) indexCommittedTransactions(blockHeight primitives.BlockHeight, receipts []*protocol.TransactionReceipt, logger log.Logger) {
+	if s.committedIndex == nil || blockHeight <= s.committedIndex.LastBlockHeight() {
+		return
+	}
+
+	txHashes := make([]primitives.Sha256, len(receipts))
+	for i, receipt := range receipts {
+		txHashes[i] = receipt.Txhash()
+	}
+	if err := s.committedIndex.Add(blockHeight, txHashes); err != nil {
+		logger.Error("failed adding committed transactions to index", log.Error(err), logfields.BlockHeight(blockHeight))
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package transactionpool
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-network-go/test/with"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type committedIndexForTests struct {
+	lastBlockHeight primitives.BlockHeight
+	txHashes        map[string]bool
+	err             error
+}
+
+func (i *committedIndexForTests) Add(blockHeight primitives.BlockHeight, txHashes []primitives.Sha256) error {
+	for _, txHash := range txHashes {
+		i.txHashes[txHash.KeyForMap()] = true
+	}
+	i.lastBlockHeight = blockHeight
+	return nil
+}
+
+func (i *committedIndexForTests) Contains(txHash primitives.Sha256) (bool, error) {
+	return i.txHashes[txHash.KeyForMap()], i.err
+}
+
+func (i *committedIndexForTests) LastBlockHeight() primitives.BlockHeight {
+	return i.lastBlockHeight
+}
+
+func TestCommittedIndex_RejectsTransactionsCommittedBeforeTheCommittedPoolWindow(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(harness *with.LoggingHarness) {
+			var removed []removedTransaction
+			s := serviceForCancellationTests(t, harness, &cancellationRelayForTests{}, &removed)
+			index := &committedIndexForTests{txHashes: make(map[string]bool)}
+			s.committedIndex = index
+			s.metrics.indexedDuplicates = metric.NewRegistry().NewGauge("TransactionPool.CommittedIndex.RejectedDuplicates.Count")
+
+			committedLongAgo := builders.TransferTransaction().Build()
+			receipt := builders.TransactionReceipt().WithTransaction(committedLongAgo.Transaction()).Build()
+			s.indexCommittedTransactions(5, []*protocol.TransactionReceipt{receipt}, harness.Logger)
+			s.indexCommittedTransactions(5, []*protocol.TransactionReceipt{builders.TransactionReceipt().Build()}, harness.Logger)
+			require.Len(t, index.txHashes, 1, "an already indexed block should not be indexed again")
+
+			output, err := s.addToPendingPoolAfterCheckingCommitted(digest.CalcTxHash(committedLongAgo.Transaction()), harness.Logger, func() *ErrTransactionRejected {
+				return s.addUnlessCancelled(ctx, committedLongAgo, s.config.NodeAddress())
+			})
+			require.NoError(t, err)
+			require.Equal(t, protocol.TRANSACTION_STATUS_DUPLICATE_TRANSACTION_ALREADY_COMMITTED, output.TransactionStatus)
+			require.False(t, s.pendingPool.has(committedLongAgo))
+			require.EqualValues(t, 1, s.metrics.indexedDuplicates.Value())
+
+			notCommitted := builders.TransferTransaction().WithAmountAndTargetAddress(7, builders.ClientAddressForEd25519SignerForTests(3)).Build()
+			output, err = s.addToPendingPoolAfterCheckingCommitted(digest.CalcTxHash(notCommitted.Transaction()), harness.Logger, func() *ErrTransactionRejected {
+				return s.addUnlessCancelled(ctx, notCommitted, s.config.NodeAddress())
+			})
+			require.NoError(t, err)
+			require.Nil(t, output, "a transaction that was not committed should be added")
+			require.True(t, s.pendingPool.has(notCommitted))
+		})
+	})
+}
+
+func TestCommittedIndex_DoesNotAddTransactionsWhenTheIndexCannotBeRead(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(harness *with.LoggingHarness) {
+			harness.AllowErrorsMatching("error checking if transaction was committed")
+			var removed []removedTransaction
+			s := serviceForCancellationTests(t, harness, &cancellationRelayForTests{}, &removed)
+			s.committedIndex = &committedIndexForTests{txHashes: make(map[string]bool), err: errors.New("disk failure")}
+
+			tx := builders.TransferTransaction().Build()
+			output, err := s.addToPendingPoolAfterCheckingCommitted(digest.CalcTxHash(tx.Transaction()), harness.Logger, func() *ErrTransactionRejected {
+				return s.addUnlessCancelled(ctx, tx, s.config.NodeAddress())
+			})
+			require.Error(t, err)
+			require.NotEqual(t, protocol.TRANSACTION_STATUS_PENDING, output.TransactionStatus)
+			require.False(t, s.pendingPool.has(tx), "a transaction which may have been committed should not be added")
+		})
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/orbs-network/orbs-network-go/crypto/digest"
 	"github.com/orbs-network/orbs-network-go/crypto/signer"
 	"github.com/orbs-network/orbs-network-go/instrumentation/logfields"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
 	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
 	"github.com/orbs-network/orbs-network-go/synchronization"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
@@ -30,6 +31,7 @@ type TransactionForwarderConfig interface {
 	NodeAddress() primitives.NodeAddress
 	TransactionPoolPropagationBatchSize() uint16
 	TransactionPoolPropagationBatchingTimeout() time.Duration
+	leaderAwareForwardingConfig
 }
 
 func (s *Service) RegisterTransactionResultsHandler(handler handlers.TransactionResultsHandler) {
@@ -51,10 +53,20 @@ func (s *Service) HandleForwardedTransactions(ctx context.Context, input *gossip
 		return nil, errors.Wrapf(err, "invalid signature in relay message from sender %s", sender.SenderNodeAddress())
 	}
 
+	now := time.Now()
 	for _, tx := range input.Message.SignedTransactions {
 		txHash := digest.CalcTxHash(tx.Transaction())
+		if isCancellation(tx.Transaction()) {
+			if cancelledTxHash, err := s.cancel(ctx, tx, now); err != nil {
+				logger.Info("relayed transaction cancellation rejected", log.Error(err), logfields.Transaction(cancelledTxHash), log.Stringable("sender", sender.SenderNodeAddress()))
+			} else {
+				logger.Info("cancelled transaction by relayed cancellation", log.String("flow", "checkpoint"), logfields.Transaction(cancelledTxHash))
+			}
+			continue
+		}
+
 		logger.Info("adding forwarded transaction to the pool", log.String("flow", "checkpoint"), logfields.Transaction(txHash))
-		if _, err := s.pendingPool.add(tx, sender.SenderNodeAddress()); err != nil {
+		if err := s.addUnlessCancelled(ctx, tx, sender.SenderNodeAddress()); err != nil {
 			logger.Error("error adding forwarded transaction to pending pool", log.Error(err), log.Stringable("transaction", tx), logfields.Transaction(txHash))
 		}
 	}
@@ -71,9 +83,18 @@ type transactionForwarder struct {
 	forwardQueueMutex *sync.Mutex
 	forwardQueue      []*protocol.SignedTransaction
 	transactionAdded  chan uint16
+
+	leaders struct {
+		sync.RWMutex
+		provider               LeaderProvider
+		ownPendingTransactions func() Transactions
+		lastHeight             primitives.BlockHeight     // only accessed by the forwarding goroutine
+		forwardedTo            map[string]map[string]bool // only accessed by the forwarding goroutine
+	}
+	metrics *forwardingMetrics
 }
 
-func NewTransactionForwarder(ctx context.Context, logger log.Logger, signer signer.Signer, config TransactionForwarderConfig, gossip gossiptopics.TransactionRelay) *transactionForwarder {
+func NewTransactionForwarder(ctx context.Context, logger log.Logger, signer signer.Signer, config TransactionForwarderConfig, gossip gossiptopics.TransactionRelay, metricFactory metric.Factory) *transactionForwarder {
 	f := &transactionForwarder{
 		logger:            logger.WithTags(log.String("component", "transaction-forwarder")),
 		config:            config,
@@ -81,6 +102,7 @@ func NewTransactionForwarder(ctx context.Context, logger log.Logger, signer sign
 		signer:            signer,
 		forwardQueueMutex: &sync.Mutex{},
 		transactionAdded:  make(chan uint16, 1), // buffered channel because we don't always have a receiver to read
+		metrics:           newForwardingMetrics(metricFactory),
 	}
 
 	f.start(ctx)
@@ -123,26 +145,53 @@ func (f *transactionForwarder) start(parent context.Context) {
 
 func (f *transactionForwarder) drainQueueAndForward(ctx context.Context) {
 	logger := f.logger.WithTags(trace.LogFieldFrom(ctx))
-	txs := f.drainQueue()
-	if len(txs) == 0 {
-		return
+	targets, newHeight := f.upcomingLeaderTargets(ctx)
+	targeted, isTargeted := f.gossip.(targetedTransactionRelay)
+
+	if txs := f.drainQueue(); len(txs) > 0 {
+		input, hashes, err := f.signedForwardedTransactions(ctx, txs)
+		if err != nil {
+			logger.Error("error signing transactions", log.Error(err), log.StringableSlice("transactions", txs))
+			f.submit(txs...)
+			return
+		}
+
+		if isTargeted && targets != nil {
+			err = f.sendToUpcomingLeaders(ctx, targeted, targets.recipients(), targets.committeeSize, input)
+			if err == nil {
+				f.recordForwarded(hashes, targets.recipients())
+			}
+		} else {
+			f.metrics.broadcastBatches.Inc()
+			_, err = f.gossip.BroadcastForwardedTransactions(ctx, input)
+		}
+
+		for _, hash := range hashes {
+			if err != nil {
+				logger.Info("failed forwarding transaction via gossip", log.Error(err), log.String("flow", "checkpoint"), logfields.Transaction(hash))
+			} else {
+				logger.Info("forwarded transaction via gossip", log.String("flow", "checkpoint"), logfields.Transaction(hash))
+			}
+		}
 	}
 
+	if isTargeted && newHeight {
+		f.forwardToNewLeaders(ctx, targeted, targets)
+	}
+}
+
+func (f *transactionForwarder) signedForwardedTransactions(ctx context.Context, txs []*protocol.SignedTransaction) (*gossiptopics.ForwardedTransactionsInput, []primitives.Sha256, error) {
 	oneBigHash, hashes, err := HashTransactions(txs...)
 	if err != nil {
-		logger.Error("error creating one big hash while signing transactions", log.Error(err), log.StringableSlice("transactions", txs))
-		f.submit(txs...)
-		return
+		return nil, nil, errors.Wrap(err, "error creating one big hash")
 	}
 
 	sig, err := f.signer.Sign(ctx, oneBigHash)
 	if err != nil {
-		logger.Error("error signing transactions", log.Error(err), log.StringableSlice("transactions", txs))
-		f.submit(txs...)
-		return
+		return nil, nil, err
 	}
 
-	_, err = f.gossip.BroadcastForwardedTransactions(ctx, &gossiptopics.ForwardedTransactionsInput{
+	return &gossiptopics.ForwardedTransactionsInput{
 		Message: &gossipmessages.ForwardedTransactionsMessage{
 			SignedTransactions: txs,
 			Sender: (&gossipmessages.SenderSignatureBuilder{
@@ -150,15 +199,25 @@ func (f *transactionForwarder) drainQueueAndForward(ctx context.Context) {
 				Signature:         sig,
 			}).Build(),
 		},
-	})
+	}, hashes, nil
+}
 
-	for _, hash := range hashes {
-		if err != nil {
-			logger.Info("failed forwarding transaction via gossip", log.Error(err), log.String("flow", "checkpoint"), logfields.Transaction(hash))
-		} else {
-			logger.Info("forwarded transaction via gossip", log.String("flow", "checkpoint"), logfields.Transaction(hash))
-		}
+func (f *transactionForwarder) sendToUpcomingLeaders(ctx context.Context, targeted targetedTransactionRelay, recipients []primitives.NodeAddress, committeeSize int, input *gossiptopics.ForwardedTransactionsInput) error {
+	f.metrics.targetedBatches.Inc()
+	f.metrics.skippedRecipients.Add(int64(committeeSize - 1 - len(recipients)))
+	if len(recipients) == 0 { // this node leads all the upcoming views
+		return nil
+	}
+
+	var size uint32
+	for _, tx := range input.Message.SignedTransactions {
+		size += sizeOfSignedTransaction(tx)
 	}
+	f.metrics.recipients.Add(int64(len(recipients)))
+	f.metrics.targetedBytes.Add(int64(size) * int64(len(recipients)))
+
+	_, err := targeted.SendForwardedTransactions(ctx, recipients, input)
+	return err
 }
 
 func (f *transactionForwarder) drainQueue() []*protocol.SignedTransaction {
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"github.com/orbs-network/go-mock"
 	"github.com/orbs-network/orbs-network-go/crypto/signer"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
 	"github.com/orbs-network/orbs-network-go/test"
 	"github.com/orbs-network/orbs-network-go/test/builders"
 	testKeys "github.com/orbs-network/orbs-network-go/test/crypto/keys"
@@ -41,6 +42,18 @@ func (c *forwarderConfig) TransactionPoolPropagationBatchingTimeout() time.Durat
 	return 50 * time.Millisecond
 }
 
+func (c *forwarderConfig) TransactionPoolLeaderAwareForwardingEnabled() bool {
+	return false
+}
+
+func (c *forwarderConfig) TransactionPoolForwardingUpcomingLeaders() uint32 {
+	return 0
+}
+
+func (c *forwarderConfig) TransactionPoolForwardingFallbackFanout() uint32 {
+	return 0
+}
+
 type signerConfig struct {
 	keyPair *testKeys.TestEcdsaSecp256K1KeyPair
 }
@@ -74,7 +87,7 @@ func TestForwardsTransactionAfterTimeout(t *testing.T) {
 		signer, err := signer.New(&signerConfig{keyPair})
 		require.NoError(t, err)
 
-		txForwarder := NewTransactionForwarder(ctx, harness.Logger, signer, cfg, gossip)
+		txForwarder := NewTransactionForwarder(ctx, harness.Logger, signer, cfg, gossip, metric.NewRegistry())
 		harness.Supervise(txForwarder)
 
 		tx := builders.TransferTransaction().Build()
@@ -100,7 +113,7 @@ func TestForwardsTransactionAfterLimitWasReached(t *testing.T) {
 		signer, err := signer.New(&signerConfig{keyPair})
 		require.NoError(t, err)
 
-		txForwarder := NewTransactionForwarder(ctx, harness.Logger, signer, cfg, gossip)
+		txForwarder := NewTransactionForwarder(ctx, harness.Logger, signer, cfg, gossip, metric.NewRegistry())
 		harness.Supervise(txForwarder)
 
 		tx := builders.TransferTransaction().Build()
@@ -128,7 +141,7 @@ func TestForwardsTransactionWithFaultySigner(t *testing.T) {
 		signer := &FaultySigner{}
 		signer.When("Sign", mock.Any, mock.Any).Return([]byte{}, fmt.Errorf("signer unavailable"))
 
-		txForwarder := NewTransactionForwarder(ctx, harness.Logger, signer, cfg, gossip)
+		txForwarder := NewTransactionForwarder(ctx, harness.Logger, signer, cfg, gossip, metric.NewRegistry())
 		harness.Supervise(txForwarder)
 
 		tx := builders.TransferTransaction().Build()
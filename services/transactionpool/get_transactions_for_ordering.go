@@ -192,6 +192,9 @@ func (r *transactionBatch) runPreOrderValidations(ctx context.Context, validator
 	for i, tx := range r.transactionsForPreOrder {
 		if preOrderResults[i] == protocol.TRANSACTION_STATUS_PRE_ORDER_VALID {
 			r.accept(tx)
+		} else if preOrderResults[i] == protocol.TRANSACTION_STATUS_PENDING {
+			// with signer sequencing the transaction is ahead of its signer's sequence, it stays in the pool for a later block
+			r.logger.Info("holding transaction that is ahead of its signer's sequence", log.String("flow", "checkpoint"), logfields.Transaction(digest.CalcTxHash(tx.Transaction())))
 		} else {
 			txHash := digest.CalcTxHash(tx.Transaction())
 			r.logger.Info("dropping transaction that failed pre-order validation", log.String("flow", "checkpoint"), logfields.Transaction(txHash))
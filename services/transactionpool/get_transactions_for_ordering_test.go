@@ -129,6 +129,22 @@ func TestTransactionBatchRejectsTransactionsFailingPreOrderValidation(t *testing
 	})
 }
 
+func TestTransactionBatchHoldsTransactionsAheadOfTheirSignerSequence(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+			tx1 := builders.TransferTransaction().Build()
+			tx2 := builders.TransferTransaction().Build()
+
+			b := &transactionBatch{transactionsForPreOrder: Transactions{tx1, tx2}, logger: parent.Logger}
+			err := b.runPreOrderValidations(ctx, &fakeValidator{statuses: []protocol.TransactionStatus{protocol.TRANSACTION_STATUS_PENDING, protocol.TRANSACTION_STATUS_PRE_ORDER_VALID}}, 0, 0)
+
+			require.NoError(t, err, "this should really never happen")
+			require.Equal(t, Transactions{tx2}, b.validTransactions, "only the transaction in sequence should be ordered")
+			require.Empty(t, b.transactionsToReject, "a transaction ahead of its signer's sequence should stay in the pool rather than be rejected")
+		})
+	})
+}
+
 func TestTransactionBatchPanicsIfPreOrderResultsHasDifferentLengthThanSent(t *testing.T) {
 	with.Context(func(ctx context.Context) {
 		with.Logging(t, func(parent *with.LoggingHarness) {
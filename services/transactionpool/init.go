@@ -8,8 +8,11 @@ package transactionpool
 
 import (
 	"context"
+	"fmt"
 	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
 	"github.com/orbs-network/orbs-network-go/crypto/signer"
+	"github.com/orbs-network/orbs-network-go/instrumentation/logfields"
 	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
 	"github.com/orbs-network/orbs-network-go/services/transactionpool/adapter"
 	"github.com/orbs-network/orbs-network-go/synchronization"
@@ -27,6 +30,8 @@ func NewTransactionPool(ctx context.Context,
 	virtualMachine services.VirtualMachine,
 	signer signer.Signer,
 	blockHeightReporter BlockHeightReporter,
+	pendingJournal adapter.PendingTransactionJournal,
+	committedIndex adapter.CommittedTransactionIndex,
 	config config.TransactionPoolConfig,
 	parent log.Logger,
 	metricFactory metric.Factory) *Service {
@@ -36,12 +41,22 @@ func NewTransactionPool(ctx context.Context,
 	}
 	waiter := newTransactionWaiter()
 	onNewTransaction := func() { waiter.inc(ctx) }
-	pendingPool := NewPendingPool(config.TransactionPoolPendingPoolSizeInBytes, metricFactory, onNewTransaction)
+	orderingPolicy, err := NewOrderingPolicy(config)
+	if err != nil {
+		panic(fmt.Sprintf("invalid transaction pool priority ordering configuration: %s", err))
+	}
+	pendingPool := NewPendingPool(config.TransactionPoolPendingPoolSizeInBytes, metricFactory, onNewTransaction, orderingPolicy)
+	pendingPool.signerQuotas = newSignerQuotas(config)
+	pendingPool.sequencing = config.TransactionSignerSequencingEnabled()
 	committedPool := NewCommittedPool(config.TransactionPoolFutureTimestampGraceTimeout, metricFactory)
+	admissionChain, err := newAdmissionChain(config, virtualMachine, metricFactory)
+	if err != nil {
+		panic(fmt.Sprintf("invalid transaction pool admission configuration: %s", err))
+	}
 
 	logger := parent.WithTags(LogTag)
 
-	txForwarder := NewTransactionForwarder(ctx, logger, signer, config, gossip)
+	txForwarder := NewTransactionForwarder(ctx, logger, signer, config, gossip, metricFactory)
 
 	s := &Service{
 		clock:          createClockIfNeeded(maybeClock),
@@ -50,8 +65,12 @@ func NewTransactionPool(ctx context.Context,
 		config:         config,
 		logger:         logger,
 
+		orderingPolicy:                      orderingPolicy,
 		pendingPool:                         pendingPool,
 		committedPool:                       committedPool,
+		cancelledTransactions:               newCancelledTransactions(config.TransactionPoolFutureTimestampGraceTimeout),
+		admissionChain:                      admissionChain,
+		committedIndex:                      committedIndex,
 		blockTracker:                        synchronization.NewBlockTracker(logger, 0, uint16(config.BlockTrackerGraceDistance())),
 		blockHeightReporter:                 blockHeightReporter,
 		transactionForwarder:                txForwarder,
@@ -65,12 +84,19 @@ func NewTransactionPool(ctx context.Context,
 	s.metrics.lastCommittedTimestamp = metricFactory.NewGauge("TransactionPool.LastCommitted.TimeNano")
 	s.metrics.commitRate = metricFactory.NewRate("TransactionPool.CommitRate.PerSecond")
 	s.metrics.commitCount = metricFactory.NewGauge("TransactionPool.TotalCommits.Count")
+	s.metrics.cancelledCount = metricFactory.NewGauge("TransactionPool.CancelledTransactions.Count")
+	s.metrics.indexedDuplicates = metricFactory.NewGauge("TransactionPool.CommittedIndex.RejectedDuplicates.Count")
 
 	gossip.RegisterTransactionRelayHandler(s)
 	pendingPool.onTransactionRemoved = s.onTransactionError
 
+	if pendingJournal != nil {
+		s.restorePendingPool(pendingJournal)
+	}
+
 	s.Supervise(startCleaningProcess(ctx, "committed pool", config.TransactionPoolCommittedPoolClearExpiredInterval, config.TransactionExpirationWindow, s.committedPool, s.lastCommittedBlockHeightAndTime, logger))
 	s.Supervise(startCleaningProcess(ctx, "pending pool", config.TransactionPoolPendingPoolClearExpiredInterval, config.TransactionExpirationWindow, s.pendingPool, s.lastCommittedBlockHeightAndTime, logger))
+	s.Supervise(startCleaningProcess(ctx, "cancelled transactions", config.TransactionPoolPendingPoolClearExpiredInterval, config.TransactionExpirationWindow, s.cancelledTransactions, s.lastCommittedBlockHeightAndTime, logger))
 	s.Supervise(txForwarder)
 
 	return s
@@ -84,6 +110,51 @@ func createClockIfNeeded(maybeClock adapter.Clock) adapter.Clock {
 	return maybeClock
 }
 
+// the committed pool is empty after a restart, so only the committed index can tell which journaled transactions were
+// committed; transactions committed in blocks the index does not hold yet are removed once block storage syncs them
+func (s *Service) restorePendingPool(journal adapter.PendingTransactionJournal) {
+	transactions, err := journal.Load()
+	if err != nil {
+		s.logger.Error("failed loading pending transactions journal, pending transactions from before the restart are lost", log.Error(err))
+	}
+
+	expiredBefore := primitives.TimestampNano(s.clock.CurrentTime().Add(-s.config.TransactionExpirationWindow()).UnixNano())
+	var restored, expired, committed, rejected int
+	for _, journaled := range transactions {
+		tx := journaled.Transaction
+		if tx.Transaction().Timestamp() < expiredBefore {
+			expired++
+			continue
+		}
+		if s.committedBeforeRestart(digest.CalcTxHash(tx.Transaction())) {
+			committed++
+			continue
+		}
+		if _, rejection := s.pendingPool.add(tx, journaled.GatewayNodeAddress); rejection != nil {
+			rejected++
+			continue
+		}
+		restored++
+	}
+
+	s.pendingPool.attachJournal(journal, s.logger)
+
+	s.logger.Info("restored pending pool from journal", log.Int("restored", restored), log.Int("expired", expired), log.Int("committed", committed), log.Int("rejected", rejected))
+}
+
+func (s *Service) committedBeforeRestart(txHash primitives.Sha256) bool {
+	if s.committedIndex == nil {
+		return false
+	}
+
+	committed, err := s.committedIndex.Contains(txHash)
+	if err != nil {
+		s.logger.Error("failed looking up committed transactions index, restoring transaction", log.Error(err), logfields.Transaction(txHash))
+		return false
+	}
+	return committed
+}
+
 func (s *Service) onTransactionError(ctx context.Context, txHash primitives.Sha256, removalReason protocol.TransactionStatus) {
 	bh, ts := s.lastCommittedBlockHeightAndTime()
 	if removalReason != protocol.TRANSACTION_STATUS_COMMITTED {
@@ -0,0 +1,226 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package transactionpool
+
+import (
+	"context"
+	"encoding/binary"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/crypto/hash"
+	"github.com/orbs-network/orbs-network-go/instrumentation/logfields"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/services/gossiptopics"
+	"github.com/orbs-network/scribe/log"
+)
+
+// A LeaderProvider knows the committee of the height after the current consensus term, ordered so that the member at
+// index i leads view i. With leader-aware forwarding, new transactions are forwarded to the leaders of its first views
+// and to a few other committee members instead of to all peers. Once the height changes, transactions submitted through
+// this node that are still pending are forwarded to the new leaders that have not received them yet, a batch at most per
+// leader. Without a committee, e.g. before the first term started, transactions are broadcast.
+type LeaderProvider interface {
+	UpcomingLeaders(ctx context.Context) (height primitives.BlockHeight, orderedCommittee []primitives.NodeAddress)
+}
+
+type leaderAwareForwardingConfig interface {
+	TransactionPoolLeaderAwareForwardingEnabled() bool
+	TransactionPoolForwardingUpcomingLeaders() uint32
+	TransactionPoolForwardingFallbackFanout() uint32
+}
+
+// implemented by the gossip service in addition to gossiptopics.TransactionRelay
+type targetedTransactionRelay interface {
+	SendForwardedTransactions(ctx context.Context, recipients []primitives.NodeAddress, input *gossiptopics.ForwardedTransactionsInput) (*gossiptopics.EmptyOutput, error)
+}
+
+type forwardingMetrics struct {
+	broadcastBatches       *metric.Gauge
+	targetedBatches        *metric.Gauge
+	recipients             *metric.Gauge
+	skippedRecipients      *metric.Gauge
+	targetedBytes          *metric.Gauge
+	reforwardedTransaction *metric.Gauge
+}
+
+func newForwardingMetrics(factory metric.Factory) *forwardingMetrics {
+	return &forwardingMetrics{
+		broadcastBatches:       factory.NewGauge("TransactionPool.Forwarding.BroadcastBatches.Count"),
+		targetedBatches:        factory.NewGauge("TransactionPool.Forwarding.TargetedBatches.Count"),
+		recipients:             factory.NewGauge("TransactionPool.Forwarding.TargetedRecipients.Count"),
+		skippedRecipients:      factory.NewGauge("TransactionPool.Forwarding.SkippedRecipients.Count"),
+		targetedBytes:          factory.NewGauge("TransactionPool.Forwarding.TargetedBytes.Count"),
+		reforwardedTransaction: factory.NewGauge("TransactionPool.Forwarding.ReforwardedTransactions.Count"),
+	}
+}
+
+// SetLeaderProvider lets the forwarder send new transactions only to the upcoming leaders, if enabled in configuration
+func (s *Service) SetLeaderProvider(provider LeaderProvider) {
+	nodeAddress := s.config.NodeAddress()
+	s.transactionForwarder.setLeaderProvider(provider, func() Transactions {
+		return s.pendingPool.transactionsFrom(nodeAddress)
+	})
+}
+
+func (f *transactionForwarder) setLeaderProvider(provider LeaderProvider, ownPendingTransactions func() Transactions) {
+	f.leaders.Lock()
+	defer f.leaders.Unlock()
+
+	f.leaders.provider = provider
+	f.leaders.ownPendingTransactions = ownPendingTransactions
+}
+
+type forwardingTargets struct {
+	height        primitives.BlockHeight
+	leaders       []primitives.NodeAddress
+	fallback      []primitives.NodeAddress
+	committeeSize int
+}
+
+// nil when the batch should be broadcast
+func (t *forwardingTargets) recipients() []primitives.NodeAddress {
+	if t == nil {
+		return nil
+	}
+	return append(append([]primitives.NodeAddress{}, t.leaders...), t.fallback...)
+}
+
+// returns the targets of the next batch, nil if it should be broadcast, and whether the height changed since the last
+// batch
+func (f *transactionForwarder) upcomingLeaderTargets(ctx context.Context) (targets *forwardingTargets, newHeight bool) {
+	if !f.config.TransactionPoolLeaderAwareForwardingEnabled() {
+		return nil, false
+	}
+
+	f.leaders.RLock()
+	provider := f.leaders.provider
+	f.leaders.RUnlock()
+	if provider == nil {
+		return nil, false
+	}
+
+	height, committee := provider.UpcomingLeaders(ctx)
+	if len(committee) == 0 {
+		return nil, false
+	}
+
+	if height != f.leaders.lastHeight {
+		newHeight = f.leaders.lastHeight != 0
+		f.leaders.lastHeight = height
+	}
+
+	leaders, fallback := selectForwardingTargets(f.config.NodeAddress(), height, committee, int(f.config.TransactionPoolForwardingUpcomingLeaders()), int(f.config.TransactionPoolForwardingFallbackFanout()))
+	return &forwardingTargets{height: height, leaders: leaders, fallback: fallback, committeeSize: len(committee)}, newHeight
+}
+
+// the leaders of the first views and fallbackFanout other committee members, never including this node. The other
+// members are picked by a rotation that depends on this node and the height so different nodes pick different members
+func selectForwardingTargets(self primitives.NodeAddress, height primitives.BlockHeight, orderedCommittee []primitives.NodeAddress, upcomingLeaders int, fallbackFanout int) (leaders []primitives.NodeAddress, fallback []primitives.NodeAddress) {
+	leaders = []primitives.NodeAddress{}
+	var others []primitives.NodeAddress
+	for i, member := range orderedCommittee {
+		if member.Equal(self) {
+			continue
+		}
+		if i < upcomingLeaders {
+			leaders = append(leaders, member)
+		} else {
+			others = append(others, member)
+		}
+	}
+
+	if fallbackFanout > len(others) {
+		fallbackFanout = len(others)
+	}
+	if fallbackFanout == 0 {
+		return leaders, nil
+	}
+
+	heightBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(heightBytes, uint64(height))
+	offset := int(binary.BigEndian.Uint32(hash.CalcSha256(self, heightBytes)) % uint32(len(others)))
+	for i := 0; i < fallbackFanout; i++ {
+		fallback = append(fallback, others[(offset+i)%len(others)])
+	}
+	return leaders, fallback
+}
+
+// records the recipients of transactions submitted through this node, they are not sent the same transactions again
+func (f *transactionForwarder) recordForwarded(hashes []primitives.Sha256, recipients []primitives.NodeAddress) {
+	if f.leaders.forwardedTo == nil {
+		f.leaders.forwardedTo = make(map[string]map[string]bool)
+	}
+	for _, txHash := range hashes {
+		sentTo, ok := f.leaders.forwardedTo[txHash.KeyForMap()]
+		if !ok {
+			sentTo = make(map[string]bool)
+			f.leaders.forwardedTo[txHash.KeyForMap()] = sentTo
+		}
+		for _, recipient := range recipients {
+			sentTo[recipient.KeyForMap()] = true
+		}
+	}
+}
+
+// sends each leader of a new height the pending transactions of this node it did not receive yet, up to a batch, and
+// forgets the transactions that are no longer pending
+func (f *transactionForwarder) forwardToNewLeaders(ctx context.Context, targeted targetedTransactionRelay, targets *forwardingTargets) {
+	logger := f.logger.WithTags(trace.LogFieldFrom(ctx))
+
+	f.leaders.RLock()
+	ownPendingTransactions := f.leaders.ownPendingTransactions
+	f.leaders.RUnlock()
+	if ownPendingTransactions == nil {
+		return
+	}
+
+	pending := ownPendingTransactions()
+	hashes := make([]primitives.Sha256, len(pending))
+	stillPending := make(map[string]bool, len(pending))
+	for i, tx := range pending {
+		hashes[i] = digest.CalcTxHash(tx.Transaction())
+		stillPending[hashes[i].KeyForMap()] = true
+	}
+	for key := range f.leaders.forwardedTo {
+		if !stillPending[key] {
+			delete(f.leaders.forwardedTo, key)
+		}
+	}
+
+	batchSize := int(f.config.TransactionPoolPropagationBatchSize())
+	for _, leader := range targets.leaders {
+		var txs Transactions
+		var sentHashes []primitives.Sha256
+		for i, tx := range pending {
+			if len(txs) >= batchSize {
+				break
+			}
+			if !f.leaders.forwardedTo[hashes[i].KeyForMap()][leader.KeyForMap()] {
+				txs = append(txs, tx)
+				sentHashes = append(sentHashes, hashes[i])
+			}
+		}
+		if len(txs) == 0 {
+			continue
+		}
+
+		input, _, err := f.signedForwardedTransactions(ctx, txs)
+		if err != nil {
+			logger.Error("error signing pending transactions to forward to a new leader", log.Error(err))
+			return
+		}
+		recipients := []primitives.NodeAddress{leader}
+		if _, err := targeted.SendForwardedTransactions(ctx, recipients, input); err != nil {
+			logger.Info("failed forwarding pending transactions to a new leader", log.Error(err), log.Stringable("leader", leader))
+			continue
+		}
+		f.recordForwarded(sentHashes, recipients)
+		f.metrics.reforwardedTransaction.Add(int64(len(txs)))
+		logger.Info("forwarded pending transactions to a new leader", log.Int("num-transactions", len(txs)), log.Stringable("leader", leader), logfields.BlockHeight(targets.height))
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package transactionpool
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/crypto/signer"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	testKeys "github.com/orbs-network/orbs-network-go/test/crypto/keys"
+	"github.com/orbs-network/orbs-network-go/test/with"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services/gossiptopics"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+)
+
+type leaderAwareForwarderConfig struct {
+	forwarderConfig
+}
+
+func (c *leaderAwareForwarderConfig) TransactionPoolLeaderAwareForwardingEnabled() bool {
+	return true
+}
+
+func (c *leaderAwareForwarderConfig) TransactionPoolForwardingUpcomingLeaders() uint32 {
+	return 2
+}
+
+func (c *leaderAwareForwarderConfig) TransactionPoolForwardingFallbackFanout() uint32 {
+	return 1
+}
+
+type leaderProviderForTests struct {
+	height    primitives.BlockHeight
+	committee []primitives.NodeAddress
+}
+
+func (p *leaderProviderForTests) UpcomingLeaders(ctx context.Context) (primitives.BlockHeight, []primitives.NodeAddress) {
+	return p.height, p.committee
+}
+
+type sentForwardedTransactions struct {
+	recipients   []primitives.NodeAddress
+	transactions []*protocol.SignedTransaction
+}
+
+type targetedRelayForTests struct {
+	gossiptopics.MockTransactionRelay
+	sent []*sentForwardedTransactions
+}
+
+func (r *targetedRelayForTests) SendForwardedTransactions(ctx context.Context, recipients []primitives.NodeAddress, input *gossiptopics.ForwardedTransactionsInput) (*gossiptopics.EmptyOutput, error) {
+	r.sent = append(r.sent, &sentForwardedTransactions{recipients: recipients, transactions: input.Message.SignedTransactions})
+	return nil, nil
+}
+
+func TestSelectForwardingTargets_SendsToUpcomingLeadersAndFallbackFanoutButNotToSelf(t *testing.T) {
+	committee := testKeys.NodeAddressesForTests()[:6]
+
+	leaders, fallback := selectForwardingTargets(committee[1], 8, committee, 3, 2)
+	require.Equal(t, []primitives.NodeAddress{committee[0], committee[2]}, leaders, "leaders of the first views should be picked, skipping this node")
+	require.Len(t, fallback, 2)
+	for _, member := range fallback {
+		require.Contains(t, committee[3:], member)
+	}
+	require.NotEqual(t, fallback[0], fallback[1])
+
+	_, samePick := selectForwardingTargets(committee[1], 8, committee, 3, 2)
+	require.Equal(t, fallback, samePick, "the fallback members should be picked deterministically")
+
+	leaders, fallback = selectForwardingTargets(committee[0], 8, committee, 10, 10)
+	require.Len(t, append(leaders, fallback...), 5, "should never send to more than the rest of the committee")
+	leaders, fallback = selectForwardingTargets(committee[0], 8, committee[:1], 1, 0)
+	require.Empty(t, append(leaders, fallback...))
+}
+
+func TestLeaderAwareForwarder_ForwardsToUpcomingLeadersAndPendingTransactionsToNewLeaders(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(harness *with.LoggingHarness) {
+			keyPair := testKeys.EcdsaSecp256K1KeyPairForTests(0)
+			txSigner, err := signer.New(&signerConfig{keyPair})
+			require.NoError(t, err)
+			nodes := testKeys.NodeAddressesForTests()[:5]
+			require.Equal(t, keyPair.NodeAddress(), nodes[0])
+			relay := &targetedRelayForTests{}
+			f := &transactionForwarder{
+				logger:            harness.Logger,
+				config:            &leaderAwareForwarderConfig{forwarderConfig{2, keyPair}},
+				gossip:            relay,
+				signer:            txSigner,
+				forwardQueueMutex: &sync.Mutex{},
+				transactionAdded:  make(chan uint16, 1),
+				metrics:           newForwardingMetrics(metric.NewRegistry()),
+			}
+
+			tx1 := builders.TransferTransaction().WithAmountAndTargetAddress(1, builders.ClientAddressForEd25519SignerForTests(1)).Build()
+			tx2 := builders.TransferTransaction().WithAmountAndTargetAddress(2, builders.ClientAddressForEd25519SignerForTests(1)).Build()
+			tx3 := builders.TransferTransaction().WithAmountAndTargetAddress(3, builders.ClientAddressForEd25519SignerForTests(1)).Build()
+			ownPending := Transactions{tx1, tx2, tx3}
+			provider := &leaderProviderForTests{height: 5, committee: []primitives.NodeAddress{nodes[1], nodes[0]}}
+			f.setLeaderProvider(provider, func() Transactions {
+				return ownPending
+			})
+
+			f.appendToQueue(Transactions{tx1})
+			f.drainQueueAndForward(ctx)
+			require.Len(t, relay.sent, 1)
+			require.Equal(t, []primitives.NodeAddress{nodes[1]}, relay.sent[0].recipients, "should send to the upcoming leader that is not this node")
+
+			f.drainQueueAndForward(ctx)
+			require.Len(t, relay.sent, 1, "should not forward anything again for the same height")
+
+			provider.height, provider.committee = 6, []primitives.NodeAddress{nodes[2], nodes[1], nodes[3], nodes[4]}
+			f.drainQueueAndForward(ctx)
+			require.Len(t, relay.sent, 3, "should forward pending transactions to each of the new leaders once the height changes")
+			require.Equal(t, []primitives.NodeAddress{nodes[2]}, relay.sent[1].recipients)
+			require.Equal(t, []*protocol.SignedTransaction{tx1, tx2}, relay.sent[1].transactions, "should send a batch at most")
+			require.Equal(t, []primitives.NodeAddress{nodes[1]}, relay.sent[2].recipients)
+			require.Equal(t, []*protocol.SignedTransaction{tx2, tx3}, relay.sent[2].transactions, "should not send a leader transactions it already received")
+
+			ownPending = Transactions{tx3}
+			provider.height = 7
+			f.drainQueueAndForward(ctx)
+			require.Len(t, relay.sent, 4)
+			require.Equal(t, []primitives.NodeAddress{nodes[2]}, relay.sent[3].recipients)
+			require.Equal(t, []*protocol.SignedTransaction{tx3}, relay.sent[3].transactions)
+			require.Len(t, f.leaders.forwardedTo, 1, "transactions that are no longer pending should be forgotten")
+
+			require.EqualValues(t, 1, f.metrics.targetedBatches.Value())
+			require.EqualValues(t, 0, f.metrics.broadcastBatches.Value())
+			require.EqualValues(t, 5, f.metrics.reforwardedTransaction.Value())
+		})
+	})
+}
@@ -0,0 +1,136 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package transactionpool
+
+import (
+	"encoding/hex"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+type PriorityClass int
+
+// ordered from highest to lowest priority
+const (
+	PRIORITY_CLASS_SYSTEM PriorityClass = iota
+	PRIORITY_CLASS_HIGH
+	PRIORITY_CLASS_NORMAL
+	NUM_PRIORITY_CLASSES = 3
+)
+
+func (c PriorityClass) String() string {
+	switch c {
+	case PRIORITY_CLASS_SYSTEM:
+		return "System"
+	case PRIORITY_CLASS_HIGH:
+		return "High"
+	default:
+		return "Normal"
+	}
+}
+
+// An OrderingPolicy decides the priority class of each pending transaction. Batches for ordering are built from the
+// highest class down and returned grouped by class. The policy is configured per node, so it only shapes the blocks
+// this node proposes and is not enforced on blocks proposed by others.
+// To keep lower classes from starving, each class that has pending transactions is first given MinSharePercent of the
+// batch before the rest of the batch is filled by priority.
+// The transaction format has no declared priority field, so classes are currently assigned by contract and by signer.
+type OrderingPolicy interface {
+	ClassOf(transaction *protocol.Transaction) PriorityClass
+	MinSharePercent() uint32
+}
+
+type priorityOrderingConfig interface {
+	TransactionPoolPriorityOrderingEnabled() bool
+	TransactionPoolPrioritySystemContracts() string
+	TransactionPoolPriorityHighContracts() string
+	TransactionPoolPriorityHighSigners() string
+	TransactionPoolPriorityMinSharePercent() uint32
+}
+
+// all transactions are in the same class, which keeps the pool in arrival order
+type fifoOrderingPolicy struct{}
+
+func NewFifoOrderingPolicy() OrderingPolicy {
+	return &fifoOrderingPolicy{}
+}
+
+func (p *fifoOrderingPolicy) ClassOf(transaction *protocol.Transaction) PriorityClass {
+	return PRIORITY_CLASS_NORMAL
+}
+
+func (p *fifoOrderingPolicy) MinSharePercent() uint32 {
+	return 0
+}
+
+type configuredOrderingPolicy struct {
+	systemContracts map[string]bool
+	highContracts   map[string]bool
+	highSigners     map[string]bool
+	minSharePercent uint32
+}
+
+func NewOrderingPolicy(config priorityOrderingConfig) (OrderingPolicy, error) {
+	if !config.TransactionPoolPriorityOrderingEnabled() {
+		return NewFifoOrderingPolicy(), nil
+	}
+
+	highSigners := make(map[string]bool)
+	for _, signer := range splitList(config.TransactionPoolPriorityHighSigners()) {
+		address, err := hex.DecodeString(strings.TrimPrefix(signer, "0x"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid high priority signer %s", signer)
+		}
+		highSigners[string(address)] = true
+	}
+
+	return &configuredOrderingPolicy{
+		systemContracts: setOf(splitList(config.TransactionPoolPrioritySystemContracts())),
+		highContracts:   setOf(splitList(config.TransactionPoolPriorityHighContracts())),
+		highSigners:     highSigners,
+		minSharePercent: config.TransactionPoolPriorityMinSharePercent(),
+	}, nil
+}
+
+func (p *configuredOrderingPolicy) ClassOf(transaction *protocol.Transaction) PriorityClass {
+	contractName := string(transaction.ContractName())
+	if p.systemContracts[contractName] {
+		return PRIORITY_CLASS_SYSTEM
+	}
+	if p.highContracts[contractName] {
+		return PRIORITY_CLASS_HIGH
+	}
+	if len(p.highSigners) > 0 {
+		if address, err := digest.CalcClientAddressOfSigner(transaction.Signer()); err == nil && p.highSigners[string(address)] {
+			return PRIORITY_CLASS_HIGH
+		}
+	}
+	return PRIORITY_CLASS_NORMAL
+}
+
+func (p *configuredOrderingPolicy) MinSharePercent() uint32 {
+	return p.minSharePercent
+}
+
+func splitList(list string) (res []string) {
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
+	}
+	return
+}
+
+func setOf(items []string) map[string]bool {
+	res := make(map[string]bool)
+	for _, item := range items {
+		res[item] = true
+	}
+	return res
+}
@@ -0,0 +1,109 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package transactionpool
+
+import (
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-network-go/test/crypto/keys"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type priorityOrderingConfigForTests struct {
+	highSigners     string
+	minSharePercent uint32
+}
+
+func (c *priorityOrderingConfigForTests) TransactionPoolPriorityOrderingEnabled() bool {
+	return true
+}
+
+func (c *priorityOrderingConfigForTests) TransactionPoolPrioritySystemContracts() string {
+	return "_Deployments, _Elections"
+}
+
+func (c *priorityOrderingConfigForTests) TransactionPoolPriorityHighContracts() string {
+	return "HighValue"
+}
+
+func (c *priorityOrderingConfigForTests) TransactionPoolPriorityHighSigners() string {
+	return c.highSigners
+}
+
+func (c *priorityOrderingConfigForTests) TransactionPoolPriorityMinSharePercent() uint32 {
+	return c.minSharePercent
+}
+
+func aPriorityPolicy(t *testing.T, config *priorityOrderingConfigForTests) OrderingPolicy {
+	policy, err := NewOrderingPolicy(config)
+	require.NoError(t, err)
+	return policy
+}
+
+func TestOrderingPolicy_AssignsClassesByContractAndSigner(t *testing.T) {
+	highSigner := keys.Ed25519KeyPairForTests(3)
+	address, err := digest.CalcClientAddressOfEd25519PublicKey(highSigner.PublicKey())
+	require.NoError(t, err)
+	policy := aPriorityPolicy(t, &priorityOrderingConfigForTests{highSigners: "0x" + address.String()})
+
+	require.Equal(t, PRIORITY_CLASS_SYSTEM, policy.ClassOf(builders.Transaction().WithContract("_Deployments").Build().Transaction()))
+	require.Equal(t, PRIORITY_CLASS_HIGH, policy.ClassOf(builders.Transaction().WithContract("HighValue").Build().Transaction()))
+	require.Equal(t, PRIORITY_CLASS_HIGH, policy.ClassOf(builders.Transaction().WithEd25519Signer(highSigner).Build().Transaction()))
+	require.Equal(t, PRIORITY_CLASS_NORMAL, policy.ClassOf(builders.Transaction().Build().Transaction()))
+}
+
+func TestOrderingPolicy_RejectsInvalidSigners(t *testing.T) {
+	_, err := NewOrderingPolicy(&priorityOrderingConfigForTests{highSigners: "not-hex"})
+	require.Error(t, err)
+}
+
+func TestPendingTransactionPoolGetBatchOrdersByPriorityClass(t *testing.T) {
+	p := NewPendingPool(func() uint32 { return 100000 }, metric.NewRegistry(), func() {}, aPriorityPolicy(t, &priorityOrderingConfigForTests{}))
+
+	normal1 := builders.TransferTransaction().Build()
+	high := builders.TransferTransaction().WithContract("HighValue").Build()
+	normal2 := builders.TransferTransaction().Build()
+	system := builders.TransferTransaction().WithContract("_Elections").Build()
+	add(p, normal1, high, normal2, system)
+
+	batch := p.getBatch(10, 0)
+
+	require.Equal(t, Transactions{system, high, normal1, normal2}, batch, "batch should be ordered by class and then by arrival")
+	require.NoError(t, validatePriorityOrder(p.policy, batch))
+}
+
+func TestPendingTransactionPoolGetBatchGivesLowerClassesTheirMinimumShare(t *testing.T) {
+	p := NewPendingPool(func() uint32 { return 100000 }, metric.NewRegistry(), func() {}, aPriorityPolicy(t, &priorityOrderingConfigForTests{minSharePercent: 20}))
+
+	for i := 0; i < 20; i++ {
+		add(p, builders.TransferTransaction().WithContract("_Deployments").Build())
+	}
+	normal := Transactions{builders.TransferTransaction().Build(), builders.TransferTransaction().Build(), builders.TransferTransaction().Build()}
+	add(p, normal...)
+
+	batch := p.getBatch(10, 0)
+
+	require.Len(t, batch, 10)
+	require.Equal(t, normal[:2], batch[8:], "normal transactions should get 20% of the batch despite pending system transactions")
+	require.NoError(t, validatePriorityOrder(p.policy, batch))
+}
+
+// returns an error for the first transaction whose class is higher than that of a transaction before it
+func validatePriorityOrder(policy OrderingPolicy, transactions Transactions) error {
+	previous := PRIORITY_CLASS_SYSTEM
+	for i, tx := range transactions {
+		class := policy.ClassOf(tx.Transaction())
+		if class < previous {
+			return errors.Errorf("transaction %d of class %s is ordered after a transaction of class %s", i, class, previous)
+		}
+		previous = class
+	}
+	return nil
+}
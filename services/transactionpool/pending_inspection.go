@@ -0,0 +1,163 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package transactionpool
+
+import (
+	"encoding/hex"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	PENDING_INSPECTION_DEFAULT_LIMIT = 100
+	PENDING_INSPECTION_MAX_LIMIT     = 1000
+)
+
+// PendingTransactionsQuery selects a page of pending transactions in arrival order, oldest first; empty filters match everything
+type PendingTransactionsQuery struct {
+	Signer       string // hex client address of the signer, with or without 0x
+	ContractName string
+	MethodName   string
+	MinAge       time.Duration
+	MaxAge       time.Duration // 0 for no limit
+	Offset       int
+	Limit        int // 0 for PENDING_INSPECTION_DEFAULT_LIMIT
+}
+
+// PendingTransactionsReport is served on the /api/v1/pending-transactions http endpoint
+type PendingTransactionsReport struct {
+	Stats        *PendingPoolStats         `json:"stats"`
+	Matching     int                       `json:"matching"`
+	Offset       int                       `json:"offset"`
+	Transactions []*PendingTransactionInfo `json:"transactions"`
+}
+
+type PendingPoolStats struct {
+	Count            int        `json:"count"`
+	SizeInBytes      uint32     `json:"sizeInBytes"`
+	Signers          int        `json:"signers"`
+	OldestTxHash     string     `json:"oldestTxHash,omitempty"`
+	OldestTimeAdded  *time.Time `json:"oldestTimeAdded,omitempty"`
+	OldestAgeSeconds float64    `json:"oldestAgeSeconds"`
+}
+
+type PendingTransactionInfo struct {
+	TxHash             string    `json:"txHash"`
+	Signer             string    `json:"signer"`
+	ContractName       string    `json:"contractName"`
+	MethodName         string    `json:"methodName"`
+	Timestamp          time.Time `json:"timestamp"`
+	TimeAdded          time.Time `json:"timeAdded"`
+	AgeSeconds         float64   `json:"ageSeconds"`
+	SizeInBytes        uint32    `json:"sizeInBytes"`
+	GatewayNodeAddress string    `json:"gatewayNodeAddress"`
+	PriorityClass      string    `json:"priorityClass"`
+}
+
+func (s *Service) PendingTransactions(query *PendingTransactionsQuery) *PendingTransactionsReport {
+	return s.pendingPool.inspect(query, time.Now())
+}
+
+// the signer is compared last since it requires calculating the client address
+func (q *PendingTransactionsQuery) matches(pendingTx *pendingTransaction, age time.Duration) bool {
+	tx := pendingTx.transaction.Transaction()
+	if q.ContractName != "" && q.ContractName != string(tx.ContractName()) {
+		return false
+	}
+	if q.MethodName != "" && q.MethodName != string(tx.MethodName()) {
+		return false
+	}
+	if age < q.MinAge || (q.MaxAge != 0 && age > q.MaxAge) {
+		return false
+	}
+	return q.Signer == "" || strings.EqualFold(strings.TrimPrefix(q.Signer, "0x"), signerAddressOf(tx.Signer()))
+}
+
+// only a snapshot of the pool is taken under its lock, filtering and describing the transactions is done outside it
+func (p *pendingTxPool) inspect(query *PendingTransactionsQuery, now time.Time) *PendingTransactionsReport {
+	snapshot, stats := p.snapshotForInspection()
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = PENDING_INSPECTION_DEFAULT_LIMIT
+	}
+	if limit > PENDING_INSPECTION_MAX_LIMIT {
+		limit = PENDING_INSPECTION_MAX_LIMIT
+	}
+
+	report := &PendingTransactionsReport{
+		Stats:        stats,
+		Offset:       query.Offset,
+		Transactions: []*PendingTransactionInfo{},
+	}
+
+	if len(snapshot) > 0 {
+		oldest := snapshot[0]
+		report.Stats.OldestTxHash = oldest.txHash.String()
+		report.Stats.OldestTimeAdded = &oldest.timeAdded
+		report.Stats.OldestAgeSeconds = now.Sub(oldest.timeAdded).Seconds()
+	}
+
+	for _, pendingTx := range snapshot {
+		if !query.matches(pendingTx, now.Sub(pendingTx.timeAdded)) {
+			continue
+		}
+		if report.Matching >= query.Offset && len(report.Transactions) < limit {
+			report.Transactions = append(report.Transactions, describePendingTransaction(pendingTx, now))
+		}
+		report.Matching++
+	}
+
+	return report
+}
+
+// returns the pending transactions oldest first, they are sorted once the pool lock is released
+func (p *pendingTxPool) snapshotForInspection() ([]*pendingTransaction, *PendingPoolStats) {
+	p.lock.RLock()
+	snapshot := make([]*pendingTransaction, 0, len(p.transactionsByHash))
+	for _, pendingTx := range p.transactionsByHash {
+		snapshot = append(snapshot, pendingTx)
+	}
+	stats := &PendingPoolStats{
+		Count:       len(p.transactionsByHash),
+		SizeInBytes: p.currentSizeInBytes,
+		Signers:     len(p.signers),
+	}
+	p.lock.RUnlock()
+
+	sort.Slice(snapshot, func(i, j int) bool {
+		return snapshot[i].timeAdded.Before(snapshot[j].timeAdded)
+	})
+	return snapshot, stats
+}
+
+func describePendingTransaction(pendingTx *pendingTransaction, now time.Time) *PendingTransactionInfo {
+	tx := pendingTx.transaction.Transaction()
+	return &PendingTransactionInfo{
+		TxHash:             pendingTx.txHash.String(),
+		Signer:             signerAddressOf(tx.Signer()),
+		ContractName:       string(tx.ContractName()),
+		MethodName:         string(tx.MethodName()),
+		Timestamp:          time.Unix(0, int64(tx.Timestamp())),
+		TimeAdded:          pendingTx.timeAdded,
+		AgeSeconds:         now.Sub(pendingTx.timeAdded).Seconds(),
+		SizeInBytes:        sizeOfSignedTransaction(pendingTx.transaction),
+		GatewayNodeAddress: pendingTx.gatewayNodeAddress.String(),
+		PriorityClass:      pendingTx.class.String(),
+	}
+}
+
+// the client address for ed25519 signers, otherwise the raw signer
+func signerAddressOf(signer *protocol.Signer) string {
+	if address, err := digest.CalcClientAddressOfSigner(signer); err == nil {
+		return hex.EncodeToString(address)
+	}
+	return hex.EncodeToString(signer.Raw())
+}
@@ -0,0 +1,60 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package transactionpool
+
+import (
+	"encoding/hex"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-network-go/test/crypto/keys"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestPendingPoolInspectionPagesThroughMatchingTransactionsOldestFirst(t *testing.T) {
+	p := makePendingPool()
+	txs := transactionsOfSigner(1, 3)
+	other := builders.TransferTransaction().WithEd25519Signer(keys.Ed25519KeyPairForTests(2)).WithMethod("OtherContract", "otherMethod").Build()
+	add(p, txs[0], other, txs[1], txs[2])
+
+	report := p.inspect(&PendingTransactionsQuery{ContractName: "BenchmarkToken", Offset: 1, Limit: 1}, time.Now())
+
+	require.Equal(t, 4, report.Stats.Count)
+	require.Equal(t, 2, report.Stats.Signers)
+	require.Equal(t, p.currentSizeInBytes, report.Stats.SizeInBytes)
+	require.Equal(t, digest.CalcTxHash(txs[0].Transaction()).String(), report.Stats.OldestTxHash, "the oldest transaction should be reported regardless of filters")
+	require.Equal(t, 3, report.Matching)
+	require.Len(t, report.Transactions, 1)
+	require.Equal(t, digest.CalcTxHash(txs[1].Transaction()).String(), report.Transactions[0].TxHash, "should skip the offset in arrival order")
+	require.Equal(t, nodeAddress.String(), report.Transactions[0].GatewayNodeAddress)
+	require.Equal(t, sizeOfSignedTransaction(txs[1]), report.Transactions[0].SizeInBytes)
+}
+
+func TestPendingPoolInspectionFiltersBySignerMethodAndAge(t *testing.T) {
+	p := makePendingPool()
+	other := builders.TransferTransaction().WithEd25519Signer(keys.Ed25519KeyPairForTests(2)).WithMethod("OtherContract", "otherMethod").Build()
+	add(p, transactionsOfSigner(1, 2)...)
+	add(p, other)
+
+	signer, err := digest.CalcClientAddressOfEd25519Signer(other.Transaction().Signer())
+	require.NoError(t, err)
+
+	report := p.inspect(&PendingTransactionsQuery{Signer: "0x" + hex.EncodeToString(signer)}, time.Now())
+	require.Equal(t, 1, report.Matching)
+	require.Equal(t, "otherMethod", report.Transactions[0].MethodName)
+
+	report = p.inspect(&PendingTransactionsQuery{MethodName: "transfer"}, time.Now())
+	require.Equal(t, 2, report.Matching)
+
+	report = p.inspect(&PendingTransactionsQuery{MinAge: time.Hour}, time.Now())
+	require.Zero(t, report.Matching, "no transaction has been pending for an hour yet")
+
+	report = p.inspect(&PendingTransactionsQuery{MaxAge: time.Hour}, time.Now().Add(2*time.Hour))
+	require.Zero(t, report.Matching, "all transactions have been pending for more than an hour")
+	require.Equal(t, 3, report.Stats.Count)
+}
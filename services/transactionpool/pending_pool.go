@@ -9,21 +9,36 @@ package transactionpool
 import (
 	"container/list"
 	"context"
+	"fmt"
 	"github.com/orbs-network/orbs-network-go/crypto/digest"
 	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-network-go/services/transactionpool/adapter"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/scribe/log"
+	"sort"
 	"sync"
 	"time"
 )
 
+// the journal is compacted once it holds this many records more than twice the number of pending transactions
+const PENDING_JOURNAL_COMPACTION_SLACK = 1000
+
 type transactionRemovedListener func(ctx context.Context, txHash primitives.Sha256, reason protocol.TransactionStatus)
 
-func NewPendingPool(pendingPoolSizeInBytes func() uint32, metricFactory metric.Factory, onNewTransaction func()) *pendingTxPool {
+func NewPendingPool(pendingPoolSizeInBytes func() uint32, metricFactory metric.Factory, onNewTransaction func(), policy OrderingPolicy) *pendingTxPool {
+	var classLists [NUM_PRIORITY_CLASSES]*list.List
+	for i := range classLists {
+		classLists[i] = list.New()
+	}
+
 	return &pendingTxPool{
 		pendingPoolSizeInBytes: pendingPoolSizeInBytes,
 		transactionsByHash:     make(map[string]*pendingTransaction),
 		transactionList:        list.New(),
+		signers:                make(map[string]*signerUsage),
+		classLists:             classLists,
+		policy:                 policy,
 		lock:                   &sync.RWMutex{},
 		onNewTransaction:       onNewTransaction,
 
@@ -32,10 +47,16 @@ func NewPendingPool(pendingPoolSizeInBytes func() uint32, metricFactory metric.F
 }
 
 type pendingTransaction struct {
+	txHash             primitives.Sha256
 	gatewayNodeAddress primitives.NodeAddress
 	transaction        *protocol.SignedTransaction
 	listElement        *list.Element
+	class              PriorityClass
+	classElement       *list.Element // its value is the pending transaction
 	timeAdded          time.Time
+	signer             string
+	nonce              uint64
+	sequenced          bool // only with signer sequencing, for a transaction which carries a nonce
 }
 
 type pendingPoolMetrics struct {
@@ -44,35 +65,81 @@ type pendingPoolMetrics struct {
 	transactionRatePerSecond *metric.Rate
 	transactionSpentInQueue  *metric.Histogram
 	transactionServiceTime   *metric.Histogram
+	journalErrors            *metric.Gauge
+
+	signerCount           *metric.Gauge
+	signerQuotaRejections *metric.Gauge
+
+	classTransactionCount   [NUM_PRIORITY_CLASSES]*metric.Gauge
+	classTimeSpentInQueue   [NUM_PRIORITY_CLASSES]*metric.Histogram
+	classTransactionsPicked [NUM_PRIORITY_CLASSES]*metric.Gauge
 }
 
 func newPendingPoolMetrics(factory metric.Factory) *pendingPoolMetrics {
-	return &pendingPoolMetrics{
+	m := &pendingPoolMetrics{
 		transactionServiceTime:   factory.NewLatency("TransactionPool.ServiceTime.Millis", 30*time.Minute),
 		transactionCountGauge:    factory.NewGauge("TransactionPool.PendingPool.Transactions.Count"),
 		poolSizeInBytesGauge:     factory.NewGauge("TransactionPool.PendingPool.PoolSize.Bytes"),
 		transactionRatePerSecond: factory.NewRate("TransactionPool.TransactionsEnteringPool.PerSecond"),
 		transactionSpentInQueue:  factory.NewLatency("TransactionPool.PendingPool.TimeSpentInQueue.Millis", 30*time.Minute),
+		journalErrors:            factory.NewGauge("TransactionPool.PendingPool.JournalErrors.Count"),
+		signerCount:              factory.NewGauge("TransactionPool.PendingPool.Signers.Count"),
+		signerQuotaRejections:    factory.NewGauge("TransactionPool.PendingPool.SignerQuotaRejections.Count"),
+	}
+
+	for class := PriorityClass(0); class < NUM_PRIORITY_CLASSES; class++ {
+		m.classTransactionCount[class] = factory.NewGauge(fmt.Sprintf("TransactionPool.PendingPool.Class.%s.Transactions.Count", class))
+		m.classTimeSpentInQueue[class] = factory.NewLatency(fmt.Sprintf("TransactionPool.PendingPool.Class.%s.TimeSpentInQueue.Millis", class), 30*time.Minute)
+		m.classTransactionsPicked[class] = factory.NewGauge(fmt.Sprintf("TransactionPool.PendingPool.Class.%s.TransactionsPicked.Count", class))
 	}
+
+	return m
 }
 
 type pendingTxPool struct {
 	currentSizeInBytes uint32
 	transactionsByHash map[string]*pendingTransaction
-	transactionList    *list.List
+	transactionList    *list.List // in arrival order, newest at the front
+	classLists         [NUM_PRIORITY_CLASSES]*list.List
+	policy             OrderingPolicy
 	onNewTransaction   func()
 	lock               *sync.RWMutex
 
 	pendingPoolSizeInBytes func() uint32
 	onTransactionRemoved   transactionRemovedListener
 
+	signers      map[string]*signerUsage
+	signerQuotas *signerQuotas // nil if signers are not limited
+	sequencing   bool          // offer each signer's transactions for ordering in nonce order only
+
+	// the pool only queues journal records under its lock, they are written by whoever holds journalLock once the
+	// pool lock is released, so that journal I/O and compaction never block the pool
+	journal       adapter.PendingTransactionJournal // nil if the pending pool is not persisted
+	journalLogger log.Logger
+	journalLock   sync.Mutex
+	journalQueue  []*journalRecord
+
 	metrics *pendingPoolMetrics
 }
 
+type journalRecord struct {
+	added   *adapter.JournaledTransaction
+	removed primitives.Sha256
+}
+
 func (p *pendingTxPool) add(transaction *protocol.SignedTransaction, gatewayNodeAddress primitives.NodeAddress) (primitives.Sha256, *ErrTransactionRejected) {
+	defer p.writeJournal()
+	return p.addUnderLock(transaction, gatewayNodeAddress)
+}
+
+func (p *pendingTxPool) addUnderLock(transaction *protocol.SignedTransaction, gatewayNodeAddress primitives.NodeAddress) (primitives.Sha256, *ErrTransactionRejected) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
+	return p.addUnderMutex(transaction, gatewayNodeAddress)
+}
+
+func (p *pendingTxPool) addUnderMutex(transaction *protocol.SignedTransaction, gatewayNodeAddress primitives.NodeAddress) (primitives.Sha256, *ErrTransactionRejected) {
 	size := sizeOfSignedTransaction(transaction)
 
 	if p.currentSizeInBytes+size > p.pendingPoolSizeInBytes() {
@@ -85,18 +152,48 @@ func (p *pendingTxPool) add(transaction *protocol.SignedTransaction, gatewayNode
 		return nil, &ErrTransactionRejected{TransactionStatus: protocol.TRANSACTION_STATUS_DUPLICATE_TRANSACTION_ALREADY_PENDING}
 	}
 
-	p.currentSizeInBytes += size
-	p.transactionsByHash[key.KeyForMap()] = &pendingTransaction{
+	signer := signerKeyOf(transaction)
+	if !p.signerQuotas.allows(p.signers[signer], size) {
+		p.metrics.signerQuotaRejections.Inc()
+		return nil, &ErrTransactionRejected{TransactionStatus: protocol.TRANSACTION_STATUS_REJECTED_CONGESTION}
+	}
+	var nonce uint64
+	var sequenced bool
+	if p.sequencing {
+		var err error
+		if nonce, sequenced, err = digest.SignerNonce(transaction.Transaction()); err != nil {
+			return nil, &ErrTransactionRejected{protocol.TRANSACTION_STATUS_REJECTED_VIRTUAL_CHAIN_PRE_ORDER, log.String("signer-nonce", "tagged first input argument"), log.Error(err)}
+		}
+	}
+	pendingTx := &pendingTransaction{
+		txHash:             key,
 		transaction:        transaction,
 		gatewayNodeAddress: gatewayNodeAddress,
-		listElement:        p.transactionList.PushFront(transaction),
+		class:              p.policy.ClassOf(transaction.Transaction()),
 		timeAdded:          time.Now(),
+		signer:             signer,
+		nonce:              nonce,
+		sequenced:          sequenced,
+	}
+	if !p.addSignerUsageUnderMutex(pendingTx, size) {
+		return nil, &ErrTransactionRejected{TransactionStatus: protocol.TRANSACTION_STATUS_DUPLICATE_TRANSACTION_ALREADY_PENDING}
 	}
 
+	class := pendingTx.class
+	p.currentSizeInBytes += size
+	pendingTx.listElement = p.transactionList.PushFront(transaction)
+	pendingTx.classElement = p.classLists[class].PushFront(pendingTx)
+	p.transactionsByHash[key.KeyForMap()] = pendingTx
+
 	p.metrics.transactionCountGauge.Inc()
+	p.metrics.classTransactionCount[class].Inc()
 	p.metrics.poolSizeInBytesGauge.AddUint32(size)
 	p.metrics.transactionRatePerSecond.Measure(1)
 
+	if p.journal != nil {
+		p.journalQueue = append(p.journalQueue, &journalRecord{added: &adapter.JournaledTransaction{Transaction: transaction, GatewayNodeAddress: gatewayNodeAddress}})
+	}
+
 	p.onNewTransaction()
 
 	return key, nil
@@ -111,58 +208,170 @@ func (p *pendingTxPool) has(transaction *protocol.SignedTransaction) bool {
 }
 
 func (p *pendingTxPool) remove(ctx context.Context, txHash primitives.Sha256, removalReason protocol.TransactionStatus) *primitives.NodeAddress {
+	defer p.writeJournal()
+	return p.removeUnderLock(ctx, txHash, removalReason)
+}
+
+func (p *pendingTxPool) removeUnderLock(ctx context.Context, txHash primitives.Sha256, removalReason protocol.TransactionStatus) *primitives.NodeAddress {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
+	pendingTx := p.removeUnderMutex(txHash)
+	if pendingTx == nil {
+		return nil
+	}
+
+	if p.onTransactionRemoved != nil {
+		p.onTransactionRemoved(ctx, txHash, removalReason)
+	}
+	return &pendingTx.gatewayNodeAddress
+}
+
+func (p *pendingTxPool) removeUnderMutex(txHash primitives.Sha256) *pendingTransaction {
 	pendingTx, ok := p.transactionsByHash[txHash.KeyForMap()]
-	if ok {
-		delete(p.transactionsByHash, txHash.KeyForMap())
-		p.currentSizeInBytes -= sizeOfSignedTransaction(pendingTx.transaction)
-		p.transactionList.Remove(pendingTx.listElement)
+	if !ok {
+		return nil
+	}
 
-		if p.onTransactionRemoved != nil {
-			p.onTransactionRemoved(ctx, txHash, removalReason)
-		}
+	delete(p.transactionsByHash, txHash.KeyForMap())
+	p.currentSizeInBytes -= sizeOfSignedTransaction(pendingTx.transaction)
+	p.transactionList.Remove(pendingTx.listElement)
+	p.classLists[pendingTx.class].Remove(pendingTx.classElement)
+	p.removeSignerUsageUnderMutex(pendingTx, sizeOfSignedTransaction(pendingTx.transaction))
 
-		p.metrics.transactionCountGauge.Dec()
-		p.metrics.poolSizeInBytesGauge.SubUint32(sizeOfSignedTransaction(pendingTx.transaction))
-		p.metrics.transactionServiceTime.RecordSince(pendingTx.timeAdded)
+	p.metrics.transactionCountGauge.Dec()
+	p.metrics.classTransactionCount[pendingTx.class].Dec()
+	p.metrics.poolSizeInBytesGauge.SubUint32(sizeOfSignedTransaction(pendingTx.transaction))
+	p.metrics.transactionServiceTime.RecordSince(pendingTx.timeAdded)
 
-		return &pendingTx.gatewayNodeAddress
+	if p.journal != nil {
+		p.journalQueue = append(p.journalQueue, &journalRecord{removed: txHash})
 	}
 
-	return nil
+	return pendingTx
+}
+
+// replaces a pending transaction with another one in a single step, so the replacement takes the place (and the signer
+// quota and nonce) of the removed transaction. If the replacement is rejected the removed transaction is added back,
+// as the newest transaction, and those waiting for it are not notified
+func (p *pendingTxPool) replace(ctx context.Context, txHash primitives.Sha256, removalReason protocol.TransactionStatus, transaction *protocol.SignedTransaction, gatewayNodeAddress primitives.NodeAddress) (primitives.Sha256, *ErrTransactionRejected) {
+	defer p.writeJournal()
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	removed := p.removeUnderMutex(txHash)
+	key, err := p.addUnderMutex(transaction, gatewayNodeAddress)
+	if err != nil {
+		if removed != nil {
+			p.addUnderMutex(removed.transaction, removed.gatewayNodeAddress)
+		}
+		return nil, err
+	}
+
+	if removed != nil && p.onTransactionRemoved != nil {
+		p.onTransactionRemoved(ctx, txHash, removalReason)
+	}
+	return key, nil
 }
 
+// the batch is grouped by priority class, highest first, and in arrival order within each class (except that with
+// signer sequencing each signer's transactions are in nonce order)
 func (p *pendingTxPool) getBatch(maxNumOfTransactions uint32, sizeLimitInBytes uint32) (txs Transactions) {
 	p.lock.RLock()
 	defer p.lock.RUnlock()
 
-	var sizeInBytes uint32
-
-	e := p.transactionList.Back()
-	for {
-		if e == nil {
-			break
+	picked := make(map[*pendingTransaction]bool)
+	pickedBySigner := make(map[string]uint32)
+	pickedSequenceBySigner := make(map[string]int) // the signer's next transaction to pick is sequence[pickedSequenceBySigner[signer]]
+	highestClassBySigner := make(map[string]PriorityClass)
+	var sizeExceeded [NUM_PRIORITY_CLASSES]bool
+	var pickedPerClass [NUM_PRIORITY_CLASSES]int
+	var count, sizeInBytes uint32
+
+	// takes up to limit transactions of a class, oldest first, skipping signers who already have signerShare transactions
+	// in the batch (unless signerShare is 0); a class is done once its next transaction does not fit the size limit
+	pick := func(class PriorityClass, limit uint32, signerShare uint32) {
+		taken := uint32(0)
+		hasRoom := func() bool {
+			return !sizeExceeded[class] && taken < limit && count < maxNumOfTransactions
 		}
-
-		if uint32(len(txs)) >= maxNumOfTransactions {
-			break
+		mayTake := func(ptx *pendingTransaction) bool {
+			return signerShare == 0 || pickedBySigner[ptx.signer] < signerShare
+		}
+		take := func(ptx *pendingTransaction) {
+			txSize := sizeOfSignedTransaction(ptx.transaction)
+			if sizeLimitInBytes > 0 && sizeInBytes+txSize > sizeLimitInBytes {
+				sizeExceeded[class] = true
+				return
+			}
+
+			sizeInBytes += txSize
+			count++
+			taken++
+			picked[ptx] = true
+			pickedPerClass[class]++
+			pickedBySigner[ptx.signer]++
+			if ptx.sequenced {
+				pickedSequenceBySigner[ptx.signer]++
+				highestClassBySigner[ptx.signer] = class
+			}
 		}
 
-		tx := e.Value.(*protocol.SignedTransaction)
-		//
-		txSize := sizeOfSignedTransaction(tx)
-		if sizeLimitInBytes > 0 && sizeInBytes+txSize > sizeLimitInBytes {
-			break
+		for e := p.classLists[class].Back(); e != nil && hasRoom(); e = e.Prev() {
+			ptx := e.Value.(*pendingTransaction)
+			if picked[ptx] || !mayTake(ptx) {
+				continue
+			}
+			if !ptx.sequenced {
+				take(ptx)
+				continue
+			}
+
+			// with sequencing a signer's picked transactions are always its lowest pending nonces, and their classes
+			// may not decrease since the batch is emitted grouped by class; so a signer is picked from the head of its
+			// sequence, followed by its successors for as long as they are of this class
+			sequence := p.signers[ptx.signer].sequence
+			if sequence[pickedSequenceBySigner[ptx.signer]] != ptx || class < highestClassBySigner[ptx.signer] {
+				continue
+			}
+			for i := pickedSequenceBySigner[ptx.signer]; i < len(sequence) && sequence[i].class == class && mayTake(sequence[i]) && hasRoom(); i++ {
+				take(sequence[i])
+			}
 		}
+	}
 
-		sizeInBytes += txSize
-		txs = append(txs, tx)
+	signerShare := p.signerQuotas.batchShare(maxNumOfTransactions, len(p.signers))
 
-		e = e.Prev()
+	// anti-starvation: every class first gets its minimum share of the batch
+	if share := maxNumOfTransactions * p.policy.MinSharePercent() / 100; share > 0 {
+		for class := PriorityClass(0); class < NUM_PRIORITY_CLASSES; class++ {
+			pick(class, share, signerShare)
+		}
+	}
+	for class := PriorityClass(0); class < NUM_PRIORITY_CLASSES; class++ {
+		pick(class, maxNumOfTransactions, signerShare)
+	}
+	// what is left over once every signer got its share goes to whoever has more pending
+	if signerShare > 0 {
+		for class := PriorityClass(0); class < NUM_PRIORITY_CLASSES; class++ {
+			pick(class, maxNumOfTransactions, 0)
+		}
+	}
 
-		p.transactionPickedFromQueueUnderMutex(tx)
+	for class := PriorityClass(0); class < NUM_PRIORITY_CLASSES; class++ {
+		var classTxs Transactions
+		for e := p.classLists[class].Back(); e != nil && len(classTxs) < pickedPerClass[class]; e = e.Prev() {
+			if ptx := e.Value.(*pendingTransaction); picked[ptx] {
+				p.transactionPickedFromQueueUnderMutex(ptx)
+				classTxs = append(classTxs, ptx.transaction)
+			}
+		}
+		if p.sequencing {
+			sortBySignerSequence(classTxs)
+		}
+		txs = append(txs, classTxs...)
+		p.metrics.classTransactionsPicked[class].Add(int64(len(classTxs)))
 	}
 
 	return
@@ -179,6 +388,26 @@ func (p *pendingTxPool) get(txHash primitives.Sha256) *protocol.SignedTransactio
 	return nil
 }
 
+func (p *pendingTxPool) transactionsFrom(gatewayNodeAddress primitives.NodeAddress) Transactions {
+	p.lock.RLock()
+	var ptxs []*pendingTransaction
+	for _, ptx := range p.transactionsByHash {
+		if ptx.gatewayNodeAddress.Equal(gatewayNodeAddress) {
+			ptxs = append(ptxs, ptx)
+		}
+	}
+	p.lock.RUnlock()
+
+	sort.Slice(ptxs, func(i, j int) bool {
+		return ptxs[i].timeAdded.Before(ptxs[j].timeAdded)
+	})
+	txs := make(Transactions, 0, len(ptxs))
+	for _, ptx := range ptxs {
+		txs = append(txs, ptx.transaction)
+	}
+	return txs
+}
+
 func (p *pendingTxPool) clearTransactionsOlderThan(ctx context.Context, timestamp primitives.TimestampNano) {
 	for e := p.lastTransaction(); e != nil; e = p.prevTransaction(e) {
 		tx := e.Value.(*protocol.SignedTransaction)
@@ -201,11 +430,122 @@ func (p *pendingTxPool) prevTransaction(e *list.Element) *list.Element {
 	return e.Prev()
 }
 
-func (p *pendingTxPool) transactionPickedFromQueueUnderMutex(tx *protocol.SignedTransaction) {
-	txHash := digest.CalcTxHash(tx.Transaction())
-	ptx, found := p.transactionsByHash[txHash.KeyForMap()]
-	if found {
-		p.metrics.transactionSpentInQueue.RecordSince(ptx.timeAdded)
+func (p *pendingTxPool) transactionPickedFromQueueUnderMutex(ptx *pendingTransaction) {
+	p.metrics.transactionSpentInQueue.RecordSince(ptx.timeAdded)
+	p.metrics.classTimeSpentInQueue[ptx.class].RecordSince(ptx.timeAdded)
+}
+
+// returns false if signer sequencing is on and the signer already has a pending transaction with this nonce
+func (p *pendingTxPool) addSignerUsageUnderMutex(pendingTx *pendingTransaction, size uint32) bool {
+	signer := pendingTx.signer
+	usage, found := p.signers[signer]
+	if !found {
+		usage = &signerUsage{}
+	}
+	if pendingTx.sequenced {
+		sequence, inserted := insertSignerSequence(usage.sequence, pendingTx)
+		if !inserted {
+			return false
+		}
+		usage.sequence = sequence
+	}
+	if !found {
+		p.signers[signer] = usage
+		p.metrics.signerCount.Inc()
+	}
+	usage.transactions++
+	usage.bytes += size
+	return true
+}
+
+func (p *pendingTxPool) removeSignerUsageUnderMutex(pendingTx *pendingTransaction, size uint32) {
+	signer := pendingTx.signer
+	usage, found := p.signers[signer]
+	if !found {
+		return
+	}
+	if pendingTx.sequenced {
+		usage.sequence = removeSignerSequence(usage.sequence, pendingTx)
+	}
+	usage.transactions--
+	usage.bytes -= size
+	if usage.transactions == 0 {
+		delete(p.signers, signer)
+		p.metrics.signerCount.Dec()
+	}
+}
+
+// starts journaling the pool, the journal is rewritten to hold exactly the transactions currently pending
+func (p *pendingTxPool) attachJournal(journal adapter.PendingTransactionJournal, logger log.Logger) {
+	p.journalLock.Lock()
+	defer p.journalLock.Unlock()
+
+	p.lock.Lock()
+	p.journal = journal
+	p.journalLogger = logger
+	p.lock.Unlock()
+
+	p.rewriteJournalUnderJournalLock(journal)
+}
+
+// writes the queued records in the order the pool changed, and compacts the journal once it grew too long
+func (p *pendingTxPool) writeJournal() {
+	p.journalLock.Lock()
+	defer p.journalLock.Unlock()
+
+	p.lock.Lock()
+	journal, records := p.journal, p.journalQueue
+	p.journalQueue = nil
+	pendingCount := len(p.transactionsByHash)
+	p.lock.Unlock()
+
+	if journal == nil {
+		return
+	}
+
+	for _, record := range records {
+		if record.added != nil {
+			p.handleJournalError(journal.Add(record.added))
+		} else {
+			p.handleJournalError(journal.Remove(record.removed))
+		}
+	}
+
+	if journal.RecordCount() > 2*pendingCount+PENDING_JOURNAL_COMPACTION_SLACK {
+		p.rewriteJournalUnderJournalLock(journal)
+	}
+}
+
+// the snapshot holds every change queued so far, so the queue is dropped along with taking it
+func (p *pendingTxPool) rewriteJournalUnderJournalLock(journal adapter.PendingTransactionJournal) {
+	p.lock.Lock()
+	pending := make([]*pendingTransaction, 0, len(p.transactionsByHash))
+	for _, ptx := range p.transactionsByHash {
+		pending = append(pending, ptx)
+	}
+	p.journalQueue = nil
+	p.lock.Unlock()
+
+	p.handleJournalError(journal.Rewrite(journaledTransactionsOf(pending)))
+}
+
+// oldest first, so that the pool keeps its order when restored
+func journaledTransactionsOf(pending []*pendingTransaction) []*adapter.JournaledTransaction {
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].timeAdded.Before(pending[j].timeAdded)
+	})
+	transactions := make([]*adapter.JournaledTransaction, len(pending))
+	for i, ptx := range pending {
+		transactions[i] = &adapter.JournaledTransaction{Transaction: ptx.transaction, GatewayNodeAddress: ptx.gatewayNodeAddress}
+	}
+	return transactions
+}
+
+// a journal failure must not fail the pool, the transaction is just not guaranteed to survive a restart
+func (p *pendingTxPool) handleJournalError(err error) {
+	if err != nil {
+		p.metrics.journalErrors.Inc()
+		p.journalLogger.Error("failed writing to pending transactions journal", log.Error(err))
 	}
 }
 
@@ -0,0 +1,163 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package transactionpool
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/instrumentation/metric"
+	"github.com/orbs-network/orbs-network-go/services/transactionpool/adapter"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-network-go/test/crypto/keys"
+	"github.com/orbs-network/orbs-network-go/test/with"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type memoryJournal struct {
+	transactions []*adapter.JournaledTransaction
+	records      int
+}
+
+func (j *memoryJournal) Add(transaction *adapter.JournaledTransaction) error {
+	j.transactions = append(j.transactions, transaction)
+	j.records++
+	return nil
+}
+
+func (j *memoryJournal) Remove(txHash primitives.Sha256) error {
+	for i, transaction := range j.transactions {
+		if digest.CalcTxHash(transaction.Transaction.Transaction()).Equal(txHash) {
+			j.transactions = append(j.transactions[:i], j.transactions[i+1:]...)
+			break
+		}
+	}
+	j.records++
+	return nil
+}
+
+func (j *memoryJournal) Load() ([]*adapter.JournaledTransaction, error) {
+	return j.transactions, nil
+}
+
+func (j *memoryJournal) Rewrite(transactions []*adapter.JournaledTransaction) error {
+	j.transactions = append([]*adapter.JournaledTransaction{}, transactions...)
+	j.records = len(transactions)
+	return nil
+}
+
+func (j *memoryJournal) RecordCount() int {
+	return j.records
+}
+
+func TestPendingTransactionPoolJournalsAddedAndRemovedTransactions(t *testing.T) {
+	with.Logging(t, func(harness *with.LoggingHarness) {
+		p := makePendingPool()
+		journal := &memoryJournal{}
+		p.attachJournal(journal, harness.Logger)
+
+		tx1 := builders.TransferTransaction().Build()
+		tx2 := builders.TransferTransaction().Build()
+		k1, _ := p.add(tx1, nodeAddress)
+		add(p, tx2)
+		p.remove(context.Background(), k1, protocol.TRANSACTION_STATUS_COMMITTED)
+
+		require.Len(t, journal.transactions, 1)
+		require.Equal(t, tx2, journal.transactions[0].Transaction)
+		require.Equal(t, 3, journal.RecordCount())
+	})
+}
+
+func TestPendingTransactionPoolCompactsJournal(t *testing.T) {
+	with.Logging(t, func(harness *with.LoggingHarness) {
+		p := makePendingPool()
+		journal := &memoryJournal{}
+		p.attachJournal(journal, harness.Logger)
+
+		remaining := builders.TransferTransaction().Build()
+		add(p, remaining)
+		for i := 0; i < PENDING_JOURNAL_COMPACTION_SLACK; i++ {
+			k, _ := p.add(builders.TransferTransaction().Build(), nodeAddress)
+			p.remove(context.Background(), k, protocol.TRANSACTION_STATUS_COMMITTED)
+		}
+
+		require.True(t, journal.RecordCount() < PENDING_JOURNAL_COMPACTION_SLACK, "journal was not compacted")
+		require.Len(t, journal.transactions, 1)
+		require.Equal(t, remaining, journal.transactions[0].Transaction)
+	})
+}
+
+func TestRestorePendingPoolDropsExpiredAndCommittedTransactions(t *testing.T) {
+	with.Logging(t, func(harness *with.LoggingHarness) {
+		s := &Service{
+			clock:         adapter.NewSystemClock(),
+			config:        config.ForTransactionPoolTests(100000, keys.EcdsaSecp256K1KeyPairForTests(8), 0),
+			logger:        harness.Logger,
+			pendingPool:   makePendingPool(),
+			committedPool: NewCommittedPool(func() time.Duration { return 3 * time.Minute }, metric.NewRegistry()),
+		}
+		index := &committedIndexForTests{txHashes: make(map[string]bool)}
+		s.committedIndex = index
+
+		pending1 := builders.TransferTransaction().Build()
+		pending2 := builders.TransferTransaction().Build()
+		expired := builders.TransferTransaction().WithTimestamp(time.Now().Add(-31 * time.Minute)).Build()
+		committed := builders.TransferTransaction().Build()
+		require.NoError(t, index.Add(1, []primitives.Sha256{digest.CalcTxHash(committed.Transaction())}))
+
+		journal := &memoryJournal{}
+		for _, tx := range []*protocol.SignedTransaction{pending1, expired, committed, pending2} {
+			require.NoError(t, journal.Add(&adapter.JournaledTransaction{Transaction: tx, GatewayNodeAddress: nodeAddress}))
+		}
+
+		s.restorePendingPool(journal)
+
+		require.Equal(t, Transactions{pending1, pending2}, s.pendingPool.getBatch(10, 0), "restored pool should hold the live transactions in their original order")
+		require.Len(t, journal.transactions, 2, "journal should be rewritten with the restored transactions only")
+
+		k, _ := s.pendingPool.add(builders.TransferTransaction().Build(), nodeAddress)
+		require.NotNil(t, k)
+		require.Len(t, journal.transactions, 3, "pool should keep journaling after it was restored")
+	})
+}
+
+type blockingJournal struct {
+	memoryJournal
+	writing chan struct{}
+	release chan struct{}
+}
+
+func (j *blockingJournal) Add(transaction *adapter.JournaledTransaction) error {
+	j.writing <- struct{}{}
+	<-j.release
+	return j.memoryJournal.Add(transaction)
+}
+
+func TestPendingTransactionPoolDoesNotHoldItsLockWhileWritingJournal(t *testing.T) {
+	with.Logging(t, func(harness *with.LoggingHarness) {
+		p := makePendingPool()
+		journal := &blockingJournal{writing: make(chan struct{}), release: make(chan struct{})}
+		p.attachJournal(journal, harness.Logger)
+
+		tx := builders.TransferTransaction().Build()
+		added := make(chan struct{})
+		go func() {
+			add(p, tx)
+			close(added)
+		}()
+
+		<-journal.writing
+		require.True(t, p.has(tx), "pool should be readable while the journal is written")
+		close(journal.release)
+		<-added
+		require.Len(t, journal.transactions, 1)
+	})
+}
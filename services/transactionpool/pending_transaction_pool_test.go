@@ -172,7 +172,7 @@ func TestPendingPoolNotifiesOnNewTransactions(t *testing.T) {
 	var called bool
 	p := NewPendingPool(func() uint32 { return 100000 }, metric.NewRegistry(), func() {
 		called = true
-	})
+	}, NewFifoOrderingPolicy())
 
 	p.add(builders.Transaction().Build(), nodeAddress)
 
@@ -222,5 +222,5 @@ func add(p *pendingTxPool, txs ...*protocol.SignedTransaction) {
 
 func makePendingPool() *pendingTxPool {
 	metricFactory := metric.NewRegistry()
-	return NewPendingPool(func() uint32 { return 100000 }, metricFactory, func() {})
+	return NewPendingPool(func() uint32 { return 100000 }, metricFactory, func() {}, NewFifoOrderingPolicy())
 }
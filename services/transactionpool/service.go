@@ -41,18 +41,27 @@ type Service struct {
 		handlers []handlers.TransactionResultsHandler
 	}
 
+	signatureVerifier struct {
+		sync.RWMutex
+		verifier TransactionSignatureVerifier
+	}
+
 	lastCommitted struct {
 		sync.RWMutex
 		blockHeight primitives.BlockHeight
 		timestamp   primitives.TimestampNano
 	}
 
+	orderingPolicy                      OrderingPolicy
 	pendingPool                         *pendingTxPool
 	committedPool                       *committedTxPool
+	cancelledTransactions               *cancelledTransactions
 	blockTracker                        *synchronization.BlockTracker
 	transactionForwarder                *transactionForwarder
 	transactionWaiter                   *transactionWaiter
 	validationContext                   *validationContext
+	admissionChain                      *admissionChain
+	committedIndex                      adapter.CommittedTransactionIndex
 	addNewTransactionConcurrencyLimiter *requestConcurrencyLimiter
 
 	metrics struct {
@@ -60,6 +69,8 @@ type Service struct {
 		lastCommittedTimestamp *metric.Gauge
 		commitRate             *metric.Rate
 		commitCount            *metric.Gauge
+		cancelledCount         *metric.Gauge
+		indexedDuplicates      *metric.Gauge
 	}
 
 	addCommitLock sync.RWMutex
@@ -73,9 +84,10 @@ func (s *Service) lastCommittedBlockHeightAndTime() (primitives.BlockHeight, pri
 
 func (s *Service) createValidationContext() *validationContext {
 	return &validationContext{
-		expiryWindow:           s.config.TransactionExpirationWindow(),
-		nodeSyncRejectInterval: s.config.TransactionPoolNodeSyncRejectTime(),
-		futureTimestampGrace:   s.config.TransactionPoolFutureTimestampGraceTimeout(),
-		virtualChainId:         s.config.VirtualChainId(),
+		expiryWindow:                    s.config.TransactionExpirationWindow(),
+		nodeSyncRejectInterval:          s.config.TransactionPoolNodeSyncRejectTime(),
+		futureTimestampGrace:            s.config.TransactionPoolFutureTimestampGraceTimeout(),
+		virtualChainId:                  s.config.VirtualChainId(),
+		provisionalSignerSchemesEnabled: s.config.TransactionPoolProvisionalSignerSchemesEnabled(),
 	}
 }
@@ -0,0 +1,80 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package transactionpool
+
+import (
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+)
+
+type signerQuotaConfig interface {
+	TransactionPoolSignerQuotasEnabled() bool
+	TransactionPoolMaxPendingTransactionsPerSigner() uint32
+	TransactionPoolMaxPendingBytesPerSigner() uint32
+}
+
+// limits how much of the pending pool a single signer may fill, and spreads batches for ordering between signers
+// so that a flooding signer does not push everyone else's transactions out of blocks
+type signerQuotas struct {
+	maxTransactions func() uint32
+	maxBytes        func() uint32
+}
+
+type signerUsage struct {
+	transactions uint32
+	bytes        uint32
+	sequence     []*pendingTransaction // sorted by nonce, only kept with signer sequencing
+}
+
+// returns nil (which imposes no limits) if signer quotas are disabled
+func newSignerQuotas(config signerQuotaConfig) *signerQuotas {
+	if !config.TransactionPoolSignerQuotasEnabled() {
+		return nil
+	}
+
+	return &signerQuotas{
+		maxTransactions: config.TransactionPoolMaxPendingTransactionsPerSigner,
+		maxBytes:        config.TransactionPoolMaxPendingBytesPerSigner,
+	}
+}
+
+// usage is nil for a signer with no pending transactions
+func (q *signerQuotas) allows(usage *signerUsage, size uint32) bool {
+	if q == nil {
+		return true
+	}
+	if usage == nil {
+		usage = &signerUsage{}
+	}
+
+	if max := q.maxTransactions(); max > 0 && usage.transactions+1 > max {
+		return false
+	}
+	if max := q.maxBytes(); max > 0 && usage.bytes+size > max {
+		return false
+	}
+	return true
+}
+
+// the number of transactions each signer may have in a batch before other signers' transactions are considered,
+// 0 if batches are not shared between signers
+func (q *signerQuotas) batchShare(maxNumOfTransactions uint32, numOfSigners int) uint32 {
+	if q == nil || numOfSigners <= 1 {
+		return 0
+	}
+	return (maxNumOfTransactions + uint32(numOfSigners) - 1) / uint32(numOfSigners)
+}
+
+// signers are told apart by their client address, which is how contracts identify them, so that the same account
+// shares its quota and nonce sequence however its signer is encoded (e.g. the order of the keys of a multi signature)
+func signerKeyOf(transaction *protocol.SignedTransaction) string {
+	clientAddress, err := digest.CalcClientAddressOfSigner(transaction.Transaction().Signer())
+	if err != nil { // rejected by the signer scheme validation before it is added to the pool
+		return string(transaction.Transaction().Signer().Raw())
+	}
+	return string(clientAddress)
+}
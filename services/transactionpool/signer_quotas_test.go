@@ -0,0 +1,127 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package transactionpool
+
+import (
+	"context"
+	cryptoKeys "github.com/orbs-network/orbs-network-go/crypto/keys"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-network-go/test/crypto/keys"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type signerQuotaConfigForTests struct {
+	maxTransactions uint32
+	maxBytes        uint32
+}
+
+func (c *signerQuotaConfigForTests) TransactionPoolSignerQuotasEnabled() bool {
+	return true
+}
+
+func (c *signerQuotaConfigForTests) TransactionPoolMaxPendingTransactionsPerSigner() uint32 {
+	return c.maxTransactions
+}
+
+func (c *signerQuotaConfigForTests) TransactionPoolMaxPendingBytesPerSigner() uint32 {
+	return c.maxBytes
+}
+
+func makePendingPoolWithSignerQuotas(config *signerQuotaConfigForTests) *pendingTxPool {
+	p := makePendingPool()
+	p.signerQuotas = newSignerQuotas(config)
+	return p
+}
+
+func transactionsOfSigner(setIndex int, count int) (txs Transactions) {
+	for i := 0; i < count; i++ {
+		txs = append(txs, builders.TransferTransaction().WithEd25519Signer(keys.Ed25519KeyPairForTests(setIndex)).Build())
+	}
+	return
+}
+
+func TestPendingTransactionPoolRejectsSignerOverItsTransactionQuota(t *testing.T) {
+	p := makePendingPoolWithSignerQuotas(&signerQuotaConfigForTests{maxTransactions: 2})
+
+	flooder := transactionsOfSigner(1, 3)
+	k, err := p.add(flooder[0], nodeAddress)
+	require.Nil(t, err)
+	add(p, flooder[1])
+
+	_, err = p.add(flooder[2], nodeAddress)
+	require.NotNil(t, err, "signer should not be able to exceed its quota")
+	require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_CONGESTION, err.TransactionStatus)
+
+	_, err = p.add(transactionsOfSigner(2, 1)[0], nodeAddress)
+	require.Nil(t, err, "other signers should not be affected by a signer exceeding its quota")
+
+	p.remove(context.Background(), k, protocol.TRANSACTION_STATUS_COMMITTED)
+	_, err = p.add(flooder[2], nodeAddress)
+	require.Nil(t, err, "quota should be freed once a transaction leaves the pool")
+}
+
+func TestPendingTransactionPoolRejectsSignerOverItsBytesQuota(t *testing.T) {
+	flooder := transactionsOfSigner(1, 2)
+	p := makePendingPoolWithSignerQuotas(&signerQuotaConfigForTests{maxBytes: sizeOfSignedTransaction(flooder[0]) + 1})
+
+	add(p, flooder[0])
+	_, err := p.add(flooder[1], nodeAddress)
+	require.NotNil(t, err, "signer should not be able to exceed its bytes quota")
+}
+
+func TestPendingTransactionPoolRejectsFirstTransactionOfSignerOverItsBytesQuota(t *testing.T) {
+	tx := transactionsOfSigner(1, 1)[0]
+	p := makePendingPoolWithSignerQuotas(&signerQuotaConfigForTests{maxBytes: sizeOfSignedTransaction(tx) - 1})
+
+	_, err := p.add(tx, nodeAddress)
+	require.NotNil(t, err, "a single transaction should not be able to exceed its signer's bytes quota")
+	require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_CONGESTION, err.TransactionStatus)
+}
+
+func TestPendingTransactionPoolGetBatchSharesBatchBetweenSigners(t *testing.T) {
+	p := makePendingPoolWithSignerQuotas(&signerQuotaConfigForTests{})
+
+	flooder := transactionsOfSigner(1, 10)
+	other := transactionsOfSigner(2, 2)
+	add(p, flooder...)
+	add(p, other...)
+
+	batch := p.getBatch(4, 0)
+	require.Equal(t, Transactions{flooder[0], flooder[1], other[0], other[1]}, batch, "each signer should get its share of the batch, in arrival order")
+
+	batch = p.getBatch(8, 0)
+	require.Len(t, batch, 8, "what other signers do not use should go to the flooding signer")
+	require.Equal(t, flooder[:6], batch[:6])
+}
+
+func TestPendingTransactionPoolGetBatchWithoutSignerQuotasKeepsArrivalOrder(t *testing.T) {
+	p := makePendingPool()
+
+	flooder := transactionsOfSigner(1, 10)
+	add(p, flooder...)
+	add(p, transactionsOfSigner(2, 2)...)
+
+	require.Equal(t, flooder[:4], p.getBatch(4, 0))
+}
+
+func TestPendingTransactionPoolSharesTheQuotaOfSignersWithTheSameClientAddress(t *testing.T) {
+	p := makePendingPoolWithSignerQuotas(&signerQuotaConfigForTests{maxTransactions: 1})
+
+	mainNetBuilder := builders.TransferTransaction().WithEd25519Signer(keys.Ed25519KeyPairForTests(1)).Builder()
+	mainNetBuilder.Transaction.Signer.Eddsa.NetworkType = protocol.NETWORK_TYPE_MAIN_NET
+	add(p, builders.TransferTransaction().WithEd25519Signer(keys.Ed25519KeyPairForTests(1)).Build())
+	_, err := p.add(mainNetBuilder.Build(), nodeAddress)
+	require.NotNil(t, err, "an ed25519 signer should share its quota across network types")
+
+	keyPairs := []*cryptoKeys.Ed25519KeyPair{keys.Ed25519KeyPairForTests(2), keys.Ed25519KeyPairForTests(3)}
+	add(p, builders.TransferTransaction().WithMultiSigSigner(2, keyPairs, 0, 1).Build())
+	reordered := builders.TransferTransaction().WithMultiSigSigner(2, []*cryptoKeys.Ed25519KeyPair{keyPairs[1], keyPairs[0]}, 0, 1).Build()
+	_, err = p.add(reordered, nodeAddress)
+	require.NotNil(t, err, "a multi signature signer should share its quota whatever the order of its keys")
+}
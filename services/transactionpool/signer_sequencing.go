@@ -0,0 +1,94 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package transactionpool
+
+import (
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
+	"sort"
+)
+
+// With signer sequencing a signed transaction may carry a per-signer nonce (see digest.SignerNonce) which the virtual
+// machine enforces against state. The pool keeps the pending transactions of every signer which carry a nonce in a
+// queue sorted by nonce, and offers them for ordering from the head of the queue. A transaction whose nonce is ahead of
+// its signer's executed sequence fails pre-order checks as pending and is held in the pool (rather than dropped) until
+// its predecessors are executed or it expires. Transactions which carry no nonce are not sequenced.
+
+// the nonce of a pending transaction, which was verified when the transaction was added
+func signerNonceOf(transaction *protocol.SignedTransaction) (uint64, bool) {
+	nonce, found, err := digest.SignerNonce(transaction.Transaction())
+	return nonce, found && err == nil
+}
+
+// inserts a pending transaction into its signer's sequence, which is sorted by nonce, returns false if the sequence
+// already holds a transaction with its nonce
+func insertSignerSequence(sequence []*pendingTransaction, pendingTx *pendingTransaction) ([]*pendingTransaction, bool) {
+	i := sort.Search(len(sequence), func(i int) bool { return sequence[i].nonce >= pendingTx.nonce })
+	if i < len(sequence) && sequence[i].nonce == pendingTx.nonce {
+		return sequence, false
+	}
+	sequence = append(sequence, nil)
+	copy(sequence[i+1:], sequence[i:])
+	sequence[i] = pendingTx
+	return sequence, true
+}
+
+func removeSignerSequence(sequence []*pendingTransaction, pendingTx *pendingTransaction) []*pendingTransaction {
+	i := sort.Search(len(sequence), func(i int) bool { return sequence[i].nonce >= pendingTx.nonce })
+	if i < len(sequence) && sequence[i] == pendingTx {
+		return append(sequence[:i], sequence[i+1:]...)
+	}
+	return sequence
+}
+
+// reorders the transactions of each signer which carry a nonce by nonce, keeping the positions they occupy
+func sortBySignerSequence(transactions Transactions) {
+	positionsBySigner := make(map[string][]int)
+	for i, tx := range transactions {
+		if _, sequenced := signerNonceOf(tx); sequenced {
+			signer := signerKeyOf(tx)
+			positionsBySigner[signer] = append(positionsBySigner[signer], i)
+		}
+	}
+
+	for _, positions := range positionsBySigner {
+		signerTransactions := make(Transactions, len(positions))
+		for i, position := range positions {
+			signerTransactions[i] = transactions[position]
+		}
+		sort.SliceStable(signerTransactions, func(i, j int) bool {
+			first, _ := signerNonceOf(signerTransactions[i])
+			second, _ := signerNonceOf(signerTransactions[j])
+			return first < second
+		})
+		for i, position := range positions {
+			transactions[position] = signerTransactions[i]
+		}
+	}
+}
+
+// returns an error for the first transaction with a malformed nonce, or whose nonce does not directly follow that of
+// an earlier transaction of its signer
+func validateSignerSequences(transactions Transactions) error {
+	lastBySigner := make(map[string]uint64)
+	for i, tx := range transactions {
+		nonce, found, err := digest.SignerNonce(tx.Transaction())
+		if err != nil {
+			return errors.Wrapf(err, "transaction %d", i)
+		}
+		if !found {
+			continue
+		}
+		signer := signerKeyOf(tx)
+		if last, found := lastBySigner[signer]; found && nonce != last+1 {
+			return errors.Errorf("transaction %d with nonce %d does not follow nonce %d of the same signer", i, nonce, last)
+		}
+		lastBySigner[signer] = nonce
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package transactionpool
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-network-go/test/crypto/keys"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func makePendingPoolWithSignerSequencing() *pendingTxPool {
+	p := makePendingPool()
+	p.sequencing = true
+	return p
+}
+
+func transactionOfSignerWithNonce(setIndex int, nonce uint64) *protocol.SignedTransaction {
+	return builders.TransferTransaction().WithEd25519Signer(keys.Ed25519KeyPairForTests(setIndex)).WithSignerNonce(nonce).Build()
+}
+
+func TestPendingTransactionPoolWithSignerSequencingRejectsPendingNonceOfSameSigner(t *testing.T) {
+	p := makePendingPoolWithSignerSequencing()
+
+	add(p, transactionOfSignerWithNonce(1, 1))
+
+	_, err := p.add(builders.TransferTransaction().WithEd25519Signer(keys.Ed25519KeyPairForTests(1)).WithAmountAndTargetAddress(17, builders.ClientAddressForEd25519SignerForTests(3)).WithSignerNonce(1).Build(), nodeAddress)
+	require.NotNil(t, err, "a second transaction with the same nonce should be rejected")
+	require.Equal(t, protocol.TRANSACTION_STATUS_DUPLICATE_TRANSACTION_ALREADY_PENDING, err.TransactionStatus)
+
+	_, err = p.add(transactionOfSignerWithNonce(2, 1), nodeAddress)
+	require.Nil(t, err, "another signer may use the same nonce")
+}
+
+func TestPendingTransactionPoolWithSignerSequencingRejectsMisplacedNonceButNotTransactionWithoutNonce(t *testing.T) {
+	p := makePendingPoolWithSignerSequencing()
+
+	_, err := p.add(builders.TransferTransaction().WithArgs(uint64(10), digest.SignerNonceArgument(1)).Build(), nodeAddress)
+	require.NotNil(t, err, "a transaction with a nonce which is not its first argument should be rejected")
+	require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_VIRTUAL_CHAIN_PRE_ORDER, err.TransactionStatus)
+
+	_, err = p.add(builders.TransferTransaction().Build(), nodeAddress)
+	require.Nil(t, err, "a transaction without a nonce should not be sequenced")
+}
+
+func TestPendingTransactionPoolWithSignerSequencingHoldsTransactionsUntilTheirPredecessorsArePicked(t *testing.T) {
+	p := makePendingPoolWithSignerSequencing()
+
+	third := transactionOfSignerWithNonce(1, 3)
+	second := transactionOfSignerWithNonce(1, 2)
+	add(p, third, second)
+
+	txs := p.getBatch(1, 0)
+	require.Equal(t, Transactions{second}, txs, "the lowest nonce of the signer should be offered first even though it arrived last")
+
+	txs = p.getBatch(10, 0)
+	require.Equal(t, Transactions{second, third}, txs, "the signer's transactions should be offered in nonce order")
+
+	p.remove(context.Background(), digest.CalcTxHash(second.Transaction()), protocol.TRANSACTION_STATUS_COMMITTED)
+	first := transactionOfSignerWithNonce(1, 1)
+	add(p, first)
+
+	txs = p.getBatch(1, 0)
+	require.Equal(t, Transactions{first}, txs, "a new lower nonce should be offered before pending higher ones")
+}
+
+func TestPendingTransactionPoolWithSignerSequencingPicksSignerSequencesWithinTheirShare(t *testing.T) {
+	p := makePendingPoolWithSignerSequencing()
+	p.signerQuotas = newSignerQuotas(&signerQuotaConfigForTests{})
+
+	var flooder Transactions
+	for nonce := uint64(5); nonce > 0; nonce-- {
+		flooder = append(flooder, transactionOfSignerWithNonce(1, nonce))
+	}
+	other := transactionsOfSigner(2, 2)
+	add(p, flooder...)
+	add(p, other...)
+
+	txs := p.getBatch(4, 0)
+	require.Equal(t, Transactions{flooder[4], flooder[3], other[0], other[1]}, txs, "the signer's lowest nonces should be picked within its share")
+
+	txs = p.getBatch(7, 0)
+	require.Equal(t, Transactions{flooder[4], flooder[3], flooder[2], flooder[1], flooder[0], other[0], other[1]}, txs)
+}
+
+func TestValidateSignerSequences(t *testing.T) {
+	first, second, third := transactionOfSignerWithNonce(1, 1), transactionOfSignerWithNonce(1, 2), transactionOfSignerWithNonce(1, 3)
+	other := transactionOfSignerWithNonce(2, 7)
+
+	require.NoError(t, validateSignerSequences(Transactions{first, other, second}))
+	require.Error(t, validateSignerSequences(Transactions{second, other, first}), "a signer's transactions must be in nonce order")
+	require.Error(t, validateSignerSequences(Transactions{first, first}), "a signer's nonces must be strictly increasing")
+	require.Error(t, validateSignerSequences(Transactions{first, third}), "a signer's nonces must be consecutive")
+	require.NoError(t, validateSignerSequences(Transactions{first, builders.TransferTransaction().Build(), second}), "transactions without a nonce are not sequenced")
+	require.Error(t, validateSignerSequences(Transactions{builders.TransferTransaction().WithArgs(uint64(10), digest.SignerNonceArgument(1)).Build()}), "a nonce must be the first argument")
+}
@@ -231,7 +231,7 @@ func (h *harness) getTxReceipt(ctx context.Context, tx *protocol.SignedTransacti
 }
 
 func (h *harness) start(ctx context.Context) *harness {
-	service := transactionpool.NewTransactionPool(ctx, adapter.NewSystemClock(), h.gossip, h.vm, h.signer, nil, h.config, h.Logger, metric.NewRegistry())
+	service := transactionpool.NewTransactionPool(ctx, adapter.NewSystemClock(), h.gossip, h.vm, h.signer, nil, nil, nil, h.config, h.Logger, metric.NewRegistry())
 	service.RegisterTransactionResultsHandler(h.trh)
 	h.txpool = service
 	h.fastForwardTo(ctx, 1)
@@ -7,6 +7,7 @@
 package transactionpool
 
 import (
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
 	"github.com/orbs-network/orbs-network-go/crypto/keys"
 	"github.com/orbs-network/orbs-network-go/instrumentation/logfields"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
@@ -18,10 +19,11 @@ import (
 const ProtocolVersion = primitives.ProtocolVersion(1)
 
 type validationContext struct {
-	nodeSyncRejectInterval time.Duration
-	expiryWindow           time.Duration
-	futureTimestampGrace   time.Duration
-	virtualChainId         primitives.VirtualChainId
+	nodeSyncRejectInterval          time.Duration
+	expiryWindow                    time.Duration
+	futureTimestampGrace            time.Duration
+	virtualChainId                  primitives.VirtualChainId
+	provisionalSignerSchemesEnabled bool
 }
 
 func (c *validationContext) ValidateAddedTransaction(transaction *protocol.SignedTransaction, currentTime time.Time, lastCommittedBlockTimestamp primitives.TimestampNano) *ErrTransactionRejected {
@@ -83,12 +85,27 @@ func (c *validationContext) validateTransactionVirtualChainId(transaction *proto
 
 func (c *validationContext) validateSignatureType(transaction *protocol.SignedTransaction) *ErrTransactionRejected {
 	tx := transaction.Transaction()
-	if !tx.Signer().IsSchemeEddsa() {
-		return &ErrTransactionRejected{protocol.TRANSACTION_STATUS_REJECTED_UNKNOWN_SIGNER_SCHEME, log.String("signer-scheme", "Eddsa"), log.Stringable("signer", tx.Signer())}
+	scheme := digest.SignerScheme(tx.Signer())
+	if digest.IsProvisionalSignerScheme(scheme) && !c.provisionalSignerSchemesEnabled {
+		return &ErrTransactionRejected{protocol.TRANSACTION_STATUS_REJECTED_UNKNOWN_SIGNER_SCHEME, log.String("signer-scheme", "Eddsa"), log.Uint32("signer-scheme", uint32(scheme))}
 	}
 
-	if len(tx.Signer().Eddsa().SignerPublicKey()) != keys.ED25519_PUBLIC_KEY_SIZE_BYTES {
-		return &ErrTransactionRejected{protocol.TRANSACTION_STATUS_REJECTED_SIGNATURE_MISMATCH, log.Int("signature-length", keys.ED25519_PUBLIC_KEY_SIZE_BYTES), log.Int("signature-length", len(tx.Signer().Eddsa().SignerPublicKey()))}
+	switch scheme {
+	case protocol.SIGNER_SCHEME_EDDSA:
+		if len(tx.Signer().Eddsa().SignerPublicKey()) != keys.ED25519_PUBLIC_KEY_SIZE_BYTES {
+			return &ErrTransactionRejected{protocol.TRANSACTION_STATUS_REJECTED_SIGNATURE_MISMATCH, log.Int("signature-length", keys.ED25519_PUBLIC_KEY_SIZE_BYTES), log.Int("signature-length", len(tx.Signer().Eddsa().SignerPublicKey()))}
+		}
+	case digest.SIGNER_SCHEME_ECDSA_SECP256K1:
+		signerPublicKey, err := digest.EcdsaSecp256K1SignerPublicKey(tx.Signer())
+		if err != nil || len(signerPublicKey) != keys.ECDSA_SECP256K1_PUBLIC_KEY_SIZE_BYTES {
+			return &ErrTransactionRejected{protocol.TRANSACTION_STATUS_REJECTED_SIGNATURE_MISMATCH, log.Int("signature-length", keys.ECDSA_SECP256K1_PUBLIC_KEY_SIZE_BYTES), log.Int("signature-length", len(signerPublicKey))}
+		}
+	case digest.SIGNER_SCHEME_MULTISIG:
+		if _, _, err := digest.MultiSigSignerThresholdAndPublicKeys(tx.Signer()); err != nil {
+			return &ErrTransactionRejected{protocol.TRANSACTION_STATUS_REJECTED_SIGNATURE_MISMATCH, log.String("signer-scheme", "MultiSig"), log.Error(err)}
+		}
+	default:
+		return &ErrTransactionRejected{protocol.TRANSACTION_STATUS_REJECTED_UNKNOWN_SIGNER_SCHEME, log.String("signer-scheme", "Eddsa, EcdsaSecp256K1 or MultiSig"), log.Stringable("signer", tx.Signer())}
 	}
 
 	return nil
@@ -8,7 +8,9 @@ package transactionpool
 
 import (
 	"fmt"
+	cryptoKeys "github.com/orbs-network/orbs-network-go/crypto/keys"
 	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-network-go/test/crypto/keys"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
 	"github.com/stretchr/testify/require"
@@ -26,6 +28,8 @@ func aValidationContextAsOf() *validationContext {
 		nodeSyncRejectInterval: nodeSyncRejectInterval,
 		futureTimestampGrace:   futureTimestampGrace,
 		virtualChainId:         builders.DEFAULT_TEST_VIRTUAL_CHAIN_ID,
+
+		provisionalSignerSchemesEnabled: true,
 	}
 }
 
@@ -40,6 +44,25 @@ func TestValidateTransaction_Add_ValidTransaction(t *testing.T) {
 	require.Nil(t, err, "a valid transaction was rejected")
 }
 
+func TestValidateTransaction_Add_ValidEcdsaSecp256K1Transaction(t *testing.T) {
+	currentTime := time.Now()
+	lastCommittedBlockTime := primitives.TimestampNano(currentTime.Add(nodeSyncRejectInterval / 2).UnixNano())
+	tx := aTransactionAtNodeTimestamp(lastCommittedBlockTime).WithEcdsaSecp256K1Signer(keys.EcdsaSecp256K1KeyPairForTests(1).EcdsaSecp256K1KeyPair).Build()
+	err := aValidationContextAsOf().ValidateAddedTransaction(tx, currentTime, lastCommittedBlockTime)
+	require.Nil(t, err, "a valid ecdsa secp256k1 transaction was rejected")
+}
+
+func TestValidateTransaction_Add_RejectsProvisionalSignerSchemesUnlessEnabled(t *testing.T) {
+	currentTime := time.Now()
+	lastCommittedBlockTime := primitives.TimestampNano(currentTime.Add(nodeSyncRejectInterval / 2).UnixNano())
+	tx := aTransactionAtNodeTimestamp(lastCommittedBlockTime).WithEcdsaSecp256K1Signer(keys.EcdsaSecp256K1KeyPairForTests(1).EcdsaSecp256K1KeyPair).Build()
+	vctx := aValidationContextAsOf()
+	vctx.provisionalSignerSchemesEnabled = false
+	err := vctx.ValidateAddedTransaction(tx, currentTime, lastCommittedBlockTime)
+	require.NotNil(t, err, "an ecdsa secp256k1 transaction was accepted although the scheme is not part of the spec yet")
+	require.Equal(t, protocol.TRANSACTION_STATUS_REJECTED_UNKNOWN_SIGNER_SCHEME, err.TransactionStatus)
+}
+
 func TestValidateTransaction_Add_RejectsTransactionsWhenTimestampIsZero(t *testing.T) {
 	vctx := &validationContext{
 		expiryWindow:         expirationWindowInterval,
@@ -70,6 +93,7 @@ func TestValidateTransaction_Add_InvalidTransactions(t *testing.T) {
 		{"protocol version", aTransactionAtNodeTimestamp(lastCommittedBlockTime).WithProtocolVersion(ProtocolVersion + 1), protocol.TRANSACTION_STATUS_REJECTED_UNSUPPORTED_VERSION},
 		{"signer scheme", aTransactionAtNodeTimestamp(lastCommittedBlockTime).WithInvalidSignerScheme(), protocol.TRANSACTION_STATUS_REJECTED_UNKNOWN_SIGNER_SCHEME},
 		{"signer public key (wrong length)", aTransactionAtNodeTimestamp(lastCommittedBlockTime).WithInvalidPublicKey(), protocol.TRANSACTION_STATUS_REJECTED_SIGNATURE_MISMATCH},
+		{"multi signature signer threshold", aTransactionAtNodeTimestamp(lastCommittedBlockTime).WithMultiSigSigner(2, []*cryptoKeys.Ed25519KeyPair{keys.Ed25519KeyPairForTests(1)}, 0), protocol.TRANSACTION_STATUS_REJECTED_SIGNATURE_MISMATCH},
 		{"timestamp (created prior to the expiry window)", builders.TransferTransaction().WithTimestamp(currentTime.Add(expirationWindowInterval * -2)), protocol.TRANSACTION_STATUS_REJECTED_TIMESTAMP_WINDOW_EXCEEDED},
 		{"timestamp (ahead of timestamp for last committed block)", builders.TransferTransaction().WithTimestamp(futureTimeAfterGracePeriod(lastCommittedBlockTime)), protocol.TRANSACTION_STATUS_REJECTED_TIMESTAMP_AHEAD_OF_NODE_TIME},
 		{"virtual chain id", aTransactionAtNodeTimestamp(lastCommittedBlockTime).WithVirtualChainId(primitives.VirtualChainId(1)), protocol.TRANSACTION_STATUS_REJECTED_VIRTUAL_CHAIN_MISMATCH},
@@ -27,6 +27,12 @@ func (s *Service) ValidateTransactionsForOrdering(ctx context.Context, input *se
 
 	proposedBlockTimestamp := input.CurrentBlockTimestamp
 
+	if s.config.TransactionSignerSequencingEnabled() {
+		if err := validateSignerSequences(input.SignedTransactions); err != nil {
+			return nil, errors.Wrap(err, "transactions are not ordered by signer sequence")
+		}
+	}
+
 	for _, tx := range input.SignedTransactions {
 		txHash := digest.CalcTxHash(tx.Transaction())
 		if s.committedPool.has(txHash) {
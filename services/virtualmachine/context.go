@@ -32,6 +32,11 @@ type executionContext struct {
 	batchTransientState         *transientState
 	transactionOrQuery          TransactionOrQuery
 	eventList                   []*protocol.EventBuilder
+	gas                         *gasMeter        // nil if not metered
+	reads                       stateReadSet     // nil unless executed speculatively
+	tracer                      *executionTracer // nil unless traced
+	unavailableService          primitives.ContractName
+	unavailableServiceErr       error // set when a nested call reached a paused or disabled contract
 }
 
 func (c *executionContext) serviceStackTop() primitives.ContractName {
@@ -8,6 +8,7 @@ package virtualmachine
 
 import (
 	"context"
+	"fmt"
 	"github.com/orbs-network/orbs-network-go/services/processor/native/repository/_Deployments"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
@@ -16,9 +17,24 @@ import (
 	"github.com/pkg/errors"
 )
 
+var errContractPaused = errors.New("contract is paused")
+var errContractDisabled = errors.New("contract is disabled")
+
+// a paused or disabled contract is reported like a contract which is not deployed, the output argument tells them apart
+func isServiceUnavailable(err error) bool {
+	return err == errContractPaused || err == errContractDisabled
+}
+
+func serviceUnavailableOutputArgs(serviceName primitives.ContractName, err error) *protocol.ArgumentArray {
+	return (&protocol.ArgumentArrayBuilder{Arguments: []*protocol.ArgumentBuilder{{
+		Type:        protocol.ARGUMENT_TYPE_STRING_VALUE,
+		StringValue: fmt.Sprintf("%s: %s", serviceName, err.Error()),
+	}}}).Build()
+}
+
 func (s *service) getServiceDeployment(ctx context.Context, executionContext *executionContext, serviceName primitives.ContractName) (services.Processor, error) {
 	// call the system contract to identify the processor
-	processorType, err := s.callGetInfoOfDeploymentSystemContract(ctx, executionContext, serviceName)
+	processorType, status, err := s.callGetInfoOfDeploymentSystemContract(ctx, executionContext, serviceName)
 
 	// on failure (contract not deployed), attempt to auto deploy pre-built (in repository) native contract
 	if err != nil {
@@ -40,6 +56,17 @@ func (s *service) getServiceDeployment(ctx context.Context, executionContext *ex
 		}
 	}
 
+	switch status {
+	case deployments_systemcontract.SERVICE_STATUS_PAUSED:
+		return nil, errContractPaused
+	case deployments_systemcontract.SERVICE_STATUS_PAUSED_ALLOWING_QUERIES:
+		if executionContext.accessScope != protocol.ACCESS_SCOPE_READ_ONLY {
+			return nil, errContractPaused
+		}
+	case deployments_systemcontract.SERVICE_STATUS_DISABLED:
+		return nil, errContractDisabled
+	}
+
 	if processor, found := s.processors[processorType]; !found {
 		return nil, errors.Errorf("_Deployments.getInfo contract returned unknown processor type: %s", processorType)
 	} else {
@@ -76,7 +103,8 @@ func (s *service) hasDeployableCode(ctx context.Context, serviceName primitives.
 	return err
 }
 
-func (s *service) callGetInfoOfDeploymentSystemContract(ctx context.Context, executionContext *executionContext, serviceName primitives.ContractName) (protocol.ProcessorType, error) {
+// the status of the service is SERVICE_STATUS_ACTIVE if getInfo does not return it
+func (s *service) callGetInfoOfDeploymentSystemContract(ctx context.Context, executionContext *executionContext, serviceName primitives.ContractName) (protocol.ProcessorType, uint32, error) {
 	systemContractName := primitives.ContractName(deployments_systemcontract.CONTRACT_NAME)
 	systemMethodName := primitives.MethodName(deployments_systemcontract.METHOD_GET_INFO)
 
@@ -103,17 +131,25 @@ func (s *service) callGetInfoOfDeploymentSystemContract(ctx context.Context, exe
 		CallingPermissionScope: protocol.PERMISSION_SCOPE_SERVICE,
 	})
 	if err != nil {
-		return 0, err
+		return 0, 0, err
 	}
 	outputArgsIterator := output.OutputArgumentArray.ArgumentsIterator()
 	if !outputArgsIterator.HasNext() {
-		return 0, errors.Errorf("_Deployments.getInfo contract returned corrupt output value")
+		return 0, 0, errors.Errorf("_Deployments.getInfo contract returned corrupt output value")
 	}
 	outputArg0 := outputArgsIterator.NextArguments()
 	if !outputArg0.IsTypeUint32Value() {
-		return 0, errors.Errorf("_Deployments.getInfo contract returned corrupt output value")
+		return 0, 0, errors.Errorf("_Deployments.getInfo contract returned corrupt output value")
+	}
+	status := deployments_systemcontract.SERVICE_STATUS_ACTIVE
+	if outputArgsIterator.HasNext() {
+		outputArg1 := outputArgsIterator.NextArguments()
+		if !outputArg1.IsTypeUint32Value() {
+			return 0, 0, errors.Errorf("_Deployments.getInfo contract returned corrupt output value")
+		}
+		status = outputArg1.Uint32Value()
 	}
-	return protocol.ProcessorType(outputArg0.Uint32Value()), nil
+	return protocol.ProcessorType(outputArg0.Uint32Value()), status, nil
 }
 
 func (s *service) callDeployServiceOfDeploymentSystemContract(ctx context.Context, executionContext *executionContext, serviceName primitives.ContractName) error {
@@ -15,6 +15,7 @@ import (
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
 	"github.com/orbs-network/orbs-spec/types/go/services"
 	"github.com/orbs-network/scribe/log"
+	"github.com/pkg/errors"
 )
 
 type TransactionOrQuery interface {
@@ -34,17 +35,27 @@ func (s *service) runMethod(
 	transactionOrQuery TransactionOrQuery,
 	accessScope protocol.ExecutionAccessScope,
 	batchTransientState *transientState,
+	gas *gasMeter,
+	reads stateReadSet,
+	tracer *executionTracer,
 ) (protocol.ExecutionResult, *protocol.ArgumentArray, *protocol.EventsArray, error) {
 
 	// create execution context
 	executionContextId, executionContext := s.contexts.allocateExecutionContext(lastCommittedBlockHeight, currentBlockHeight, currentBlockTimestamp, currentBlockProposerAddress, accessScope, transactionOrQuery)
 	defer s.contexts.destroyExecutionContext(executionContextId)
 	executionContext.batchTransientState = batchTransientState
+	executionContext.gas = gas
+	executionContext.reads = reads
+	executionContext.tracer = tracer
 
 	// get deployment info
 	processor, err := s.getServiceDeployment(ctx, executionContext, transactionOrQuery.ContractName())
 	if err != nil {
 		s.logger.Info("get deployment info for contract failed", log.Error(err), log.Stringable("transaction-or-query", transactionOrQuery))
+		tracer.end(protocol.EXECUTION_RESULT_ERROR_CONTRACT_NOT_DEPLOYED, err)
+		if isServiceUnavailable(err) {
+			return protocol.EXECUTION_RESULT_ERROR_CONTRACT_NOT_DEPLOYED, serviceUnavailableOutputArgs(transactionOrQuery.ContractName(), err), nil, err
+		}
 		return protocol.EXECUTION_RESULT_ERROR_CONTRACT_NOT_DEPLOYED, nil, nil, err
 	}
 
@@ -53,7 +64,12 @@ func (s *service) runMethod(
 	defer executionContext.serviceStackPop()
 
 	// execute the call
-	inputArgs := protocol.ArgumentArrayReader(transactionOrQuery.RawInputArgumentArrayWithHeader())
+	inputArgs, err := s.contractInputArguments(transactionOrQuery)
+	if err != nil {
+		s.logger.Info("transaction input arguments are invalid", log.Error(err), log.Stringable("transaction-or-query", transactionOrQuery))
+		tracer.end(protocol.EXECUTION_RESULT_ERROR_INPUT, err)
+		return protocol.EXECUTION_RESULT_ERROR_INPUT, nil, nil, err
+	}
 	output, err := processor.ProcessCall(ctx, &services.ProcessCallInput{
 		ContextId:              executionContextId,
 		ContractName:           transactionOrQuery.ContractName(),
@@ -66,6 +82,20 @@ func (s *service) runMethod(
 		s.logger.Info("transaction execution failed", log.Stringable("result", output.CallResult), log.Error(err), log.Stringable("transaction-or-query", transactionOrQuery))
 	}
 
+	// the contract may have recovered from the failed sdk calls, calling a paused or disabled contract and running out of gas abort it regardless
+	if executionContext.unavailableServiceErr != nil {
+		output.CallResult = protocol.EXECUTION_RESULT_ERROR_CONTRACT_NOT_DEPLOYED
+		output.OutputArgumentArray = serviceUnavailableOutputArgs(executionContext.unavailableService, executionContext.unavailableServiceErr)
+	}
+	if gas.outOfGas() {
+		output.CallResult = EXECUTION_RESULT_ERROR_OUT_OF_GAS
+		output.OutputArgumentArray = outOfGasOutputArgs(gas)
+	}
+	if gas != nil {
+		output.OutputArgumentArray = withGasUsedOutputArgument(output.OutputArgumentArray, gas.used)
+	}
+	tracer.end(output.CallResult, err)
+
 	if batchTransientState != nil && output.CallResult == protocol.EXECUTION_RESULT_SUCCESS {
 		executionContext.transientState.mergeIntoTransientState(batchTransientState)
 	}
@@ -83,7 +113,8 @@ func (s *service) processTransactionSet(
 	currentBlockTimestamp primitives.TimestampNano,
 	currentBlockProposerAddress primitives.NodeAddress,
 	signedTransactions []*protocol.SignedTransaction,
-) ([]*protocol.TransactionReceipt, []*protocol.ContractStateDiff) {
+	tracer *transactionSetTracer,
+) ([]*protocol.TransactionReceipt, []*protocol.ContractStateDiff, error) {
 
 	logger := s.logger.WithTags(trace.LogFieldFrom(ctx))
 	lastCommittedBlockHeight := currentBlockHeight - 1
@@ -94,10 +125,52 @@ func (s *service) processTransactionSet(
 	// receipts for result
 	receipts := make([]*protocol.TransactionReceipt, 0, len(signedTransactions))
 
-	for _, signedTransaction := range signedTransactions {
+	blockGasLeft := uint64(s.config.VirtualMachineGasLimitPerBlock())
+
+	// a traced transaction set is executed sequentially so the traced transaction runs with its tracer
+	var speculations speculativeTransactions
+	if tracer == nil {
+		speculations = s.speculateTransactionSet(ctx, currentBlockHeight, currentBlockTimestamp, currentBlockProposerAddress, signedTransactions)
+	}
+	reexecuted := 0
+
+	for i, signedTransaction := range signedTransactions {
+
+		if s.config.TransactionSignerSequencingEnabled() {
+			inSequence, err := s.advanceSignerSequence(ctx, lastCommittedBlockHeight, batchTransientState, signedTransaction.Transaction())
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "failed reading signer sequence")
+			}
+			if !inSequence {
+				logger.Info("transaction not executed since it is out of its signer's sequence", logfields.Transaction(digest.CalcTxHash(signedTransaction.Transaction())), logfields.BlockHeight(currentBlockHeight))
+				receipts = append(receipts, encodeTransactionReceipt(signedTransaction.Transaction(), protocol.EXECUTION_RESULT_NOT_EXECUTED, protocol.ArgumentsArrayEmpty(), (&protocol.EventsArrayBuilder{}).Build()))
+				continue
+			}
+		}
+
+		gas := s.newTransactionGasMeter(signedTransaction.Transaction(), blockGasLeft)
+		if gas != nil && blockGasLeft == 0 {
+			logger.Info("transaction not executed since the block ran out of gas", logfields.Transaction(digest.CalcTxHash(signedTransaction.Transaction())), logfields.BlockHeight(currentBlockHeight))
+			receipts = append(receipts, encodeTransactionReceipt(signedTransaction.Transaction(), protocol.EXECUTION_RESULT_NOT_EXECUTED, withGasUsedOutputArgument(nil, 0), (&protocol.EventsArrayBuilder{}).Build()))
+			continue
+		}
 
-		logger.Info("processing transaction", log.Stringable("contract", signedTransaction.Transaction().ContractName()), log.Stringable("method", signedTransaction.Transaction().MethodName()), logfields.BlockHeight(currentBlockHeight))
-		callResult, outputArgs, outputEvents, _ := s.runMethod(ctx, lastCommittedBlockHeight, currentBlockHeight, currentBlockTimestamp, currentBlockProposerAddress, signedTransaction.Transaction(), protocol.ACCESS_SCOPE_READ_WRITE, batchTransientState)
+		var callResult protocol.ExecutionResult
+		var outputArgs *protocol.ArgumentArray
+		var outputEvents *protocol.EventsArray
+		if speculation := speculations.usable(i, batchTransientState, gas); speculation != nil {
+			speculation.writes.mergeIntoTransientState(batchTransientState)
+			callResult, outputArgs, outputEvents, gas = speculation.callResult, speculation.outputArgs, speculation.outputEvents, speculation.gas
+		} else {
+			if speculations != nil {
+				reexecuted++
+			}
+			logger.Info("processing transaction", log.Stringable("contract", signedTransaction.Transaction().ContractName()), log.Stringable("method", signedTransaction.Transaction().MethodName()), logfields.BlockHeight(currentBlockHeight))
+			callResult, outputArgs, outputEvents, _ = s.runMethod(ctx, lastCommittedBlockHeight, currentBlockHeight, currentBlockTimestamp, currentBlockProposerAddress, signedTransaction.Transaction(), protocol.ACCESS_SCOPE_READ_WRITE, batchTransientState, gas, nil, tracer.tracerFor(i, signedTransaction.Transaction()))
+		}
+		if gas != nil {
+			blockGasLeft -= gas.used
+		}
 		if outputArgs == nil {
 			outputArgs = protocol.ArgumentsArrayEmpty()
 		}
@@ -109,8 +182,12 @@ func (s *service) processTransactionSet(
 		receipts = append(receipts, receipt)
 	}
 
+	if speculations != nil {
+		logger.Info("processed transaction set in parallel", log.Int("num-transactions", len(signedTransactions)), log.Int("num-reexecuted", reexecuted), logfields.BlockHeight(currentBlockHeight))
+	}
+
 	stateDiffs := encodeBatchTransientStateToStateDiffs(batchTransientState)
-	return receipts, stateDiffs
+	return receipts, stateDiffs, nil
 }
 
 func (s *service) getRecentCommittedBlockInfo(ctx context.Context) (primitives.BlockHeight, primitives.TimestampNano, primitives.NodeAddress, error) {
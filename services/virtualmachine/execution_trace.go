@@ -0,0 +1,207 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package virtualmachine
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/instrumentation/logfields"
+	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services/handlers"
+	"github.com/orbs-network/scribe/log"
+	"github.com/pkg/errors"
+)
+
+// TransactionTracer is implemented by the virtual machine service in addition to services.VirtualMachine
+type TransactionTracer interface {
+	TraceTransaction(ctx context.Context, input *TraceTransactionInput) (*TraceTransactionOutput, error)
+}
+
+// the transactions preceding the traced one in its block are executed first so it sees the same state it was committed on
+type TraceTransactionInput struct {
+	SignedTransactions    []*protocol.SignedTransaction // all the transactions of the block
+	TransactionIndex      int
+	CurrentBlockHeight    primitives.BlockHeight
+	CurrentBlockTimestamp primitives.TimestampNano
+	BlockProposerAddress  primitives.NodeAddress
+}
+
+type TraceTransactionOutput struct {
+	Trace   *ExecutionTrace
+	Receipt *protocol.TransactionReceipt // of the re-execution, under the gas and sequencing config the node runs now
+}
+
+// ExecutionTrace holds the sdk calls a transaction made, Error includes the panic message of a contract that panicked
+type ExecutionTrace struct {
+	Contract        string          `json:"contract"`
+	Method          string          `json:"method"`
+	ExecutionResult string          `json:"executionResult"`
+	Error           string          `json:"error,omitempty"`
+	Calls           []*SdkCallTrace `json:"calls"`
+}
+
+// SdkCallTrace is a single call to HandleSdkCall, the calls made by a service while handling a service call are nested in it
+type SdkCallTrace struct {
+	ServiceStack    []string        `json:"serviceStack"`
+	Operation       string          `json:"operation"`
+	Method          string          `json:"method"`
+	InputArguments  []string        `json:"inputArguments"`
+	OutputArguments []string        `json:"outputArguments,omitempty"`
+	Error           string          `json:"error,omitempty"`
+	Calls           []*SdkCallTrace `json:"calls,omitempty"`
+}
+
+// TODO(micro-services): like the service stack, this is only accessed by the goroutine running the execution context
+type executionTracer struct {
+	trace *ExecutionTrace
+	open  []*SdkCallTrace
+}
+
+func newExecutionTracer(transactionOrQuery TransactionOrQuery) *executionTracer {
+	return &executionTracer{
+		trace: &ExecutionTrace{
+			Contract: string(transactionOrQuery.ContractName()),
+			Method:   string(transactionOrQuery.MethodName()),
+			Calls:    []*SdkCallTrace{},
+		},
+	}
+}
+
+func (t *executionTracer) beginSdkCall(executionContext *executionContext, input *handlers.HandleSdkCallInput) *SdkCallTrace {
+	if t == nil {
+		return nil
+	}
+	call := &SdkCallTrace{
+		ServiceStack:   make([]string, 0, len(executionContext.serviceStack)),
+		Operation:      string(input.OperationName),
+		Method:         string(input.MethodName),
+		InputArguments: traceArguments(input.InputArguments),
+	}
+	for _, service := range executionContext.serviceStack {
+		call.ServiceStack = append(call.ServiceStack, string(service))
+	}
+
+	if len(t.open) == 0 {
+		t.trace.Calls = append(t.trace.Calls, call)
+	} else {
+		parent := t.open[len(t.open)-1]
+		parent.Calls = append(parent.Calls, call)
+	}
+	t.open = append(t.open, call)
+	return call
+}
+
+func (t *executionTracer) endSdkCall(call *SdkCallTrace, output []*protocol.Argument, err error) {
+	if t == nil {
+		return
+	}
+	call.OutputArguments = traceArguments(output)
+	if err != nil {
+		call.Error = err.Error()
+	}
+	t.open = t.open[:len(t.open)-1]
+}
+
+func (t *executionTracer) end(callResult protocol.ExecutionResult, err error) {
+	if t == nil {
+		return
+	}
+	t.trace.ExecutionResult = callResult.String()
+	if err != nil {
+		t.trace.Error = err.Error()
+	}
+}
+
+func traceArguments(args []*protocol.Argument) []string {
+	if len(args) == 0 {
+		return nil
+	}
+	res := make([]string, len(args))
+	for i, arg := range args {
+		res[i] = traceArgument(arg)
+	}
+	return res
+}
+
+func traceArgument(arg *protocol.Argument) string {
+	switch arg.Type() {
+	case protocol.ARGUMENT_TYPE_UINT_32_VALUE:
+		return arg.StringUint32Value()
+	case protocol.ARGUMENT_TYPE_UINT_64_VALUE:
+		return arg.StringUint64Value()
+	case protocol.ARGUMENT_TYPE_STRING_VALUE:
+		return arg.StringValue()
+	case protocol.ARGUMENT_TYPE_BYTES_VALUE:
+		return "0x" + arg.StringBytesValue()
+	case protocol.ARGUMENT_TYPE_BOOL_VALUE:
+		return arg.StringBoolValue()
+	case protocol.ARGUMENT_TYPE_UINT_256_VALUE:
+		return arg.StringUint256Value()
+	case protocol.ARGUMENT_TYPE_BYTES_20_VALUE:
+		return "0x" + arg.StringBytes20Value()
+	case protocol.ARGUMENT_TYPE_BYTES_32_VALUE:
+		return "0x" + arg.StringBytes32Value()
+	default:
+		return arg.StringType()
+	}
+}
+
+// TraceTransaction re-executes a committed transaction against the state of the block before it, which state storage must still hold
+func (s *service) TraceTransaction(ctx context.Context, input *TraceTransactionInput) (*TraceTransactionOutput, error) {
+	logger := s.logger.WithTags(trace.LogFieldFrom(ctx))
+
+	if input.TransactionIndex < 0 || input.TransactionIndex >= len(input.SignedTransactions) {
+		return nil, errors.Errorf("transaction index %d is not in a block of %d transactions", input.TransactionIndex, len(input.SignedTransactions))
+	}
+
+	if err := s.verifyStateOfBlockIsKept(ctx, input.CurrentBlockHeight-1); err != nil {
+		return nil, err
+	}
+
+	logger.Info("tracing transaction", log.Int("transaction-index", input.TransactionIndex), logfields.BlockHeight(input.CurrentBlockHeight))
+	tracer := &transactionSetTracer{index: input.TransactionIndex}
+	receipts, _, err := s.processTransactionSet(ctx, input.CurrentBlockHeight, input.CurrentBlockTimestamp, input.BlockProposerAddress, input.SignedTransactions[:input.TransactionIndex+1], tracer)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed executing the transactions of the block up to the traced one")
+	}
+	if tracer.tracer == nil {
+		return nil, errors.Errorf("transaction was not executed")
+	}
+
+	return &TraceTransactionOutput{Trace: tracer.tracer.trace, Receipt: receipts[input.TransactionIndex]}, nil
+}
+
+// state storage keeps the state of the last StateStorageHistorySnapshotNum blocks only, older blocks cannot be re-executed
+func (s *service) verifyStateOfBlockIsKept(ctx context.Context, blockHeight primitives.BlockHeight) error {
+	lastCommittedBlockHeight, _, _, err := s.getRecentCommittedBlockInfo(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed reading the last committed block height")
+	}
+
+	if blockHeight > lastCommittedBlockHeight {
+		return errors.Errorf("state of block %d is not committed yet, state storage is at block %d", blockHeight, lastCommittedBlockHeight)
+	}
+	if blockHeight+primitives.BlockHeight(s.config.StateStorageHistorySnapshotNum()) <= lastCommittedBlockHeight {
+		return errors.Errorf("state of block %d is no longer kept, state storage is at block %d and keeps %d blocks back", blockHeight, lastCommittedBlockHeight, s.config.StateStorageHistorySnapshotNum())
+	}
+	return nil
+}
+
+// transactionSetTracer traces a single transaction of a transaction set
+type transactionSetTracer struct {
+	index  int
+	tracer *executionTracer // nil until the transaction is executed
+}
+
+func (t *transactionSetTracer) tracerFor(index int, transaction *protocol.Transaction) *executionTracer {
+	if t == nil || index != t.index {
+		return nil
+	}
+	t.tracer = newExecutionTracer(transaction)
+	return t.tracer
+}
@@ -0,0 +1,108 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package virtualmachine
+
+import (
+	"fmt"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
+)
+
+// With gas metering every sdk call of a transaction is charged according to the table below, which depends only on the
+// call and its arguments and results, so that all nodes charge the same. Deployed contracts are instrumented by the
+// processor to count a computation step at every function call and loop iteration, the steps are charged through the
+// sdk as well. A transaction that exceeds its gas limit, or the gas left in the block, is aborted with
+// EXECUTION_RESULT_ERROR_OUT_OF_GAS and its state changes are discarded, transactions left once the block ran out
+// of gas are EXECUTION_RESULT_NOT_EXECUTED. System transactions (which have no signer) and the processor loading a
+// contract's code are not metered. The receipt of a metered transaction (and the output of a metered query) reports
+// the gas used as its last output argument, a uint64 appended after the output arguments of the contract, so its
+// events are only those the contracts emitted. An aborted transaction has a string describing the limit before it.
+const (
+	GAS_COST_STATE_READ           = 200
+	GAS_COST_STATE_READ_PER_BYTE  = 1
+	GAS_COST_STATE_WRITE          = 1000
+	GAS_COST_STATE_WRITE_PER_BYTE = 5
+	GAS_COST_SERVICE_CALL         = 500
+	GAS_COST_EVENT_EMIT           = 300
+	GAS_COST_EVENT_EMIT_PER_BYTE  = 2
+	GAS_COST_ETHEREUM_CALL        = 5000
+	GAS_COST_COMPUTATION_STEP     = 1
+)
+
+// not part of the spec's ExecutionResult yet, running out of gas is the result following the last one the spec
+// defines. until orbs-spec defines it the value is provisional
+const EXECUTION_RESULT_ERROR_OUT_OF_GAS = protocol.ExecutionResult(7)
+
+type gasMeter struct {
+	limit     uint64
+	used      uint64
+	exhausted bool
+}
+
+func newGasMeter(limit uint64) *gasMeter {
+	return &gasMeter{limit: limit}
+}
+
+// a nil meter is not metered, e.g. for system contract calls
+func (m *gasMeter) charge(amount uint64) error {
+	if m == nil {
+		return nil
+	}
+	if m.exhausted || amount > m.limit-m.used {
+		m.used = m.limit
+		m.exhausted = true
+		return errors.Errorf("out of gas, limit is %d", m.limit)
+	}
+	m.used += amount
+	return nil
+}
+
+func (m *gasMeter) outOfGas() bool {
+	return m != nil && m.exhausted
+}
+
+// returns nil if gas metering is disabled or the transaction is a system transaction
+func (s *service) newTransactionGasMeter(transaction *protocol.Transaction, blockGasLeft uint64) *gasMeter {
+	if !s.config.VirtualMachineGasMeteringEnabled() || isSystemTransaction(transaction) {
+		return nil
+	}
+	limit := uint64(s.config.VirtualMachineGasLimitPerTransaction())
+	if blockGasLeft < limit {
+		limit = blockGasLeft
+	}
+	return newGasMeter(limit)
+}
+
+// queries are not part of a block and are limited like a single transaction
+func (s *service) newQueryGasMeter() *gasMeter {
+	if !s.config.VirtualMachineGasMeteringEnabled() {
+		return nil
+	}
+	return newGasMeter(uint64(s.config.VirtualMachineGasLimitPerTransaction()))
+}
+
+// system transactions (like the triggers transaction) are added by the block proposer and have no signer
+func isSystemTransaction(transaction *protocol.Transaction) bool {
+	return len(transaction.Signer().Raw()) == 0
+}
+
+func outOfGasOutputArgs(gas *gasMeter) *protocol.ArgumentArray {
+	return (&protocol.ArgumentArrayBuilder{Arguments: []*protocol.ArgumentBuilder{{
+		Type:        protocol.ARGUMENT_TYPE_STRING_VALUE,
+		StringValue: fmt.Sprintf("out of gas, limit is %d", gas.limit),
+	}}}).Build()
+}
+
+// appends the gas used to the output arguments of a metered transaction or query, args may be nil
+func withGasUsedOutputArgument(args *protocol.ArgumentArray, gasUsed uint64) *protocol.ArgumentArray {
+	var natives []interface{}
+	if args != nil {
+		natives, _ = args.ToNatives() // err ignored because output arguments are encoded from natives
+	}
+	res, _ := protocol.ArgumentArrayFromNatives(append(natives, gasUsed)) // err ignored because we support argument with type uint64
+	return res
+}
@@ -0,0 +1,33 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package virtualmachine
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestGasMeter_ExhaustsOnceChargedBeyondTheLimit(t *testing.T) {
+	m := newGasMeter(100)
+
+	require.NoError(t, m.charge(60))
+	require.NoError(t, m.charge(40), "charging exactly up to the limit should succeed")
+	require.False(t, m.outOfGas())
+
+	require.Error(t, m.charge(1))
+	require.True(t, m.outOfGas())
+	require.EqualValues(t, 100, m.used, "an exhausted meter should use up its whole limit")
+
+	require.Error(t, m.charge(0), "an exhausted meter should not accept any further charges")
+}
+
+func TestGasMeter_NilMeterIsNotMetered(t *testing.T) {
+	var m *gasMeter
+
+	require.NoError(t, m.charge(1<<60))
+	require.False(t, m.outOfGas())
+}
@@ -0,0 +1,171 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package virtualmachine
+
+import (
+	"context"
+	"github.com/orbs-network/govnr"
+	"github.com/orbs-network/orbs-network-go/instrumentation/logfields"
+	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"sync"
+	"sync/atomic"
+)
+
+// With parallel execution, before a transaction set is processed in order all of its transactions are first executed
+// speculatively by a pool of workers, each one on its own against the last committed state, recording the keys it read.
+// When processing in order, a speculative execution is used only if none of the keys it read were written by the
+// transactions before it in the set, otherwise the transaction is executed again on the state they left, so receipts
+// and state diffs are always the same as executing one transaction at a time. Transactions which processing in order
+// skips for being out of their signer's sequence are not speculated, nor are transactions once those speculated
+// before them used up the gas of the block.
+
+// keys of each contract read from outside the transaction, i.e. not written by the transaction itself before
+type stateReadSet map[primitives.ContractName]map[string]bool
+
+func (r stateReadSet) add(contract primitives.ContractName, key []byte) {
+	if r == nil {
+		return
+	}
+	keys, found := r[contract]
+	if !found {
+		keys = make(map[string]bool)
+		r[contract] = keys
+	}
+	keys[keyForMap(key)] = true
+}
+
+func (r stateReadSet) conflictsWith(batchTransientState *transientState) bool {
+	for contract, keys := range r {
+		for key := range keys {
+			if _, written := batchTransientState.getValue(contract, []byte(key)); written {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type speculativeTransaction struct {
+	callResult   protocol.ExecutionResult
+	outputArgs   *protocol.ArgumentArray
+	outputEvents *protocol.EventsArray
+	writes       *transientState
+	reads        stateReadSet
+	gas          *gasMeter
+}
+
+// nil unless parallel execution is enabled, an execution that failed to complete is nil
+type speculativeTransactions []*speculativeTransaction
+
+// returns the speculative execution of the transaction at index if it is the same as executing it on batchTransientState with gas
+func (st speculativeTransactions) usable(index int, batchTransientState *transientState, gas *gasMeter) *speculativeTransaction {
+	if index >= len(st) || st[index] == nil {
+		return nil
+	}
+	speculation := st[index]
+	if (gas == nil) != (speculation.gas == nil) || (gas != nil && gas.limit != speculation.gas.limit) {
+		return nil
+	}
+	if speculation.reads.conflictsWith(batchTransientState) {
+		return nil
+	}
+	return speculation
+}
+
+func (s *service) speculateTransactionSet(
+	ctx context.Context,
+	currentBlockHeight primitives.BlockHeight,
+	currentBlockTimestamp primitives.TimestampNano,
+	currentBlockProposerAddress primitives.NodeAddress,
+	signedTransactions []*protocol.SignedTransaction,
+) speculativeTransactions {
+
+	workers := int(s.config.VirtualMachineParallelExecutionWorkers())
+	if workers == 0 || len(signedTransactions) < 2 {
+		return nil
+	}
+	if workers > len(signedTransactions) {
+		workers = len(signedTransactions)
+	}
+
+	inSequence, err := s.transactionsInSignerSequence(ctx, currentBlockHeight-1, signedTransactions)
+	if err != nil { // processing the set in order fails as well
+		return nil
+	}
+
+	logger := s.logger.WithTags(trace.LogFieldFrom(ctx))
+	speculations := make(speculativeTransactions, len(signedTransactions))
+	next := int32(-1)
+	blockGasLimit := uint64(s.config.VirtualMachineGasLimitPerBlock())
+	blockGasUsed := uint64(0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		govnr.Once(logfields.GovnrErrorer(logger), func() {
+			defer wg.Done()
+			for {
+				index := int(atomic.AddInt32(&next, 1))
+				if index >= len(signedTransactions) {
+					return
+				}
+				if !inSequence[index] {
+					continue
+				}
+				// transactions are taken in order, so those after the ones which used up the block's gas are most
+				// likely not executed
+				if s.config.VirtualMachineGasMeteringEnabled() && atomic.LoadUint64(&blockGasUsed) >= blockGasLimit {
+					return
+				}
+				speculation := s.speculateTransaction(ctx, currentBlockHeight, currentBlockTimestamp, currentBlockProposerAddress, signedTransactions[index])
+				speculations[index] = speculation
+				if speculation.gas != nil {
+					atomic.AddUint64(&blockGasUsed, speculation.gas.used)
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	return speculations
+}
+
+func (s *service) speculateTransaction(
+	ctx context.Context,
+	currentBlockHeight primitives.BlockHeight,
+	currentBlockTimestamp primitives.TimestampNano,
+	currentBlockProposerAddress primitives.NodeAddress,
+	signedTransaction *protocol.SignedTransaction,
+) *speculativeTransaction {
+
+	speculation := &speculativeTransaction{
+		writes: newTransientState(),
+		reads:  make(stateReadSet),
+		gas:    s.newTransactionGasMeter(signedTransaction.Transaction(), uint64(s.config.VirtualMachineGasLimitPerBlock())),
+	}
+	speculation.callResult, speculation.outputArgs, speculation.outputEvents, _ = s.runMethod(ctx, currentBlockHeight-1, currentBlockHeight, currentBlockTimestamp, currentBlockProposerAddress, signedTransaction.Transaction(), protocol.ACCESS_SCOPE_READ_WRITE, speculation.writes, speculation.gas, speculation.reads, nil)
+	return speculation
+}
+
+// whether processing the transactions in order executes each of them as far as its signer's sequence goes
+func (s *service) transactionsInSignerSequence(ctx context.Context, lastCommittedBlockHeight primitives.BlockHeight, signedTransactions []*protocol.SignedTransaction) ([]bool, error) {
+	inSequence := make([]bool, len(signedTransactions))
+	signerSequences := newTransientState()
+	for i, signedTransaction := range signedTransactions {
+		if !s.config.TransactionSignerSequencingEnabled() {
+			inSequence[i] = true
+			continue
+		}
+		var err error
+		if inSequence[i], err = s.advanceSignerSequence(ctx, lastCommittedBlockHeight, signerSequences, signedTransaction.Transaction()); err != nil {
+			return nil, err
+		}
+	}
+	return inSequence, nil
+}
@@ -17,6 +17,10 @@ import (
 )
 
 func (s *service) handleSdkEthereumCall(ctx context.Context, executionContext *executionContext, methodName primitives.MethodName, args []*protocol.Argument, permissionScope protocol.ExecutionPermissionScope) ([]*protocol.Argument, error) {
+	if err := executionContext.gas.charge(GAS_COST_ETHEREUM_CALL); err != nil {
+		return nil, err
+	}
+
 	switch methodName {
 
 	case "callMethod":
@@ -37,6 +37,10 @@ func (s *service) handleSdkEventsEmitEvent(ctx context.Context, executionContext
 	eventName := args[0].StringValue()
 	inputArgumentArray := protocol.ArgumentArrayReader(args[1].BytesValue())
 
+	if err := executionContext.gas.charge(GAS_COST_EVENT_EMIT + GAS_COST_EVENT_EMIT_PER_BYTE*uint64(len(eventName)+len(inputArgumentArray.RawArgumentsArray()))); err != nil {
+		return err
+	}
+
 	executionContext.eventListAdd(primitives.EventName(eventName), inputArgumentArray.RawArgumentsArray())
 
 	return nil
@@ -0,0 +1,39 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package virtualmachine
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
+	"math"
+)
+
+func (s *service) handleSdkGasCall(ctx context.Context, executionContext *executionContext, methodName primitives.MethodName, args []*protocol.Argument, permissionScope protocol.ExecutionPermissionScope) ([]*protocol.Argument, error) {
+	switch methodName {
+
+	case "chargeComputation":
+		err := s.handleSdkGasChargeComputation(executionContext, args)
+		return []*protocol.Argument{}, err
+
+	default:
+		return nil, errors.Errorf("unknown SDK gas call method: %s", methodName)
+	}
+}
+
+// inputArg0: steps (uint64)
+func (s *service) handleSdkGasChargeComputation(executionContext *executionContext, args []*protocol.Argument) error {
+	if len(args) != 1 || !args[0].IsTypeUint64Value() {
+		return errors.Errorf("invalid SDK gas chargeComputation args: %v", args)
+	}
+	steps := args[0].Uint64Value()
+	if steps > math.MaxUint64/GAS_COST_COMPUTATION_STEP {
+		steps = math.MaxUint64 / GAS_COST_COMPUTATION_STEP
+	}
+	return executionContext.gas.charge(GAS_COST_COMPUTATION_STEP * steps)
+}
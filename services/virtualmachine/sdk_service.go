@@ -8,6 +8,7 @@ package virtualmachine
 
 import (
 	"context"
+	"github.com/orbs-network/orbs-network-go/services/processor/native/repository/_Deployments"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
 	"github.com/orbs-network/orbs-spec/types/go/services"
@@ -43,10 +44,22 @@ func (s *service) handleSdkServiceCallMethod(ctx context.Context, executionConte
 	methodName := args[1].StringValue()
 	inputArgumentArray := protocol.ArgumentArrayReader(args[2].BytesValue())
 
+	// the processor loading the code of a contract is not metered, whether it does depends on its cache
+	if permissionScope == protocol.PERMISSION_SCOPE_SYSTEM && isContractCodeLoading(serviceName, methodName) {
+		gas := executionContext.gas
+		executionContext.gas = nil
+		defer func() { executionContext.gas = gas }()
+	} else if err := executionContext.gas.charge(GAS_COST_SERVICE_CALL); err != nil {
+		return nil, err
+	}
+
 	// get deployment info
 	processor, err := s.getServiceDeployment(ctx, executionContext, primitives.ContractName(serviceName))
 	if err != nil {
 		s.logger.Info("get deployment info for contract failed during Sdk.Service.CallMethod", log.Error(err), log.String("contract", serviceName))
+		if isServiceUnavailable(err) && executionContext.unavailableServiceErr == nil {
+			executionContext.unavailableService, executionContext.unavailableServiceErr = primitives.ContractName(serviceName), err
+		}
 		return nil, err
 	}
 
@@ -70,3 +83,14 @@ func (s *service) handleSdkServiceCallMethod(ctx context.Context, executionConte
 
 	return output.OutputArgumentArray.Raw(), nil
 }
+
+func isContractCodeLoading(serviceName string, methodName string) bool {
+	if serviceName != deployments_systemcontract.CONTRACT_NAME {
+		return false
+	}
+	switch methodName {
+	case deployments_systemcontract.METHOD_GET_CODE_VERSION, deployments_systemcontract.METHOD_GET_CODE, deployments_systemcontract.METHOD_GET_CODE_PART, deployments_systemcontract.METHOD_GET_CODE_PARTS:
+		return true
+	}
+	return false
+}
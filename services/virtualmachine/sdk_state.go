@@ -48,20 +48,25 @@ func (s *service) handleSdkStateRead(ctx context.Context, executionContext *exec
 	}
 	key := args[0].BytesValue()
 
+	if err := executionContext.gas.charge(GAS_COST_STATE_READ + GAS_COST_STATE_READ_PER_BYTE*uint64(len(key))); err != nil {
+		return nil, err
+	}
+
 	// get current running service
 	currentService := executionContext.serviceStackTop()
 
 	// try from transient state first
 	value, found := executionContext.transientState.getValue(currentService, key)
 	if found {
-		return value, nil
+		return value, executionContext.gas.charge(GAS_COST_STATE_READ_PER_BYTE * uint64(len(value)))
 	}
+	executionContext.reads.add(currentService, key)
 
 	// try from batch transient state first
 	if executionContext.batchTransientState != nil {
 		value, found = executionContext.batchTransientState.getValue(currentService, key)
 		if found {
-			return value, nil
+			return value, executionContext.gas.charge(GAS_COST_STATE_READ_PER_BYTE * uint64(len(value)))
 		}
 	}
 
@@ -82,7 +87,7 @@ func (s *service) handleSdkStateRead(ctx context.Context, executionContext *exec
 	// store in transient state (cache)
 	executionContext.transientState.setValue(currentService, key, value, false)
 
-	return value, nil
+	return value, executionContext.gas.charge(GAS_COST_STATE_READ_PER_BYTE * uint64(len(value)))
 }
 
 // inputArg0: key ([]byte)
@@ -98,6 +103,10 @@ func (s *service) handleSdkStateWrite(executionContext *executionContext, args [
 	key := args[0].BytesValue()
 	value := args[1].BytesValue()
 
+	if err := executionContext.gas.charge(GAS_COST_STATE_WRITE + GAS_COST_STATE_WRITE_PER_BYTE*uint64(len(key)+len(value))); err != nil {
+		return err
+	}
+
 	// get current running service
 	currentService := executionContext.serviceStackTop()
 
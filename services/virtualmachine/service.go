@@ -8,6 +8,7 @@ package virtualmachine
 
 import (
 	"context"
+	"github.com/orbs-network/orbs-network-go/config"
 	"github.com/orbs-network/orbs-network-go/instrumentation/logfields"
 	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
 	"github.com/orbs-network/orbs-network-go/services/processor/native/repository/GlobalPreOrder"
@@ -18,6 +19,7 @@ import (
 	"github.com/orbs-network/orbs-spec/types/go/services/handlers"
 	"github.com/orbs-network/scribe/log"
 	"github.com/pkg/errors"
+	"sync"
 )
 
 var LogTag = log.Service("virtual-machine")
@@ -31,18 +33,25 @@ type service struct {
 	processors           map[protocol.ProcessorType]services.Processor
 	crosschainConnectors map[protocol.CrosschainConnectorType]services.CrosschainConnector
 	committeeProvider    CommitteeProvider
+	config               config.VirtualMachineConfig
 	logger               log.Logger
 
 	contexts *executionContextProvider
+
+	signatureVerifier struct {
+		sync.RWMutex
+		verifier TransactionSignatureVerifier
+	}
 }
 
-func NewVirtualMachine(stateStorage services.StateStorage, processors map[protocol.ProcessorType]services.Processor, crosschainConnectors map[protocol.CrosschainConnectorType]services.CrosschainConnector, committeeProvider CommitteeProvider, logger log.Logger, ) services.VirtualMachine {
+func NewVirtualMachine(stateStorage services.StateStorage, processors map[protocol.ProcessorType]services.Processor, crosschainConnectors map[protocol.CrosschainConnectorType]services.CrosschainConnector, committeeProvider CommitteeProvider, config config.VirtualMachineConfig, logger log.Logger, ) services.VirtualMachine {
 
 	s := &service{
 		processors:           processors,
 		crosschainConnectors: crosschainConnectors,
 		stateStorage:         stateStorage,
 		committeeProvider:    committeeProvider,
+		config:               config,
 		logger:               logger.WithTags(LogTag),
 
 		contexts: newExecutionContextProvider(),
@@ -79,7 +88,7 @@ func (s *service) ProcessQuery(ctx context.Context, input *services.ProcessQuery
 	}
 
 	logger.Info("running local method", log.Stringable("contract", input.SignedQuery.Query().ContractName()), log.Stringable("method", input.SignedQuery.Query().MethodName()), logfields.BlockHeight(committedBlockHeight))
-	callResult, outputArgs, outputEvents, err := s.runMethod(ctx, committedBlockHeight, committedBlockHeight, committedBlockTimestamp, committedBlockProposerAddress, input.SignedQuery.Query(), protocol.ACCESS_SCOPE_READ_ONLY, nil)
+	callResult, outputArgs, outputEvents, err := s.runMethod(ctx, committedBlockHeight, committedBlockHeight, committedBlockTimestamp, committedBlockProposerAddress, input.SignedQuery.Query(), protocol.ACCESS_SCOPE_READ_ONLY, nil, s.newQueryGasMeter(), nil, nil)
 	if outputArgs == nil {
 		outputArgs = protocol.ArgumentsArrayEmpty()
 	}
@@ -100,7 +109,11 @@ func (s *service) ProcessTransactionSet(ctx context.Context, input *services.Pro
 	logger := s.logger.WithTags(trace.LogFieldFrom(ctx))
 
 	logger.Info("processing transaction set", log.Int("num-transactions", len(input.SignedTransactions)), logfields.BlockHeight(input.CurrentBlockHeight))
-	receipts, stateDiffs := s.processTransactionSet(ctx, input.CurrentBlockHeight, input.CurrentBlockTimestamp, input.BlockProposerAddress, input.SignedTransactions)
+	receipts, stateDiffs, err := s.processTransactionSet(ctx, input.CurrentBlockHeight, input.CurrentBlockTimestamp, input.BlockProposerAddress, input.SignedTransactions, nil)
+	if err != nil {
+		logger.Info("failed processing transaction set", log.Error(err), logfields.BlockHeight(input.CurrentBlockHeight))
+		return nil, err
+	}
 
 	return &services.ProcessTransactionSetOutput{
 		TransactionReceipts: receipts,
@@ -129,6 +142,16 @@ func (s *service) TransactionSetPreOrder(ctx context.Context, input *services.Tr
 	// check signatures
 	s.verifyTransactionSignatures(input.SignedTransactions, statuses)
 
+	// check signer sequences
+	if s.config.TransactionSignerSequencingEnabled() {
+		if sequenceErr := s.verifySignerSequences(ctx, input.CurrentBlockHeight-1, input.SignedTransactions, statuses); sequenceErr != nil {
+			logger.Info("failed reading signer sequences", log.Error(sequenceErr), logfields.BlockHeight(input.CurrentBlockHeight))
+			return &services.TransactionSetPreOrderOutput{
+				PreOrderResults: statuses,
+			}, errors.Wrap(sequenceErr, "failed reading signer sequences")
+		}
+	}
+
 	if err != nil {
 		logger.Info("performed pre order checks", log.Error(err), logfields.BlockHeight(input.CurrentBlockHeight), log.Int("num-statuses", len(statuses)))
 	} else {
@@ -164,6 +187,7 @@ func (s *service) HandleSdkCall(ctx context.Context, input *handlers.HandleSdkCa
 		return nil, errors.Errorf("invalid execution context %s", input.ContextId)
 	}
 
+	call := executionContext.tracer.beginSdkCall(executionContext, input)
 	switch input.OperationName {
 	case sdk.SDK_OPERATION_NAME_STATE:
 		output, err = s.handleSdkStateCall(ctx, executionContext, input.MethodName, input.InputArguments, input.PermissionScope)
@@ -177,9 +201,12 @@ func (s *service) HandleSdkCall(ctx context.Context, input *handlers.HandleSdkCa
 		output, err = s.handleSdkAddressCall(ctx, executionContext, input.MethodName, input.InputArguments, input.PermissionScope)
 	case sdk.SDK_OPERATION_NAME_ENV:
 		output, err = s.handleSdkEnvCall(ctx, executionContext, input.MethodName, input.InputArguments, input.PermissionScope)
+	case sdk.SDK_OPERATION_NAME_GAS:
+		output, err = s.handleSdkGasCall(ctx, executionContext, input.MethodName, input.InputArguments, input.PermissionScope)
 	default:
-		return nil, errors.Errorf("unknown SDK call operation: %s", input.OperationName)
+		err = errors.Errorf("unknown SDK call operation: %s", input.OperationName)
 	}
+	executionContext.tracer.endSdkCall(call, output, err)
 
 	if err != nil {
 		return nil, err
@@ -10,7 +10,6 @@ import (
 	"github.com/orbs-network/orbs-network-go/crypto/digest"
 	"github.com/orbs-network/orbs-spec/types/go/primitives"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
-	"github.com/pkg/errors"
 )
 
 var EmptySignerAddress = []byte{0x0,0x0,0x0,0x0,0x0,0x0,0x0,0x0,0x0,0x0,0x0,0x0,0x0,0x0,0x0,0x0,0x0,0x0,0x0,0x0}
@@ -19,10 +18,5 @@ func (s *service) getSignerAddress(signer *protocol.Signer) (primitives.ClientAd
 	if len(signer.Raw()) == 0 {
 		return EmptySignerAddress, nil
 	}
-	switch signer.Scheme() {
-	case protocol.SIGNER_SCHEME_EDDSA:
-		return digest.CalcClientAddressOfEd25519Signer(signer)
-	default:
-		return nil, errors.New("transaction is not signed by any Signer")
-	}
+	return digest.CalcClientAddressOfSigner(signer)
 }
@@ -0,0 +1,144 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package virtualmachine
+
+import (
+	"context"
+	"encoding/binary"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/pkg/errors"
+)
+
+// With signer sequencing a signed transaction may carry a per-signer nonce (see digest.SignerNonce) and is then only
+// executed if its nonce follows that of the last executed transaction of its signer, which is kept in state under a
+// reserved contract name. This orders each signer's transactions strictly and protects them from replay for good
+// rather than only within the expiration window. A transaction whose nonce is ahead of its signer's sequence is held
+// in the transaction pool until its predecessors are executed.
+const SIGNER_SEQUENCES_CONTRACT_NAME = primitives.ContractName("_SignerSequences")
+
+// keyed by the signer's client address, which is how contracts identify a signer, so the same account has one
+// sequence however its signer is encoded (e.g. the order of the keys of a multi signature)
+func signerSequenceKey(signer *protocol.Signer) ([]byte, error) {
+	return digest.CalcClientAddressOfSigner(signer)
+}
+
+func encodeSignerSequence(nonce uint64) []byte {
+	value := make([]byte, 8)
+	binary.LittleEndian.PutUint64(value, nonce)
+	return value
+}
+
+func decodeSignerSequence(value []byte) uint64 {
+	if len(value) != 8 {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(value)
+}
+
+// returns the nonce of the last executed transaction of the signer, 0 if there is none
+func (s *service) lastSignerSequence(ctx context.Context, lastCommittedBlockHeight primitives.BlockHeight, batchTransientState *transientState, key []byte) (uint64, error) {
+	if batchTransientState != nil {
+		if value, found := batchTransientState.getValue(SIGNER_SEQUENCES_CONTRACT_NAME, key); found {
+			return decodeSignerSequence(value), nil
+		}
+	}
+
+	output, err := s.stateStorage.ReadKeys(ctx, &services.ReadKeysInput{
+		BlockHeight:  lastCommittedBlockHeight,
+		ContractName: SIGNER_SEQUENCES_CONTRACT_NAME,
+		Keys:         [][]byte{key},
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(output.StateRecords) == 0 {
+		return 0, errors.Errorf("state read returned no value")
+	}
+	return decodeSignerSequence(output.StateRecords[0].Value()), nil
+}
+
+// rejects transactions with a malformed or misplaced nonce, rejects transactions whose nonce is not above that of the last executed
+// transaction of their signer as already committed, and marks transactions whose nonce is ahead of their signer's
+// sequence (counting valid transactions of the same signer earlier in the set) as pending so the pool holds them
+func (s *service) verifySignerSequences(ctx context.Context, lastCommittedBlockHeight primitives.BlockHeight, signedTransactions []*protocol.SignedTransaction, resultStatuses []protocol.TransactionStatus) error {
+	setTransientState := newTransientState()
+	for i, signedTransaction := range signedTransactions {
+		transaction := signedTransaction.Transaction()
+		if resultStatuses[i] != protocol.TRANSACTION_STATUS_PRE_ORDER_VALID {
+			continue
+		}
+
+		nonce, found, err := digest.SignerNonce(transaction)
+		if err != nil {
+			resultStatuses[i] = protocol.TRANSACTION_STATUS_REJECTED_VIRTUAL_CHAIN_PRE_ORDER
+			continue
+		}
+		if !found {
+			continue
+		}
+
+		key, err := signerSequenceKey(transaction.Signer())
+		if err != nil {
+			resultStatuses[i] = protocol.TRANSACTION_STATUS_REJECTED_VIRTUAL_CHAIN_PRE_ORDER
+			continue
+		}
+		last, err := s.lastSignerSequence(ctx, lastCommittedBlockHeight, setTransientState, key)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case nonce <= last:
+			resultStatuses[i] = protocol.TRANSACTION_STATUS_DUPLICATE_TRANSACTION_ALREADY_COMMITTED
+		case nonce > last+1:
+			resultStatuses[i] = protocol.TRANSACTION_STATUS_PENDING
+		default:
+			setTransientState.setValue(SIGNER_SEQUENCES_CONTRACT_NAME, key, encodeSignerSequence(nonce), true)
+		}
+	}
+	return nil
+}
+
+// returns false if the transaction is out of sequence and must not be executed, otherwise advances the sequence of its
+// signer; an error reading the sequence fails the whole transaction set
+func (s *service) advanceSignerSequence(ctx context.Context, lastCommittedBlockHeight primitives.BlockHeight, batchTransientState *transientState, transaction *protocol.Transaction) (bool, error) {
+	nonce, found, err := digest.SignerNonce(transaction)
+	if err != nil {
+		return false, nil
+	}
+	if !found {
+		return true, nil
+	}
+
+	key, err := signerSequenceKey(transaction.Signer())
+	if err != nil {
+		return false, nil
+	}
+	last, err := s.lastSignerSequence(ctx, lastCommittedBlockHeight, batchTransientState, key)
+	if err != nil {
+		return false, err
+	}
+
+	if nonce != last+1 {
+		return false, nil
+	}
+	batchTransientState.setValue(SIGNER_SEQUENCES_CONTRACT_NAME, key, encodeSignerSequence(nonce), true)
+	return true, nil
+}
+
+// with signer sequencing the nonce carried by a transaction is not an argument of the called contract method
+func (s *service) contractInputArguments(transactionOrQuery TransactionOrQuery) (*protocol.ArgumentArray, error) {
+	if transaction, ok := transactionOrQuery.(*protocol.Transaction); ok && s.config.TransactionSignerSequencingEnabled() {
+		if _, found, _ := digest.SignerNonce(transaction); found {
+			return digest.InputArgumentsWithoutSignerNonce(transaction)
+		}
+	}
+	return protocol.ArgumentArrayReader(transactionOrQuery.RawInputArgumentArrayWithHeader()), nil
+}
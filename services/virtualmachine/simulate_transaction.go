@@ -0,0 +1,88 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package virtualmachine
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/instrumentation/logfields"
+	"github.com/orbs-network/orbs-network-go/instrumentation/trace"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/scribe/log"
+	"time"
+)
+
+// TransactionSimulator is implemented by the virtual machine service in addition to services.VirtualMachine
+type TransactionSimulator interface {
+	SimulateTransaction(ctx context.Context, input *SimulateTransactionInput) (*SimulateTransactionOutput, error)
+}
+
+type SimulateTransactionInput struct {
+	Transaction *protocol.Transaction // its signature is verified by the caller
+	Trace       bool
+}
+
+// ContractStateDiffs are the state changes the transaction would make if committed in the next block
+type SimulateTransactionOutput struct {
+	CallResult              protocol.ExecutionResult
+	OutputArgumentArray     primitives.PackedArgumentArray
+	OutputEventsArray       primitives.PackedEventsArray
+	ContractStateDiffs      []*protocol.ContractStateDiff
+	ReferenceBlockHeight    primitives.BlockHeight
+	ReferenceBlockTimestamp primitives.TimestampNano
+	Trace                   *ExecutionTrace // nil unless requested
+}
+
+// SimulateTransaction executes a transaction with write access as if it were the only transaction of the next block,
+// against the last committed state, and discards its state changes; signer sequences are not checked
+func (s *service) SimulateTransaction(ctx context.Context, input *SimulateTransactionInput) (*SimulateTransactionOutput, error) {
+	logger := s.logger.WithTags(trace.LogFieldFrom(ctx))
+
+	committedBlockHeight, committedBlockTimestamp, committedBlockProposerAddress, err := s.getRecentCommittedBlockInfo(ctx)
+	if err != nil {
+		return &SimulateTransactionOutput{
+			CallResult:              protocol.EXECUTION_RESULT_ERROR_UNEXPECTED,
+			OutputArgumentArray:     protocol.ArgumentsArrayEmpty().RawArgumentsArray(),
+			ReferenceBlockHeight:    committedBlockHeight,
+			ReferenceBlockTimestamp: committedBlockTimestamp,
+		}, err
+	}
+
+	// the proposer of the next block is not known yet, so the one of the last block is used
+	currentBlockHeight := committedBlockHeight + 1
+	currentBlockTimestamp := primitives.TimestampNano(time.Now().UnixNano())
+	if currentBlockTimestamp <= committedBlockTimestamp {
+		currentBlockTimestamp = committedBlockTimestamp + 1
+	}
+
+	logger.Info("simulating transaction", log.Stringable("contract", input.Transaction.ContractName()), log.Stringable("method", input.Transaction.MethodName()), logfields.BlockHeight(currentBlockHeight))
+	var tracer *executionTracer
+	if input.Trace {
+		tracer = newExecutionTracer(input.Transaction)
+	}
+	batchTransientState := newTransientState()
+	callResult, outputArgs, outputEvents, err := s.runMethod(ctx, committedBlockHeight, currentBlockHeight, currentBlockTimestamp, committedBlockProposerAddress, input.Transaction, protocol.ACCESS_SCOPE_READ_WRITE, batchTransientState, s.newTransactionGasMeter(input.Transaction, uint64(s.config.VirtualMachineGasLimitPerBlock())), nil, tracer)
+	if outputArgs == nil {
+		outputArgs = protocol.ArgumentsArrayEmpty()
+	}
+	if outputEvents == nil {
+		outputEvents = (&protocol.EventsArrayBuilder{}).Build()
+	}
+
+	output := &SimulateTransactionOutput{
+		CallResult:              callResult,
+		OutputArgumentArray:     outputArgs.RawArgumentsArray(),
+		OutputEventsArray:       outputEvents.RawEventsArray(),
+		ContractStateDiffs:      encodeBatchTransientStateToStateDiffs(batchTransientState),
+		ReferenceBlockHeight:    committedBlockHeight,
+		ReferenceBlockTimestamp: committedBlockTimestamp,
+	}
+	if tracer != nil {
+		output.Trace = tracer.trace
+	}
+	return output, err
+}
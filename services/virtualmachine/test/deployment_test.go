@@ -155,3 +155,95 @@ func TestFailingAutoDeployPreBuiltNativeContractDuringProcessTransactionSet(t *t
 		})
 	})
 }
+
+func TestProcessTransactionSet_WhenContractPaused(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+			h := newHarness(parent.Logger)
+
+			h.expectSystemContractCalled(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_INFO, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE), deployments_systemcontract.SERVICE_STATUS_PAUSED_ALLOWING_QUERIES)
+			h.expectNativeContractMethodNotCalled("Contract1", "method1")
+
+			results, outputArgs, _, _ := h.processTransactionSet(ctx, []*contractAndMethod{
+				{"Contract1", "method1"},
+			})
+			require.Equal(t, results, []protocol.ExecutionResult{
+				protocol.EXECUTION_RESULT_ERROR_CONTRACT_NOT_DEPLOYED,
+			}, "processTransactionSet returned receipts should match")
+			require.Equal(t, outputArgs, [][]byte{
+				builders.ArgumentsArray("Contract1: contract is paused").RawArgumentsArray(),
+			}, "processTransactionSet returned output args should tell the contract is paused")
+
+			h.verifySystemContractCalled(t)
+			h.verifyNativeContractMethodCalled(t)
+		})
+	})
+}
+
+func TestProcessQuery_WhenContractPausedAllowingQueries(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+			h := newHarness(parent.Logger)
+
+			h.expectSystemContractCalled(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_INFO, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE), deployments_systemcontract.SERVICE_STATUS_PAUSED_ALLOWING_QUERIES)
+			h.expectStateStorageLastCommittedBlockInfoBlockHeightRequested(12)
+			h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.ArgumentArray) (protocol.ExecutionResult, *protocol.ArgumentArray, error) {
+				return protocol.EXECUTION_RESULT_SUCCESS, builders.ArgumentsArray(), nil
+			})
+
+			result, _, _, _, err := h.processQuery(ctx, "Contract1", "method1")
+			require.NoError(t, err, "process query should not fail")
+			require.Equal(t, protocol.EXECUTION_RESULT_SUCCESS, result, "process query should run on a contract paused allowing queries")
+
+			h.verifySystemContractCalled(t)
+			h.verifyStateStorageBlockHeightRequested(t)
+			h.verifyNativeContractMethodCalled(t)
+		})
+	})
+}
+
+func TestProcessQuery_WhenContractDisabled(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+			h := newHarness(parent.Logger)
+
+			h.expectSystemContractCalled(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_INFO, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE), deployments_systemcontract.SERVICE_STATUS_DISABLED)
+			h.expectStateStorageLastCommittedBlockInfoBlockHeightRequested(12)
+			h.expectNativeContractMethodNotCalled("Contract1", "method1")
+
+			result, outputArgs, _, _, err := h.processQuery(ctx, "Contract1", "method1")
+			require.Error(t, err, "process query should fail")
+			require.Equal(t, protocol.EXECUTION_RESULT_ERROR_CONTRACT_NOT_DEPLOYED, result, "process query should return not deployed")
+			require.EqualValues(t, builders.ArgumentsArray("Contract1: contract is disabled").RawArgumentsArray(), outputArgs, "process query output args should tell the contract is disabled")
+
+			h.verifySystemContractCalled(t)
+			h.verifyStateStorageBlockHeightRequested(t)
+			h.verifyNativeContractMethodCalled(t)
+		})
+	})
+}
+
+func TestProcessTransactionSet_WhenCallingAPausedContract(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+			h := newHarness(parent.Logger)
+
+			h.expectSystemContractCalled(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_INFO, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE))
+			h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.ArgumentArray) (protocol.ExecutionResult, *protocol.ArgumentArray, error) {
+				h.expectSystemContractCalled(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_INFO, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE), deployments_systemcontract.SERVICE_STATUS_PAUSED)
+				_, err := h.handleSdkCall(ctx, executionContextId, sdk.SDK_OPERATION_NAME_SERVICE, "callMethod", "Contract2", "method1", builders.ArgumentsArray().Raw())
+				require.Error(t, err, "handleSdkCall should fail")
+				return protocol.EXECUTION_RESULT_SUCCESS, builders.ArgumentsArray(), nil // recovered from the failed call
+			})
+			h.expectNativeContractMethodNotCalled("Contract2", "method1")
+
+			results, outputArgs, _, _ := h.processTransactionSet(ctx, []*contractAndMethod{
+				{"Contract1", "method1"},
+			})
+			require.Equal(t, []protocol.ExecutionResult{protocol.EXECUTION_RESULT_ERROR_CONTRACT_NOT_DEPLOYED}, results, "the transaction should fail like a top level call to the paused contract")
+			require.Equal(t, [][]byte{builders.ArgumentsArray("Contract2: contract is paused").RawArgumentsArray()}, outputArgs, "the output args should tell which contract is paused")
+
+			h.verifyNativeContractMethodCalled(t)
+		})
+	})
+}
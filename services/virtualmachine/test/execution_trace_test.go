@@ -0,0 +1,136 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package test
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/crypto/hash"
+	"github.com/orbs-network/orbs-network-go/services/processor/native/repository/_Deployments"
+	"github.com/orbs-network/orbs-network-go/services/processor/sdk"
+	"github.com/orbs-network/orbs-network-go/services/virtualmachine"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-network-go/test/with"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSimulateTransaction_TracesNestedSdkCalls(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+
+			h := newHarness(parent.Logger)
+			h.expectSystemContractCalled(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_INFO, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+
+			h.expectStateStorageLastCommittedBlockInfoBlockHeightRequested(12)
+			h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.ArgumentArray) (protocol.ExecutionResult, *protocol.ArgumentArray, error) {
+				_, err := h.handleSdkCall(ctx, executionContextId, sdk.SDK_OPERATION_NAME_STATE, "write", []byte{0x01}, []byte{0x02})
+				require.NoError(t, err, "handleSdkCall should not fail")
+				_, err = h.handleSdkCall(ctx, executionContextId, sdk.SDK_OPERATION_NAME_SERVICE, "callMethod", "Contract2", "method1", builders.ArgumentsArray().Raw())
+				require.Error(t, err, "handleSdkCall should fail")
+				return protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT, builders.ArgumentsArray(), errors.New("contract panicked")
+			})
+			h.expectNativeContractMethodCalled("Contract2", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.ArgumentArray) (protocol.ExecutionResult, *protocol.ArgumentArray, error) {
+				_, err := h.handleSdkCall(ctx, executionContextId, sdk.SDK_OPERATION_NAME_STATE, "read", []byte{0x03})
+				require.NoError(t, err, "handleSdkCall should not fail")
+				return protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT, builders.ArgumentsArray(), errors.New("callee failed")
+			})
+			h.expectStateStorageRead(12, "Contract2", []byte{0x03}, []byte{0x04})
+
+			tx := builders.Transaction().WithMethod("Contract1", "method1").Build().Transaction()
+			output, _ := h.service.(virtualmachine.TransactionSimulator).SimulateTransaction(ctx, &virtualmachine.SimulateTransactionInput{Transaction: tx, Trace: true})
+			require.NotNil(t, output.Trace, "simulation should be traced")
+
+			trace := output.Trace
+			require.Equal(t, "Contract1", trace.Contract)
+			require.Equal(t, protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT.String(), trace.ExecutionResult)
+			require.Equal(t, "contract panicked", trace.Error)
+			require.Len(t, trace.Calls, 2, "should trace the calls of the transaction's contract")
+
+			require.Equal(t, "write", trace.Calls[0].Method)
+			require.Equal(t, []string{"0x01", "0x02"}, trace.Calls[0].InputArguments)
+
+			serviceCall := trace.Calls[1]
+			require.Equal(t, []string{"Contract1"}, serviceCall.ServiceStack)
+			require.Equal(t, "callee failed", serviceCall.Error)
+			require.Len(t, serviceCall.Calls, 1, "should nest the calls of the called service")
+			require.Equal(t, []string{"Contract1", "Contract2"}, serviceCall.Calls[0].ServiceStack)
+			require.Equal(t, []string{"0x04"}, serviceCall.Calls[0].OutputArguments)
+
+			h.verifySystemContractCalled(t)
+			h.verifyNativeContractMethodCalled(t)
+			h.verifyStateStorageRead(t)
+		})
+	})
+}
+
+func TestTraceTransaction_ExecutesOnTopOfPrecedingTransactions(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+
+			h := newHarness(parent.Logger)
+			h.expectSystemContractCalled(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_INFO, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+
+			h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.ArgumentArray) (protocol.ExecutionResult, *protocol.ArgumentArray, error) {
+				_, err := h.handleSdkCall(ctx, executionContextId, sdk.SDK_OPERATION_NAME_STATE, "write", []byte{0x01}, []byte{0x02})
+				require.NoError(t, err, "handleSdkCall should not fail")
+				return protocol.EXECUTION_RESULT_SUCCESS, builders.ArgumentsArray(), nil
+			})
+			h.expectNativeContractMethodCalled("Contract1", "method2", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.ArgumentArray) (protocol.ExecutionResult, *protocol.ArgumentArray, error) {
+				_, err := h.handleSdkCall(ctx, executionContextId, sdk.SDK_OPERATION_NAME_STATE, "read", []byte{0x01})
+				require.NoError(t, err, "handleSdkCall should not fail")
+				return protocol.EXECUTION_RESULT_SUCCESS, builders.ArgumentsArray(), nil
+			})
+			h.expectNativeContractMethodNotCalled("Contract1", "method3")
+			h.expectStateStorageLastCommittedBlockInfoBlockHeightRequested(12)
+			h.expectStateStorageNotRead()
+
+			output, err := h.service.(virtualmachine.TransactionTracer).TraceTransaction(ctx, &virtualmachine.TraceTransactionInput{
+				SignedTransactions: []*protocol.SignedTransaction{
+					builders.Transaction().WithMethod("Contract1", "method1").Build(),
+					builders.Transaction().WithMethod("Contract1", "method2").Build(),
+					builders.Transaction().WithMethod("Contract1", "method3").Build(),
+				},
+				TransactionIndex:      1,
+				CurrentBlockHeight:    12,
+				CurrentBlockTimestamp: 0x777,
+				BlockProposerAddress:  hash.Make32BytesWithFirstByte(5),
+			})
+			require.NoError(t, err, "trace transaction should not fail")
+
+			require.Equal(t, "method2", output.Trace.Method, "only the transaction at the index should be traced")
+			require.Equal(t, protocol.EXECUTION_RESULT_SUCCESS, output.Receipt.ExecutionResult(), "should return the receipt of the re-execution")
+			require.Len(t, output.Trace.Calls, 1)
+			require.Equal(t, []string{"0x02"}, output.Trace.Calls[0].OutputArguments, "the traced transaction should read the state written before it in the block")
+
+			h.verifySystemContractCalled(t)
+			h.verifyNativeContractMethodCalled(t)
+		})
+	})
+}
+
+func TestTraceTransaction_FailsForBlocksOlderThanTheStateStorageHistory(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+
+			h := newHarness(parent.Logger)
+			h.expectStateStorageLastCommittedBlockInfoBlockHeightRequested(20)
+			h.expectNativeContractMethodNotCalled("Contract1", "method1")
+
+			_, err := h.service.(virtualmachine.TransactionTracer).TraceTransaction(ctx, &virtualmachine.TraceTransactionInput{
+				SignedTransactions: []*protocol.SignedTransaction{builders.Transaction().WithMethod("Contract1", "method1").Build()},
+				TransactionIndex:   0,
+				CurrentBlockHeight: 12,
+			})
+			require.Error(t, err, "the state the block was executed on is no longer kept")
+
+			h.verifyNativeContractMethodCalled(t)
+		})
+	})
+}
@@ -0,0 +1,211 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package test
+
+import (
+	"context"
+	"fmt"
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/services/processor/native/repository/Triggers"
+	"github.com/orbs-network/orbs-network-go/services/processor/native/repository/_Deployments"
+	"github.com/orbs-network/orbs-network-go/services/processor/sdk"
+	"github.com/orbs-network/orbs-network-go/services/virtualmachine"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-network-go/test/with"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const GAS_OF_ONE_BYTE_WRITE = virtualmachine.GAS_COST_STATE_WRITE + 2*virtualmachine.GAS_COST_STATE_WRITE_PER_BYTE
+
+// the gas used is the last output argument of a metered transaction
+func outputArgsWithGasUsed(gasUsed uint64, args ...interface{}) []byte {
+	return builders.ArgumentsArray(append(args, gasUsed)...).RawArgumentsArray()
+}
+
+func TestGasMetering_AbortsTransactionThatExceedsItsLimit(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+
+			h := newHarnessWithConfig(parent.Logger, config.ForVirtualMachineGasMeteringTests(2*GAS_OF_ONE_BYTE_WRITE+1, 100*GAS_OF_ONE_BYTE_WRITE))
+			h.expectSystemContractCalled(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_INFO, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+
+			h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.ArgumentArray) (protocol.ExecutionResult, *protocol.ArgumentArray, error) {
+				for i := byte(1); i <= 2; i++ {
+					_, err := h.handleSdkCall(ctx, executionContextId, sdk.SDK_OPERATION_NAME_STATE, "write", []byte{i}, []byte{i})
+					require.NoError(t, err, "handleSdkCall should succeed within the gas limit")
+				}
+				return protocol.EXECUTION_RESULT_SUCCESS, builders.ArgumentsArray(), nil
+			})
+			h.expectNativeContractMethodCalled("Contract1", "method2", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.ArgumentArray) (protocol.ExecutionResult, *protocol.ArgumentArray, error) {
+				for i := byte(3); i <= 4; i++ {
+					_, err := h.handleSdkCall(ctx, executionContextId, sdk.SDK_OPERATION_NAME_STATE, "write", []byte{i}, []byte{i})
+					require.NoError(t, err, "handleSdkCall should succeed within the gas limit")
+				}
+				_, err := h.handleSdkCall(ctx, executionContextId, sdk.SDK_OPERATION_NAME_STATE, "write", []byte{0x05}, []byte{0x05})
+				require.Error(t, err, "handleSdkCall should fail when out of gas")
+				return protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT, builders.ArgumentsArray(), errors.New("out of gas")
+			})
+
+			results, outputArgs, stateDiffs, outputEvents := h.processTransactionSet(ctx, []*contractAndMethod{
+				{"Contract1", "method1"},
+				{"Contract1", "method2"},
+			})
+			require.Equal(t, []protocol.ExecutionResult{protocol.EXECUTION_RESULT_SUCCESS, virtualmachine.EXECUTION_RESULT_ERROR_OUT_OF_GAS}, results, "the transaction that ran out of gas should be aborted")
+			require.Equal(t, []*keyValuePair{{[]byte{0x01}, []byte{0x01}}, {[]byte{0x02}, []byte{0x02}}}, stateDiffs["Contract1"], "writes of the aborted transaction should be discarded")
+			require.EqualValues(t, outputArgsWithGasUsed(2*GAS_OF_ONE_BYTE_WRITE), outputArgs[0], "gas used should be recorded in the receipt")
+			require.EqualValues(t, outputArgsWithGasUsed(2*GAS_OF_ONE_BYTE_WRITE+1, fmt.Sprintf("out of gas, limit is %d", 2*GAS_OF_ONE_BYTE_WRITE+1)), outputArgs[1], "all the gas of an aborted transaction should be used")
+			require.EqualValues(t, builders.PackedEventsArrayEncode(), outputEvents[0], "gas used should not be reported as an event")
+
+			h.verifySystemContractCalled(t)
+			h.verifyNativeContractMethodCalled(t)
+		})
+	})
+}
+
+func TestGasMetering_DoesNotExecuteTransactionsOnceTheBlockRunsOutOfGas(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+
+			h := newHarnessWithConfig(parent.Logger, config.ForVirtualMachineGasMeteringTests(2*GAS_OF_ONE_BYTE_WRITE, 3*GAS_OF_ONE_BYTE_WRITE-1))
+			h.expectSystemContractCalled(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_INFO, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+
+			h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.ArgumentArray) (protocol.ExecutionResult, *protocol.ArgumentArray, error) {
+				for i := byte(1); i <= 2; i++ {
+					_, err := h.handleSdkCall(ctx, executionContextId, sdk.SDK_OPERATION_NAME_STATE, "write", []byte{i}, []byte{i})
+					require.NoError(t, err, "handleSdkCall should succeed within the gas limit")
+				}
+				return protocol.EXECUTION_RESULT_SUCCESS, builders.ArgumentsArray(), nil
+			})
+			h.expectNativeContractMethodCalled("Contract1", "method2", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.ArgumentArray) (protocol.ExecutionResult, *protocol.ArgumentArray, error) {
+				_, err := h.handleSdkCall(ctx, executionContextId, sdk.SDK_OPERATION_NAME_STATE, "write", []byte{0x03}, []byte{0x03})
+				require.Error(t, err, "handleSdkCall should fail when the block is out of gas")
+				return protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT, builders.ArgumentsArray(), errors.New("out of gas")
+			})
+			h.expectNativeContractMethodNotCalled("Contract1", "method3")
+
+			results, outputArgs, _, _ := h.processTransactionSet(ctx, []*contractAndMethod{
+				{"Contract1", "method1"},
+				{"Contract1", "method2"},
+				{"Contract1", "method3"},
+			})
+			require.Equal(t, []protocol.ExecutionResult{
+				protocol.EXECUTION_RESULT_SUCCESS,
+				virtualmachine.EXECUTION_RESULT_ERROR_OUT_OF_GAS,
+				protocol.EXECUTION_RESULT_NOT_EXECUTED,
+			}, results, "transactions should be limited by the gas left in the block")
+			require.EqualValues(t, outputArgsWithGasUsed(GAS_OF_ONE_BYTE_WRITE-1, fmt.Sprintf("out of gas, limit is %d", GAS_OF_ONE_BYTE_WRITE-1)), outputArgs[1], "the second transaction should be limited to the gas left in the block")
+			require.EqualValues(t, outputArgsWithGasUsed(0), outputArgs[2], "a transaction that was not executed should use no gas")
+
+			h.verifySystemContractCalled(t)
+			h.verifyNativeContractMethodCalled(t)
+		})
+	})
+}
+
+func TestGasMetering_DoesNotMeterSystemTransactions(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+
+			h := newHarnessWithConfig(parent.Logger, config.ForVirtualMachineGasMeteringTests(GAS_OF_ONE_BYTE_WRITE, GAS_OF_ONE_BYTE_WRITE))
+			h.expectSystemContractCalled(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_INFO, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+
+			h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.ArgumentArray) (protocol.ExecutionResult, *protocol.ArgumentArray, error) {
+				_, err := h.handleSdkCall(ctx, executionContextId, sdk.SDK_OPERATION_NAME_STATE, "write", []byte{0x01}, []byte{0x01})
+				require.NoError(t, err, "handleSdkCall should succeed within the gas limit")
+				return protocol.EXECUTION_RESULT_SUCCESS, builders.ArgumentsArray(), nil
+			})
+			h.expectNativeContractMethodCalled(triggers_systemcontract.CONTRACT_NAME, triggers_systemcontract.METHOD_TRIGGER, func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.ArgumentArray) (protocol.ExecutionResult, *protocol.ArgumentArray, error) {
+				for i := byte(2); i <= 3; i++ {
+					_, err := h.handleSdkCall(ctx, executionContextId, sdk.SDK_OPERATION_NAME_STATE, "write", []byte{i}, []byte{i})
+					require.NoError(t, err, "handleSdkCall of a system transaction should not be metered")
+				}
+				return protocol.EXECUTION_RESULT_SUCCESS, builders.ArgumentsArray(), nil
+			})
+
+			output, err := h.service.ProcessTransactionSet(ctx, &services.ProcessTransactionSetInput{
+				SignedTransactions: []*protocol.SignedTransaction{
+					builders.Transaction().WithMethod("Contract1", "method1").Build(),
+					builders.TriggerTransaction().Build(),
+				},
+				CurrentBlockHeight: 12,
+			})
+			require.NoError(t, err)
+
+			require.Equal(t, protocol.EXECUTION_RESULT_SUCCESS, output.TransactionReceipts[0].ExecutionResult())
+			require.Equal(t, protocol.EXECUTION_RESULT_SUCCESS, output.TransactionReceipts[1].ExecutionResult(), "a system transaction should run although the block is out of gas")
+			require.EqualValues(t, builders.ArgumentsArray().RawArgumentsArray(), output.TransactionReceipts[1].RawOutputArgumentArray(), "no gas should be recorded for a system transaction")
+
+			h.verifySystemContractCalled(t)
+			h.verifyNativeContractMethodCalled(t)
+		})
+	})
+}
+
+func TestGasMetering_ChargesComputationSteps(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+
+			h := newHarnessWithConfig(parent.Logger, config.ForVirtualMachineGasMeteringTests(10*virtualmachine.GAS_COST_COMPUTATION_STEP, 100*GAS_OF_ONE_BYTE_WRITE))
+			h.expectSystemContractCalled(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_INFO, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+
+			h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.ArgumentArray) (protocol.ExecutionResult, *protocol.ArgumentArray, error) {
+				_, err := h.handleSdkCallWithSystemPermissions(ctx, executionContextId, sdk.SDK_OPERATION_NAME_GAS, "chargeComputation", uint64(4))
+				require.NoError(t, err, "handleSdkCall should succeed within the gas limit")
+				return protocol.EXECUTION_RESULT_SUCCESS, builders.ArgumentsArray(), nil
+			})
+			h.expectNativeContractMethodCalled("Contract1", "method2", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.ArgumentArray) (protocol.ExecutionResult, *protocol.ArgumentArray, error) {
+				_, err := h.handleSdkCallWithSystemPermissions(ctx, executionContextId, sdk.SDK_OPERATION_NAME_GAS, "chargeComputation", uint64(11))
+				require.Error(t, err, "handleSdkCall should fail when out of gas")
+				return protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT, builders.ArgumentsArray(), err
+			})
+
+			results, outputArgs, _, _ := h.processTransactionSet(ctx, []*contractAndMethod{
+				{"Contract1", "method1"},
+				{"Contract1", "method2"},
+			})
+			require.Equal(t, []protocol.ExecutionResult{protocol.EXECUTION_RESULT_SUCCESS, virtualmachine.EXECUTION_RESULT_ERROR_OUT_OF_GAS}, results)
+			require.EqualValues(t, outputArgsWithGasUsed(4*virtualmachine.GAS_COST_COMPUTATION_STEP), outputArgs[0], "the computation steps should be charged")
+			require.EqualValues(t, outputArgsWithGasUsed(10, "out of gas, limit is 10"), outputArgs[1], "the transaction should fail with an out of gas message")
+
+			h.verifySystemContractCalled(t)
+			h.verifyNativeContractMethodCalled(t)
+		})
+	})
+}
+
+func TestGasMetering_DoesNotMeterLoadingTheCodeOfAContract(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+
+			h := newHarnessWithConfig(parent.Logger, config.ForVirtualMachineGasMeteringTests(GAS_OF_ONE_BYTE_WRITE, 100*GAS_OF_ONE_BYTE_WRITE))
+			h.expectSystemContractCalled(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_INFO, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+			h.expectSystemContractCalled(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_CODE_VERSION, nil, uint32(1))
+
+			h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.ArgumentArray) (protocol.ExecutionResult, *protocol.ArgumentArray, error) {
+				_, err := h.handleSdkCallWithSystemPermissions(ctx, executionContextId, sdk.SDK_OPERATION_NAME_SERVICE, "callMethod", deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_CODE_VERSION, builders.ArgumentsArray("Contract1").Raw())
+				require.NoError(t, err, "the processor loading the code of a contract should not be metered")
+				_, err = h.handleSdkCall(ctx, executionContextId, sdk.SDK_OPERATION_NAME_STATE, "write", []byte{0x01}, []byte{0x01})
+				require.NoError(t, err, "handleSdkCall should succeed within the gas limit")
+				return protocol.EXECUTION_RESULT_SUCCESS, builders.ArgumentsArray(), nil
+			})
+
+			results, outputArgs, _, _ := h.processTransactionSet(ctx, []*contractAndMethod{
+				{"Contract1", "method1"},
+			})
+			require.Equal(t, []protocol.ExecutionResult{protocol.EXECUTION_RESULT_SUCCESS}, results)
+			require.EqualValues(t, outputArgsWithGasUsed(GAS_OF_ONE_BYTE_WRITE), outputArgs[0], "loading the code should use no gas")
+
+			h.verifySystemContractCalled(t)
+			h.verifyNativeContractMethodCalled(t)
+		})
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/orbs-network/go-mock"
+	"github.com/orbs-network/orbs-network-go/config"
 	"github.com/orbs-network/orbs-network-go/crypto/hash"
 	"github.com/orbs-network/orbs-network-go/services/virtualmachine"
 	"github.com/orbs-network/orbs-network-go/test/builders"
@@ -31,6 +32,10 @@ type harness struct {
 }
 
 func newHarness(logger log.Logger) *harness {
+	return newHarnessWithConfig(logger, config.ForVirtualMachineTests(false))
+}
+
+func newHarnessWithConfig(logger log.Logger, cfg config.VirtualMachineConfig) *harness {
 
 	blockStorage := &services.MockBlockStorage{}
 	stateStorage := &services.MockStateStorage{}
@@ -53,7 +58,7 @@ func newHarness(logger log.Logger) *harness {
 	}
 
 	committeeProvider := NewTestCommitteeProvider(4)
-	service := virtualmachine.NewVirtualMachine(stateStorage, processorsForService, crosschainConnectorsForService, committeeProvider, logger)
+	service := virtualmachine.NewVirtualMachine(stateStorage, processorsForService, crosschainConnectorsForService, committeeProvider, cfg, logger)
 
 	return &harness{
 		blockStorage:         blockStorage,
@@ -0,0 +1,182 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package test
+
+import (
+	"context"
+	"fmt"
+	"github.com/orbs-network/go-mock"
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/services/processor/native/repository/_Deployments"
+	"github.com/orbs-network/orbs-network-go/services/processor/sdk"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-network-go/test/with"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// contract methods may run more than once with parallel execution, so they must not fail the test on their own
+func (h *harness) expectNativeContractMethodCalledAtLeastOnce(expectedContractName primitives.ContractName, expectedMethodName primitives.MethodName, contractFunction func(context.Context, primitives.ExecutionContextId) (protocol.ExecutionResult, *protocol.ArgumentArray, error)) {
+	contractMethodMatcher := func(i interface{}) bool {
+		input, ok := i.(*services.ProcessCallInput)
+		return ok && input.ContractName == expectedContractName && input.MethodName == expectedMethodName
+	}
+
+	h.processors[protocol.PROCESSOR_TYPE_NATIVE].When("ProcessCall", mock.Any, mock.AnyIf(fmt.Sprintf("Contract equals %s and Method %s", expectedContractName, expectedMethodName), contractMethodMatcher)).Call(func(ctx context.Context, input *services.ProcessCallInput) (*services.ProcessCallOutput, error) {
+		callResult, outputArgsArray, err := contractFunction(ctx, input.ContextId)
+		return &services.ProcessCallOutput{
+			OutputArgumentArray: outputArgsArray,
+			CallResult:          callResult,
+		}, err
+	}).AtLeast(1)
+}
+
+// every key in state storage holds a single byte, 0x10 more than the key's first byte
+func (h *harness) expectStateStorageReadOfAnyKey() {
+	h.stateStorage.When("ReadKeys", mock.Any, mock.Any).Call(func(ctx context.Context, input *services.ReadKeysInput) (*services.ReadKeysOutput, error) {
+		return &services.ReadKeysOutput{
+			StateRecords: []*protocol.StateRecord{(&protocol.StateRecordBuilder{
+				Key:   input.Keys[0],
+				Value: []byte{input.Keys[0][0] + 0x10},
+			}).Build()},
+		}, nil
+	})
+}
+
+type executedTransactionSet struct {
+	results      []protocol.ExecutionResult
+	outputArgs   [][]byte
+	stateDiffs   map[primitives.ContractName][]*keyValuePair
+	outputEvents [][]byte
+}
+
+func executeTransactionSetWithWorkers(ctx context.Context, parent *with.LoggingHarness, workers uint32) *executedTransactionSet {
+	h := newHarnessWithConfig(parent.Logger, config.ForVirtualMachineParallelExecutionTests(workers))
+	h.expectSystemContractCalled(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_INFO, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+	h.expectStateStorageReadOfAnyKey()
+
+	h.expectNativeContractMethodCalledAtLeastOnce("Contract1", "increment", func(ctx context.Context, executionContextId primitives.ExecutionContextId) (protocol.ExecutionResult, *protocol.ArgumentArray, error) {
+		res, err := h.handleSdkCall(ctx, executionContextId, sdk.SDK_OPERATION_NAME_STATE, "read", []byte{0x01})
+		if err != nil {
+			return protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT, builders.ArgumentsArray(), err
+		}
+		value := res[0].BytesValue()[0] + 1
+		if _, err := h.handleSdkCall(ctx, executionContextId, sdk.SDK_OPERATION_NAME_STATE, "write", []byte{0x01}, []byte{value}); err != nil {
+			return protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT, builders.ArgumentsArray(), err
+		}
+		return protocol.EXECUTION_RESULT_SUCCESS, builders.ArgumentsArray([]byte{value}), nil
+	})
+	h.expectNativeContractMethodCalledAtLeastOnce("Contract1", "overwrite", func(ctx context.Context, executionContextId primitives.ExecutionContextId) (protocol.ExecutionResult, *protocol.ArgumentArray, error) {
+		if _, err := h.handleSdkCall(ctx, executionContextId, sdk.SDK_OPERATION_NAME_STATE, "write", []byte{0x02}, []byte{0x02}); err != nil {
+			return protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT, builders.ArgumentsArray(), err
+		}
+		return protocol.EXECUTION_RESULT_SUCCESS, builders.ArgumentsArray(), nil
+	})
+	h.expectNativeContractMethodCalledAtLeastOnce("Contract2", "readAndEmit", func(ctx context.Context, executionContextId primitives.ExecutionContextId) (protocol.ExecutionResult, *protocol.ArgumentArray, error) {
+		res, err := h.handleSdkCall(ctx, executionContextId, sdk.SDK_OPERATION_NAME_STATE, "read", []byte{0x03})
+		if err != nil {
+			return protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT, builders.ArgumentsArray(), err
+		}
+		if _, err := h.handleSdkCall(ctx, executionContextId, sdk.SDK_OPERATION_NAME_EVENTS, "emitEvent", "Read", builders.ArgumentsArray(res[0].BytesValue()).Raw()); err != nil {
+			return protocol.EXECUTION_RESULT_ERROR_SMART_CONTRACT, builders.ArgumentsArray(), err
+		}
+		return protocol.EXECUTION_RESULT_SUCCESS, builders.ArgumentsArray(), nil
+	})
+
+	results, outputArgs, stateDiffs, outputEvents := h.processTransactionSet(ctx, []*contractAndMethod{
+		{"Contract1", "increment"},
+		{"Contract1", "overwrite"},
+		{"Contract2", "readAndEmit"},
+		{"Contract1", "increment"},
+		{"Contract1", "overwrite"},
+		{"Contract1", "increment"},
+	})
+	return &executedTransactionSet{results, outputArgs, stateDiffs, outputEvents}
+}
+
+func TestParallelExecution_ProducesTheSameResultsAsSequentialExecution(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+			sequential := executeTransactionSetWithWorkers(ctx, parent, 0)
+			parallel := executeTransactionSetWithWorkers(ctx, parent, 4)
+
+			require.Equal(t, []*keyValuePair{{[]byte{0x01}, []byte{0x14}}, {[]byte{0x02}, []byte{0x02}}}, sequential.stateDiffs["Contract1"], "each increment should see the one before it")
+			require.Equal(t, builders.ArgumentsArray([]byte{0x14}).RawArgumentsArray(), sequential.outputArgs[5])
+
+			require.Equal(t, sequential.results, parallel.results, "results should be the same as sequential execution")
+			require.Equal(t, sequential.outputArgs, parallel.outputArgs, "output arguments should be the same as sequential execution")
+			require.Equal(t, sequential.stateDiffs, parallel.stateDiffs, "state diffs should be the same as sequential execution")
+			require.Equal(t, sequential.outputEvents, parallel.outputEvents, "output events should be the same as sequential execution")
+		})
+	})
+}
+
+type parallelExecutionConfig struct {
+	config.VirtualMachineConfig
+	workers uint32
+}
+
+func (c *parallelExecutionConfig) VirtualMachineParallelExecutionWorkers() uint32 {
+	return c.workers
+}
+
+func TestParallelExecution_DoesNotSpeculateTransactionsOnceTheBlockRanOutOfGas(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+			h := newHarnessWithConfig(parent.Logger, &parallelExecutionConfig{config.ForVirtualMachineGasMeteringTests(GAS_OF_ONE_BYTE_WRITE, GAS_OF_ONE_BYTE_WRITE), 1})
+			h.expectSystemContractCalled(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_INFO, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+
+			h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.ArgumentArray) (protocol.ExecutionResult, *protocol.ArgumentArray, error) {
+				_, err := h.handleSdkCall(ctx, executionContextId, sdk.SDK_OPERATION_NAME_STATE, "write", []byte{0x01}, []byte{0x01})
+				require.NoError(t, err, "handleSdkCall should succeed within the gas limit")
+				return protocol.EXECUTION_RESULT_SUCCESS, builders.ArgumentsArray(), nil
+			})
+			h.expectNativeContractMethodNotCalled("Contract1", "method2")
+
+			results, _, _, _ := h.processTransactionSet(ctx, []*contractAndMethod{
+				{"Contract1", "method1"},
+				{"Contract1", "method2"},
+			})
+			require.Equal(t, []protocol.ExecutionResult{protocol.EXECUTION_RESULT_SUCCESS, protocol.EXECUTION_RESULT_NOT_EXECUTED}, results)
+
+			h.verifyNativeContractMethodCalled(t)
+		})
+	})
+}
+
+func TestParallelExecution_DoesNotSpeculateTransactionsOutOfTheirSignerSequence(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+			h := newHarnessWithConfig(parent.Logger, &parallelExecutionConfig{config.ForVirtualMachineTests(true), 1})
+			h.expectSystemContractCalled(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_INFO, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+			h.stateStorage.When("ReadKeys", mock.Any, mock.Any).Return(&services.ReadKeysOutput{
+				StateRecords: []*protocol.StateRecord{(&protocol.StateRecordBuilder{Value: signerSequenceValue(5)}).Build()},
+			}, nil)
+
+			h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.ArgumentArray) (protocol.ExecutionResult, *protocol.ArgumentArray, error) {
+				return protocol.EXECUTION_RESULT_SUCCESS, builders.ArgumentsArray(), nil
+			})
+			h.expectNativeContractMethodNotCalled("Contract1", "method2")
+
+			output, err := h.service.ProcessTransactionSet(ctx, &services.ProcessTransactionSetInput{
+				CurrentBlockHeight: 12,
+				SignedTransactions: []*protocol.SignedTransaction{
+					builders.Transaction().WithMethod("Contract1", "method1").WithSignerNonce(6).Build(),
+					builders.Transaction().WithMethod("Contract1", "method2").WithSignerNonce(8).Build(),
+				},
+			})
+			require.NoError(t, err)
+			require.Equal(t, protocol.EXECUTION_RESULT_SUCCESS, output.TransactionReceipts[0].ExecutionResult())
+			require.Equal(t, protocol.EXECUTION_RESULT_NOT_EXECUTED, output.TransactionReceipts[1].ExecutionResult())
+
+			h.verifyNativeContractMethodCalled(t)
+		})
+	})
+}
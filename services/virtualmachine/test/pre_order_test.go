@@ -8,6 +8,7 @@ package test
 
 import (
 	"context"
+	cryptoKeys "github.com/orbs-network/orbs-network-go/crypto/keys"
 	"github.com/orbs-network/orbs-network-go/services/processor/native/repository/GlobalPreOrder"
 	"github.com/orbs-network/orbs-network-go/test/builders"
 	"github.com/orbs-network/orbs-network-go/test/crypto/keys"
@@ -39,6 +40,31 @@ func TestPreOrder_DifferentSignerSchemes(t *testing.T) {
 			tx:     builders.Transaction().WithEd25519Signer(keys.Ed25519KeyPairForTests(1)).Build(),
 			status: protocol.TRANSACTION_STATUS_PRE_ORDER_VALID,
 		},
+		{
+			name:   "InvalidEcdsaSecp256K1Signature",
+			tx:     aTransactionWithCorruptSignature(builders.Transaction().WithEcdsaSecp256K1Signer(keys.EcdsaSecp256K1KeyPairForTests(1).EcdsaSecp256K1KeyPair).Build()),
+			status: protocol.TRANSACTION_STATUS_REJECTED_SIGNATURE_MISMATCH,
+		},
+		{
+			name:   "ValidEcdsaSecp256K1Signature",
+			tx:     builders.Transaction().WithEcdsaSecp256K1Signer(keys.EcdsaSecp256K1KeyPairForTests(1).EcdsaSecp256K1KeyPair).Build(),
+			status: protocol.TRANSACTION_STATUS_PRE_ORDER_VALID,
+		},
+		{
+			name:   "MultiSigBelowThreshold",
+			tx:     builders.Transaction().WithMultiSigSigner(2, multiSigKeyPairs(), 0).Build(),
+			status: protocol.TRANSACTION_STATUS_REJECTED_SIGNATURE_MISMATCH,
+		},
+		{
+			name:   "CorruptMultiSignature",
+			tx:     aTransactionWithCorruptSignature(builders.Transaction().WithMultiSigSigner(1, multiSigKeyPairs(), 0).Build()),
+			status: protocol.TRANSACTION_STATUS_REJECTED_SIGNATURE_MISMATCH,
+		},
+		{
+			name:   "ValidMultiSigSignature",
+			tx:     builders.Transaction().WithMultiSigSigner(2, multiSigKeyPairs(), 0, 2).Build(),
+			status: protocol.TRANSACTION_STATUS_PRE_ORDER_VALID,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -59,6 +85,17 @@ func TestPreOrder_DifferentSignerSchemes(t *testing.T) {
 	}
 }
 
+func multiSigKeyPairs() []*cryptoKeys.Ed25519KeyPair {
+	return []*cryptoKeys.Ed25519KeyPair{keys.Ed25519KeyPairForTests(1), keys.Ed25519KeyPairForTests(2), keys.Ed25519KeyPairForTests(3)}
+}
+
+func aTransactionWithCorruptSignature(tx *protocol.SignedTransaction) *protocol.SignedTransaction {
+	sig := append([]byte{}, tx.Signature()...)
+	sig[0] ^= 0xff
+	tx.MutateSignature(sig)
+	return tx
+}
+
 func TestPreOrder_GlobalSubscriptionContractNotApproved(t *testing.T) {
 	with.Context(func(ctx context.Context) {
 		with.Logging(t, func(parent *with.LoggingHarness) {
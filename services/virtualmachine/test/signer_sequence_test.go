@@ -0,0 +1,108 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package test
+
+import (
+	"context"
+	"encoding/binary"
+	"github.com/orbs-network/go-mock"
+	"github.com/orbs-network/orbs-network-go/config"
+	"github.com/orbs-network/orbs-network-go/crypto/digest"
+	"github.com/orbs-network/orbs-network-go/services/processor/native/repository/GlobalPreOrder"
+	"github.com/orbs-network/orbs-network-go/services/virtualmachine"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-network-go/test/crypto/keys"
+	"github.com/orbs-network/orbs-network-go/test/with"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/orbs-network/orbs-spec/types/go/services"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func signerSequenceValue(nonce uint64) []byte {
+	value := make([]byte, 8)
+	binary.LittleEndian.PutUint64(value, nonce)
+	return value
+}
+
+func clientAddressOf(t *testing.T, transaction *protocol.SignedTransaction) []byte {
+	clientAddress, err := digest.CalcClientAddressOfSigner(transaction.Transaction().Signer())
+	require.NoError(t, err)
+	return clientAddress
+}
+
+func TestPreOrder_SignerSequencingRejectsPastNoncesAndHoldsNoncesAheadOfTheSignerSequence(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+			h := newHarnessWithConfig(parent.Logger, config.ForVirtualMachineTests(true))
+
+			h.expectSystemContractCalled(globalpreorder_systemcontract.CONTRACT_NAME, globalpreorder_systemcontract.METHOD_APPROVE, nil)
+
+			keyPair := keys.Ed25519KeyPairForTests(1)
+			txs := []*protocol.SignedTransaction{
+				builders.Transaction().WithEd25519Signer(keyPair).WithSignerNonce(6).Build(),
+				builders.Transaction().WithEd25519Signer(keyPair).WithSignerNonce(5).Build(),
+				builders.Transaction().WithEd25519Signer(keyPair).WithSignerNonce(8).Build(),
+				builders.Transaction().WithEd25519Signer(keyPair).WithSignerNonce(7).Build(),
+				builders.Transaction().WithEd25519Signer(keyPair).Build(),
+				builders.Transaction().WithEd25519Signer(keyPair).WithArgs(uint64(10), digest.SignerNonceArgument(9)).Build(),
+			}
+			h.expectStateStorageRead(11, virtualmachine.SIGNER_SEQUENCES_CONTRACT_NAME, clientAddressOf(t, txs[0]), signerSequenceValue(5))
+
+			results, err := h.transactionSetPreOrder(ctx, txs)
+			require.NoError(t, err, "transaction set pre order should not fail")
+			require.Equal(t, []protocol.TransactionStatus{
+				protocol.TRANSACTION_STATUS_PRE_ORDER_VALID,
+				protocol.TRANSACTION_STATUS_DUPLICATE_TRANSACTION_ALREADY_COMMITTED,
+				protocol.TRANSACTION_STATUS_PENDING,
+				protocol.TRANSACTION_STATUS_PRE_ORDER_VALID,
+				protocol.TRANSACTION_STATUS_PRE_ORDER_VALID,
+				protocol.TRANSACTION_STATUS_REJECTED_VIRTUAL_CHAIN_PRE_ORDER,
+			}, results, "past nonces should be rejected, nonces ahead of the sequence held, transactions without a nonce not sequenced and misplaced nonces rejected")
+
+			h.verifySystemContractCalled(t)
+			h.verifyStateStorageRead(t)
+		})
+	})
+}
+
+func TestProcessTransactionSet_SignerSequencingFailsTheSetWhenTheSignerSequenceCannotBeRead(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+			h := newHarnessWithConfig(parent.Logger, config.ForVirtualMachineTests(true))
+			h.stateStorage.When("ReadKeys", mock.Any, mock.Any).Return(nil, errors.New("state storage failed")).Times(1)
+
+			_, err := h.service.ProcessTransactionSet(ctx, &services.ProcessTransactionSetInput{
+				SignedTransactions: []*protocol.SignedTransaction{builders.Transaction().WithSignerNonce(1).Build()},
+				CurrentBlockHeight: 12,
+			})
+			require.Error(t, err, "a failed read of the signer sequence should fail the transaction set rather than skip the transaction")
+		})
+	})
+}
+
+func TestPreOrder_SignerSequencingDisabledDoesNotReadState(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+			h := newHarness(parent.Logger)
+
+			h.expectSystemContractCalled(globalpreorder_systemcontract.CONTRACT_NAME, globalpreorder_systemcontract.METHOD_APPROVE, nil)
+			h.expectStateStorageNotRead()
+
+			keyPair := keys.Ed25519KeyPairForTests(1)
+			tx := builders.Transaction().WithEd25519Signer(keyPair).WithTimestamp(time.Unix(0, 1000)).Build()
+
+			results, err := h.transactionSetPreOrder(ctx, []*protocol.SignedTransaction{tx, tx})
+			require.NoError(t, err, "transaction set pre order should not fail")
+			require.Equal(t, []protocol.TransactionStatus{protocol.TRANSACTION_STATUS_PRE_ORDER_VALID, protocol.TRANSACTION_STATUS_PRE_ORDER_VALID}, results)
+
+			h.verifyStateStorageRead(t)
+		})
+	})
+}
@@ -0,0 +1,63 @@
+// Copyright 2019 the orbs-network-go authors
+// This file is part of the orbs-network-go library in the Orbs project.
+//
+// This source code is licensed under the MIT license found in the LICENSE file in the root directory of this source tree.
+// The above notice should be included in all copies or substantial portions of the software.
+
+package test
+
+import (
+	"context"
+	"github.com/orbs-network/orbs-network-go/services/processor/native/repository/_Deployments"
+	"github.com/orbs-network/orbs-network-go/services/processor/sdk"
+	"github.com/orbs-network/orbs-network-go/services/virtualmachine"
+	"github.com/orbs-network/orbs-network-go/test/builders"
+	"github.com/orbs-network/orbs-network-go/test/with"
+	"github.com/orbs-network/orbs-spec/types/go/primitives"
+	"github.com/orbs-network/orbs-spec/types/go/protocol"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSimulateTransaction_ReturnsStateDiffsWithoutCommitting(t *testing.T) {
+	with.Context(func(ctx context.Context) {
+		with.Logging(t, func(parent *with.LoggingHarness) {
+
+			h := newHarness(parent.Logger)
+			h.expectSystemContractCalled(deployments_systemcontract.CONTRACT_NAME, deployments_systemcontract.METHOD_GET_INFO, nil, uint32(protocol.PROCESSOR_TYPE_NATIVE)) // assume all contracts are deployed
+
+			h.expectStateStorageLastCommittedBlockInfoBlockHeightRequested(12)
+			h.expectNativeContractMethodCalled("Contract1", "method1", func(executionContextId primitives.ExecutionContextId, inputArgs *protocol.ArgumentArray) (protocol.ExecutionResult, *protocol.ArgumentArray, error) {
+				res, err := h.handleSdkCall(ctx, executionContextId, sdk.SDK_OPERATION_NAME_STATE, "read", []byte{0x01})
+				require.NoError(t, err, "handleSdkCall should not fail")
+				require.Equal(t, []byte{0x02}, res[0].BytesValue(), "reads should be from the last committed block")
+
+				_, err = h.handleSdkCall(ctx, executionContextId, sdk.SDK_OPERATION_NAME_STATE, "write", []byte{0x01}, []byte{0x03})
+				require.NoError(t, err, "simulated transactions should have write access")
+
+				return protocol.EXECUTION_RESULT_SUCCESS, builders.ArgumentsArray(uint32(17)), nil
+			})
+			h.expectStateStorageRead(12, "Contract1", []byte{0x01}, []byte{0x02})
+
+			tx := builders.Transaction().WithMethod("Contract1", "method1").Build().Transaction()
+			output, err := h.service.(virtualmachine.TransactionSimulator).SimulateTransaction(ctx, &virtualmachine.SimulateTransactionInput{Transaction: tx})
+			require.NoError(t, err, "simulate transaction should not fail")
+			require.Equal(t, protocol.EXECUTION_RESULT_SUCCESS, output.CallResult)
+			expectedOutputArgs, err := protocol.PackedInputArgumentsFromNatives(builders.VarsToSlice(uint32(17)))
+			require.NoError(t, err, "output args must pack")
+			require.EqualValues(t, expectedOutputArgs, output.OutputArgumentArray)
+			require.EqualValues(t, 12, output.ReferenceBlockHeight)
+
+			require.Len(t, output.ContractStateDiffs, 1, "the write should be returned as a state diff")
+			require.EqualValues(t, "Contract1", output.ContractStateDiffs[0].ContractName())
+			stateDiff := output.ContractStateDiffs[0].StateDiffsIterator().NextStateDiffs()
+			require.Equal(t, []byte{0x01}, stateDiff.Key())
+			require.Equal(t, []byte{0x03}, stateDiff.Value())
+
+			h.verifySystemContractCalled(t)
+			h.verifyStateStorageBlockHeightRequested(t)
+			h.verifyNativeContractMethodCalled(t)
+			h.verifyStateStorageRead(t)
+		})
+	})
+}
@@ -7,36 +7,37 @@
 package virtualmachine
 
 import (
-	"github.com/orbs-network/orbs-network-go/crypto/digest"
-	"github.com/orbs-network/orbs-network-go/crypto/signature"
+	"github.com/orbs-network/orbs-network-go/crypto/validators"
 	"github.com/orbs-network/orbs-spec/types/go/protocol"
 )
 
-func (s *service) verifyTransactionSignatures(signedTransactions []*protocol.SignedTransaction, resultStatuses []protocol.TransactionStatus) {
-	for i, signedTransaction := range signedTransactions {
-
-		// skip transactions that already failed due to different reasons
-		if resultStatuses[i] != protocol.TRANSACTION_STATUS_RESERVED {
-			continue
-		}
-
-		// check transaction signature
-		switch signedTransaction.Transaction().Signer().Scheme() {
-		case protocol.SIGNER_SCHEME_EDDSA:
-			if verifyEd25519Signer(signedTransaction) {
-				resultStatuses[i] = protocol.TRANSACTION_STATUS_PRE_ORDER_VALID
-			} else {
-				resultStatuses[i] = protocol.TRANSACTION_STATUS_REJECTED_SIGNATURE_MISMATCH
-			}
-		default:
-			resultStatuses[i] = protocol.TRANSACTION_STATUS_REJECTED_UNKNOWN_SIGNER_SCHEME
-		}
+// TransactionSignatureVerifier verifies the signatures of a transaction set, in parallel and remembering the ones
+// it already verified, it is shared with the transaction pool
+type TransactionSignatureVerifier interface {
+	VerifyTransactionSignatures(signedTransactions []*protocol.SignedTransaction, resultStatuses []protocol.TransactionStatus)
+}
 
-	}
+// TransactionSignatureVerifierUser is implemented by the virtual machine service in addition to services.VirtualMachine
+type TransactionSignatureVerifierUser interface {
+	SetTransactionSignatureVerifier(verifier TransactionSignatureVerifier)
 }
 
-func verifyEd25519Signer(signedTransaction *protocol.SignedTransaction) bool {
-	signerPublicKey := signedTransaction.Transaction().Signer().Eddsa().SignerPublicKey()
-	txHash := digest.CalcTxHash(signedTransaction.Transaction())
-	return signature.VerifyEd25519(signerPublicKey, txHash, signedTransaction.Signature())
+// SetTransactionSignatureVerifier replaces verifying the signatures one at a time on every pre order
+func (s *service) SetTransactionSignatureVerifier(verifier TransactionSignatureVerifier) {
+	s.signatureVerifier.Lock()
+	defer s.signatureVerifier.Unlock()
+
+	s.signatureVerifier.verifier = verifier
+}
+
+func (s *service) verifyTransactionSignatures(signedTransactions []*protocol.SignedTransaction, resultStatuses []protocol.TransactionStatus) {
+	s.signatureVerifier.RLock()
+	verifier := s.signatureVerifier.verifier
+	s.signatureVerifier.RUnlock()
+
+	if verifier == nil {
+		validators.VerifyTransactionSignatures(signedTransactions, resultStatuses)
+		return
+	}
+	verifier.VerifyTransactionSignatures(signedTransactions, resultStatuses)
 }
@@ -22,9 +22,12 @@ import (
 
 // do not create this struct directly although it's exported
 type TransactionBuilder struct {
-	signer   primitives.Ed25519PrivateKey
-	dontSign bool // special case for nil signer (trigger) will be set to true
-	builder  *protocol.SignedTransactionBuilder
+	signer       primitives.Ed25519PrivateKey
+	ecdsaSigner  primitives.EcdsaSecp256K1PrivateKey // when set the transaction is signed with ecdsa secp256k1 instead
+	multiSigners []primitives.Ed25519PrivateKey      // when set the transaction is multi signed, nil keys do not sign
+	dontSign     bool                                // special case for nil signer (trigger) will be set to true
+	args         []interface{}
+	builder      *protocol.SignedTransactionBuilder
 }
 
 func TransferTransaction() *TransactionBuilder {
@@ -58,12 +61,26 @@ func Transaction() *TransactionBuilder {
 
 func (t *TransactionBuilder) Build() *protocol.SignedTransaction {
 	if !t.dontSign {
-		t.builder.Signature = make([]byte, signature.ED25519_SIGNATURE_SIZE_BYTES)
+		if t.multiSigners != nil {
+			t.builder.Signature = digest.MultiSigSignatures(t.multiSign(nil))
+		} else if t.ecdsaSigner != nil {
+			t.builder.Signature = make([]byte, signature.ECDSA_SECP256K1_SIGNATURE_SIZE_BYTES)
+		} else {
+			t.builder.Signature = make([]byte, signature.ED25519_SIGNATURE_SIZE_BYTES)
+		}
 	}
 	signedTransaction := t.builder.Build()
 	if !t.dontSign {
 		txHash := digest.CalcTxHash(signedTransaction.Transaction())
-		sig, err := signature.SignEd25519(t.signer, txHash)
+		var sig []byte
+		var err error
+		if t.multiSigners != nil {
+			sig = digest.MultiSigSignatures(t.multiSign(txHash))
+		} else if t.ecdsaSigner != nil {
+			sig, err = signature.SignEcdsaSecp256K1(t.ecdsaSigner, txHash)
+		} else {
+			sig, err = signature.SignEd25519(t.signer, txHash)
+		}
 		if err != nil {
 			panic(err)
 		}
@@ -72,6 +89,26 @@ func (t *TransactionBuilder) Build() *protocol.SignedTransaction {
 	return signedTransaction
 }
 
+// without a tx hash returns placeholder signatures of the right size
+func (t *TransactionBuilder) multiSign(txHash primitives.Sha256) [][]byte {
+	signatures := make([][]byte, len(t.multiSigners))
+	for i, privateKey := range t.multiSigners {
+		if privateKey == nil {
+			continue
+		}
+		if txHash == nil {
+			signatures[i] = make([]byte, signature.ED25519_SIGNATURE_SIZE_BYTES)
+			continue
+		}
+		sig, err := signature.SignEd25519(privateKey, txHash)
+		if err != nil {
+			panic(err)
+		}
+		signatures[i] = sig
+	}
+	return signatures
+}
+
 func (t *TransactionBuilder) Builder() *protocol.SignedTransactionBuilder {
 	signedTransaction := t.Build()
 	t.builder.Signature = signedTransaction.Signature()
@@ -84,6 +121,26 @@ func (t *TransactionBuilder) WithEd25519Signer(keyPair *keys.Ed25519KeyPair) *Tr
 	return t
 }
 
+func (t *TransactionBuilder) WithEcdsaSecp256K1Signer(keyPair *keys.EcdsaSecp256K1KeyPair) *TransactionBuilder {
+	t.builder.Transaction.Signer = digest.EcdsaSecp256K1SignerBuilder(protocol.NETWORK_TYPE_TEST_NET, keyPair.PublicKey())
+	t.ecdsaSigner = keyPair.PrivateKey()
+	return t
+}
+
+// only the key pairs at signingIndexes sign the transaction
+func (t *TransactionBuilder) WithMultiSigSigner(threshold uint32, keyPairs []*keys.Ed25519KeyPair, signingIndexes ...int) *TransactionBuilder {
+	publicKeys := make([][]byte, len(keyPairs))
+	t.multiSigners = make([]primitives.Ed25519PrivateKey, len(keyPairs))
+	for i, keyPair := range keyPairs {
+		publicKeys[i] = keyPair.PublicKey()
+	}
+	for _, i := range signingIndexes {
+		t.multiSigners[i] = keyPairs[i].PrivateKey()
+	}
+	t.builder.Transaction.Signer = digest.MultiSigSignerBuilder(protocol.NETWORK_TYPE_TEST_NET, threshold, publicKeys)
+	return t
+}
+
 func (t *TransactionBuilder) WithTimestamp(timestamp time.Time) *TransactionBuilder {
 	t.builder.Transaction.Timestamp = primitives.TimestampNano(timestamp.UnixNano())
 	return t
@@ -119,10 +176,16 @@ func (t *TransactionBuilder) WithMethod(contractName primitives.ContractName, me
 }
 
 func (t *TransactionBuilder) WithArgs(args ...interface{}) *TransactionBuilder {
+	t.args = args
 	t.builder.Transaction.InputArgumentArray = ArgumentsArray(args...).RawArgumentsArray()
 	return t
 }
 
+// prepends the signer nonce to the arguments, so must come after them
+func (t *TransactionBuilder) WithSignerNonce(nonce uint64) *TransactionBuilder {
+	return t.WithArgs(append([]interface{}{digest.SignerNonceArgument(nonce)}, t.args...)...)
+}
+
 func (t *TransactionBuilder) WithAmountAndTargetAddress(amount uint64, targetAddress []byte) *TransactionBuilder {
 	return t.WithArgs(amount, targetAddress)
 }